@@ -1,16 +1,72 @@
 package main
 
 import (
+	"context"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"hellper/internal"
 	"hellper/internal/config"
 	"hellper/internal/handler"
+	"hellper/internal/job"
+	"hellper/internal/log"
 )
 
 func main() {
+	var (
+		ctx       = context.Background()
+		logger    = internal.NewLogger()
+		app       = internal.New(logger)
+		server    = &http.Server{Addr: determineListenAddress()}
+		scheduler *job.Scheduler
+	)
+
+	handler.Init(app)
 	http.HandleFunc("/", handler.NewHandlerRoute())
-	http.ListenAndServe(determineListenAddress(), nil)
+	// the Google APIs may be slow to answer, the server starts without waiting for the check
+	go internal.CheckGoogle(ctx, logger)
+
+	if config.Env.SchedulerEnabled {
+		scheduler = internal.NewScheduler(app)
+		scheduler.Start()
+	}
+
+	go func() {
+		err := server.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			logger.Error(ctx, log.Trace(), log.Action("server.ListenAndServe"), log.Reason(err.Error()))
+			os.Exit(1)
+		}
+	}()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	<-stop
+
+	shutdownCtx, cancel := context.WithTimeout(ctx, time.Duration(config.Env.ShutdownTimeoutSeconds)*time.Second)
+	defer cancel()
+
+	logger.Info(ctx, log.Trace(), log.Action("shutdown"))
+	err := server.Shutdown(shutdownCtx)
+	if err != nil {
+		logger.Error(ctx, log.Trace(), log.Action("server.Shutdown"), log.Reason(err.Error()))
+	}
+
+	if scheduler != nil {
+		err = scheduler.Shutdown(shutdownCtx)
+		if err != nil {
+			logger.Error(ctx, log.Trace(), log.Action("scheduler.Shutdown"), log.Reason(err.Error()))
+		}
+	}
+
+	// the jobs and the requests are done, so no new event is dispatched while the listeners are drained
+	err = app.Shutdown(shutdownCtx)
+	if err != nil {
+		logger.Error(ctx, log.Trace(), log.Action("app.Shutdown"), log.Reason(err.Error()))
+	}
 }
 
 func determineListenAddress() string {
@@ -3,9 +3,10 @@ package main
 import (
 	"context"
 	"hellper/internal/notify"
+	"os"
 )
 
 func main() {
 	ctx := context.Background()
-	notify.Notify(ctx)
+	os.Exit(notify.Notify(ctx, os.Args[1:], os.Stdout, os.Stderr))
 }
@@ -4,7 +4,7 @@ set -e
 
 if [ $# -eq 0 ]
 then
-  /app/http
+  exec /app/http
 fi
 
 exec $@
@@ -15,4 +15,5 @@ require (
 	golang.org/x/net v0.0.0-20200822124328-c89045814202
 	golang.org/x/oauth2 v0.0.0-20200902213428-5d25da1a8d43
 	google.golang.org/api v0.35.0
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c
 )
@@ -0,0 +1,175 @@
+// Package alert reads the alerts sent by the monitoring tools and maps them to incidents
+package alert
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"hellper/internal/model"
+)
+
+// Sources of the alerts
+const (
+	SourceAlertmanager = "alertmanager"
+	SourceGrafana      = "grafana"
+	SourceGeneric      = "generic"
+)
+
+var (
+	// ErrInvalidPayload is returned when the payload can not be read
+	ErrInvalidPayload = errors.New("invalid alert payload")
+	// ErrUnknownSource is returned for a source without parser
+	ErrUnknownSource = errors.New("unknown alert source")
+	// ErrInvalidSeverities is returned when the severities can not be parsed
+	ErrInvalidSeverities = errors.New("invalid alert severities")
+)
+
+// Alert is an alert firing or resolved on a monitoring tool
+type Alert struct {
+	Fingerprint string
+	Status      string
+	Name        string
+	Summary     string
+	Description string
+	URL         string
+	Labels      map[string]string
+	StartsAt    *time.Time
+}
+
+// Parse reads the alerts of the payload of the source
+func Parse(source string, body []byte) ([]Alert, error) {
+	switch source {
+	case SourceAlertmanager:
+		return ParseAlertmanager(body)
+	case SourceGrafana:
+		return ParseGrafana(body)
+	case SourceGeneric:
+		return ParseGeneric(body)
+	default:
+		return nil, fmt.Errorf("%w: %q, use %s, %s or %s", ErrUnknownSource, source, SourceAlertmanager, SourceGrafana, SourceGeneric)
+	}
+}
+
+// Settings map the labels of the alerts to the product and severity of the incidents they open
+type Settings struct {
+	ProductLabel    string
+	DefaultProduct  string
+	SeverityLabel   string
+	Severities      map[string]int64
+	DefaultSeverity int64
+	Commander       string
+	AutoResolve     bool
+}
+
+// Product is the product of the alert label, or the default product
+func (s Settings) Product(alert Alert) string {
+	if product := alert.Labels[s.ProductLabel]; product != "" {
+		return product
+	}
+	return s.DefaultProduct
+}
+
+// SeverityLevel is the severity of the alert label, or the default severity when the label is missing or unknown
+func (s Settings) SeverityLevel(alert Alert) int64 {
+	value := strings.ToLower(alert.Labels[s.SeverityLabel])
+	if level, ok := s.Severities[value]; ok {
+		return level
+	}
+	if level, err := strconv.ParseInt(strings.TrimPrefix(value, "sev"), 10, 64); err == nil && level >= 0 && level <= 3 {
+		return level
+	}
+	return s.DefaultSeverity
+}
+
+// ParseSeverities reads the severity levels of the label values in the format value=level;value=level, e.g. critical=0;warning=2
+func ParseSeverities(value string) (map[string]int64, error) {
+	severities := map[string]int64{}
+
+	for _, statement := range strings.Split(value, ";") {
+		statement = strings.TrimSpace(statement)
+		if statement == "" {
+			continue
+		}
+
+		parts := strings.SplitN(statement, "=", 2)
+		label := strings.ToLower(strings.TrimSpace(parts[0]))
+		if len(parts) != 2 || label == "" {
+			return nil, fmt.Errorf("%w: %q has no label value", ErrInvalidSeverities, statement)
+		}
+
+		level, err := strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 64)
+		if err != nil || level < 0 || level > 3 {
+			return nil, fmt.Errorf("%w: invalid severity %q of %s, use 0 to 3", ErrInvalidSeverities, strings.TrimSpace(parts[1]), label)
+		}
+		severities[label] = level
+	}
+
+	return severities, nil
+}
+
+// Title is the title of the incident opened by the alert
+func Title(alert Alert) string {
+	if alert.Summary != "" {
+		return alert.Summary
+	}
+	return alert.Name
+}
+
+// ChannelName is the name of the channel of the incident opened by the alert, unique by minute
+func ChannelName(alert Alert, now time.Time) string {
+	base := alert.Name
+	if base == "" {
+		base = alert.Summary
+	}
+
+	var slug strings.Builder
+	for _, r := range strings.ToLower(base) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			slug.WriteRune(r)
+		case slug.Len() > 0 && !strings.HasSuffix(slug.String(), "-"):
+			slug.WriteRune('-')
+		}
+	}
+
+	name := strings.Trim(slug.String(), "-")
+	if len(name) > 13 {
+		name = strings.Trim(name[:13], "-")
+	}
+	if name == "" {
+		name = "alert"
+	}
+	return "alert-" + name + "-" + now.UTC().Format("0601021504")
+}
+
+// fingerprint identifies an alert without fingerprint by its labels
+func fingerprint(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	for _, key := range keys {
+		hash.Write([]byte(key + "=" + labels[key] + "\n"))
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+func status(value string) (string, error) {
+	switch strings.ToLower(value) {
+	case model.AlertFiring:
+		return model.AlertFiring, nil
+	case model.AlertResolved:
+		return model.AlertResolved, nil
+	default:
+		return "", fmt.Errorf("%w: invalid status %q", ErrInvalidPayload, value)
+	}
+}
@@ -0,0 +1,223 @@
+package alert_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"hellper/internal/alert"
+	"hellper/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	startsAt := time.Date(2020, time.October, 19, 12, 0, 0, 0, time.UTC)
+
+	table := []struct {
+		testName      string
+		source        string
+		body          string
+		expected      []alert.Alert
+		expectedError error
+	}{
+		{
+			testName: "Alertmanager",
+			source:   alert.SourceAlertmanager,
+			body: `{"status": "firing", "alerts": [{"status": "firing", "fingerprint": "f1", "startsAt": "2020-10-19T12:00:00Z",
+				"labels": {"alertname": "HighErrorRate", "product": "Checkout", "severity": "critical"},
+				"annotations": {"summary": "Checkout errors", "description": "5% of the requests fail"},
+				"generatorURL": "https://prometheus.example/graph"}]}`,
+			expected: []alert.Alert{{
+				Fingerprint: "f1", Status: model.AlertFiring, Name: "HighErrorRate", Summary: "Checkout errors",
+				Description: "5% of the requests fail", URL: "https://prometheus.example/graph", StartsAt: &startsAt,
+				Labels: map[string]string{"alertname": "HighErrorRate", "product": "Checkout", "severity": "critical"},
+			}},
+		},
+		{
+			testName: "Grafana unified alerting",
+			source:   alert.SourceGrafana,
+			body: `{"alerts": [{"status": "resolved", "fingerprint": "f2", "labels": {"alertname": "Latency"},
+				"generatorURL": "https://grafana.example/alerting", "panelURL": "https://grafana.example/d/1?viewPanel=2"}]}`,
+			expected: []alert.Alert{{
+				Fingerprint: "f2", Status: model.AlertResolved, Name: "Latency", URL: "https://grafana.example/d/1?viewPanel=2",
+				Labels: map[string]string{"alertname": "Latency"},
+			}},
+		},
+		{
+			testName: "Grafana legacy alert",
+			source:   alert.SourceGrafana,
+			body: `{"ruleId": 7, "ruleName": "Latency", "ruleUrl": "https://grafana.example/d/1", "state": "alerting",
+				"title": "[Alerting] Latency", "message": "p99 above 2s", "tags": {"product": "Search"}}`,
+			expected: []alert.Alert{{
+				Fingerprint: "grafana-7", Status: model.AlertFiring, Name: "Latency", Summary: "[Alerting] Latency",
+				Description: "p99 above 2s", URL: "https://grafana.example/d/1", Labels: map[string]string{"product": "Search"},
+			}},
+		},
+		{
+			testName: "Grafana legacy pending state is ignored",
+			source:   alert.SourceGrafana,
+			body:     `{"ruleId": 7, "ruleName": "Latency", "state": "pending"}`,
+		},
+		{
+			testName: "Generic",
+			source:   alert.SourceGeneric,
+			body:     `{"alerts": [{"fingerprint": "db-1", "status": "FIRING", "title": "Database down", "labels": {"severity": "sev1"}}]}`,
+			expected: []alert.Alert{{
+				Fingerprint: "db-1", Status: model.AlertFiring, Summary: "Database down", Labels: map[string]string{"severity": "sev1"},
+			}},
+		},
+		{
+			testName:      "Generic without fingerprint",
+			source:        alert.SourceGeneric,
+			body:          `{"alerts": [{"status": "firing", "title": "Database down"}]}`,
+			expectedError: alert.ErrInvalidPayload,
+		},
+		{
+			testName:      "Invalid status",
+			source:        alert.SourceAlertmanager,
+			body:          `{"alerts": [{"status": "pending", "labels": {"alertname": "Latency"}}]}`,
+			expectedError: alert.ErrInvalidPayload,
+		},
+		{
+			testName:      "Invalid json",
+			source:        alert.SourceGeneric,
+			body:          `alerts`,
+			expectedError: alert.ErrInvalidPayload,
+		},
+		{
+			testName:      "Unknown source",
+			source:        "nagios",
+			body:          `{}`,
+			expectedError: alert.ErrUnknownSource,
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			alerts, err := alert.Parse(f.source, []byte(f.body))
+			if f.expectedError != nil {
+				assert.True(t, errors.Is(err, f.expectedError), "unexpected error %v", err)
+				return
+			}
+			assert.NoError(t, err)
+			if len(f.expected) == 0 {
+				assert.Empty(t, alerts)
+				return
+			}
+			assert.Equal(t, f.expected, alerts)
+		})
+	}
+}
+
+func TestAlertmanagerFingerprintFromLabels(t *testing.T) {
+	first, err := alert.ParseAlertmanager([]byte(`{"alerts": [{"status": "firing", "labels": {"alertname": "A", "instance": "1"}}]}`))
+	assert.NoError(t, err)
+	second, err := alert.ParseAlertmanager([]byte(`{"alerts": [{"status": "resolved", "labels": {"instance": "1", "alertname": "A"}}]}`))
+	assert.NoError(t, err)
+	other, err := alert.ParseAlertmanager([]byte(`{"alerts": [{"status": "firing", "labels": {"alertname": "A", "instance": "2"}}]}`))
+	assert.NoError(t, err)
+
+	assert.Equal(t, first[0].Fingerprint, second[0].Fingerprint)
+	assert.NotEqual(t, first[0].Fingerprint, other[0].Fingerprint)
+}
+
+func TestParseSeverities(t *testing.T) {
+	table := []struct {
+		testName      string
+		value         string
+		expected      map[string]int64
+		expectedError string
+	}{
+		{
+			testName: "Severities",
+			value:    "Critical=0; warning = 2;",
+			expected: map[string]int64{"critical": 0, "warning": 2},
+		},
+		{
+			testName: "Empty",
+			expected: map[string]int64{},
+		},
+		{
+			testName:      "Missing level",
+			value:         "critical",
+			expectedError: `invalid alert severities: "critical" has no label value`,
+		},
+		{
+			testName:      "Invalid level",
+			value:         "critical=5",
+			expectedError: `invalid alert severities: invalid severity "5" of critical, use 0 to 3`,
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			severities, err := alert.ParseSeverities(f.value)
+			if f.expectedError != "" {
+				assert.EqualError(t, err, f.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, f.expected, severities)
+		})
+	}
+}
+
+func TestSettings(t *testing.T) {
+	settings := alert.Settings{
+		ProductLabel:    "product",
+		DefaultProduct:  "Platform",
+		SeverityLabel:   "severity",
+		Severities:      map[string]int64{"critical": 0, "warning": 2},
+		DefaultSeverity: 3,
+	}
+
+	table := []struct {
+		testName         string
+		labels           map[string]string
+		expectedProduct  string
+		expectedSeverity int64
+	}{
+		{
+			testName:         "Labels",
+			labels:           map[string]string{"product": "Checkout", "severity": "Critical"},
+			expectedProduct:  "Checkout",
+			expectedSeverity: 0,
+		},
+		{
+			testName:         "Severity level label",
+			labels:           map[string]string{"severity": "sev1"},
+			expectedProduct:  "Platform",
+			expectedSeverity: 1,
+		},
+		{
+			testName:         "Unknown severity",
+			labels:           map[string]string{"severity": "page"},
+			expectedProduct:  "Platform",
+			expectedSeverity: 3,
+		},
+		{
+			testName:         "Without labels",
+			expectedProduct:  "Platform",
+			expectedSeverity: 3,
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			a := alert.Alert{Labels: f.labels}
+			assert.Equal(t, f.expectedProduct, settings.Product(a))
+			assert.Equal(t, f.expectedSeverity, settings.SeverityLevel(a))
+		})
+	}
+}
+
+func TestChannelName(t *testing.T) {
+	now := time.Date(2020, time.October, 19, 12, 34, 0, 0, time.UTC)
+
+	assert.Equal(t, "alert-higherrorrate-2010191234", alert.ChannelName(alert.Alert{Name: "HighErrorRate"}, now))
+	assert.Equal(t, "alert-database-down-2010191234", alert.ChannelName(alert.Alert{Summary: "Database down!"}, now))
+	assert.Equal(t, "alert-checkout-api-2010191234", alert.ChannelName(alert.Alert{Name: "checkout api 5xx errors"}, now))
+	assert.Equal(t, "alert-alert-2010191234", alert.ChannelName(alert.Alert{Name: "!!!"}, now))
+}
@@ -0,0 +1,161 @@
+package alert
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"hellper/internal/model"
+)
+
+type alertmanagerPayload struct {
+	Alerts []struct {
+		Status       string            `json:"status"`
+		Labels       map[string]string `json:"labels"`
+		Annotations  map[string]string `json:"annotations"`
+		StartsAt     *time.Time        `json:"startsAt"`
+		GeneratorURL string            `json:"generatorURL"`
+		Fingerprint  string            `json:"fingerprint"`
+		PanelURL     string            `json:"panelURL"`
+		DashboardURL string            `json:"dashboardURL"`
+	} `json:"alerts"`
+}
+
+// ParseAlertmanager reads the alerts of an Alertmanager webhook, the Grafana unified alerting payload is read the same way
+func ParseAlertmanager(body []byte) ([]Alert, error) {
+	var payload alertmanagerPayload
+	err := json.Unmarshal(body, &payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPayload, err.Error())
+	}
+
+	alerts := make([]Alert, 0, len(payload.Alerts))
+	for _, a := range payload.Alerts {
+		alertStatus, err := status(a.Status)
+		if err != nil {
+			return nil, err
+		}
+
+		url := a.GeneratorURL
+		if a.PanelURL != "" {
+			url = a.PanelURL
+		} else if a.DashboardURL != "" {
+			url = a.DashboardURL
+		}
+
+		fp := a.Fingerprint
+		if fp == "" {
+			fp = fingerprint(a.Labels)
+		}
+
+		alerts = append(alerts, Alert{
+			Fingerprint: fp,
+			Status:      alertStatus,
+			Name:        a.Labels["alertname"],
+			Summary:     a.Annotations["summary"],
+			Description: a.Annotations["description"],
+			URL:         url,
+			Labels:      a.Labels,
+			StartsAt:    a.StartsAt,
+		})
+	}
+	return alerts, nil
+}
+
+type grafanaLegacyPayload struct {
+	Alerts   json.RawMessage   `json:"alerts"`
+	RuleID   int64             `json:"ruleId"`
+	RuleName string            `json:"ruleName"`
+	RuleURL  string            `json:"ruleUrl"`
+	State    string            `json:"state"`
+	Title    string            `json:"title"`
+	Message  string            `json:"message"`
+	Tags     map[string]string `json:"tags"`
+}
+
+// ParseGrafana reads the alerts of a Grafana webhook, either of the unified alerting or of the legacy alert rules.
+// The legacy pending, no data and paused states are ignored
+func ParseGrafana(body []byte) ([]Alert, error) {
+	var payload grafanaLegacyPayload
+	err := json.Unmarshal(body, &payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPayload, err.Error())
+	}
+	if len(payload.Alerts) > 0 {
+		return ParseAlertmanager(body)
+	}
+
+	var alertStatus string
+	switch payload.State {
+	case "alerting":
+		alertStatus = model.AlertFiring
+	case "ok":
+		alertStatus = model.AlertResolved
+	default:
+		return nil, nil
+	}
+
+	if payload.RuleID == 0 {
+		return nil, fmt.Errorf("%w: missing ruleId", ErrInvalidPayload)
+	}
+
+	return []Alert{{
+		Fingerprint: "grafana-" + strconv.FormatInt(payload.RuleID, 10),
+		Status:      alertStatus,
+		Name:        payload.RuleName,
+		Summary:     payload.Title,
+		Description: payload.Message,
+		URL:         payload.RuleURL,
+		Labels:      payload.Tags,
+	}}, nil
+}
+
+type genericPayload struct {
+	Alerts []struct {
+		Fingerprint string            `json:"fingerprint"`
+		Status      string            `json:"status"`
+		Name        string            `json:"name"`
+		Title       string            `json:"title"`
+		Description string            `json:"description"`
+		URL         string            `json:"url"`
+		Labels      map[string]string `json:"labels"`
+		StartsAt    *time.Time        `json:"starts_at"`
+	} `json:"alerts"`
+}
+
+// ParseGeneric reads the alerts of the generic schema:
+// {"alerts": [{"fingerprint", "status": "firing|resolved", "name", "title", "description", "url", "labels": {}, "starts_at"}]}
+func ParseGeneric(body []byte) ([]Alert, error) {
+	var payload genericPayload
+	err := json.Unmarshal(body, &payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPayload, err.Error())
+	}
+
+	alerts := make([]Alert, 0, len(payload.Alerts))
+	for index, a := range payload.Alerts {
+		alertStatus, err := status(a.Status)
+		if err != nil {
+			return nil, err
+		}
+		if a.Fingerprint == "" {
+			return nil, fmt.Errorf("%w: alert %d has no fingerprint", ErrInvalidPayload, index)
+		}
+		if a.Name == "" && a.Title == "" {
+			return nil, fmt.Errorf("%w: alert %d has no name nor title", ErrInvalidPayload, index)
+		}
+
+		alerts = append(alerts, Alert{
+			Fingerprint: a.Fingerprint,
+			Status:      alertStatus,
+			Name:        a.Name,
+			Summary:     a.Title,
+			Description: a.Description,
+			URL:         a.URL,
+			Labels:      a.Labels,
+			StartsAt:    a.StartsAt,
+		})
+	}
+	return alerts, nil
+}
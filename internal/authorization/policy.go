@@ -0,0 +1,174 @@
+// Package authorization decides who may run the lifecycle commands of an incident
+package authorization
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"hellper/internal/bot"
+	"hellper/internal/log"
+	"hellper/internal/model"
+)
+
+// Actions guarded by the policy
+const (
+	ActionResolve = "resolve"
+	ActionClose   = "close"
+	ActionCancel  = "cancel"
+)
+
+// Role identifies a group of users allowed to run an action
+type Role string
+
+// Roles accepted by the policy
+const (
+	RoleAnyone    Role = "anyone"
+	RoleCommander Role = "commander"
+	RoleAuthor    Role = "author"
+	RoleUserGroup Role = "usergroup"
+)
+
+// Rule allows the users of a role to run an action
+type Rule struct {
+	Role        Role
+	UserGroupID string
+}
+
+// Policy lists, for each action, the rules that allow a user to run it.
+// Actions without rules can be run by anyone.
+type Policy map[string][]Rule
+
+// Decision is the result of an authorization, with the reason that led to it
+type Decision struct {
+	Allowed bool
+	Reason  string
+}
+
+// ErrInvalidPolicy is returned when the policy can not be parsed
+var ErrInvalidPolicy = errors.New("invalid authorization policy")
+
+// ParsePolicy reads a policy in the format action=role,role;action=role.
+// Roles are anyone, commander, author and usergroup:<Slack user group ID>, e.g.
+// resolve=commander,author,usergroup:S0123ABC;close=commander;cancel=commander,author
+func ParsePolicy(value string) (Policy, error) {
+	policy := Policy{}
+
+	for _, statement := range strings.Split(value, ";") {
+		statement = strings.TrimSpace(statement)
+		if statement == "" {
+			continue
+		}
+
+		parts := strings.SplitN(statement, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%w: %q has no roles", ErrInvalidPolicy, statement)
+		}
+
+		action := strings.TrimSpace(parts[0])
+		switch action {
+		case ActionResolve, ActionClose, ActionCancel:
+		default:
+			return nil, fmt.Errorf("%w: unknown action %q", ErrInvalidPolicy, action)
+		}
+
+		for _, role := range strings.Split(parts[1], ",") {
+			rule, err := parseRule(strings.TrimSpace(role))
+			if err != nil {
+				return nil, err
+			}
+			policy[action] = append(policy[action], rule)
+		}
+	}
+
+	return policy, nil
+}
+
+func parseRule(role string) (Rule, error) {
+	switch {
+	case role == string(RoleAnyone), role == string(RoleCommander), role == string(RoleAuthor):
+		return Rule{Role: Role(role)}, nil
+	case strings.HasPrefix(role, string(RoleUserGroup)+":"):
+		userGroupID := strings.TrimPrefix(role, string(RoleUserGroup)+":")
+		if userGroupID == "" {
+			return Rule{}, fmt.Errorf("%w: usergroup without ID", ErrInvalidPolicy)
+		}
+		return Rule{Role: RoleUserGroup, UserGroupID: userGroupID}, nil
+	default:
+		return Rule{}, fmt.Errorf("%w: unknown role %q", ErrInvalidPolicy, role)
+	}
+}
+
+// Guards tells if the policy has rules for the action
+func (p Policy) Guards(action string) bool {
+	return len(p[action]) > 0
+}
+
+// Authorize checks if the user can run the action on the incident
+func (p Policy) Authorize(
+	ctx context.Context,
+	client bot.Client,
+	logger log.Logger,
+	action string,
+	incident model.Incident,
+	userID string,
+) Decision {
+	rules := p[action]
+	if len(rules) == 0 {
+		return Decision{Allowed: true, Reason: "no policy for " + action}
+	}
+
+	for _, rule := range rules {
+		switch rule.Role {
+		case RoleAnyone:
+			return Decision{Allowed: true, Reason: "anyone"}
+		case RoleCommander:
+			if incident.CommanderId != "" && incident.CommanderId == userID {
+				return Decision{Allowed: true, Reason: "commander"}
+			}
+		case RoleAuthor:
+			if incident.IncidentAuthor != "" && incident.IncidentAuthor == userID {
+				return Decision{Allowed: true, Reason: "author"}
+			}
+		case RoleUserGroup:
+			members, err := client.GetUserGroupMembersContext(ctx, rule.UserGroupID)
+			if err != nil {
+				logger.Error(
+					ctx,
+					log.Trace(),
+					log.Action("client.GetUserGroupMembersContext"),
+					log.Reason(err.Error()),
+					log.NewValue("userGroupID", rule.UserGroupID),
+				)
+				continue
+			}
+			for _, member := range members {
+				if member == userID {
+					return Decision{Allowed: true, Reason: "member of usergroup " + rule.UserGroupID}
+				}
+			}
+		}
+	}
+
+	return Decision{Allowed: false, Reason: "only " + describeRules(rules) + " can " + action + " this incident"}
+}
+
+func describeRules(rules []Rule) string {
+	var roles []string
+	for _, rule := range rules {
+		switch rule.Role {
+		case RoleCommander:
+			roles = append(roles, "the incident commander")
+		case RoleAuthor:
+			roles = append(roles, "the incident author")
+		case RoleUserGroup:
+			roles = append(roles, "members of <!subteam^"+rule.UserGroupID+">")
+		}
+	}
+
+	if len(roles) == 1 {
+		return roles[0]
+	}
+	return strings.Join(roles[:len(roles)-1], ", ") + " or " + roles[len(roles)-1]
+}
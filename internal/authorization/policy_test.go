@@ -0,0 +1,178 @@
+package authorization_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"hellper/internal/authorization"
+	"hellper/internal/bot"
+	"hellper/internal/log"
+	"hellper/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePolicy(t *testing.T) {
+	table := []struct {
+		testName     string
+		value        string
+		expected     authorization.Policy
+		errorMessage string
+	}{
+		{
+			testName: "Empty policy",
+			value:    "",
+			expected: authorization.Policy{},
+		},
+		{
+			testName: "Every role",
+			value:    "resolve=commander, author,usergroup:S0123;close=commander; cancel=anyone",
+			expected: authorization.Policy{
+				authorization.ActionResolve: {
+					{Role: authorization.RoleCommander},
+					{Role: authorization.RoleAuthor},
+					{Role: authorization.RoleUserGroup, UserGroupID: "S0123"},
+				},
+				authorization.ActionClose:  {{Role: authorization.RoleCommander}},
+				authorization.ActionCancel: {{Role: authorization.RoleAnyone}},
+			},
+		},
+		{
+			testName:     "Unknown action",
+			value:        "open=commander",
+			errorMessage: `invalid authorization policy: unknown action "open"`,
+		},
+		{
+			testName:     "Unknown role",
+			value:        "close=admin",
+			errorMessage: `invalid authorization policy: unknown role "admin"`,
+		},
+		{
+			testName:     "User group without ID",
+			value:        "close=usergroup:",
+			errorMessage: "invalid authorization policy: usergroup without ID",
+		},
+		{
+			testName:     "Action without roles",
+			value:        "close",
+			errorMessage: `invalid authorization policy: "close" has no roles`,
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			policy, err := authorization.ParsePolicy(f.value)
+			if f.errorMessage != "" {
+				assert.EqualError(t, err, f.errorMessage)
+				assert.True(t, errors.Is(err, authorization.ErrInvalidPolicy))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, f.expected, policy)
+		})
+	}
+}
+
+type authorizeFixture struct {
+	testName string
+	policy   string
+	action   string
+	userID   string
+	expected authorization.Decision
+
+	ctx        context.Context
+	mockLogger log.Logger
+	mockClient bot.Client
+}
+
+func (f *authorizeFixture) setup(t *testing.T) {
+	var (
+		loggerMock = log.NewLoggerMock()
+		clientMock = bot.NewClientMock()
+	)
+
+	f.ctx = context.Background()
+
+	loggerMock.On("Error", f.ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+	clientMock.On("GetUserGroupMembersContext", f.ctx, "SOPS").Return([]string{"U3", "U4"}, nil)
+	clientMock.On("GetUserGroupMembersContext", f.ctx, "SGONE").Return([]string{}, errors.New("no_such_subteam"))
+
+	f.mockLogger = loggerMock
+	f.mockClient = clientMock
+}
+
+func TestAuthorize(t *testing.T) {
+	incident := model.Incident{CommanderId: "U1", IncidentAuthor: "U2"}
+
+	table := []authorizeFixture{
+		{
+			testName: "Anyone runs an action without policy",
+			policy:   "close=commander",
+			action:   authorization.ActionResolve,
+			userID:   "U9",
+			expected: authorization.Decision{Allowed: true, Reason: "no policy for resolve"},
+		},
+		{
+			testName: "Commander is allowed",
+			policy:   "close=author,commander",
+			action:   authorization.ActionClose,
+			userID:   "U1",
+			expected: authorization.Decision{Allowed: true, Reason: "commander"},
+		},
+		{
+			testName: "Author is allowed",
+			policy:   "cancel=commander,author",
+			action:   authorization.ActionCancel,
+			userID:   "U2",
+			expected: authorization.Decision{Allowed: true, Reason: "author"},
+		},
+		{
+			testName: "User group member is allowed",
+			policy:   "resolve=commander,usergroup:SGONE,usergroup:SOPS",
+			action:   authorization.ActionResolve,
+			userID:   "U4",
+			expected: authorization.Decision{Allowed: true, Reason: "member of usergroup SOPS"},
+		},
+		{
+			testName: "Anyone role",
+			policy:   "resolve=commander,anyone",
+			action:   authorization.ActionResolve,
+			userID:   "U9",
+			expected: authorization.Decision{Allowed: true, Reason: "anyone"},
+		},
+		{
+			testName: "Other users are denied",
+			policy:   "close=commander,author,usergroup:SOPS",
+			action:   authorization.ActionClose,
+			userID:   "U9",
+			expected: authorization.Decision{
+				Allowed: false,
+				Reason:  "only the incident commander, the incident author or members of <!subteam^SOPS> can close this incident",
+			},
+		},
+		{
+			testName: "Author is denied when only the commander is allowed",
+			policy:   "close=commander",
+			action:   authorization.ActionClose,
+			userID:   "U2",
+			expected: authorization.Decision{Allowed: false, Reason: "only the incident commander can close this incident"},
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			f.setup(t)
+
+			policy, err := authorization.ParsePolicy(f.policy)
+			require.NoError(t, err)
+
+			decision := policy.Authorize(f.ctx, f.mockClient, f.mockLogger, f.action, incident, f.userID)
+			assert.Equal(t, f.expected, decision)
+		})
+	}
+}
@@ -19,4 +19,10 @@ type Client interface {
 	ArchiveConversationContext(ctx context.Context, channelID string) error
 	JoinConversationContext(ctx context.Context, channelID string) (*slack.Channel, string, []string, error)
 	GetUsersInConversationContext(context.Context, *slack.GetUsersInConversationParameters) ([]string, string, error)
+	GetConversationHistoryContext(context.Context, *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error)
+	GetConversationInfoContext(ctx context.Context, channelID string, includeLocale bool) (*slack.Channel, error)
+	PublishViewContext(ctx context.Context, userID string, view slack.HomeTabViewRequest, hash string) (*slack.ViewResponse, error)
+	GetUserGroupMembersContext(ctx context.Context, userGroup string) ([]string, error)
+	GetConversationRepliesContext(context.Context, *slack.GetConversationRepliesParameters) ([]slack.Message, bool, string, error)
+	UploadFileContext(context.Context, slack.FileUploadParameters) (*slack.File, error)
 }
@@ -118,3 +118,57 @@ func (mock *ClientMock) JoinConversationContext(ctx context.Context, channelID s
 	args := mock.Called(ctx, channelID)
 	return args.Get(0).(*slack.Channel), args.String(1), args.Get(2).([]string), args.Error(3)
 }
+
+func (mock *ClientMock) GetConversationHistoryContext(ctx context.Context, params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error) {
+	var (
+		args   = mock.Called(ctx, params)
+		result = args.Get(0)
+	)
+	if result == nil {
+		return nil, args.Error(1)
+	}
+	return result.(*slack.GetConversationHistoryResponse), args.Error(1)
+}
+
+func (mock *ClientMock) GetConversationRepliesContext(ctx context.Context, params *slack.GetConversationRepliesParameters) ([]slack.Message, bool, string, error) {
+	args := mock.Called(ctx, params)
+	return args.Get(0).([]slack.Message), args.Bool(1), args.String(2), args.Error(3)
+}
+
+func (mock *ClientMock) GetUserGroupMembersContext(ctx context.Context, userGroup string) ([]string, error) {
+	args := mock.Called(ctx, userGroup)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (mock *ClientMock) GetConversationInfoContext(ctx context.Context, channelID string, includeLocale bool) (*slack.Channel, error) {
+	var (
+		args   = mock.Called(ctx, channelID, includeLocale)
+		result = args.Get(0)
+	)
+	if result == nil {
+		return nil, args.Error(1)
+	}
+	return result.(*slack.Channel), args.Error(1)
+}
+
+func (mock *ClientMock) PublishViewContext(ctx context.Context, userID string, view slack.HomeTabViewRequest, hash string) (*slack.ViewResponse, error) {
+	var (
+		args   = mock.Called(ctx, userID, view, hash)
+		result = args.Get(0)
+	)
+	if result == nil {
+		return nil, args.Error(1)
+	}
+	return result.(*slack.ViewResponse), args.Error(1)
+}
+
+func (mock *ClientMock) UploadFileContext(ctx context.Context, params slack.FileUploadParameters) (*slack.File, error) {
+	var (
+		args   = mock.Called(ctx, params)
+		result = args.Get(0)
+	)
+	if result == nil {
+		return nil, args.Error(1)
+	}
+	return result.(*slack.File), args.Error(1)
+}
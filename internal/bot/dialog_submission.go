@@ -11,6 +11,13 @@ type DialogSubmission struct {
 	CallbackID  string     `json:"callback_id"`
 	ResponseURL string     `json:"response_url"`
 	State       string     `json:"state"`
+	Actions     []Action   `json:"actions"`
+}
+
+// Action is a button clicked on an interactive message
+type Action struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
 }
 
 type Team struct {
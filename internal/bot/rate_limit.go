@@ -0,0 +1,380 @@
+package bot
+
+import (
+	"context"
+	"expvar"
+	"strings"
+	"sync"
+	"time"
+
+	"hellper/internal/log"
+
+	"github.com/slack-go/slack"
+)
+
+// Tier groups the Slack Web API methods that share the same rate limit.
+// See https://api.slack.com/docs/rate-limits
+type Tier int
+
+const (
+	Tier1 Tier = iota + 1
+	Tier2
+	Tier3
+	Tier4
+	// TierPostMessage is the special limit of chat.postMessage, around one message per second per channel
+	TierPostMessage
+)
+
+type tierLimit struct {
+	perMinute int
+	burst     int
+}
+
+var tierLimits = map[Tier]tierLimit{
+	Tier1:           {perMinute: 1, burst: 1},
+	Tier2:           {perMinute: 20, burst: 3},
+	Tier3:           {perMinute: 50, burst: 5},
+	Tier4:           {perMinute: 100, burst: 10},
+	TierPostMessage: {perMinute: 60, burst: 1},
+}
+
+var methodTiers = map[string]Tier{
+	"chat.postEphemeral":     Tier4,
+	"chat.postMessage":       TierPostMessage,
+	"conversations.create":   Tier2,
+	"conversations.invite":   Tier3,
+	"pins.list":              Tier2,
+	"users.info":             Tier4,
+	"conversations.setTopic": Tier2,
+	"dialog.open":            Tier4,
+	"pins.add":               Tier2,
+	"conversations.archive":  Tier2,
+	"conversations.join":     Tier3,
+	"conversations.members":  Tier4,
+	"conversations.history":  Tier3,
+	"conversations.replies":  Tier3,
+	"usergroups.users.list":  Tier2,
+	"conversations.info":     Tier3,
+	"views.publish":          Tier4,
+	"files.upload":           Tier2,
+}
+
+// rateLimitMetrics is published on /debug/vars by the expvar package
+var rateLimitMetrics = expvar.NewMap("slack_client")
+
+// limiter is a token bucket that tells how long a caller must wait before the next request
+type limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(limit tierLimit) *limiter {
+	return &limiter{
+		rate:   float64(limit.perMinute) / 60,
+		burst:  float64(limit.burst),
+		tokens: float64(limit.burst),
+	}
+}
+
+func (l *limiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+	l.tokens--
+
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+type rateLimitedClient struct {
+	logger     log.Logger
+	client     Client
+	maxRetries int
+	backoff    time.Duration
+	sleep      func(context.Context, time.Duration) error
+
+	mu       sync.Mutex
+	limiters map[string]*limiter
+}
+
+// NewRateLimitedClient wraps a Client, pacing the calls by the Slack rate limit tier of each method
+// and retrying the ones that fail with a rate limit or a transient server error.
+func NewRateLimitedClient(logger log.Logger, client Client, maxRetries int) Client {
+	return &rateLimitedClient{
+		logger:     logger,
+		client:     client,
+		maxRetries: maxRetries,
+		backoff:    time.Second,
+		sleep:      sleepContext,
+		limiters:   map[string]*limiter{},
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (c *rateLimitedClient) limiterFor(method, key string) *limiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tier := methodTiers[method]
+	name := method
+	if tier == TierPostMessage {
+		name = method + ":" + key
+	}
+
+	l, ok := c.limiters[name]
+	if !ok {
+		limit, ok := tierLimits[tier]
+		if !ok {
+			limit = tierLimits[Tier2]
+		}
+		l = newLimiter(limit)
+		c.limiters[name] = l
+	}
+	return l
+}
+
+func (c *rateLimitedClient) throttle(ctx context.Context, method, key string) error {
+	wait := c.limiterFor(method, key).reserve(time.Now())
+	if wait <= 0 {
+		return nil
+	}
+
+	rateLimitMetrics.Add(method+".throttled", 1)
+	rateLimitMetrics.Add(method+".throttled_ms", wait.Milliseconds())
+	c.logger.Info(
+		ctx,
+		log.Trace(),
+		log.Action("throttle"),
+		log.NewValue("method", method),
+		log.NewValue("wait", wait.String()),
+	)
+
+	return c.sleep(ctx, wait)
+}
+
+// retryDelay tells whether a failed call can be retried and how long to wait before it
+func retryDelay(err error, attempt int, backoff time.Duration) (time.Duration, bool) {
+	exponential := backoff * time.Duration(1<<uint(attempt))
+
+	switch e := err.(type) {
+	case *slack.RateLimitedError:
+		if e.RetryAfter > 0 {
+			return e.RetryAfter, true
+		}
+		return exponential, true
+	case interface{ HTTPStatusCode() int }:
+		if e.HTTPStatusCode() >= 500 {
+			return exponential, true
+		}
+		return 0, false
+	}
+
+	if err.Error() == "ratelimited" {
+		return exponential, true
+	}
+	return 0, false
+}
+
+func (c *rateLimitedClient) do(ctx context.Context, method, key string, call func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := c.throttle(ctx, method, key)
+		if err != nil {
+			return err
+		}
+
+		err = call()
+		if err == nil {
+			return nil
+		}
+
+		wait, retryable := retryDelay(err, attempt, c.backoff)
+		if _, ok := err.(*slack.RateLimitedError); ok || err.Error() == "ratelimited" {
+			rateLimitMetrics.Add(method+".rate_limited", 1)
+		}
+		if !retryable || attempt >= c.maxRetries {
+			rateLimitMetrics.Add(method+".failures", 1)
+			return err
+		}
+
+		rateLimitMetrics.Add(method+".retries", 1)
+		c.logger.Info(
+			ctx,
+			log.Trace(),
+			log.Action("retry"),
+			log.Reason(err.Error()),
+			log.NewValue("method", method),
+			log.NewValue("attempt", attempt+1),
+			log.NewValue("wait", wait.String()),
+		)
+
+		err = c.sleep(ctx, wait)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (c *rateLimitedClient) PostEphemeralContext(ctx context.Context, channelID, userID string, options ...slack.MsgOption) (timestamp string, err error) {
+	err = c.do(ctx, "chat.postEphemeral", channelID, func() (err error) {
+		timestamp, err = c.client.PostEphemeralContext(ctx, channelID, userID, options...)
+		return err
+	})
+	return timestamp, err
+}
+
+func (c *rateLimitedClient) PostMessage(channelID string, options ...slack.MsgOption) (channel string, timestamp string, err error) {
+	err = c.do(context.Background(), "chat.postMessage", channelID, func() (err error) {
+		channel, timestamp, err = c.client.PostMessage(channelID, options...)
+		return err
+	})
+	return channel, timestamp, err
+}
+
+func (c *rateLimitedClient) CreateConversationContext(ctx context.Context, channelName string, isPrivate bool) (channel *slack.Channel, err error) {
+	err = c.do(ctx, "conversations.create", "", func() (err error) {
+		channel, err = c.client.CreateConversationContext(ctx, channelName, isPrivate)
+		return err
+	})
+	return channel, err
+}
+
+func (c *rateLimitedClient) InviteUsersToConversationContext(ctx context.Context, channelID string, users ...string) (channel *slack.Channel, err error) {
+	err = c.do(ctx, "conversations.invite", channelID, func() (err error) {
+		channel, err = c.client.InviteUsersToConversationContext(ctx, channelID, users...)
+		return err
+	})
+	return channel, err
+}
+
+func (c *rateLimitedClient) ListPins(channelID string) (items []slack.Item, paging *slack.Paging, err error) {
+	err = c.do(context.Background(), "pins.list", channelID, func() (err error) {
+		items, paging, err = c.client.ListPins(channelID)
+		return err
+	})
+	return items, paging, err
+}
+
+func (c *rateLimitedClient) GetUserInfoContext(ctx context.Context, userID string) (user *slack.User, err error) {
+	err = c.do(ctx, "users.info", userID, func() (err error) {
+		user, err = c.client.GetUserInfoContext(ctx, userID)
+		return err
+	})
+	return user, err
+}
+
+func (c *rateLimitedClient) SetTopicOfConversation(channelID, topic string) (channel *slack.Channel, err error) {
+	err = c.do(context.Background(), "conversations.setTopic", channelID, func() (err error) {
+		channel, err = c.client.SetTopicOfConversation(channelID, topic)
+		return err
+	})
+	return channel, err
+}
+
+func (c *rateLimitedClient) OpenDialog(triggerID string, dialog slack.Dialog) error {
+	return c.do(context.Background(), "dialog.open", triggerID, func() error {
+		return c.client.OpenDialog(triggerID, dialog)
+	})
+}
+
+func (c *rateLimitedClient) AddPin(channelID string, item slack.ItemRef) error {
+	return c.do(context.Background(), "pins.add", channelID, func() error {
+		return c.client.AddPin(channelID, item)
+	})
+}
+
+func (c *rateLimitedClient) ArchiveConversationContext(ctx context.Context, channelID string) error {
+	return c.do(ctx, "conversations.archive", channelID, func() error {
+		return c.client.ArchiveConversationContext(ctx, channelID)
+	})
+}
+
+func (c *rateLimitedClient) JoinConversationContext(ctx context.Context, channelID string) (channel *slack.Channel, warning string, warnings []string, err error) {
+	err = c.do(ctx, "conversations.join", channelID, func() (err error) {
+		channel, warning, warnings, err = c.client.JoinConversationContext(ctx, channelID)
+		return err
+	})
+	return channel, warning, warnings, err
+}
+
+func (c *rateLimitedClient) GetUsersInConversationContext(ctx context.Context, params *slack.GetUsersInConversationParameters) (members []string, cursor string, err error) {
+	err = c.do(ctx, "conversations.members", params.ChannelID, func() (err error) {
+		members, cursor, err = c.client.GetUsersInConversationContext(ctx, params)
+		return err
+	})
+	return members, cursor, err
+}
+
+func (c *rateLimitedClient) GetConversationHistoryContext(ctx context.Context, params *slack.GetConversationHistoryParameters) (history *slack.GetConversationHistoryResponse, err error) {
+	err = c.do(ctx, "conversations.history", params.ChannelID, func() (err error) {
+		history, err = c.client.GetConversationHistoryContext(ctx, params)
+		return err
+	})
+	return history, err
+}
+
+func (c *rateLimitedClient) GetConversationRepliesContext(ctx context.Context, params *slack.GetConversationRepliesParameters) (msgs []slack.Message, hasMore bool, cursor string, err error) {
+	err = c.do(ctx, "conversations.replies", params.ChannelID, func() (err error) {
+		msgs, hasMore, cursor, err = c.client.GetConversationRepliesContext(ctx, params)
+		return err
+	})
+	return msgs, hasMore, cursor, err
+}
+
+func (c *rateLimitedClient) GetUserGroupMembersContext(ctx context.Context, userGroup string) (members []string, err error) {
+	err = c.do(ctx, "usergroups.users.list", userGroup, func() (err error) {
+		members, err = c.client.GetUserGroupMembersContext(ctx, userGroup)
+		return err
+	})
+	return members, err
+}
+
+func (c *rateLimitedClient) GetConversationInfoContext(ctx context.Context, channelID string, includeLocale bool) (channel *slack.Channel, err error) {
+	err = c.do(ctx, "conversations.info", channelID, func() (err error) {
+		channel, err = c.client.GetConversationInfoContext(ctx, channelID, includeLocale)
+		return err
+	})
+	return channel, err
+}
+
+func (c *rateLimitedClient) PublishViewContext(ctx context.Context, userID string, view slack.HomeTabViewRequest, hash string) (resp *slack.ViewResponse, err error) {
+	err = c.do(ctx, "views.publish", userID, func() (err error) {
+		resp, err = c.client.PublishViewContext(ctx, userID, view, hash)
+		return err
+	})
+	return resp, err
+}
+
+func (c *rateLimitedClient) UploadFileContext(ctx context.Context, params slack.FileUploadParameters) (file *slack.File, err error) {
+	err = c.do(ctx, "files.upload", strings.Join(params.Channels, ","), func() (err error) {
+		file, err = c.client.UploadFileContext(ctx, params)
+		return err
+	})
+	return file, err
+}
@@ -0,0 +1,138 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"hellper/internal/log"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type httpStatusError int
+
+func (e httpStatusError) Error() string {
+	return fmt.Sprintf("slack server error: %d", int(e))
+}
+
+func (e httpStatusError) HTTPStatusCode() int {
+	return int(e)
+}
+
+type rateLimitFixture struct {
+	testName      string
+	errs          []error
+	maxRetries    int
+	expectedCalls int
+	expectedWaits []time.Duration
+	expectError   bool
+
+	client *rateLimitedClient
+	mock   *ClientMock
+	waits  []time.Duration
+}
+
+func (f *rateLimitFixture) setup(t *testing.T) {
+	loggerMock := log.NewLoggerMock()
+	loggerMock.On("Info", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+
+	f.mock = NewClientMock()
+	for _, err := range f.errs {
+		f.mock.On("ListPins", "C123").Return(nil, nil, err).Once()
+	}
+	f.mock.On("ListPins", "C123").Return([]slack.Item{}, nil, nil)
+
+	f.waits = nil
+	client := NewRateLimitedClient(loggerMock, f.mock, f.maxRetries).(*rateLimitedClient)
+	client.sleep = func(ctx context.Context, d time.Duration) error {
+		f.waits = append(f.waits, d)
+		return nil
+	}
+	f.client = client
+}
+
+func TestRateLimitedClientRetries(t *testing.T) {
+	table := []rateLimitFixture{
+		{
+			testName:      "Success without retry",
+			maxRetries:    3,
+			expectedCalls: 1,
+		},
+		{
+			testName:      "Retry after the Retry-After header",
+			errs:          []error{&slack.RateLimitedError{RetryAfter: 7 * time.Second}},
+			maxRetries:    3,
+			expectedCalls: 2,
+			expectedWaits: []time.Duration{7 * time.Second},
+		},
+		{
+			testName:      "Retry a ratelimited response with backoff",
+			errs:          []error{errors.New("ratelimited"), errors.New("ratelimited")},
+			maxRetries:    3,
+			expectedCalls: 3,
+			expectedWaits: []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			testName:      "Retry a server error",
+			errs:          []error{httpStatusError(503)},
+			maxRetries:    3,
+			expectedCalls: 2,
+			expectedWaits: []time.Duration{time.Second},
+		},
+		{
+			testName:      "Do not retry a client error",
+			errs:          []error{httpStatusError(400)},
+			maxRetries:    3,
+			expectedCalls: 1,
+			expectError:   true,
+		},
+		{
+			testName:      "Do not retry a slack error",
+			errs:          []error{errors.New("channel_not_found")},
+			maxRetries:    3,
+			expectedCalls: 1,
+			expectError:   true,
+		},
+		{
+			testName:      "Give up after the max retries",
+			errs:          []error{httpStatusError(500), httpStatusError(500), httpStatusError(500)},
+			maxRetries:    2,
+			expectedCalls: 3,
+			expectedWaits: []time.Duration{time.Second, 2 * time.Second},
+			expectError:   true,
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			f.setup(t)
+
+			_, _, err := f.client.ListPins("C123")
+
+			if f.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			f.mock.AssertNumberOfCalls(t, "ListPins", f.expectedCalls)
+			assert.Equal(t, f.expectedWaits, f.waits)
+		})
+	}
+}
+
+func TestLimiterReserve(t *testing.T) {
+	var (
+		now = time.Now()
+		l   = newLimiter(tierLimit{perMinute: 60, burst: 2})
+	)
+
+	assert.Equal(t, time.Duration(0), l.reserve(now))
+	assert.Equal(t, time.Duration(0), l.reserve(now))
+	assert.Equal(t, time.Second, l.reserve(now))
+	assert.Equal(t, time.Duration(0), l.reserve(now.Add(3*time.Second)))
+}
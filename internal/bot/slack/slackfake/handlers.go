@@ -0,0 +1,405 @@
+package slackfake
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+type response map[string]interface{}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		method = path.Base(r.URL.Path)
+		values url.Values
+		body   []byte
+	)
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		body, _ = ioutil.ReadAll(r.Body)
+		values = url.Values{}
+	} else {
+		r.ParseForm()
+		values = r.Form
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls = append(s.calls, Call{Method: method, Values: values})
+
+	var resp response
+	switch method {
+	case "auth.test":
+		resp = response{"user_id": s.BotUserID, "user": "hellper"}
+	case "chat.postMessage":
+		resp = s.postMessage(values, false)
+	case "chat.postEphemeral":
+		resp = s.postMessage(values, true)
+	case "conversations.create":
+		resp = s.createConversation(values)
+	case "conversations.invite":
+		resp = s.inviteToConversation(values)
+	case "conversations.join":
+		resp = s.joinConversation(values)
+	case "conversations.setTopic":
+		resp = s.setTopic(values)
+	case "conversations.archive":
+		resp = s.archiveConversation(values)
+	case "conversations.info":
+		resp = s.conversationInfo(values)
+	case "conversations.members":
+		resp = s.conversationMembers(values)
+	case "conversations.history":
+		resp = s.conversationHistory(values)
+	case "conversations.replies":
+		resp = s.conversationReplies(values)
+	case "pins.add":
+		resp = s.addPin(values)
+	case "pins.list":
+		resp = s.listPins(values)
+	case "users.info":
+		resp = s.userInfo(values)
+	case "usergroups.users.list":
+		resp = s.userGroupMembers(values)
+	case "dialog.open":
+		resp = s.openDialog(body)
+	case "views.open", "views.publish", "views.update", "views.push":
+		resp = s.storeView(method, body)
+	default:
+		resp = errorResponse("unknown_method")
+	}
+
+	if _, failed := resp["error"]; !failed {
+		resp["ok"] = true
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func errorResponse(reason string) response {
+	return response{"ok": false, "error": reason}
+}
+
+func (s *Server) slackChannel(channel *Channel) slack.Channel {
+	c := slack.Channel{}
+	c.ID = channel.ID
+	c.Name = channel.Name
+	c.NameNormalized = channel.Name
+	c.IsPrivate = channel.Private
+	c.IsArchived = channel.Archived
+	c.IsChannel = true
+	c.IsMember = true
+	c.Members = channel.Members
+	c.NumMembers = len(channel.Members)
+	c.Topic = slack.Topic{Value: channel.Topic}
+	return c
+}
+
+func slackMessage(msg Message) slack.Message {
+	m := slack.Message{}
+	m.Type = "message"
+	m.Channel = msg.Channel
+	m.User = msg.User
+	m.Text = msg.Text
+	m.Timestamp = msg.Timestamp
+	m.ThreadTimestamp = msg.ThreadTimestamp
+	m.Attachments = msg.Attachments
+	return m
+}
+
+func (s *Server) postMessage(values url.Values, ephemeral bool) response {
+	target := values.Get("channel")
+	channel := s.findChannel(target)
+
+	if channel == nil {
+		// Messages sent to a user ID are delivered on a direct message conversation
+		if _, isUser := s.users[target]; !isUser {
+			return errorResponse("channel_not_found")
+		}
+		channel = s.directMessage(target)
+	}
+	if channel.Archived {
+		return errorResponse("is_archived")
+	}
+
+	msg := Message{
+		Channel:         channel.ID,
+		User:            s.BotUserID,
+		Text:            values.Get("text"),
+		Timestamp:       s.nextTimestamp(),
+		ThreadTimestamp: values.Get("thread_ts"),
+		Ephemeral:       ephemeral,
+	}
+	if attachments := values.Get("attachments"); attachments != "" {
+		json.Unmarshal([]byte(attachments), &msg.Attachments)
+	}
+	if blocks := values.Get("blocks"); blocks != "" {
+		msg.Blocks = json.RawMessage(blocks)
+	}
+	channel.Messages = append(channel.Messages, msg)
+
+	if ephemeral {
+		return response{"message_ts": msg.Timestamp}
+	}
+	return response{"channel": channel.ID, "ts": msg.Timestamp, "message": slackMessage(msg)}
+}
+
+func (s *Server) directMessage(userID string) *Channel {
+	for _, channel := range s.channels {
+		if channel.Name == userID && channel.Private {
+			return channel
+		}
+	}
+
+	channel := s.createChannel("D", userID, true)
+	channel.Members = append(channel.Members, userID)
+	return channel
+}
+
+func (s *Server) createConversation(values url.Values) response {
+	name := values.Get("name")
+	if name == "" {
+		return errorResponse("invalid_name_required")
+	}
+	if s.findChannel(name) != nil {
+		return errorResponse("name_taken")
+	}
+
+	private, _ := strconv.ParseBool(values.Get("is_private"))
+	channel := s.createChannel("C", name, private)
+	return response{"channel": s.slackChannel(channel)}
+}
+
+func (s *Server) inviteToConversation(values url.Values) response {
+	channel := s.findChannel(values.Get("channel"))
+	if channel == nil {
+		return errorResponse("channel_not_found")
+	}
+
+	for _, user := range strings.Split(values.Get("users"), ",") {
+		if _, ok := s.users[user]; !ok {
+			return errorResponse("user_not_found")
+		}
+		if !contains(channel.Members, user) {
+			channel.Members = append(channel.Members, user)
+		}
+	}
+	return response{"channel": s.slackChannel(channel)}
+}
+
+func (s *Server) joinConversation(values url.Values) response {
+	channel := s.findChannel(values.Get("channel"))
+	if channel == nil {
+		return errorResponse("channel_not_found")
+	}
+	if !contains(channel.Members, s.BotUserID) {
+		channel.Members = append(channel.Members, s.BotUserID)
+	}
+	return response{"channel": s.slackChannel(channel)}
+}
+
+func (s *Server) setTopic(values url.Values) response {
+	channel := s.findChannel(values.Get("channel"))
+	if channel == nil {
+		return errorResponse("channel_not_found")
+	}
+	channel.Topic = values.Get("topic")
+	return response{"channel": s.slackChannel(channel)}
+}
+
+func (s *Server) archiveConversation(values url.Values) response {
+	channel := s.findChannel(values.Get("channel"))
+	if channel == nil {
+		return errorResponse("channel_not_found")
+	}
+	if channel.Archived {
+		return errorResponse("already_archived")
+	}
+	channel.Archived = true
+	return response{}
+}
+
+func (s *Server) conversationInfo(values url.Values) response {
+	channel := s.findChannel(values.Get("channel"))
+	if channel == nil {
+		return errorResponse("channel_not_found")
+	}
+	return response{"channel": s.slackChannel(channel)}
+}
+
+// conversationMembers pages the members two at a time, so callers have to follow the cursor
+func (s *Server) conversationMembers(values url.Values) response {
+	channel := s.findChannel(values.Get("channel"))
+	if channel == nil {
+		return errorResponse("channel_not_found")
+	}
+
+	start, _ := strconv.Atoi(values.Get("cursor"))
+	end := start + 2
+	nextCursor := strconv.Itoa(end)
+	if end >= len(channel.Members) {
+		end = len(channel.Members)
+		nextCursor = ""
+	}
+
+	return response{
+		"members":           channel.Members[start:end],
+		"response_metadata": response{"next_cursor": nextCursor},
+	}
+}
+
+func (s *Server) conversationHistory(values url.Values) response {
+	channel := s.findChannel(values.Get("channel"))
+	if channel == nil {
+		return errorResponse("channel_not_found")
+	}
+
+	var messages []slack.Message
+	for _, msg := range channel.Messages {
+		if msg.Ephemeral || (msg.ThreadTimestamp != "" && msg.ThreadTimestamp != msg.Timestamp) {
+			continue
+		}
+		m := slackMessage(msg)
+		m.ReplyCount = s.replyCount(channel, msg.Timestamp)
+		messages = append(messages, m)
+	}
+	// Slack returns the newest messages first
+	sort.Slice(messages, func(i, j int) bool { return messages[i].Timestamp > messages[j].Timestamp })
+
+	return response{"messages": messages, "has_more": false}
+}
+
+func (s *Server) replyCount(channel *Channel, threadTimestamp string) int {
+	count := 0
+	for _, msg := range channel.Messages {
+		if msg.ThreadTimestamp == threadTimestamp && msg.Timestamp != threadTimestamp {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *Server) conversationReplies(values url.Values) response {
+	channel := s.findChannel(values.Get("channel"))
+	if channel == nil {
+		return errorResponse("channel_not_found")
+	}
+
+	var (
+		threadTimestamp = values.Get("ts")
+		messages        []slack.Message
+	)
+	for _, msg := range channel.Messages {
+		if msg.Timestamp == threadTimestamp || msg.ThreadTimestamp == threadTimestamp {
+			messages = append(messages, slackMessage(msg))
+		}
+	}
+	if len(messages) == 0 {
+		return errorResponse("thread_not_found")
+	}
+
+	return response{"messages": messages, "has_more": false}
+}
+
+func (s *Server) addPin(values url.Values) response {
+	channel := s.findChannel(values.Get("channel"))
+	if channel == nil {
+		return errorResponse("channel_not_found")
+	}
+
+	timestamp := values.Get("timestamp")
+	found := false
+	for _, msg := range channel.Messages {
+		if msg.Timestamp == timestamp {
+			found = true
+		}
+	}
+	if !found {
+		return errorResponse("message_not_found")
+	}
+	if contains(channel.Pins, timestamp) {
+		return errorResponse("already_pinned")
+	}
+
+	channel.Pins = append(channel.Pins, timestamp)
+	return response{}
+}
+
+func (s *Server) listPins(values url.Values) response {
+	channel := s.findChannel(values.Get("channel"))
+	if channel == nil {
+		return errorResponse("channel_not_found")
+	}
+
+	items := []slack.Item{}
+	for _, timestamp := range channel.Pins {
+		for _, msg := range channel.Messages {
+			if msg.Timestamp == timestamp {
+				m := slackMessage(msg)
+				items = append(items, slack.Item{Type: "message", Channel: channel.ID, Message: &m})
+			}
+		}
+	}
+
+	return response{"items": items, "paging": slack.Paging{Count: len(items), Total: len(items), Page: 1, Pages: 1}}
+}
+
+func (s *Server) userInfo(values url.Values) response {
+	user, ok := s.users[values.Get("user")]
+	if !ok {
+		return errorResponse("user_not_found")
+	}
+	return response{"user": user}
+}
+
+func (s *Server) userGroupMembers(values url.Values) response {
+	members, ok := s.userGroups[values.Get("usergroup")]
+	if !ok {
+		return errorResponse("no_such_subteam")
+	}
+	return response{"users": members}
+}
+
+func (s *Server) openDialog(body []byte) response {
+	var trigger slack.DialogTrigger
+	err := json.Unmarshal(body, &trigger)
+	if err != nil || trigger.TriggerID == "" {
+		return errorResponse("invalid_trigger")
+	}
+
+	s.dialogs = append(s.dialogs, Dialog{TriggerID: trigger.TriggerID, Dialog: trigger.Dialog})
+	return response{}
+}
+
+func (s *Server) storeView(method string, body []byte) response {
+	var request struct {
+		TriggerID string          `json:"trigger_id"`
+		UserID    string          `json:"user_id"`
+		View      json.RawMessage `json:"view"`
+	}
+	err := json.Unmarshal(body, &request)
+	if err != nil {
+		return errorResponse("invalid_arguments")
+	}
+
+	s.views = append(s.views, View{Method: method, TriggerID: request.TriggerID, UserID: request.UserID, View: request.View})
+	return response{"view": request.View}
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
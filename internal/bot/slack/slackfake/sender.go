@@ -0,0 +1,100 @@
+package slackfake
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Sender delivers signed Slack requests (slash commands, interactions and events) to hellper
+type Sender struct {
+	BaseURL       string
+	SigningSecret string
+	HTTPClient    *http.Client
+}
+
+// NewSender creates a sender for the hellper server listening on baseURL
+func NewSender(baseURL, signingSecret string) *Sender {
+	return &Sender{
+		BaseURL:       baseURL,
+		SigningSecret: signingSecret,
+		HTTPClient:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Sign returns the X-Slack-Signature of a body, as described on
+// https://api.slack.com/authentication/verifying-requests-from-slack
+func Sign(signingSecret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send posts a signed body to the given path
+func (s *Sender) Send(path, contentType string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodPost, s.BaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", Sign(s.SigningSecret, timestamp, body))
+
+	return s.HTTPClient.Do(req)
+}
+
+// SlashCommand sends a slash command, as Slack does when a user types it on a channel
+func (s *Sender) SlashCommand(path, command, channelID, userID, text string) (*http.Response, error) {
+	form := url.Values{
+		"command":      {command},
+		"channel_id":   {channelID},
+		"user_id":      {userID},
+		"text":         {text},
+		"trigger_id":   {fmt.Sprintf("trigger-%d", time.Now().UnixNano())},
+		"response_url": {s.BaseURL + "/response"},
+	}
+	return s.Send(path, "application/x-www-form-urlencoded", []byte(form.Encode()))
+}
+
+// Interaction sends an interactive payload (dialog submission, block action, view submission)
+func (s *Sender) Interaction(path string, payload interface{}) (*http.Response, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{"payload": {string(encoded)}}
+	return s.Send(path, "application/x-www-form-urlencoded", []byte(form.Encode()))
+}
+
+// Event sends an Events API callback wrapping the given inner event
+func (s *Sender) Event(path string, innerEvent interface{}) (*http.Response, error) {
+	inner, err := json.Marshal(innerEvent)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"token":      "slackfake",
+		"team_id":    "TFAKE",
+		"api_app_id": "AFAKE",
+		"type":       "event_callback",
+		"event_id":   fmt.Sprintf("Ev%d", time.Now().UnixNano()),
+		"event_time": time.Now().Unix(),
+		"event":      json.RawMessage(inner),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.Send(path, "application/json", body)
+}
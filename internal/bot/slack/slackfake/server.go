@@ -0,0 +1,282 @@
+// Package slackfake is an in-memory stand-in for the Slack Web API, used by end-to-end tests.
+//
+// It keeps the channels, messages, pins, users, dialogs and views created through it,
+// so tests can inspect what hellper did on Slack.
+package slackfake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"hellper/internal/bot"
+
+	"github.com/slack-go/slack"
+)
+
+// Token is the bot token accepted by the fake server
+const Token = "xoxb-slackfake"
+
+// Message is a message posted on a fake channel
+type Message struct {
+	Channel         string
+	User            string
+	Text            string
+	Timestamp       string
+	ThreadTimestamp string
+	Ephemeral       bool
+	Attachments     []slack.Attachment
+	Blocks          json.RawMessage
+}
+
+// Channel is the state of a fake conversation
+type Channel struct {
+	ID       string
+	Name     string
+	Topic    string
+	Private  bool
+	Archived bool
+	Members  []string
+	Messages []Message
+	Pins     []string
+}
+
+// Dialog is a dialog opened with dialog.open
+type Dialog struct {
+	TriggerID string
+	Dialog    slack.Dialog
+}
+
+// View is a view opened or published with the views.* methods
+type View struct {
+	Method    string
+	TriggerID string
+	UserID    string
+	View      json.RawMessage
+}
+
+// Call is a request received by the fake server
+type Call struct {
+	Method string
+	Values map[string][]string
+}
+
+// Server is a fake Slack Web API server
+type Server struct {
+	mu         sync.Mutex
+	httpServer *httptest.Server
+	sequence   int64
+
+	BotUserID  string
+	channels   map[string]*Channel
+	users      map[string]slack.User
+	userGroups map[string][]string
+	dialogs    []Dialog
+	views      []View
+	calls      []Call
+}
+
+// NewServer starts a fake Slack Web API server, it must be closed by the caller
+func NewServer() *Server {
+	s := &Server{
+		BotUserID:  "UBOT",
+		channels:   map[string]*Channel{},
+		users:      map[string]slack.User{},
+		userGroups: map[string][]string{},
+	}
+	s.AddUser(slack.User{ID: s.BotUserID, Name: "hellper", IsBot: true})
+	s.httpServer = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// URL returns the base URL of the fake server
+func (s *Server) URL() string {
+	return s.httpServer.URL
+}
+
+// APIURL returns the URL to be used with slack.OptionAPIURL
+func (s *Server) APIURL() string {
+	return s.httpServer.URL + "/api/"
+}
+
+// Client returns a bot client connected to the fake server
+func (s *Server) Client() bot.Client {
+	return slack.New(Token, slack.OptionAPIURL(s.APIURL()))
+}
+
+// Close shuts down the fake server
+func (s *Server) Close() {
+	s.httpServer.Close()
+}
+
+func (s *Server) nextID(prefix string) string {
+	s.sequence++
+	return fmt.Sprintf("%s%07d", prefix, s.sequence)
+}
+
+func (s *Server) nextTimestamp() string {
+	s.sequence++
+	return fmt.Sprintf("%d.%06d", time.Now().Unix(), s.sequence%1000000)
+}
+
+// AddUser registers a workspace member
+func (s *Server) AddUser(user slack.User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if user.Profile.RealName == "" {
+		user.Profile.RealName = user.Name
+	}
+	if user.RealName == "" {
+		user.RealName = user.Profile.RealName
+	}
+	s.users[user.ID] = user
+}
+
+// AddUserGroup registers a user group (subteam) with the given members
+func (s *Server) AddUserGroup(id string, members ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.userGroups[id] = members
+}
+
+// AddChannel creates a public channel with the given members and returns its ID
+func (s *Server) AddChannel(name string, members ...string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	channel := s.createChannel("C", name, false)
+	channel.Members = append(channel.Members, members...)
+	return channel.ID
+}
+
+func (s *Server) createChannel(prefix, name string, private bool) *Channel {
+	channel := &Channel{
+		ID:      s.nextID(prefix),
+		Name:    name,
+		Private: private,
+		Members: []string{s.BotUserID},
+	}
+	s.channels[channel.ID] = channel
+	return channel
+}
+
+// findChannel resolves a channel by its ID or by its name, as the Slack API does
+func (s *Server) findChannel(idOrName string) *Channel {
+	if channel, ok := s.channels[idOrName]; ok {
+		return channel
+	}
+
+	name := strings.TrimPrefix(idOrName, "#")
+	for _, channel := range s.channels {
+		if channel.Name == name {
+			return channel
+		}
+	}
+	return nil
+}
+
+// Channel returns a copy of a channel, found by ID or name
+func (s *Server) Channel(idOrName string) (Channel, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	channel := s.findChannel(idOrName)
+	if channel == nil {
+		return Channel{}, false
+	}
+	return copyChannel(channel), true
+}
+
+// Channels returns a copy of every channel, sorted by ID
+func (s *Server) Channels() []Channel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var channels []Channel
+	for _, channel := range s.channels {
+		channels = append(channels, copyChannel(channel))
+	}
+	sort.Slice(channels, func(i, j int) bool { return channels[i].ID < channels[j].ID })
+	return channels
+}
+
+// Messages returns the messages posted on a channel, found by ID or name
+func (s *Server) Messages(idOrName string) []Message {
+	channel, _ := s.Channel(idOrName)
+	return channel.Messages
+}
+
+// PinnedMessages returns the messages pinned on a channel, found by ID or name
+func (s *Server) PinnedMessages(idOrName string) []Message {
+	channel, _ := s.Channel(idOrName)
+
+	var pinned []Message
+	for _, ts := range channel.Pins {
+		for _, msg := range channel.Messages {
+			if msg.Timestamp == ts {
+				pinned = append(pinned, msg)
+			}
+		}
+	}
+	return pinned
+}
+
+// Dialogs returns the dialogs opened so far
+func (s *Server) Dialogs() []Dialog {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Dialog(nil), s.dialogs...)
+}
+
+// Views returns the views opened or published so far
+func (s *Server) Views() []View {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]View(nil), s.views...)
+}
+
+// Calls returns every request received by the fake server
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Call(nil), s.calls...)
+}
+
+// PostUserMessage posts a message on a channel as if a user had written it, and returns its timestamp
+func (s *Server) PostUserMessage(channelID, userID, text, threadTimestamp string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	channel := s.findChannel(channelID)
+	if channel == nil {
+		return ""
+	}
+
+	msg := Message{
+		Channel:         channel.ID,
+		User:            userID,
+		Text:            text,
+		Timestamp:       s.nextTimestamp(),
+		ThreadTimestamp: threadTimestamp,
+	}
+	channel.Messages = append(channel.Messages, msg)
+	return msg.Timestamp
+}
+
+func copyChannel(channel *Channel) Channel {
+	c := *channel
+	c.Members = append([]string(nil), channel.Members...)
+	c.Messages = append([]Message(nil), channel.Messages...)
+	c.Pins = append([]string(nil), channel.Pins...)
+	return c
+}
@@ -0,0 +1,105 @@
+package slackfake_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hellper/internal/bot"
+	"hellper/internal/bot/slack/slackfake"
+	"hellper/internal/config"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerConversationLifecycle(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		server = slackfake.NewServer()
+		client = server.Client()
+	)
+	defer server.Close()
+
+	server.AddUser(slack.User{ID: "U1", Name: "commander"})
+	server.AddUser(slack.User{ID: "U2", Name: "responder"})
+
+	channel, err := client.CreateConversationContext(ctx, "inc-fake", false)
+	require.NoError(t, err)
+	assert.Equal(t, "inc-fake", channel.Name)
+
+	_, err = client.CreateConversationContext(ctx, "inc-fake", false)
+	assert.EqualError(t, err, "name_taken")
+
+	_, err = client.InviteUsersToConversationContext(ctx, channel.ID, "U1", "U2")
+	require.NoError(t, err)
+
+	_, err = client.SetTopicOfConversation(channel.ID, "*Commander:* <@U1>")
+	require.NoError(t, err)
+
+	channelID, timestamp, err := client.PostMessage(
+		"inc-fake",
+		slack.MsgOptionText("status", false),
+		slack.MsgOptionAttachments(slack.Attachment{Pretext: "pretext"}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, channel.ID, channelID)
+	require.NoError(t, client.AddPin(channelID, slack.NewRefToMessage(channelID, timestamp)))
+
+	lastPin, err := bot.LastPin(client, channel.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "status", lastPin.Message.Text)
+	assert.Equal(t, "pretext", lastPin.Message.Attachments[0].Pretext)
+
+	var members []string
+	params := slack.GetUsersInConversationParameters{ChannelID: channel.ID}
+	for {
+		list, cursor, err := client.GetUsersInConversationContext(ctx, &params)
+		require.NoError(t, err)
+		members = append(members, list...)
+		if cursor == "" {
+			break
+		}
+		params.Cursor = cursor
+	}
+	assert.Equal(t, []string{server.BotUserID, "U1", "U2"}, members)
+
+	user, err := client.GetUserInfoContext(ctx, "U1")
+	require.NoError(t, err)
+	assert.Equal(t, "commander", user.Profile.RealName)
+
+	require.NoError(t, client.OpenDialog("trigger", slack.Dialog{CallbackID: "inc-open"}))
+	require.NoError(t, client.ArchiveConversationContext(ctx, channel.ID))
+
+	state, ok := server.Channel(channel.ID)
+	require.True(t, ok)
+	assert.True(t, state.Archived)
+	assert.Equal(t, "*Commander:* <@U1>", state.Topic)
+	assert.Len(t, server.PinnedMessages(channel.ID), 1)
+	assert.Equal(t, "inc-open", server.Dialogs()[0].Dialog.CallbackID)
+}
+
+func TestSenderSignsRequests(t *testing.T) {
+	config.Env.SlackSigningSecret = "slackfake-secret"
+
+	var verified bool
+	hellper := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bot.VerifyRequests(r, w, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			verified = r.FormValue("command") == "/hellper_status"
+		}))
+	}))
+	defer hellper.Close()
+
+	resp, err := slackfake.NewSender(hellper.URL, "slackfake-secret").SlashCommand("/status", "/hellper_status", "C1", "U1", "")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.True(t, verified)
+
+	verified = false
+	resp, err = slackfake.NewSender(hellper.URL, "wrong-secret").SlashCommand("/status", "/hellper_status", "C1", "U1", "")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.False(t, verified)
+}
@@ -162,10 +162,11 @@ func (gc *googleCalendar) CreateCalendarEvent(ctx context.Context, start, end, s
 	}
 
 	modelEvent := &model.Event{
-		EventURL: googleEvent.HtmlLink,
-		Start:    &eventStart,
-		End:      &eventEnd,
-		Summary:  googleEvent.Summary,
+		EventURL:      googleEvent.HtmlLink,
+		ConferenceURL: googleEvent.HangoutLink,
+		Start:         &eventStart,
+		End:           &eventEnd,
+		Summary:       googleEvent.Summary,
 	}
 
 	return modelEvent, nil
@@ -0,0 +1,83 @@
+package icscalendar
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"hellper/internal/calendar"
+	"hellper/internal/log"
+	"hellper/internal/model"
+
+	"github.com/google/uuid"
+)
+
+type icsCalendar struct {
+	logger   log.Logger
+	delivery Delivery
+}
+
+// NewCalendar creates the calendar sending RFC 5545 invites through the delivery
+func NewCalendar(logger log.Logger, delivery Delivery) calendar.Calendar {
+	return &icsCalendar{
+		logger:   logger,
+		delivery: delivery,
+	}
+}
+
+// CreateCalendarEvent sends the invite of the event to the commander and attendees
+func (ic *icsCalendar) CreateCalendarEvent(ctx context.Context, start, end, summary, commander string, emails []string) (*model.Event, error) {
+	eventStart, err := time.Parse(time.RFC3339, start)
+	if err != nil {
+		ic.logger.Error(ctx, log.Trace(), log.Action("time.Parse Start"), log.Reason(err.Error()))
+		return nil, err
+	}
+
+	eventEnd, err := time.Parse(time.RFC3339, end)
+	if err != nil {
+		ic.logger.Error(ctx, log.Trace(), log.Action("time.Parse End"), log.Reason(err.Error()))
+		return nil, err
+	}
+
+	invite := Invite{
+		UID:       uuid.New().String() + "@hellper",
+		Start:     eventStart,
+		End:       eventEnd,
+		Summary:   summary,
+		Organizer: commander,
+		Attendees: attendees(commander, emails),
+		Stamp:     time.Now().UTC(),
+	}
+
+	eventURL, err := ic.delivery.Deliver(ctx, invite)
+	if err != nil {
+		ic.logger.Error(ctx, log.Trace(), log.Action("delivery.Deliver"), log.Reason(err.Error()))
+		return nil, err
+	}
+
+	modelEvent := &model.Event{
+		EventURL: eventURL,
+		Start:    &eventStart,
+		End:      &eventEnd,
+		Summary:  summary,
+	}
+
+	return modelEvent, nil
+}
+
+// attendees skips the empty and duplicated emails, and the commander who organizes the meeting
+func attendees(commander string, emails []string) []string {
+	var (
+		result []string
+		seen   = map[string]bool{strings.ToLower(commander): true}
+	)
+	for _, email := range emails {
+		key := strings.ToLower(email)
+		if email == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, email)
+	}
+	return result
+}
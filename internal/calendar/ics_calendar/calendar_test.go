@@ -0,0 +1,247 @@
+package icscalendar_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"hellper/internal/bot"
+	icscalendar "hellper/internal/calendar/ics_calendar"
+	"hellper/internal/log"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var invite = icscalendar.Invite{
+	UID:       "42@hellper",
+	Start:     time.Date(2020, 10, 21, 14, 0, 0, 0, time.UTC),
+	End:       time.Date(2020, 10, 21, 15, 0, 0, 0, time.UTC),
+	Summary:   "[Post Mortem] inc-checkout",
+	Organizer: "commander@example.com",
+	Attendees: []string{"dev@example.com"},
+	Stamp:     time.Date(2020, 10, 19, 12, 0, 0, 0, time.UTC),
+}
+
+func TestEncode(t *testing.T) {
+	table := []struct {
+		testName        string
+		summary         string
+		expectedSummary string
+	}{
+		{
+			testName:        "Plain summary",
+			summary:         "[Post Mortem] inc-checkout",
+			expectedSummary: "SUMMARY:[Post Mortem] inc-checkout",
+		},
+		{
+			testName:        "Escaped summary",
+			summary:         "Checkout, payments; refunds\\n",
+			expectedSummary: `SUMMARY:Checkout\, payments\; refunds\\n`,
+		},
+		{
+			testName:        "Folded summary",
+			summary:         strings.Repeat("ação ", 20),
+			expectedSummary: "SUMMARY:" + strings.Repeat("ação ", 20),
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			i := invite
+			i.Summary = f.summary
+			content := string(i.Encode())
+
+			assert.True(t, strings.HasPrefix(content, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+			assert.True(t, strings.HasSuffix(content, "END:VEVENT\r\nEND:VCALENDAR\r\n"))
+			for _, line := range strings.Split(strings.TrimSuffix(content, "\r\n"), "\r\n") {
+				assert.LessOrEqual(t, len(line), 75)
+			}
+
+			unfolded := strings.ReplaceAll(content, "\r\n ", "")
+			assert.Contains(t, unfolded, "\r\nMETHOD:REQUEST\r\n")
+			assert.Contains(t, unfolded, "\r\nUID:42@hellper\r\n")
+			assert.Contains(t, unfolded, "\r\nDTSTART:20201021T140000Z\r\nDTEND:20201021T150000Z\r\n")
+			assert.Contains(t, unfolded, "\r\nORGANIZER:mailto:commander@example.com\r\n")
+			assert.Contains(t, unfolded, "RSVP=TRUE:mailto:dev@example.com\r\n")
+			assert.Contains(t, unfolded, "\r\n"+f.expectedSummary+"\r\n")
+
+			resource := string(i.EncodeResource())
+			assert.Equal(t, strings.Replace(content, "METHOD:REQUEST\r\n", "", 1), resource)
+		})
+	}
+}
+
+type deliveryStub struct {
+	invites []icscalendar.Invite
+	url     string
+	err     error
+}
+
+func (d *deliveryStub) Deliver(ctx context.Context, invite icscalendar.Invite) (string, error) {
+	d.invites = append(d.invites, invite)
+	return d.url, d.err
+}
+
+func TestCreateCalendarEvent(t *testing.T) {
+	table := []struct {
+		testName          string
+		start             string
+		deliveryError     error
+		expectedAttendees []string
+		expectedError     string
+	}{
+		{
+			testName:          "Invite sent",
+			start:             "2020-10-21T11:00:00-03:00",
+			expectedAttendees: []string{"dev@example.com", "ops@example.com"},
+		},
+		{
+			testName:      "Invalid start",
+			start:         "2020-10-21 11:00",
+			expectedError: `parsing time "2020-10-21 11:00" as "2006-01-02T15:04:05Z07:00": cannot parse " 11:00" as "T"`,
+		},
+		{
+			testName:          "Delivery failure",
+			start:             "2020-10-21T11:00:00-03:00",
+			deliveryError:     errors.New("connection refused"),
+			expectedAttendees: []string{"dev@example.com", "ops@example.com"},
+			expectedError:     "connection refused",
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			var (
+				loggerMock = log.NewLoggerMock()
+				delivery   = &deliveryStub{url: "https://calendar.example.com/42.ics", err: f.deliveryError}
+				emails     = []string{"Commander@example.com", "dev@example.com", "", "ops@example.com", "dev@example.com"}
+			)
+			loggerMock.On("Error", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+
+			cal := icscalendar.NewCalendar(loggerMock, delivery)
+			event, err := cal.CreateCalendarEvent(context.Background(), f.start, "2020-10-21T12:00:00-03:00", "[Post Mortem] inc-checkout", "commander@example.com", emails)
+			if f.expectedAttendees != nil {
+				assert.Len(t, delivery.invites, 1)
+				assert.Equal(t, f.expectedAttendees, delivery.invites[0].Attendees)
+				assert.Equal(t, "commander@example.com", delivery.invites[0].Organizer)
+				assert.True(t, strings.HasSuffix(delivery.invites[0].UID, "@hellper"))
+			}
+			if f.expectedError != "" {
+				assert.EqualError(t, err, f.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "https://calendar.example.com/42.ics", event.EventURL)
+			assert.Equal(t, time.Date(2020, 10, 21, 14, 0, 0, 0, time.UTC), event.Start.UTC())
+			assert.Equal(t, time.Hour, event.End.Sub(*event.Start))
+		})
+	}
+}
+
+type senderStub struct {
+	from string
+	to   []string
+	msg  []byte
+}
+
+func (s *senderStub) Send(from string, to []string, msg []byte) error {
+	s.from, s.to, s.msg = from, to, msg
+	return nil
+}
+
+func TestEmailDelivery(t *testing.T) {
+	sender := &senderStub{}
+
+	url, err := icscalendar.NewEmailDelivery(sender, "hellper@example.com").Deliver(context.Background(), invite)
+	assert.NoError(t, err)
+	assert.Empty(t, url)
+
+	msg := string(sender.msg)
+	assert.Equal(t, "hellper@example.com", sender.from)
+	assert.Equal(t, []string{"commander@example.com", "dev@example.com"}, sender.to)
+	assert.Contains(t, msg, "To: commander@example.com, dev@example.com\r\n")
+	assert.Contains(t, msg, "Content-Type: multipart/alternative; boundary=")
+	assert.Contains(t, msg, `Content-Type: text/calendar; charset=UTF-8; method=REQUEST; name="invite.ics"`)
+	assert.Contains(t, msg, string(invite.Encode()))
+}
+
+func TestSlackDelivery(t *testing.T) {
+	clientMock := bot.NewClientMock()
+	clientMock.On("UploadFileContext", mock.Anything, mock.AnythingOfType("slack.FileUploadParameters")).Return(&slack.File{Permalink: "https://slack.example.com/files/invite.ics"}, nil)
+
+	url, err := icscalendar.NewSlackDelivery(clientMock, "C1").Deliver(context.Background(), invite)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://slack.example.com/files/invite.ics", url)
+
+	params := clientMock.Calls[0].Arguments.Get(1).(slack.FileUploadParameters)
+	assert.Equal(t, []string{"C1"}, params.Channels)
+	assert.Equal(t, "invite.ics", params.Filename)
+	assert.Equal(t, string(invite.Encode()), params.Content)
+}
+
+func TestCalDAVDelivery(t *testing.T) {
+	table := []struct {
+		testName      string
+		status        int
+		expectedError string
+	}{
+		{
+			testName: "Event created",
+			status:   http.StatusCreated,
+		},
+		{
+			testName:      "Event already on the calendar",
+			status:        http.StatusPreconditionFailed,
+			expectedError: "unexpected status 412: exists",
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			var request *http.Request
+			var body []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				request = r
+				body, _ = ioutil.ReadAll(r.Body)
+				// The calendar object resources must not have a METHOD property (RFC 4791 section 4.1)
+				if strings.Contains(string(body), "\r\nMETHOD:") {
+					w.WriteHeader(http.StatusForbidden)
+					fmt.Fprint(w, "valid-calendar-object-resource")
+					return
+				}
+				w.WriteHeader(f.status)
+				if f.status == http.StatusPreconditionFailed {
+					fmt.Fprint(w, "exists")
+				}
+			}))
+			defer server.Close()
+
+			delivery := icscalendar.NewCalDAVDelivery(server.URL+"/calendars/hellper/postmortems/", "hellper", "s3cr3t", time.Second)
+			url, err := delivery.Deliver(context.Background(), invite)
+
+			assert.Equal(t, http.MethodPut, request.Method)
+			assert.Equal(t, "/calendars/hellper/postmortems/42@hellper.ics", request.URL.Path)
+			assert.Equal(t, "*", request.Header.Get("If-None-Match"))
+			assert.Equal(t, "text/calendar; charset=utf-8", request.Header.Get("Content-Type"))
+			username, password, _ := request.BasicAuth()
+			assert.Equal(t, "hellper", username)
+			assert.Equal(t, "s3cr3t", password)
+			assert.Equal(t, invite.EncodeResource(), body)
+
+			if f.expectedError != "" {
+				assert.EqualError(t, err, f.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, server.URL+"/calendars/hellper/postmortems/42@hellper.ics", url)
+		})
+	}
+}
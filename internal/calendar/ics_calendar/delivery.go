@@ -0,0 +1,170 @@
+package icscalendar
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"time"
+
+	"hellper/internal/bot"
+	"hellper/internal/email"
+
+	"github.com/slack-go/slack"
+)
+
+// Delivery sends the invite to the attendees and returns the link to the event, empty when there is none
+type Delivery interface {
+	Deliver(ctx context.Context, invite Invite) (string, error)
+}
+
+type emailDelivery struct {
+	sender email.Sender
+	from   string
+}
+
+// NewEmailDelivery creates a Delivery emailing the invite to the organizer and attendees
+func NewEmailDelivery(sender email.Sender, from string) Delivery {
+	return &emailDelivery{
+		sender: sender,
+		from:   from,
+	}
+}
+
+func (d *emailDelivery) Deliver(ctx context.Context, invite Invite) (string, error) {
+	to := invite.Attendees
+	if invite.Organizer != "" {
+		to = append([]string{invite.Organizer}, to...)
+	}
+
+	msg, err := inviteMessage(invite, d.from, to)
+	if err != nil {
+		return "", err
+	}
+	return "", d.sender.Send(d.from, to, msg)
+}
+
+// inviteMessage builds the email of the invite, with a text part for the clients without calendar
+func inviteMessage(invite Invite, from string, to []string) ([]byte, error) {
+	var (
+		body   bytes.Buffer
+		writer = multipart.NewWriter(&body)
+	)
+
+	text, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=UTF-8"},
+		"Content-Transfer-Encoding": {"8bit"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(text, "%s\r\n\r\nStarts at %s and ends at %s.\r\n", invite.Summary, invite.Start.UTC().Format(time.RFC1123), invite.End.UTC().Format(time.RFC1123))
+
+	calendar, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {`text/calendar; charset=UTF-8; method=REQUEST; name="invite.ics"`},
+		"Content-Transfer-Encoding": {"8bit"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	calendar.Write(invite.Encode())
+
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	msg.WriteString("From: " + from + "\r\n")
+	msg.WriteString("To: " + strings.Join(to, ", ") + "\r\n")
+	msg.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", invite.Summary) + "\r\n")
+	msg.WriteString("Date: " + invite.Stamp.Format(time.RFC1123Z) + "\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: multipart/alternative; boundary=" + writer.Boundary() + "\r\n")
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+
+	return msg.Bytes(), nil
+}
+
+type slackDelivery struct {
+	client    bot.Client
+	channelID string
+}
+
+// NewSlackDelivery creates a Delivery uploading the invite file to the Slack channel
+func NewSlackDelivery(client bot.Client, channelID string) Delivery {
+	return &slackDelivery{
+		client:    client,
+		channelID: channelID,
+	}
+}
+
+func (d *slackDelivery) Deliver(ctx context.Context, invite Invite) (string, error) {
+	file, err := d.client.UploadFileContext(ctx, slack.FileUploadParameters{
+		Content:        string(invite.Encode()),
+		Filetype:       "text",
+		Filename:       "invite.ics",
+		Title:          invite.Summary,
+		InitialComment: "Add the meeting to your calendar: " + invite.Summary,
+		Channels:       []string{d.channelID},
+	})
+	if err != nil {
+		return "", err
+	}
+	return file.Permalink, nil
+}
+
+type calDAVDelivery struct {
+	collectionURL string
+	username      string
+	password      string
+	httpClient    *http.Client
+}
+
+// NewCalDAVDelivery creates a Delivery storing the invite as an event of the calendar collection of a
+// CalDAV server, which schedules it with the attendees. It authenticates only when the username is set
+func NewCalDAVDelivery(collectionURL string, username string, password string, timeout time.Duration) Delivery {
+	return &calDAVDelivery{
+		collectionURL: strings.TrimSuffix(collectionURL, "/"),
+		username:      username,
+		password:      password,
+		httpClient:    &http.Client{Timeout: timeout},
+	}
+}
+
+func (d *calDAVDelivery) Deliver(ctx context.Context, invite Invite) (string, error) {
+	resourceURL := d.collectionURL + "/" + url.PathEscape(invite.UID) + ".ics"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, resourceURL, bytes.NewReader(invite.EncodeResource()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "text/calendar; charset=utf-8")
+	req.Header.Set("If-None-Match", "*")
+	if d.username != "" {
+		req.SetBasicAuth(d.username, d.password)
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(content)))
+	}
+
+	return resourceURL, nil
+}
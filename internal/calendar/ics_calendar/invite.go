@@ -0,0 +1,94 @@
+package icscalendar
+
+import (
+	"bytes"
+	"strings"
+	"time"
+)
+
+// maxLineOctets is the longest content line allowed by RFC 5545 before it must be folded
+const maxLineOctets = 75
+
+const icsTimeLayout = "20060102T150405Z"
+
+// Invite is a meeting request of an iCalendar (RFC 5545) file
+type Invite struct {
+	UID       string
+	Start     time.Time
+	End       time.Time
+	Summary   string
+	Organizer string
+	Attendees []string
+	Stamp     time.Time
+}
+
+// Encode writes the invite as a calendar object with the REQUEST method, understood as an
+// invitation by the calendar clients
+func (i Invite) Encode() []byte {
+	return i.encode("REQUEST")
+}
+
+// EncodeResource writes the invite as a calendar object resource of a CalDAV collection, which
+// must not have a METHOD property (RFC 4791 section 4.1)
+func (i Invite) EncodeResource() []byte {
+	return i.encode("")
+}
+
+func (i Invite) encode(method string) []byte {
+	var buf bytes.Buffer
+	writeLine(&buf, "BEGIN:VCALENDAR")
+	writeLine(&buf, "VERSION:2.0")
+	writeLine(&buf, "PRODID:-//hellper//Post Mortem//EN")
+	writeLine(&buf, "CALSCALE:GREGORIAN")
+	if method != "" {
+		writeLine(&buf, "METHOD:"+method)
+	}
+	writeLine(&buf, "BEGIN:VEVENT")
+	writeLine(&buf, "UID:"+i.UID)
+	writeLine(&buf, "DTSTAMP:"+i.Stamp.UTC().Format(icsTimeLayout))
+	writeLine(&buf, "DTSTART:"+i.Start.UTC().Format(icsTimeLayout))
+	writeLine(&buf, "DTEND:"+i.End.UTC().Format(icsTimeLayout))
+	writeLine(&buf, "SUMMARY:"+escapeText(i.Summary))
+	if i.Organizer != "" {
+		writeLine(&buf, "ORGANIZER:mailto:"+i.Organizer)
+	}
+	for _, attendee := range i.Attendees {
+		writeLine(&buf, "ATTENDEE;ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:"+attendee)
+	}
+	writeLine(&buf, "SEQUENCE:0")
+	writeLine(&buf, "STATUS:CONFIRMED")
+	writeLine(&buf, "END:VEVENT")
+	writeLine(&buf, "END:VCALENDAR")
+	return buf.Bytes()
+}
+
+// escapeText escapes the characters with a meaning on the TEXT values
+func escapeText(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(text)
+}
+
+// writeLine folds the content line in lines of up to 75 octets, the continuation lines starting
+// with a space, without breaking the UTF-8 characters
+func writeLine(buf *bytes.Buffer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		limit = maxLineOctets - 1
+	}
+	buf.WriteString(line + "\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
@@ -0,0 +1,18 @@
+package calendar
+
+import (
+	"context"
+	"hellper/internal/model"
+)
+
+type noopCalendar struct{}
+
+// NewNoop creates a Calendar scheduling nothing, the post mortem meetings are then left to the commander
+func NewNoop() Calendar {
+	return noopCalendar{}
+}
+
+// CreateCalendarEvent returns no event and no error
+func (noopCalendar) CreateCalendarEvent(ctx context.Context, start, end, summary, commander string, emails []string) (*model.Event, error) {
+	return nil, nil
+}
@@ -0,0 +1,288 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"hellper/internal/bot"
+	"hellper/internal/concurrence"
+	"hellper/internal/config"
+	"hellper/internal/issuetracker"
+	"hellper/internal/log"
+	"hellper/internal/model"
+)
+
+var (
+	errMissingActionItemTitle    = errors.New("missing the title of the action item")
+	errInvalidActionItemDue      = errors.New("invalid due date, use due:YYYY-MM-DD")
+	errInvalidActionItemPriority = errors.New("invalid priority")
+	errInvalidActionItemID       = errors.New("invalid action item id")
+	actionItemCommandUsage       = "Usage: `/hellper_action add [@owner] [due:YYYY-MM-DD] [priority:<priority>] <title>`, `/hellper_action done <id>` or `/hellper_action list`.\nPriorities: `" + strings.Join(model.ActionItemPriorities, "`, `") + "`"
+)
+
+// ActionItemCommand adds, completes or lists the action items of the incident of the channel, from the /hellper_action text.
+// The new action items get a ticket on the issue tracker in the background, when there is one
+func ActionItemCommand(
+	ctx context.Context,
+	client bot.Client,
+	logger log.Logger,
+	repository model.Repository,
+	background *concurrence.Background,
+	tracker issuetracker.Provider,
+	channelID string,
+	userID string,
+	text string,
+) error {
+	logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("channelID", channelID),
+		log.NewValue("userID", userID),
+		log.NewValue("text", text),
+	)
+
+	inc, err := repository.GetIncident(ctx, channelID)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("GetIncident"),
+			log.NewValue("channelID", channelID),
+			log.NewValue("error", err),
+		)
+
+		PostErrorAttachment(ctx, client, logger, channelID, userID, err.Error())
+		return err
+	}
+
+	args := strings.Fields(text)
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	switch {
+	case args[0] == "list" && len(args) == 1:
+		return listActionItems(ctx, client, logger, repository, inc, userID)
+	case args[0] == "add":
+		return addActionItem(ctx, client, logger, repository, background, tracker, inc, userID, args[1:])
+	case args[0] == "done" && len(args) == 2:
+		return completeActionItem(ctx, client, logger, repository, inc, userID, args[1])
+	default:
+		PostInfoAttachment(ctx, client, channelID, userID, "Action items", actionItemCommandUsage)
+		return nil
+	}
+}
+
+// parseActionItem reads the owner, due date and priority options leading the title, in any order
+func parseActionItem(args []string) (model.ActionItem, error) {
+	item := model.ActionItem{Status: model.ActionItemOpen, Priority: model.ActionItemMedium}
+
+	for len(args) > 0 {
+		arg := args[0]
+		if matches := userMentionParser.FindStringSubmatch(arg); matches != nil {
+			item.OwnerId = matches[1]
+		} else if strings.HasPrefix(arg, "due:") {
+			due, err := time.Parse("2006-01-02", strings.TrimPrefix(arg, "due:"))
+			if err != nil {
+				return item, errInvalidActionItemDue
+			}
+			item.DueDate = &due
+		} else if strings.HasPrefix(arg, "priority:") {
+			priority := strings.TrimPrefix(arg, "priority:")
+			if !model.IsActionItemPriority(priority) {
+				return item, errInvalidActionItemPriority
+			}
+			item.Priority = priority
+		} else {
+			break
+		}
+		args = args[1:]
+	}
+
+	item.Title = strings.Join(args, " ")
+	if item.Title == "" {
+		return item, errMissingActionItemTitle
+	}
+	return item, nil
+}
+
+func addActionItem(
+	ctx context.Context,
+	client bot.Client,
+	logger log.Logger,
+	repository model.Repository,
+	background *concurrence.Background,
+	tracker issuetracker.Provider,
+	inc model.Incident,
+	userID string,
+	args []string,
+) error {
+	item, err := parseActionItem(args)
+	if err != nil {
+		PostErrorAttachment(ctx, client, logger, inc.ChannelId, userID, err.Error()+"\n"+actionItemCommandUsage)
+		return nil
+	}
+	item.IncidentId = inc.Id
+	item.ChannelId = inc.ChannelId
+	item.CreatedBy = userID
+
+	err = repository.InsertActionItem(ctx, &item)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("InsertActionItem"),
+			log.NewValue("channelID", inc.ChannelId),
+			log.NewValue("error", err),
+		)
+
+		PostErrorAttachment(ctx, client, logger, inc.ChannelId, userID, err.Error())
+		return err
+	}
+
+	postMessage(client, inc.ChannelId, "<@"+userID+"> added the action item "+formatActionItem(item))
+
+	if tracker != nil {
+		background.Go(func() {
+			exportActionItem(detachedContext(ctx), client, logger, repository, tracker, inc, &item)
+		})
+	}
+	return nil
+}
+
+// exportActionItem creates the ticket of the action item and posts its link on the incident channel,
+// the item is kept without ticket when the issue tracker fails
+func exportActionItem(
+	ctx context.Context,
+	client bot.Client,
+	logger log.Logger,
+	repository model.Repository,
+	tracker issuetracker.Provider,
+	inc model.Incident,
+	item *model.ActionItem,
+) {
+	ticket, err := tracker.CreateIssue(ctx, issuetracker.NewIssue(*item, inc, issuetracker.ParseLabels(config.Env.IssueTrackerLabels)))
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("tracker.CreateIssue"),
+			log.Reason(err.Error()),
+			log.NewValue("actionItemID", item.Id),
+		)
+		return
+	}
+
+	item.IssueKey = ticket.Key
+	item.IssueUrl = ticket.URL
+	err = repository.UpdateActionItem(ctx, item)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("UpdateActionItem"),
+			log.NewValue("actionItemID", item.Id),
+			log.NewValue("error", err),
+		)
+		return
+	}
+
+	postMessage(client, inc.ChannelId, "The action item "+formatActionItem(*item)+" got its ticket")
+}
+
+func completeActionItem(
+	ctx context.Context,
+	client bot.Client,
+	logger log.Logger,
+	repository model.Repository,
+	inc model.Incident,
+	userID string,
+	id string,
+) error {
+	itemID, err := strconv.ParseInt(strings.TrimPrefix(id, "#"), 10, 64)
+	if err != nil {
+		PostErrorAttachment(ctx, client, logger, inc.ChannelId, userID, errInvalidActionItemID.Error()+" `"+id+"`")
+		return nil
+	}
+
+	item, err := repository.GetActionItem(ctx, itemID)
+	if err != nil || item.IncidentId != inc.Id {
+		PostErrorAttachment(ctx, client, logger, inc.ChannelId, userID, "The action item #"+strconv.FormatInt(itemID, 10)+" is not an action item of this incident")
+		return nil
+	}
+	if item.Status == model.ActionItemDone {
+		PostInfoAttachment(ctx, client, inc.ChannelId, userID, "Action items", "The action item #"+strconv.FormatInt(itemID, 10)+" is already done")
+		return nil
+	}
+
+	now := time.Now().UTC()
+	item.Status = model.ActionItemDone
+	item.CompletedAt = &now
+	err = repository.UpdateActionItem(ctx, &item)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("UpdateActionItem"),
+			log.NewValue("actionItemID", item.Id),
+			log.NewValue("error", err),
+		)
+
+		PostErrorAttachment(ctx, client, logger, inc.ChannelId, userID, err.Error())
+		return err
+	}
+
+	postMessage(client, inc.ChannelId, ":white_check_mark: <@"+userID+"> completed the action item "+formatActionItem(item))
+	return nil
+}
+
+func listActionItems(ctx context.Context, client bot.Client, logger log.Logger, repository model.Repository, inc model.Incident, userID string) error {
+	items, err := repository.ListActionItems(ctx, inc.Id)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("ListActionItems"),
+			log.NewValue("channelID", inc.ChannelId),
+			log.NewValue("error", err),
+		)
+
+		PostErrorAttachment(ctx, client, logger, inc.ChannelId, userID, err.Error())
+		return err
+	}
+
+	if len(items) == 0 {
+		PostInfoAttachment(ctx, client, inc.ChannelId, userID, "Action items", "No action items yet\n"+actionItemCommandUsage)
+		return nil
+	}
+
+	var text strings.Builder
+	for _, item := range items {
+		text.WriteString(formatActionItem(item) + "\n")
+	}
+	PostInfoAttachment(ctx, client, inc.ChannelId, userID, "Action items", text.String())
+	return nil
+}
+
+// formatActionItem returns the action item on one line, e.g. #3 *Add a retry* (high, <@U1>, due 2020-10-30, done)
+func formatActionItem(item model.ActionItem) string {
+	details := []string{item.Priority}
+	if item.OwnerId != "" {
+		details = append(details, "<@"+item.OwnerId+">")
+	}
+	if item.DueDate != nil {
+		details = append(details, "due "+item.DueDate.Format("2006-01-02"))
+	}
+	if item.Status == model.ActionItemDone {
+		details = append(details, "done")
+	}
+
+	text := "#" + strconv.FormatInt(item.Id, 10) + " *" + item.Title + "* (" + strings.Join(details, ", ") + ")"
+	if item.IssueKey != "" {
+		text += " <" + item.IssueUrl + "|" + item.IssueKey + ">"
+	}
+	return text
+}
@@ -0,0 +1,153 @@
+package commands_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"hellper/internal/bot"
+	"hellper/internal/commands"
+	"hellper/internal/concurrence"
+	"hellper/internal/issuetracker"
+	"hellper/internal/log"
+	"hellper/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestActionItemCommand(t *testing.T) {
+	due := time.Date(2020, time.October, 30, 0, 0, 0, 0, time.UTC)
+
+	table := []struct {
+		testName         string
+		text             string
+		withoutTracker   bool
+		expectedInsert   *model.ActionItem
+		expectedUpdate   *model.ActionItem
+		expectedIssues   int
+		expectEphemeral  bool
+		expectMessage    bool
+		existingItem     model.ActionItem
+		expectedListCall bool
+	}{
+		{
+			testName: "Add an action item with options",
+			text:     "add priority:high <@U2|john> due:2020-10-30 Add a retry to the payment client",
+			expectedInsert: &model.ActionItem{
+				IncidentId: 42, ChannelId: "C1", Title: "Add a retry to the payment client", OwnerId: "U2", DueDate: &due,
+				Status: model.ActionItemOpen, Priority: model.ActionItemHigh, CreatedBy: "U1", IssueKey: "1", IssueUrl: "https://issues.example.com/1",
+			},
+			expectedIssues: 1,
+			expectMessage:  true,
+		},
+		{
+			testName:       "Add an action item without issue tracker",
+			text:           "add Add an alert on the payment errors",
+			withoutTracker: true,
+			expectedInsert: &model.ActionItem{
+				IncidentId: 42, ChannelId: "C1", Title: "Add an alert on the payment errors",
+				Status: model.ActionItemOpen, Priority: model.ActionItemMedium, CreatedBy: "U1",
+			},
+			expectMessage: true,
+		},
+		{
+			testName:        "Add without title",
+			text:            "add <@U2> due:2020-10-30",
+			expectEphemeral: true,
+		},
+		{
+			testName:        "Add with unknown priority",
+			text:            "add priority:urgent Add a retry",
+			expectEphemeral: true,
+		},
+		{
+			testName:        "Add with invalid due date",
+			text:            "add due:tomorrow Add a retry",
+			expectEphemeral: true,
+		},
+		{
+			testName:       "Complete an action item",
+			text:           "done #7",
+			existingItem:   model.ActionItem{Id: 7, IncidentId: 42, ChannelId: "C1", Title: "Add a retry", Status: model.ActionItemOpen, Priority: model.ActionItemLow},
+			expectedUpdate: &model.ActionItem{Id: 7, IncidentId: 42, ChannelId: "C1", Title: "Add a retry", Status: model.ActionItemDone, Priority: model.ActionItemLow},
+			expectMessage:  true,
+		},
+		{
+			testName:        "Complete an action item of another incident",
+			text:            "done 7",
+			existingItem:    model.ActionItem{Id: 7, IncidentId: 43, Status: model.ActionItemOpen},
+			expectEphemeral: true,
+		},
+		{
+			testName:         "List the action items",
+			expectEphemeral:  true,
+			expectedListCall: true,
+		},
+		{
+			testName:        "Unknown subcommand shows the usage",
+			text:            "remove 7",
+			expectEphemeral: true,
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			var (
+				ctx            = context.Background()
+				loggerMock     = log.NewLoggerMock()
+				clientMock     = bot.NewClientMock()
+				repositoryMock = model.NewRepositoryMock()
+				fake           = issuetracker.NewFake()
+				background     = &concurrence.Background{}
+				tracker        issuetracker.Provider
+				updated        []model.ActionItem
+			)
+
+			loggerMock.On("Info", ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+			loggerMock.On("Error", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+			clientMock.On("PostMessage", "C1", mock.AnythingOfType("[]slack.MsgOption")).Return("", "", nil)
+			clientMock.On("PostEphemeralContext", ctx, "C1", "U1", mock.AnythingOfType("[]slack.MsgOption")).Return("", nil)
+			repositoryMock.On("GetIncident", "C1").Return(model.Incident{Id: 42, ChannelId: "C1", Title: "Checkout errors", Status: model.StatusResolved}, nil)
+			repositoryMock.On("InsertActionItem", ctx, mock.AnythingOfType("*model.ActionItem")).Return(nil)
+			repositoryMock.On("GetActionItem", ctx, int64(7)).Return(f.existingItem, nil)
+			repositoryMock.On("ListActionItems", ctx, int64(42)).Return([]model.ActionItem{{Id: 1, Title: "Add a retry", Priority: model.ActionItemMedium}}, nil)
+			repositoryMock.On("UpdateActionItem", mock.Anything, mock.AnythingOfType("*model.ActionItem")).Return(nil).Run(func(args mock.Arguments) {
+				updated = append(updated, *args.Get(1).(*model.ActionItem))
+			})
+
+			if !f.withoutTracker {
+				tracker = fake
+			}
+
+			err := commands.ActionItemCommand(ctx, clientMock, loggerMock, repositoryMock, background, tracker, "C1", "U1", f.text)
+			assert.NoError(t, err)
+			assert.NoError(t, background.Wait(ctx))
+
+			if f.expectedInsert != nil {
+				repositoryMock.AssertCalled(t, "InsertActionItem", ctx, f.expectedInsert)
+			} else {
+				repositoryMock.AssertNotCalled(t, "InsertActionItem", mock.Anything, mock.Anything)
+			}
+			if f.expectedUpdate != nil {
+				assert.Len(t, updated, 1)
+				assert.NotNil(t, updated[0].CompletedAt)
+				updated[0].CompletedAt = nil
+				assert.Equal(t, *f.expectedUpdate, updated[0])
+			}
+			assert.Len(t, fake.Issues(), f.expectedIssues)
+			if f.expectedListCall {
+				repositoryMock.AssertCalled(t, "ListActionItems", ctx, int64(42))
+			}
+			if f.expectEphemeral {
+				clientMock.AssertCalled(t, "PostEphemeralContext", ctx, "C1", "U1", mock.AnythingOfType("[]slack.MsgOption"))
+			}
+			if f.expectMessage {
+				clientMock.AssertCalled(t, "PostMessage", "C1", mock.AnythingOfType("[]slack.MsgOption"))
+			} else {
+				clientMock.AssertNotCalled(t, "PostMessage", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package commands
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"hellper/internal/alert"
+	"hellper/internal/bot"
+	"hellper/internal/calendar"
+	"hellper/internal/concurrence"
+	filestorage "hellper/internal/file_storage"
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/warroom"
+	"hellper/internal/workcalendar"
+)
+
+// ReceiveAlerts opens an incident for the new firing alerts, or attaches them to the incident of an alert of the same
+// notification already known. The cleared alerts are announced on their incident, which is resolved once all its alerts
+// cleared when the settings auto resolve
+func ReceiveAlerts(
+	ctx context.Context,
+	client bot.Client,
+	logger log.Logger,
+	repository model.Repository,
+	fileStorage filestorage.Driver,
+	background *concurrence.Background,
+	warRoom warroom.Provider,
+	calendar calendar.Calendar,
+	workCalendar workcalendar.Calendar,
+	settings alert.Settings,
+	source string,
+	alerts []alert.Alert,
+) error {
+	logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("source", source),
+		log.NewValue("alerts", len(alerts)),
+	)
+
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	fingerprints := make([]string, len(alerts))
+	for index, a := range alerts {
+		fingerprints[index] = a.Fingerprint
+	}
+	// The same notification may be delivered again while it is handled, the lock keeps it from opening a second incident
+	release, err := repository.LockAlerts(ctx, fingerprints)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	known := make([]*model.Alert, len(alerts))
+	var group model.Alert
+	for index, a := range alerts {
+		existing, found, err := repository.FindAlert(ctx, a.Fingerprint)
+		if err != nil {
+			return err
+		}
+		if found {
+			known[index] = &existing
+			if group.ChannelId == "" && existing.Status == model.AlertFiring {
+				group = existing
+			}
+		}
+	}
+
+	var lastErr error
+	for index, a := range alerts {
+		var err error
+		if a.Status == model.AlertFiring {
+			group, err = fireAlert(ctx, client, logger, repository, fileStorage, warRoom, settings, source, a, known[index], group)
+		} else {
+			err = clearAlert(ctx, client, logger, repository, fileStorage, background, calendar, workCalendar, settings, a, known[index])
+		}
+		if err != nil {
+			logger.Error(
+				ctx,
+				log.Trace(),
+				log.Reason(err.Error()),
+				log.NewValue("fingerprint", a.Fingerprint),
+				log.NewValue("status", a.Status),
+			)
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// fireAlert returns the alert the next new alerts of the notification attach to
+func fireAlert(
+	ctx context.Context,
+	client bot.Client,
+	logger log.Logger,
+	repository model.Repository,
+	fileStorage filestorage.Driver,
+	warRoom warroom.Provider,
+	settings alert.Settings,
+	source string,
+	a alert.Alert,
+	known *model.Alert,
+	group model.Alert,
+) (model.Alert, error) {
+	if known != nil {
+		if known.Status == model.AlertFiring {
+			// A repeated notification of an alert already on an incident
+			return group, repository.UpdateAlertStatus(ctx, known)
+		}
+
+		inc, err := repository.GetIncident(ctx, known.ChannelId)
+		if err != nil {
+			return group, err
+		}
+		if inc.Status == model.StatusOpen {
+			known.Status = model.AlertFiring
+			known.ResolvedAt = nil
+			err = repository.UpdateAlertStatus(ctx, known)
+			if err != nil {
+				return group, err
+			}
+			postMessage(client, known.ChannelId, ":rotating_light: The alert *"+alert.Title(a)+"* is firing again."+alertLink(a))
+			if group.ChannelId == "" {
+				group = *known
+			}
+			return group, nil
+		}
+	}
+
+	if group.ChannelId != "" {
+		err := repository.InsertAlert(ctx, &model.Alert{
+			Fingerprint: a.Fingerprint,
+			IncidentId:  group.IncidentId,
+			ChannelId:   group.ChannelId,
+			Source:      source,
+			Name:        alert.Title(a),
+			Status:      model.AlertFiring,
+			StartsAt:    a.StartsAt,
+		})
+		if err != nil {
+			return group, err
+		}
+		postMessage(client, group.ChannelId, ":rotating_light: The alert *"+alert.Title(a)+"* is also firing."+alertLink(a))
+		return group, nil
+	}
+
+	inc, err := StartIncident(ctx, client, logger, repository, fileStorage, warRoom, bot.DialogSubmission{
+		User: bot.User{ID: settings.Commander},
+		Submission: bot.Submission{
+			IncidentTitle:       alert.Title(a),
+			ChannelName:         alert.ChannelName(a, time.Now()),
+			SeverityLevel:       strconv.FormatInt(settings.SeverityLevel(a), 10),
+			Product:             settings.Product(a),
+			IncidentCommander:   settings.Commander,
+			IncidentDescription: alertDescription(source, a),
+		},
+	})
+	if inc.Id == 0 {
+		return group, err
+	}
+
+	// The incident is saved even when the commander could not be invited, its alert must be kept
+	opened := model.Alert{
+		Fingerprint: a.Fingerprint,
+		IncidentId:  inc.Id,
+		ChannelId:   inc.ChannelId,
+		Source:      source,
+		Name:        alert.Title(a),
+		Status:      model.AlertFiring,
+		StartsAt:    a.StartsAt,
+	}
+	insertErr := repository.InsertAlert(ctx, &opened)
+	if insertErr != nil {
+		return group, insertErr
+	}
+	return opened, err
+}
+
+func clearAlert(
+	ctx context.Context,
+	client bot.Client,
+	logger log.Logger,
+	repository model.Repository,
+	fileStorage filestorage.Driver,
+	background *concurrence.Background,
+	calendar calendar.Calendar,
+	workCalendar workcalendar.Calendar,
+	settings alert.Settings,
+	a alert.Alert,
+	known *model.Alert,
+) error {
+	if known == nil || known.Status == model.AlertResolved {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	known.Status = model.AlertResolved
+	known.ResolvedAt = &now
+	err := repository.UpdateAlertStatus(ctx, known)
+	if err != nil {
+		return err
+	}
+	postMessage(client, known.ChannelId, ":white_check_mark: The alert *"+known.Name+"* cleared."+alertLink(a))
+
+	if !settings.AutoResolve {
+		return nil
+	}
+
+	inc, err := repository.GetIncident(ctx, known.ChannelId)
+	if err != nil {
+		return err
+	}
+	if inc.Status != model.StatusOpen {
+		return nil
+	}
+
+	alerts, err := repository.ListIncidentAlerts(ctx, known.IncidentId)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, incidentAlert := range alerts {
+		if incidentAlert.Status == model.AlertFiring {
+			return nil
+		}
+		names = append(names, incidentAlert.Name)
+	}
+
+	return ResolveIncidentByDialog(ctx, client, logger, repository, fileStorage, background, calendar, workCalendar, bot.DialogSubmission{
+		Channel: bot.Channel{ID: inc.ChannelId, Name: inc.ChannelName},
+		User:    bot.User{ID: settings.Commander, Name: settings.Commander},
+		Submission: bot.Submission{
+			IncidentDescription: "All the alerts cleared: " + strings.Join(names, ", "),
+			PostMortemMeeting:   "false",
+		},
+	})
+}
+
+func alertDescription(source string, a alert.Alert) string {
+	var description strings.Builder
+	description.WriteString("Opened by the " + source + " alert " + a.Name + ".")
+	if a.Description != "" {
+		description.WriteString("\n" + a.Description)
+	}
+	if a.URL != "" {
+		description.WriteString("\n" + a.URL)
+	}
+	return description.String()
+}
+
+func alertLink(a alert.Alert) string {
+	if a.URL == "" {
+		return ""
+	}
+	return " <" + a.URL + "|Open the alert>"
+}
@@ -0,0 +1,144 @@
+package commands_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"hellper/internal/alert"
+	"hellper/internal/bot"
+	"hellper/internal/calendar"
+	"hellper/internal/commands"
+	"hellper/internal/concurrence"
+	filestorage "hellper/internal/file_storage"
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/workcalendar"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReceiveAlerts(t *testing.T) {
+	var (
+		started = time.Date(2020, time.March, 19, 12, 0, 0, 0, time.UTC)
+		ended   = started.Add(time.Hour)
+		firing  = model.Alert{Id: 1, Fingerprint: "a", IncidentId: 42, ChannelId: "C1", Name: "High error rate", Status: model.AlertFiring}
+		cleared = model.Alert{Id: 2, Fingerprint: "b", IncidentId: 42, ChannelId: "C1", Name: "High latency", Status: model.AlertResolved}
+	)
+
+	table := []struct {
+		testName          string
+		alerts            []alert.Alert
+		autoResolve       bool
+		incidentAlerts    []model.Alert
+		expectedInsert    *model.Alert
+		expectedUpdates   []string
+		expectedResolved  bool
+		expectedMessageTo string
+	}{
+		{
+			testName: "New alert attaches to the incident of a known alert",
+			alerts: []alert.Alert{
+				{Fingerprint: "a", Status: model.AlertFiring, Name: "High error rate"},
+				{Fingerprint: "c", Status: model.AlertFiring, Name: "Checkout down", Summary: "Checkout is down"},
+			},
+			expectedInsert:    &model.Alert{Fingerprint: "c", IncidentId: 42, ChannelId: "C1", Source: alert.SourceAlertmanager, Name: "Checkout is down", Status: model.AlertFiring},
+			expectedUpdates:   []string{model.AlertFiring},
+			expectedMessageTo: "C1",
+		},
+		{
+			testName:          "Cleared alert is announced on its incident",
+			alerts:            []alert.Alert{{Fingerprint: "a", Status: model.AlertResolved, Name: "High error rate"}},
+			expectedUpdates:   []string{model.AlertResolved},
+			expectedMessageTo: "C1",
+		},
+		{
+			testName:          "Incident is resolved once all its alerts cleared",
+			alerts:            []alert.Alert{{Fingerprint: "a", Status: model.AlertResolved, Name: "High error rate"}},
+			autoResolve:       true,
+			incidentAlerts:    []model.Alert{{Name: "High error rate", Status: model.AlertResolved}, cleared},
+			expectedUpdates:   []string{model.AlertResolved},
+			expectedResolved:  true,
+			expectedMessageTo: "C1",
+		},
+		{
+			testName:          "Incident stays open while an alert is firing",
+			alerts:            []alert.Alert{{Fingerprint: "a", Status: model.AlertResolved, Name: "High error rate"}},
+			autoResolve:       true,
+			incidentAlerts:    []model.Alert{{Name: "High error rate", Status: model.AlertResolved}, {Name: "High latency", Status: model.AlertFiring}},
+			expectedUpdates:   []string{model.AlertResolved},
+			expectedMessageTo: "C1",
+		},
+		{
+			testName: "Cleared unknown alert is ignored",
+			alerts:   []alert.Alert{{Fingerprint: "c", Status: model.AlertResolved, Name: "Checkout down"}},
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			var (
+				ctx            = context.Background()
+				loggerMock     = log.NewLoggerMock()
+				clientMock     = bot.NewClientMock()
+				repositoryMock = model.NewRepositoryMock()
+				calendarMock   = calendar.NewCalendarMock()
+				storageMock    = filestorage.NewFileStorageMock()
+				settings       = alert.Settings{Commander: "U1", AutoResolve: f.autoResolve}
+				known          = firing
+				updates        []string
+				released       bool
+			)
+
+			workCalendar, err := workcalendar.Default("America/Sao_Paulo")
+			assert.NoError(t, err)
+
+			loggerMock.On("Info", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+			loggerMock.On("Error", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+			clientMock.On("PostMessage", mock.AnythingOfType("string"), mock.AnythingOfType("[]slack.MsgOption")).Return("", "", nil)
+			clientMock.On("AddPin", mock.AnythingOfType("string"), mock.AnythingOfType("slack.ItemRef")).Return(nil)
+			clientMock.On("GetConversationHistoryContext", mock.Anything, mock.AnythingOfType("*slack.GetConversationHistoryParameters")).Return(&slack.GetConversationHistoryResponse{}, nil)
+			storageMock.On("UploadFile", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("[]uint8")).Return("https://drive.example/timeline", nil)
+			storageMock.On("UpdatePostMortemDocument", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("filestorage.PostMortemData")).Return(nil)
+			repositoryMock.On("LockAlerts", ctx, mock.AnythingOfType("[]string")).Return(func() { released = true }, nil)
+			repositoryMock.On("FindAlert", ctx, "a").Return(known, true, nil)
+			repositoryMock.On("FindAlert", ctx, mock.AnythingOfType("string")).Return(model.Alert{}, false, nil)
+			repositoryMock.On("InsertAlert", ctx, mock.AnythingOfType("*model.Alert")).Return(nil)
+			repositoryMock.On("UpdateAlertStatus", ctx, mock.AnythingOfType("*model.Alert")).Return(nil).Run(func(args mock.Arguments) {
+				updates = append(updates, args.Get(1).(*model.Alert).Status)
+			})
+			repositoryMock.On("GetIncident", "C1").Return(model.Incident{Id: 42, ChannelId: "C1", ChannelName: "alert-high-error", Status: model.StatusOpen, EndTimestamp: &ended}, nil)
+			repositoryMock.On("ListIncidentAlerts", ctx, int64(42)).Return(f.incidentAlerts, nil)
+			repositoryMock.On("ResolveIncident", ctx, mock.AnythingOfType("*model.Incident")).Return(nil)
+
+			err = commands.ReceiveAlerts(ctx, clientMock, loggerMock, repositoryMock, storageMock, &concurrence.Background{}, nil, calendarMock, workCalendar, settings, alert.SourceAlertmanager, f.alerts)
+			assert.NoError(t, err)
+
+			fingerprints := make([]string, len(f.alerts))
+			for index, a := range f.alerts {
+				fingerprints[index] = a.Fingerprint
+			}
+			repositoryMock.AssertCalled(t, "LockAlerts", ctx, fingerprints)
+			assert.True(t, released)
+			assert.Equal(t, f.expectedUpdates, updates)
+			if f.expectedInsert != nil {
+				repositoryMock.AssertCalled(t, "InsertAlert", ctx, f.expectedInsert)
+			} else {
+				repositoryMock.AssertNotCalled(t, "InsertAlert", ctx, mock.Anything)
+			}
+			if f.expectedResolved {
+				repositoryMock.AssertCalled(t, "ResolveIncident", ctx, mock.AnythingOfType("*model.Incident"))
+			} else {
+				repositoryMock.AssertNotCalled(t, "ResolveIncident", ctx, mock.Anything)
+			}
+			if f.expectedMessageTo != "" {
+				clientMock.AssertCalled(t, "PostMessage", f.expectedMessageTo, mock.AnythingOfType("[]slack.MsgOption"))
+			} else {
+				clientMock.AssertNotCalled(t, "PostMessage", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"hellper/internal/bot"
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/sla"
+
+	"github.com/slack-go/slack"
+)
+
+// PublishAppHome publishes the hellper App Home of the user, with the active incidents and their roles
+func PublishAppHome(ctx context.Context, client bot.Client, logger log.Logger, repository model.Repository, userID string) error {
+	logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("userID", userID),
+	)
+
+	incidents, err := repository.ListActiveIncidents(ctx)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("repository.ListActiveIncidents"),
+			log.Reason(err.Error()),
+			log.NewValue("userID", userID),
+		)
+		return err
+	}
+
+	blocks := []slack.Block{
+		slack.NewHeaderBlock(slack.NewTextBlockObject(slack.PlainTextType, "Active incidents", false, false)),
+	}
+	if len(incidents) == 0 {
+		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, "No active incidents!", false, false), nil, nil))
+	}
+
+	for _, inc := range incidents {
+		roles, err := repository.ListIncidentRoles(ctx, inc.Id)
+		if err != nil {
+			logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("repository.ListIncidentRoles"),
+				log.Reason(err.Error()),
+				log.NewValue("channelID", inc.ChannelId),
+			)
+			return err
+		}
+
+		blocks = append(
+			blocks,
+			slack.NewDividerBlock(),
+			slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, appHomeIncidentText(inc, roles, userID), false, false), nil, nil),
+		)
+	}
+
+	view := slack.HomeTabViewRequest{
+		Type:   slack.VTHomeTab,
+		Blocks: slack.Blocks{BlockSet: blocks},
+	}
+
+	_, err = client.PublishViewContext(ctx, userID, view, "")
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("client.PublishViewContext"),
+			log.Reason(err.Error()),
+			log.NewValue("userID", userID),
+		)
+		return err
+	}
+
+	return nil
+}
+
+func appHomeIncidentText(inc model.Incident, roles []model.IncidentRole, userID string) string {
+	var text strings.Builder
+	text.WriteString("*<#" + inc.ChannelId + ">* " + inc.Title + "\n")
+	text.WriteString("*Status:* `" + inc.Status + "` - *Severity:* " + getSeverityLevelText(inc.SeverityLevel) + "\n")
+	text.WriteString(formatIncidentRoles(inc, roles))
+	if inc.Status == model.StatusResolved {
+		text.WriteString("*Post mortem:* " + model.PostMortemStatusName(inc.PostMortemStatus) + "\n")
+	}
+	if summary := sla.Summary(sla.Evaluate(sla.EnvTargets(), inc, time.Now())); summary != "" {
+		text.WriteString("*SLA:* " + summary + "\n")
+	}
+
+	var yourRoles []string
+	if inc.CommanderId == userID {
+		yourRoles = append(yourRoles, "Commander")
+	}
+	for _, role := range sortIncidentRoles(roles) {
+		if role.UserId == userID {
+			yourRoles = append(yourRoles, model.RoleName(role.Role))
+		}
+	}
+	if len(yourRoles) > 0 {
+		text.WriteString(":wave: You are the " + strings.Join(yourRoles, ", ") + " of this incident\n")
+	}
+
+	return text.String()
+}
@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"context"
+
+	"hellper/internal/authorization"
+	"hellper/internal/bot"
+	"hellper/internal/log"
+	"hellper/internal/model"
+
+	"github.com/slack-go/slack"
+)
+
+// AuthorizeIncidentAction checks the authorization policy before an action runs on the incident of the channel.
+// Every decision is stored on the audit log, and denied users receive an ephemeral message.
+func AuthorizeIncidentAction(
+	ctx context.Context,
+	client bot.Client,
+	logger log.Logger,
+	repository model.Repository,
+	policy authorization.Policy,
+	action string,
+	channelID string,
+	userID string,
+) (bool, error) {
+	if !policy.Guards(action) {
+		return true, nil
+	}
+
+	inc, err := repository.GetIncident(ctx, channelID)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("GetIncident"),
+			log.NewValue("channelID", channelID),
+			log.NewValue("error", err),
+		)
+
+		PostErrorAttachment(ctx, client, logger, channelID, userID, err.Error())
+		return false, err
+	}
+
+	decision := policy.Authorize(ctx, client, logger, action, inc, userID)
+	logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("action", action),
+		log.NewValue("channelID", channelID),
+		log.NewValue("userID", userID),
+		log.NewValue("decision", decision),
+	)
+
+	err = repository.InsertAuditLog(ctx, &model.AuditLog{
+		ChannelId: channelID,
+		UserId:    userID,
+		Action:    action,
+		Allowed:   decision.Allowed,
+		Reason:    decision.Reason,
+	})
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("InsertAuditLog"),
+			log.NewValue("channelID", channelID),
+			log.NewValue("userID", userID),
+			log.NewValue("error", err),
+		)
+	}
+
+	if !decision.Allowed {
+		postPermissionDenied(ctx, client, logger, channelID, userID, decision.Reason)
+	}
+
+	return decision.Allowed, nil
+}
+
+func postPermissionDenied(ctx context.Context, client bot.Client, logger log.Logger, channelID string, userID string, reason string) {
+	attach := slack.Attachment{
+		Pretext:  "",
+		Fallback: "Permission denied: " + reason,
+		Text:     "",
+		Color:    "#FE4D4D",
+		Fields: []slack.AttachmentField{
+			{
+				Title: "Permission denied",
+				Value: "Sorry, " + reason + ".",
+			},
+		},
+	}
+
+	_, err := client.PostEphemeralContext(ctx, channelID, userID, slack.MsgOptionAttachments(attach))
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("client.PostEphemeralContext"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", channelID),
+			log.NewValue("userID", userID),
+		)
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"hellper/internal/bot"
 	"hellper/internal/config"
+	filestorage "hellper/internal/file_storage"
 	"hellper/internal/log"
 	"hellper/internal/model"
 
@@ -52,6 +53,8 @@ func CloseIncidentDialog(ctx context.Context, logger log.Logger, client bot.Clie
 		return postMessage(client, channelID, "", attch)
 	}
 
+	warnMissingActionItems(ctx, client, logger, repository, inc, userID)
+
 	feature := &slack.TextInputElement{
 		DialogInput: slack.DialogInput{
 			Label:       "Feature",
@@ -158,8 +161,39 @@ func CloseIncidentDialog(ctx context.Context, logger log.Logger, client bot.Clie
 	return client.OpenDialog(triggerID, dialog)
 }
 
+// warnMissingActionItems tells the user closing the incident when it has no action items to follow up
+func warnMissingActionItems(ctx context.Context, client bot.Client, logger log.Logger, repository model.Repository, inc model.Incident, userID string) {
+	items, err := repository.ListActionItems(ctx, inc.Id)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("ListActionItems"),
+			log.NewValue("channelID", inc.ChannelId),
+			log.NewValue("error", err),
+		)
+		return
+	}
+	if len(items) > 0 {
+		return
+	}
+
+	client.PostEphemeralContext(ctx, inc.ChannelId, userID, slack.MsgOptionAttachments(slack.Attachment{
+		Color: "#ff8c00",
+		Text:  "The Incident <#" + inc.ChannelId + "> has no action items.\nAdd the follow-ups with `/hellper_action add` before closing it.",
+	}))
+}
+
 // CloseIncidentByDialog closes an incident after receiving data from a Slack dialog
-func CloseIncidentByDialog(ctx context.Context, client bot.Client, logger log.Logger, repository model.Repository, incidentDetails bot.DialogSubmission) error {
+func CloseIncidentByDialog(
+	ctx context.Context,
+	client bot.Client,
+	logger log.Logger,
+	repository model.Repository,
+	fileStorage filestorage.Driver,
+	background *concurrence.Background,
+	incidentDetails bot.DialogSubmission,
+) error {
 	logger.Info(
 		ctx,
 		"command/close.CloseIncidentByDialog",
@@ -253,21 +287,43 @@ func CloseIncidentByDialog(ctx context.Context, client bot.Client, logger log.Lo
 		message,
 		channelAttachment,
 	)
-	err = client.ArchiveConversationContext(ctx, channelID)
+
+	// The history can only be read before the channel is archived,
+	// and the export takes longer than Slack waits for the dialog response, so it goes on in the background
+	background.Go(func() {
+		exportTimelineAndArchive(detachedContext(ctx), logger, client, fileStorage, inc, userID)
+	})
+
+	return nil
+}
+
+func exportTimelineAndArchive(ctx context.Context, logger log.Logger, client bot.Client, fileStorage filestorage.Driver, inc model.Incident, userID string) {
+	entries, err := exportTimeline(ctx, logger, client, fileStorage, inc)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("exportTimeline"),
+			log.NewValue("channelID", inc.ChannelId),
+			log.NewValue("error", err),
+		)
+		PostErrorAttachment(ctx, client, logger, inc.ChannelId, userID, "The incident timeline could not be exported: "+err.Error())
+	}
+
+	updatePostMortem(ctx, logger, fileStorage, inc, entries)
+
+	err = client.ArchiveConversationContext(ctx, inc.ChannelId)
 	if err != nil {
 		logger.Error(
 			ctx,
 			log.Trace(),
 			log.Reason("ArchiveConversationContext"),
-			log.NewValue("channelID", channelID),
+			log.NewValue("channelID", inc.ChannelId),
 			log.NewValue("userID", userID),
 			log.NewValue("error", err),
 		)
-		PostErrorAttachment(ctx, client, logger, channelID, userID, err.Error())
-		return err
+		PostErrorAttachment(ctx, client, logger, inc.ChannelId, userID, err.Error())
 	}
-
-	return nil
 }
 
 func getResponsabilityText(r string) string {
@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"context"
+
+	"hellper/internal/bot"
+	"hellper/internal/log"
+	"hellper/internal/model"
+)
+
+// AcknowledgeEscalation acknowledges the escalation of the incident of the channel, so its reminders start over
+func AcknowledgeEscalation(ctx context.Context, client bot.Client, logger log.Logger, repository model.Repository, channelID string, userID string) error {
+	logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("channelID", channelID),
+		log.NewValue("userID", userID),
+	)
+
+	inc, err := repository.GetIncident(ctx, channelID)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("GetIncident"),
+			log.NewValue("channelID", channelID),
+			log.NewValue("error", err),
+		)
+		return err
+	}
+
+	err = repository.AcknowledgeEscalations(ctx, inc.Id, userID)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("AcknowledgeEscalations"),
+			log.NewValue("channelID", channelID),
+			log.NewValue("error", err),
+		)
+		return err
+	}
+
+	postMessage(client, channelID, "<@"+userID+"> acknowledged the escalation of this incident, the reminders start over")
+	return nil
+}
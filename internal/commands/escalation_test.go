@@ -0,0 +1,52 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"hellper/internal/bot"
+	"hellper/internal/commands"
+	"hellper/internal/log"
+	"hellper/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAcknowledgeEscalation(t *testing.T) {
+	table := []struct {
+		testName string
+		ackError error
+	}{
+		{testName: "Acknowledged"},
+		{testName: "Acknowledge error", ackError: errors.New("database is down")},
+	}
+
+	for _, f := range table {
+		t.Run(f.testName, func(t *testing.T) {
+			var (
+				ctx            = context.Background()
+				loggerMock     = log.NewLoggerMock()
+				clientMock     = bot.NewClientMock()
+				repositoryMock = model.NewRepositoryMock()
+			)
+
+			loggerMock.On("Info", ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+			loggerMock.On("Error", ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+			clientMock.On("PostMessage", "C1", mock.AnythingOfType("[]slack.MsgOption")).Return("", "", nil)
+			repositoryMock.On("GetIncident", "C1").Return(model.Incident{Id: 42, ChannelId: "C1"}, nil)
+			repositoryMock.On("AcknowledgeEscalations", ctx, int64(42), "U1").Return(f.ackError)
+
+			err := commands.AcknowledgeEscalation(ctx, clientMock, loggerMock, repositoryMock, "C1", "U1")
+			if f.ackError != nil {
+				assert.EqualError(t, err, f.ackError.Error())
+				clientMock.AssertNotCalled(t, "PostMessage", "C1", mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			clientMock.AssertCalled(t, "PostMessage", "C1", mock.Anything)
+		})
+	}
+}
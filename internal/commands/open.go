@@ -14,6 +14,7 @@ import (
 	filestorage "hellper/internal/file_storage"
 	"hellper/internal/log"
 	"hellper/internal/model"
+	"hellper/internal/warroom"
 
 	"github.com/slack-go/slack"
 )
@@ -149,6 +150,7 @@ func StartIncidentByDialog(
 	logger log.Logger,
 	repository model.Repository,
 	fileStorage filestorage.Driver,
+	warRoom warroom.Provider,
 	incidentDetails bot.DialogSubmission,
 ) error {
 	logger.Info(
@@ -157,6 +159,21 @@ func StartIncidentByDialog(
 		log.NewValue("incident_open_details", incidentDetails),
 	)
 
+	_, err := StartIncident(ctx, client, logger, repository, fileStorage, warRoom, incidentDetails)
+	return err
+}
+
+// StartIncident creates the channel of the incident of the details, saves it and announces it, returning the incident saved.
+// The war room is created by the provider unless the details have one
+func StartIncident(
+	ctx context.Context,
+	client bot.Client,
+	logger log.Logger,
+	repository model.Repository,
+	fileStorage filestorage.Driver,
+	warRoom warroom.Provider,
+	incidentDetails bot.DialogSubmission,
+) (model.Incident, error) {
 	var (
 		now              = time.Now().UTC()
 		incidentAuthor   = incidentDetails.User.ID
@@ -168,26 +185,23 @@ func StartIncidentByDialog(
 		product          = submission.Product
 		commander        = submission.IncidentCommander
 		description      = submission.IncidentDescription
-		environment      = config.Env.Environment
-		matrixURL        = config.Env.MatrixHost
 		supportTeam      = config.Env.SupportTeam
 		productChannelID = config.Env.ProductChannelID
-		stagingRoom      = "dc82e346-639c-44ee-a470-63f7545ae8e4"
 	)
 
 	user, err := getSlackUserInfo(ctx, client, logger, commander)
 	if err != nil {
-		return fmt.Errorf("commands.StartIncidentByDialog.get_slack_user_info: incident=%v commanderId=%v error=%v", channelName, commander, err)
+		return model.Incident{}, fmt.Errorf("commands.StartIncidentByDialog.get_slack_user_info: incident=%v commanderId=%v error=%v", channelName, commander, err)
 	}
 
 	channel, err := client.CreateConversationContext(ctx, channelName, false)
 	if err != nil {
-		return fmt.Errorf("commands.StartIncidentByDialog.create_conversation_context: incident=%v error=%v", channelName, err)
+		return model.Incident{}, fmt.Errorf("commands.StartIncidentByDialog.create_conversation_context: incident=%v error=%v", channelName, err)
 	}
 
 	severityLevelInt64, err := getStringInt64(severityLevel)
 	if err != nil {
-		return err
+		return model.Incident{}, err
 	}
 
 	incident := model.Incident{
@@ -202,22 +216,30 @@ func StartIncidentByDialog(
 		IncidentAuthor:          incidentAuthor,
 		CommanderId:             user.SlackID,
 		CommanderEmail:          user.Email,
+		WarRoomUrl:              warRoomURL,
 	}
 
-	incidentID, err := repository.InsertIncident(ctx, &incident)
-	if err != nil {
-		return err
+	if incident.WarRoomUrl == "" && warRoom != nil {
+		incident.WarRoomUrl, err = warRoom.CreateRoom(ctx, incident)
+		if err != nil {
+			// The incident is opened without war room rather than not opened
+			logger.Error(
+				ctx,
+				log.Trace(),
+				log.Reason("CreateRoom"),
+				log.NewValue("channelName", channelName),
+				log.NewValue("error", err),
+			)
+		}
 	}
 
-	if warRoomURL == "" {
-		if environment == "production" {
-			warRoomURL = matrixURL + "/new?roomId=" + channelName + "&roomName=" + channelName
-		} else if environment == "staging" {
-			warRoomURL = matrixURL + "/new?roomId=" + stagingRoom + "&roomName=hellper-staging"
-		}
+	incidentID, err := repository.InsertIncident(ctx, &incident)
+	if err != nil {
+		return model.Incident{}, err
 	}
+	incident.Id = incidentID
 
-	attachment := createOpenAttachment(incident, incidentID, warRoomURL, supportTeam)
+	attachment := createOpenAttachment(incident, incidentID, incident.WarRoomUrl, supportTeam)
 	message := "An Incident has been opened by <@" + incident.IncidentAuthor + ">"
 
 	var waitgroup sync.WaitGroup
@@ -231,9 +253,7 @@ func StartIncidentByDialog(
 	})
 
 	//We need run that without wait because the modal need close in only 3s
-	go createPostMortemAndUpdateTopic(ctx, logger, client, fileStorage, incident, incidentID, repository, channel, warRoomURL)
-
-	// startReminderStatusJob(ctx, logger, client, repository, incident)
+	go createPostMortemAndUpdateTopic(ctx, logger, client, fileStorage, incident, repository, channel, incident.WarRoomUrl)
 
 	_, err = client.InviteUsersToConversationContext(ctx, channel.ID, commander)
 	if err != nil {
@@ -245,14 +265,14 @@ func StartIncidentByDialog(
 			log.NewValue("commander", commander),
 			log.NewValue("error", err),
 		)
-		return err
+		return incident, err
 	}
 
-	return nil
+	return incident, nil
 }
 
-func createPostMortemAndUpdateTopic(ctx context.Context, logger log.Logger, client bot.Client, fileStorage filestorage.Driver, incident model.Incident, incidentID int64, repository model.Repository, channel *slack.Channel, warRoomURL string) {
-	postMortemURL, err := createPostMortem(ctx, logger, client, fileStorage, incidentID, incident.Title, repository, channel.Name)
+func createPostMortemAndUpdateTopic(ctx context.Context, logger log.Logger, client bot.Client, fileStorage filestorage.Driver, incident model.Incident, repository model.Repository, channel *slack.Channel, warRoomURL string) {
+	postMortemURL, err := createPostMortem(ctx, logger, client, fileStorage, incident, repository, channel.Name)
 	if err != nil {
 		logger.Error(
 			ctx,
@@ -264,19 +284,30 @@ func createPostMortemAndUpdateTopic(ctx context.Context, logger log.Logger, clie
 		return
 	}
 
-	var topic strings.Builder
-	topic.WriteString("*WarRoom:* " + warRoomURL + "\n\n")
-	topic.WriteString("*PostMortem:* " + postMortemURL + "\n\n")
-	topic.WriteString("*Commander:* <@" + incident.CommanderId + ">\n\n")
+	incident.WarRoomUrl = warRoomURL
+	incident.PostMortemUrl = postMortemURL
+
+	// the roles assigned while the post mortem was created are kept on the topic
+	roles, err := repository.ListIncidentRoles(ctx, incident.Id)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("ListIncidentRoles"),
+			log.NewValue("channel.ID", channel.ID),
+			log.NewValue("error", err),
+		)
+	}
 
-	_, err = client.SetTopicOfConversation(channel.ID, topic.String())
+	topic := incidentTopic(incident, roles)
+	_, err = client.SetTopicOfConversation(channel.ID, topic)
 	if err != nil {
 		logger.Error(
 			ctx,
 			log.Trace(),
 			log.Reason("SetTopicOfConversation"),
 			log.NewValue("channel.ID", channel.ID),
-			log.NewValue("topic.String", topic.String()),
+			log.NewValue("topic", topic),
 			log.NewValue("error", err),
 		)
 	}
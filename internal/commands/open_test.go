@@ -8,6 +8,7 @@ import (
 	filestorage "hellper/internal/file_storage"
 	"hellper/internal/log"
 	"hellper/internal/model"
+	"hellper/internal/warroom"
 	"testing"
 
 	"github.com/slack-go/slack"
@@ -26,6 +27,8 @@ type openCommandFixture struct {
 	mockFilestorage      filestorage.Driver
 	triggerID            string
 	mockDialogSubmission bot.DialogSubmission
+	warRoom              warroom.Provider
+	expectedWarRoomURL   string
 }
 
 func (f *openCommandFixture) setup(t *testing.T) {
@@ -53,7 +56,8 @@ func (f *openCommandFixture) setup(t *testing.T) {
 	clientMock.On("CreateConversationContext", f.ctx, mock.AnythingOfType("string"), mock.AnythingOfType("bool")).Return(new(slack.Channel), nil)
 	repositoryMock.On("InsertIncident", mock.AnythingOfType("*model.Incident")).Return(int64(1), nil)
 	repositoryMock.On("AddPostMortemUrl", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
-	filestorageMock.On("CreatePostMortemDocument", f.ctx, mock.AnythingOfType("string")).Return(string(""), nil)
+	repositoryMock.On("ListIncidentRoles", mock.Anything, int64(1)).Return(nil, nil)
+	filestorageMock.On("CreatePostMortemDocument", f.ctx, mock.AnythingOfType("filestorage.PostMortemData")).Return(string(""), nil)
 }
 
 func TestOpenIncidentDialog(t *testing.T) {
@@ -104,6 +108,37 @@ func TestStartIncidentByDialog(t *testing.T) {
 				},
 			},
 		},
+		{
+			testName:           "When the war room is created by the provider",
+			expectError:        false,
+			warRoom:            warroom.NewTemplate("https://meet.example.com/{channel_name}"),
+			expectedWarRoomURL: "https://meet.example.com/inc-xyz",
+			mockDialogSubmission: bot.DialogSubmission{
+				User: bot.User{ID: "UYGFQB9C0"},
+				Submission: bot.Submission{
+					IncidentTitle:     "Inc XYZ",
+					ChannelName:       "inc-xyz",
+					SeverityLevel:     "2",
+					IncidentCommander: "UYGFQB9C0",
+				},
+			},
+		},
+		{
+			testName:           "When the form has a war room the provider is not used",
+			expectError:        false,
+			warRoom:            warroom.NewTemplate("https://meet.example.com/{channel_name}"),
+			expectedWarRoomURL: "https://zoom.example.com/j/1",
+			mockDialogSubmission: bot.DialogSubmission{
+				User: bot.User{ID: "UYGFQB9C0"},
+				Submission: bot.Submission{
+					IncidentTitle:     "Inc XYZ",
+					ChannelName:       "inc-xyz",
+					WarRoomURL:        "https://zoom.example.com/j/1",
+					SeverityLevel:     "2",
+					IncidentCommander: "UYGFQB9C0",
+				},
+			},
+		},
 		{
 			testName:     "When SeverityLevel is not a number",
 			expectError:  true,
@@ -127,7 +162,7 @@ func TestStartIncidentByDialog(t *testing.T) {
 		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
 			f.setup(t)
 
-			err := commands.StartIncidentByDialog(f.ctx, f.mockClient, f.mockLogger, f.mockRepository, f.mockFilestorage, f.mockDialogSubmission)
+			err := commands.StartIncidentByDialog(f.ctx, f.mockClient, f.mockLogger, f.mockRepository, f.mockFilestorage, f.warRoom, f.mockDialogSubmission)
 			if f.expectError {
 				if err == nil {
 					t.Fatal("an error was expected, but not occurred")
@@ -139,6 +174,12 @@ func TestStartIncidentByDialog(t *testing.T) {
 					t.Fatalf("an error occurred, but was not expected:\n%s", err)
 				}
 			}
+
+			if f.expectedWarRoomURL != "" {
+				f.mockRepository.(*model.RepositoryMock).AssertCalled(t, "InsertIncident", mock.MatchedBy(func(inserted *model.Incident) bool {
+					return inserted.WarRoomUrl == f.expectedWarRoomURL
+				}))
+			}
 		})
 	}
 }
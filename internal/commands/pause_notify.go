@@ -6,14 +6,15 @@ import (
 	"hellper/internal/bot"
 	"hellper/internal/log"
 	"hellper/internal/model"
-	"strconv"
+	"hellper/internal/snooze"
+	"strings"
 	"time"
 
 	"github.com/slack-go/slack"
 )
 
 // PauseNotifyIncidentDialog opens a dialog on Slack, so the user can pause notify
-func PauseNotifyIncidentDialog(ctx context.Context, logger log.Logger, client bot.Client, repository model.Repository, channelID string, userID string, triggerID string) error {
+func PauseNotifyIncidentDialog(ctx context.Context, logger log.Logger, client bot.Client, repository model.Repository, limits snooze.Limits, channelID string, userID string, triggerID string) error {
 
 	inc, err := repository.GetIncident(ctx, channelID)
 	if err != nil {
@@ -33,17 +34,21 @@ func PauseNotifyIncidentDialog(ctx context.Context, logger log.Logger, client bo
 		return nil
 	}
 
-	pauseNotifyTime := &slack.DialogInputSelect{
+	hint := "A duration such as 30m, 4h or 2d"
+	if limit, ok := limits.Limit(inc); ok {
+		hint += ", at most " + snooze.FormatDuration(limit)
+	}
+
+	pauseNotifyTime := &slack.TextInputElement{
 		DialogInput: slack.DialogInput{
-			Label:       "How long time would you like to pause?",
+			Label:       "How long would you like to pause?",
 			Name:        "pause_notify_time",
-			Type:        "select",
-			Placeholder: "Select an option",
+			Type:        "text",
+			Placeholder: "4h",
 			Optional:    false,
 		},
-		Value:        "1",
-		Options:      optionsPauseNotify(inc.Status),
-		OptionGroups: []slack.DialogOptionGroup{},
+		Hint:      hint,
+		MaxLength: 20,
 	}
 
 	reason := &slack.TextInputElement{
@@ -68,12 +73,13 @@ func PauseNotifyIncidentDialog(ctx context.Context, logger log.Logger, client bo
 	return client.OpenDialog(triggerID, dialog)
 }
 
-// PauseNotifyIncidentByDialog Pause a notify from a Slack dialog
+// PauseNotifyIncidentByDialog Pause a notify from a Slack dialog, up to the limit of the incident status and severity
 func PauseNotifyIncidentByDialog(
 	ctx context.Context,
 	client bot.Client,
 	logger log.Logger,
 	repository model.Repository,
+	limits snooze.Limits,
 	incidentDetails bot.DialogSubmission,
 ) error {
 
@@ -84,26 +90,42 @@ func PauseNotifyIncidentByDialog(
 		submissions           = incidentDetails.Submission
 		pauseNotifyTimeText   = submissions.PauseNotifyTime
 		pauseNotifyReasonText = submissions.PauseNotifyReason
-
-		pauseNotifyTime sql.NullTime
 	)
 
-	days, err := strconv.Atoi(pauseNotifyTimeText)
+	inc, err := repository.GetIncident(ctx, channelID)
 	if err != nil {
 		logger.Error(
 			ctx,
 			log.Trace(),
-			log.Action("strconv.Atoi"),
+			log.Action("repository.GetIncident"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", channelID),
+		)
+		return err
+	}
+
+	duration, err := snooze.ParseDuration(pauseNotifyTimeText)
+	if err == nil {
+		err = limits.Check(inc, duration)
+	}
+	if err != nil {
+		logger.Info(
+			ctx,
+			log.Trace(),
+			log.Action("do_not_pause"),
 			log.Reason(err.Error()),
 			log.NewValue("channelID", channelID),
 			log.NewValue("pauseNotifyTimeText", pauseNotifyTimeText),
 		)
 
-		PostErrorAttachment(ctx, client, logger, channelID, userID, err.Error())
-		return err
+		PostInfoAttachment(ctx, client, channelID, userID, "Ops! That's not possible", err.Error())
+		return nil
 	}
 
-	pauseNotifyTime.Time = time.Now().AddDate(0, 0, days)
+	var (
+		now             = time.Now()
+		pauseNotifyTime = now.Add(duration)
+	)
 
 	logger.Info(
 		ctx,
@@ -113,46 +135,149 @@ func PauseNotifyIncidentByDialog(
 		log.NewValue("pauseNotifyTime", pauseNotifyTime),
 	)
 
-	incident := model.Incident{
-		ChannelId:    channelID,
-		SnoozedUntil: pauseNotifyTime,
+	inc.SnoozedUntil = sql.NullTime{Time: pauseNotifyTime, Valid: true}
+	err = repository.PauseNotifyIncident(ctx, &inc)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("repository.PauseNotifyIncident"),
+			log.Reason(err.Error()),
+			log.NewValue("incident", inc),
+		)
+		return err
+	}
+
+	// A new pause replaces the running one, which is recorded as resumed by its author
+	err = repository.ResumeSnoozes(ctx, inc.Id, userID)
+	if err == nil {
+		err = repository.InsertSnooze(ctx, &model.Snooze{
+			IncidentId:   inc.Id,
+			SnoozedBy:    userID,
+			Reason:       pauseNotifyReasonText,
+			SnoozedAt:    &now,
+			SnoozedUntil: &pauseNotifyTime,
+		})
+	}
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("repository.InsertSnooze"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", channelID),
+		)
 	}
 
-	err = repository.PauseNotifyIncident(ctx, &incident)
+	postAndPinMessage(client, channelID, "Hellper notifications has been paused by *"+userName+"* for *"+snooze.FormatDuration(duration)+"*, until *"+pauseNotifyTime.Format(time.RFC1123)+"* for the following reason:\n```\n"+pauseNotifyReasonText+"\n```")
+	return nil
+}
+
+// PauseNotifyHistory shows the notification pauses of the incident of the channel to the user
+func PauseNotifyHistory(ctx context.Context, client bot.Client, logger log.Logger, repository model.Repository, channelID string, userID string) error {
+	inc, err := repository.GetIncident(ctx, channelID)
 	if err != nil {
 		logger.Error(
 			ctx,
 			log.Trace(),
-			log.Action("repository.PauseNotifyIncident"),
+			log.Action("repository.GetIncident"),
 			log.Reason(err.Error()),
-			log.NewValue("incident", incident),
+			log.NewValue("channelID", channelID),
 		)
+		return err
+	}
 
-		PostErrorAttachment(ctx, client, logger, channelID, userID, err.Error())
+	snoozes, err := repository.ListSnoozes(ctx, inc.Id)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("repository.ListSnoozes"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", channelID),
+		)
+		return err
+	}
+
+	PostInfoAttachment(ctx, client, channelID, userID, "Notification pauses", formatSnoozes(snoozes, time.Now()))
+	return nil
+}
+
+// ResumeNotifyCommand resumes the paused notifications of the incident of the channel
+func ResumeNotifyCommand(ctx context.Context, client bot.Client, logger log.Logger, repository model.Repository, channelID string, userID string) error {
+	inc, err := repository.GetIncident(ctx, channelID)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("repository.GetIncident"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", channelID),
+		)
+		return err
+	}
+
+	if !inc.SnoozedUntil.Valid || !inc.SnoozedUntil.Time.After(time.Now()) {
+		PostInfoAttachment(ctx, client, channelID, userID, "Ops! That's not possible", "The notifications of this incident are not paused")
+		return nil
+	}
+
+	inc.SnoozedUntil = sql.NullTime{}
+	err = repository.PauseNotifyIncident(ctx, &inc)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("repository.PauseNotifyIncident"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", channelID),
+		)
 		return err
 	}
 
-	postAndPinMessage(client, channelID, "Hellper notifications has been paused by *"+userName+"* until *"+incident.SnoozedUntil.Time.Format(time.RFC1123)+"* for the following reason:\n```\n"+pauseNotifyReasonText+"\n```")
+	err = repository.ResumeSnoozes(ctx, inc.Id, userID)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("repository.ResumeSnoozes"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", channelID),
+		)
+	}
+
+	postMessage(client, channelID, "Hellper notifications have been resumed by <@"+userID+">")
 	return nil
 }
 
-func optionsPauseNotify(status string) (option []slack.DialogSelectOption) {
-	switch status {
-	case model.StatusOpen:
-		option = []slack.DialogSelectOption{
-			{Label: "1 day", Value: "1"},
+func formatSnoozes(snoozes []model.Snooze, now time.Time) string {
+	if len(snoozes) == 0 {
+		return "The notifications of this incident were never paused"
+	}
+
+	var text strings.Builder
+	for _, s := range snoozes {
+		text.WriteString("• <@" + s.SnoozedBy + ">")
+		if s.SnoozedAt != nil {
+			text.WriteString(" on " + s.SnoozedAt.Format(time.RFC1123))
+		}
+		if s.SnoozedUntil != nil {
+			text.WriteString(" until " + s.SnoozedUntil.Format(time.RFC1123))
 		}
-	case model.StatusResolved:
-		option = []slack.DialogSelectOption{
-			{Label: "1 day", Value: "1"},
-			{Label: "2 days", Value: "2"},
-			{Label: "3 days", Value: "3"},
+
+		switch {
+		case s.ResumedAt != nil:
+			text.WriteString(", resumed by <@" + s.ResumedBy + "> on " + s.ResumedAt.Format(time.RFC1123))
+		case s.SnoozedUntil != nil && s.SnoozedUntil.After(now):
+			text.WriteString(", running")
+		default:
+			text.WriteString(", expired")
 		}
-	default:
-		option = []slack.DialogSelectOption{
-			{Label: "1 day", Value: "1"},
+
+		if s.Reason != "" {
+			text.WriteString(": " + s.Reason)
 		}
+		text.WriteString("\n")
 	}
-
-	return
+	return text.String()
 }
@@ -0,0 +1,155 @@
+package commands_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"hellper/internal/bot"
+	"hellper/internal/commands"
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/snooze"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type pauseNotifyFixture struct {
+	testName       string
+	status         string
+	severityLevel  int64
+	snoozedUntil   sql.NullTime
+	text           string
+	expectedPaused bool
+	expectedFor    time.Duration
+
+	ctx            context.Context
+	mockLogger     log.Logger
+	mockClient     *bot.ClientMock
+	mockRepository *model.RepositoryMock
+}
+
+func (f *pauseNotifyFixture) setup(t *testing.T) {
+	var (
+		loggerMock     = log.NewLoggerMock()
+		clientMock     = bot.NewClientMock()
+		repositoryMock = model.NewRepositoryMock()
+	)
+
+	f.ctx = context.Background()
+
+	loggerMock.On("Info", f.ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+	loggerMock.On("Error", f.ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+	clientMock.On("PostMessage", "C1", mock.AnythingOfType("[]slack.MsgOption")).Return("C1", "1", nil)
+	clientMock.On("AddPin", "C1", mock.Anything).Return(nil)
+	clientMock.On("PostEphemeralContext", f.ctx, "C1", "U1", mock.AnythingOfType("[]slack.MsgOption")).Return("", nil)
+	repositoryMock.On("GetIncident", "C1").Return(model.Incident{
+		Id: 42, ChannelId: "C1", Status: f.status, SeverityLevel: f.severityLevel, SnoozedUntil: f.snoozedUntil,
+	}, nil)
+	repositoryMock.On("PauseNotifyIncident", f.ctx, mock.AnythingOfType("*model.Incident")).Return(nil)
+	repositoryMock.On("ResumeSnoozes", f.ctx, int64(42), "U1").Return(nil)
+	repositoryMock.On("InsertSnooze", f.ctx, mock.AnythingOfType("*model.Snooze")).Return(nil)
+
+	f.mockLogger = loggerMock
+	f.mockClient = clientMock
+	f.mockRepository = repositoryMock
+}
+
+func TestPauseNotifyIncidentByDialog(t *testing.T) {
+	limits := snooze.Limits{"open": 24 * time.Hour, "resolved": 72 * time.Hour, "sev0": 4 * time.Hour}
+
+	table := []pauseNotifyFixture{
+		{
+			testName:       "Pauses within the limit",
+			status:         model.StatusResolved,
+			severityLevel:  2,
+			text:           "2d",
+			expectedPaused: true,
+			expectedFor:    48 * time.Hour,
+		},
+		{
+			testName:      "Over the severity limit",
+			status:        model.StatusOpen,
+			severityLevel: 0,
+			text:          "6h",
+		},
+		{
+			testName:      "Invalid duration",
+			status:        model.StatusOpen,
+			severityLevel: 2,
+			text:          "forever",
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			f.setup(t)
+
+			err := commands.PauseNotifyIncidentByDialog(f.ctx, f.mockClient, f.mockLogger, f.mockRepository, limits, bot.DialogSubmission{
+				Channel:    bot.Channel{ID: "C1"},
+				User:       bot.User{ID: "U1", Name: "Jane"},
+				Submission: bot.Submission{PauseNotifyTime: f.text, PauseNotifyReason: "Waiting for the vendor"},
+			})
+			assert.NoError(t, err)
+
+			if !f.expectedPaused {
+				f.mockRepository.AssertNotCalled(t, "PauseNotifyIncident", mock.Anything, mock.Anything)
+				f.mockClient.AssertCalled(t, "PostEphemeralContext", f.ctx, "C1", "U1", mock.Anything)
+				return
+			}
+
+			inc := f.mockRepository.Calls[1].Arguments.Get(1).(*model.Incident)
+			assert.True(t, inc.SnoozedUntil.Valid)
+			assert.WithinDuration(t, time.Now().Add(f.expectedFor), inc.SnoozedUntil.Time, time.Minute)
+
+			f.mockRepository.AssertCalled(t, "ResumeSnoozes", f.ctx, int64(42), "U1")
+			snoozed := f.mockRepository.Calls[3].Arguments.Get(1).(*model.Snooze)
+			assert.Equal(t, "U1", snoozed.SnoozedBy)
+			assert.Equal(t, "Waiting for the vendor", snoozed.Reason)
+			f.mockClient.AssertCalled(t, "AddPin", "C1", mock.Anything)
+		})
+	}
+}
+
+func TestResumeNotifyCommand(t *testing.T) {
+	table := []pauseNotifyFixture{
+		{
+			testName:       "Resumes a running pause",
+			status:         model.StatusOpen,
+			snoozedUntil:   sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true},
+			expectedPaused: true,
+		},
+		{
+			testName:     "Expired pause",
+			status:       model.StatusOpen,
+			snoozedUntil: sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true},
+		},
+		{
+			testName: "Not paused",
+			status:   model.StatusOpen,
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			f.setup(t)
+
+			err := commands.ResumeNotifyCommand(f.ctx, f.mockClient, f.mockLogger, f.mockRepository, "C1", "U1")
+			assert.NoError(t, err)
+
+			if !f.expectedPaused {
+				f.mockRepository.AssertNotCalled(t, "ResumeSnoozes", mock.Anything, mock.Anything, mock.Anything)
+				f.mockClient.AssertCalled(t, "PostEphemeralContext", f.ctx, "C1", "U1", mock.Anything)
+				return
+			}
+
+			inc := f.mockRepository.Calls[1].Arguments.Get(1).(*model.Incident)
+			assert.False(t, inc.SnoozedUntil.Valid)
+			f.mockRepository.AssertCalled(t, "ResumeSnoozes", f.ctx, int64(42), "U1")
+			f.mockClient.AssertCalled(t, "PostMessage", "C1", mock.Anything)
+		})
+	}
+}
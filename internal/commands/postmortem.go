@@ -4,36 +4,44 @@ import (
 	"context"
 	"strconv"
 	"strings"
+	"time"
 
 	"hellper/internal/bot"
+	"hellper/internal/config"
 	filestorage "hellper/internal/file_storage"
 	"hellper/internal/log"
 	"hellper/internal/model"
+	"hellper/internal/sla"
 
 	"github.com/slack-go/slack"
 )
 
+// postMortemForceFlag moves the post mortem past the statuses in between
+const postMortemForceFlag = "--force"
+
+var postMortemCommandUsage = "Usage: `/hellper_postmortem` shows the post mortem, `/hellper_postmortem <status>` moves it to the next status or back to an earlier one, `/hellper_postmortem <status> " + postMortemForceFlag + "` skips the statuses in between.\nStatuses: `" + strings.Join(model.PostMortemStatuses, "`, `") + "`"
+
 func createPostMortem(
 	ctx context.Context,
 	logger log.Logger,
 	client bot.Client,
 	fileStorage filestorage.Driver,
-	incidentID int64,
-	incidentName string,
+	inc model.Incident,
 	repository model.Repository,
 	channelName string,
 ) (string, error) {
 
-	postMortemName := strconv.FormatInt(incidentID, 10) + " - PostMortem - " + incidentName
-	postMortemURL, err := fileStorage.CreatePostMortemDocument(ctx, postMortemName)
+	postMortemName := strconv.FormatInt(inc.Id, 10) + " - PostMortem - " + inc.Title
+	data := filestorage.NewPostMortemData(postMortemName, inc, nil, postMortemLocation(), time.Now())
+	postMortemURL, err := fileStorage.CreatePostMortemDocument(ctx, data)
 	if err != nil {
 		logger.Error(
 			ctx,
 			log.Trace(),
 			log.Action("fileStorage.CreatePostMortemDocument"),
 			log.Reason(err.Error()),
-			log.NewValue("incident_id", incidentID),
-			log.NewValue("incident_name", incidentName),
+			log.NewValue("incident_id", inc.Id),
+			log.NewValue("incident_name", inc.Title),
 			log.NewValue("channel_name", channelName),
 		)
 		return "", err
@@ -60,6 +68,36 @@ func createPostMortem(
 	return postMortemURL, nil
 }
 
+// updatePostMortem fills the post mortem document with what is known of the incident once it is resolved or closed
+func updatePostMortem(ctx context.Context, logger log.Logger, fileStorage filestorage.Driver, inc model.Incident, timeline []model.TimelineEntry) {
+	if inc.PostMortemUrl == "" {
+		return
+	}
+
+	postMortemName := strconv.FormatInt(inc.Id, 10) + " - PostMortem - " + inc.Title
+	data := filestorage.NewPostMortemData(postMortemName, inc, timeline, postMortemLocation(), time.Now())
+	err := fileStorage.UpdatePostMortemDocument(ctx, inc.PostMortemUrl, data)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("fileStorage.UpdatePostMortemDocument"),
+			log.Reason(err.Error()),
+			log.NewValue("incident_id", inc.Id),
+			log.NewValue("post_mortem_url", inc.PostMortemUrl),
+		)
+	}
+}
+
+// postMortemLocation is the timezone of the dates on the post mortems, UTC when it is invalid
+func postMortemLocation() *time.Location {
+	loc, err := time.LoadLocation(config.Env.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
 func addPostMortemURLToDB(ctx context.Context, logger log.Logger, repository model.Repository, channelName string, postMortemURL string) {
 	err := repository.AddPostMortemUrl(ctx, channelName, postMortemURL)
 	if err != nil {
@@ -71,3 +109,116 @@ func addPostMortemURLToDB(ctx context.Context, logger log.Logger, repository mod
 		)
 	}
 }
+
+// PostMortemCommand shows the post mortem of the incident of the channel, or moves it to the status on the /hellper_postmortem text
+func PostMortemCommand(
+	ctx context.Context,
+	client bot.Client,
+	logger log.Logger,
+	repository model.Repository,
+	channelID string,
+	userID string,
+	text string,
+) error {
+	logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("channelID", channelID),
+		log.NewValue("userID", userID),
+		log.NewValue("text", text),
+	)
+
+	inc, err := repository.GetIncident(ctx, channelID)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("GetIncident"),
+			log.NewValue("channelID", channelID),
+			log.NewValue("error", err),
+		)
+
+		PostErrorAttachment(ctx, client, logger, channelID, userID, err.Error())
+		return err
+	}
+
+	args := strings.Fields(text)
+	switch {
+	case len(args) == 0 || args[0] == "status" && len(args) == 1:
+		PostInfoAttachment(ctx, client, channelID, userID, "Post mortem", formatPostMortem(inc, time.Now()))
+		return nil
+	case len(args) == 1 && model.IsPostMortemStatus(args[0]):
+		return movePostMortem(ctx, client, logger, repository, inc, userID, args[0], false)
+	case len(args) == 2 && model.IsPostMortemStatus(args[0]) && args[1] == postMortemForceFlag:
+		return movePostMortem(ctx, client, logger, repository, inc, userID, args[0], true)
+	default:
+		PostInfoAttachment(ctx, client, channelID, userID, "Post mortem", postMortemCommandUsage)
+		return nil
+	}
+}
+
+func movePostMortem(
+	ctx context.Context,
+	client bot.Client,
+	logger log.Logger,
+	repository model.Repository,
+	inc model.Incident,
+	userID string,
+	status string,
+	force bool,
+) error {
+	if inc.Status == model.StatusCancel {
+		PostErrorAttachment(ctx, client, logger, inc.ChannelId, userID, "The incident was canceled, it has no post mortem")
+		return nil
+	}
+	if inc.Status == model.StatusOpen {
+		PostErrorAttachment(ctx, client, logger, inc.ChannelId, userID, "The incident is still open, the post mortem is written once it is resolved")
+		return nil
+	}
+	if !force && !model.CanMovePostMortem(inc.PostMortemStatus, status) {
+		PostErrorAttachment(
+			ctx, client, logger, inc.ChannelId, userID,
+			"The post mortem is *"+model.PostMortemStatusName(inc.PostMortemStatus)+"*, it can not be moved to *"+model.PostMortemStatusName(status)+"* without the statuses in between. Use `/hellper_postmortem "+status+" "+postMortemForceFlag+"` to skip them",
+		)
+		return nil
+	}
+
+	err := repository.UpdatePostMortemStatus(ctx, inc.ChannelId, status)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("UpdatePostMortemStatus"),
+			log.NewValue("channelID", inc.ChannelId),
+			log.NewValue("status", status),
+			log.NewValue("error", err),
+		)
+
+		PostErrorAttachment(ctx, client, logger, inc.ChannelId, userID, err.Error())
+		return err
+	}
+
+	postMessage(client, inc.ChannelId, "The post mortem of this incident is now *"+model.PostMortemStatusName(status)+"*, moved by <@"+userID+">")
+	return nil
+}
+
+// formatPostMortem describes the status, due date and document of the post mortem
+func formatPostMortem(inc model.Incident, now time.Time) string {
+	var text strings.Builder
+	text.WriteString("*Status:* " + model.PostMortemStatusName(inc.PostMortemStatus) + "\n")
+
+	if dueAt, ok := sla.DueAt(sla.EnvTargets(), inc, sla.MetricPostmortem); ok && inc.PostMortemStatus != model.PostMortemPublished {
+		text.WriteString("*Due:* " + dueAt.Format(time.RFC1123))
+		if left := dueAt.Sub(now); left < 0 {
+			text.WriteString(" (overdue by " + sla.FormatDuration(-left) + ")")
+		} else {
+			text.WriteString(" (in " + sla.FormatDuration(left) + ")")
+		}
+		text.WriteString("\n")
+	}
+
+	if inc.PostMortemUrl != "" {
+		text.WriteString("*Document:* " + inc.PostMortemUrl + "\n")
+	}
+	return text.String()
+}
@@ -0,0 +1,152 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"hellper/internal/bot"
+	"hellper/internal/commands"
+	"hellper/internal/log"
+	"hellper/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type postMortemCommandFixture struct {
+	testName         string
+	text             string
+	status           string
+	postMortemStatus string
+	updateError      error
+	expectError      bool
+	errorMessage     string
+
+	expectedStatus  string
+	expectEphemeral bool
+
+	ctx            context.Context
+	mockLogger     log.Logger
+	mockClient     *bot.ClientMock
+	mockRepository *model.RepositoryMock
+}
+
+func (f *postMortemCommandFixture) setup(t *testing.T) {
+	var (
+		loggerMock     = log.NewLoggerMock()
+		clientMock     = bot.NewClientMock()
+		repositoryMock = model.NewRepositoryMock()
+	)
+
+	f.ctx = context.Background()
+	if f.status == "" {
+		f.status = model.StatusResolved
+	}
+	if f.postMortemStatus == "" {
+		f.postMortemStatus = model.PostMortemDraft
+	}
+
+	//Logger Mock
+	loggerMock.On("Info", f.ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+	loggerMock.On("Error", f.ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+
+	//Client Mock
+	clientMock.On("PostMessage", "C1", mock.AnythingOfType("[]slack.MsgOption")).Return("", "", nil)
+	clientMock.On("PostEphemeralContext", f.ctx, "C1", "U1", mock.AnythingOfType("[]slack.MsgOption")).Return("", nil)
+
+	//Repository Mock
+	repositoryMock.On("GetIncident", "C1").Return(model.Incident{Id: 42, ChannelId: "C1", Status: f.status, PostMortemStatus: f.postMortemStatus}, nil)
+	repositoryMock.On("UpdatePostMortemStatus", f.ctx, "C1", mock.AnythingOfType("string")).Return(f.updateError)
+
+	f.mockLogger = loggerMock
+	f.mockClient = clientMock
+	f.mockRepository = repositoryMock
+}
+
+func TestPostMortemCommand(t *testing.T) {
+	table := []postMortemCommandFixture{
+		{
+			testName:        "Show the post mortem",
+			expectEphemeral: true,
+		},
+		{
+			testName:       "Move to review",
+			text:           "in_review",
+			expectedStatus: model.PostMortemInReview,
+		},
+		{
+			testName:         "Publish",
+			text:             "published",
+			status:           model.StatusClosed,
+			postMortemStatus: model.PostMortemInReview,
+			expectedStatus:   model.PostMortemPublished,
+		},
+		{
+			testName:         "Move back to draft",
+			text:             "draft",
+			postMortemStatus: model.PostMortemInReview,
+			expectedStatus:   model.PostMortemDraft,
+		},
+		{
+			testName:         "Publish without review",
+			text:             "published",
+			postMortemStatus: model.PostMortemNotStarted,
+			expectEphemeral:  true,
+		},
+		{
+			testName:         "Publish without review forced",
+			text:             "published --force",
+			postMortemStatus: model.PostMortemNotStarted,
+			expectedStatus:   model.PostMortemPublished,
+		},
+		{
+			testName:        "Open incident",
+			text:            "in_review",
+			status:          model.StatusOpen,
+			expectEphemeral: true,
+		},
+		{
+			testName:        "Canceled incident",
+			text:            "draft",
+			status:          model.StatusCancel,
+			expectEphemeral: true,
+		},
+		{
+			testName:        "Usage",
+			text:            "finished",
+			expectEphemeral: true,
+		},
+		{
+			testName:     "Update error",
+			text:         "draft",
+			updateError:  errors.New("database is down"),
+			expectError:  true,
+			errorMessage: "database is down",
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			f.setup(t)
+
+			err := commands.PostMortemCommand(f.ctx, f.mockClient, f.mockLogger, f.mockRepository, "C1", "U1", f.text)
+			if f.expectError {
+				assert.EqualError(t, err, f.errorMessage)
+				return
+			}
+			assert.NoError(t, err)
+
+			if f.expectedStatus != "" {
+				f.mockRepository.AssertCalled(t, "UpdatePostMortemStatus", f.ctx, "C1", f.expectedStatus)
+				f.mockClient.AssertCalled(t, "PostMessage", "C1", mock.Anything)
+			} else {
+				f.mockRepository.AssertNotCalled(t, "UpdatePostMortemStatus", f.ctx, mock.Anything, mock.Anything)
+			}
+			if f.expectEphemeral {
+				f.mockClient.AssertCalled(t, "PostEphemeralContext", f.ctx, "C1", "U1", mock.Anything)
+			}
+		})
+	}
+}
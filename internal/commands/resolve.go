@@ -11,8 +11,10 @@ import (
 	"hellper/internal/bot"
 	calendar "hellper/internal/calendar"
 	"hellper/internal/config"
+	filestorage "hellper/internal/file_storage"
 	"hellper/internal/log"
 	"hellper/internal/model"
+	"hellper/internal/workcalendar"
 
 	"github.com/slack-go/slack"
 )
@@ -85,7 +87,10 @@ func ResolveIncidentByDialog(
 	client bot.Client,
 	logger log.Logger,
 	repository model.Repository,
+	fileStorage filestorage.Driver,
+	background *concurrence.Background,
 	calendar calendar.Calendar,
+	workCalendar workcalendar.Calendar,
 	incidentDetails bot.DialogSubmission,
 ) error {
 	logger.Info(
@@ -153,7 +158,7 @@ func ResolveIncidentByDialog(
 	}
 
 	if hasPostMortemMeeting {
-		calendarEvent, err = getCalendarEvent(ctx, client, logger, repository, calendar, incident.EndTimestamp, channelName, channelID)
+		calendarEvent, err = getCalendarEvent(ctx, client, logger, repository, calendar, workCalendar, incident.EndTimestamp, channelName, channelID)
 		if err != nil {
 			logger.Error(
 				ctx,
@@ -192,39 +197,19 @@ func ResolveIncidentByDialog(
 	}
 	postMessage(client, userID, "", privateAttachment)
 
+	background.Go(func() {
+		exportTimelineAndUpdatePostMortem(detachedContext(ctx), logger, client, fileStorage, inc, userID)
+	})
+
 	return nil
 }
 
-func setMeetingDate(ctx context.Context, logger log.Logger, d *time.Time, postMortemGapDays int, timezone string) (string, string, error) {
-	previewPostMortemDate := d.AddDate(0, 0, postMortemGapDays)
-
-	switch previewPostMortemDate.Weekday() {
-	case time.Saturday:
-		postMortemGapDays += 2
-	case time.Sunday:
-		postMortemGapDays++
-	default:
-		break
-	}
-
-	utc, err := time.LoadLocation(timezone)
-	if err != nil {
-		logger.Error(
-			ctx,
-			log.Trace(),
-			log.Reason("time.LoadLocation"),
-			log.NewValue("timezone", timezone),
-			log.NewValue("error", err),
-		)
-		return "", "", err
-	}
-
-	setMeetingHour := time.Date(d.Year(), d.Month(), d.Day(), 15, 0, 0, 0, utc)
+// setMeetingDate books the post mortem meeting on the first working day of the region at least postMortemGapDays after d
+func setMeetingDate(region *workcalendar.Region, d *time.Time, postMortemGapDays int) (string, string) {
+	startMeeting := region.MeetingSlot(*d, postMortemGapDays)
+	endMeeting := startMeeting.Add(time.Hour)
 
-	startMeeting := setMeetingHour.AddDate(0, 0, postMortemGapDays)
-	endMeeting := startMeeting.Add(time.Hour).Format(time.RFC3339)
-
-	return startMeeting.Format(time.RFC3339), endMeeting, err
+	return startMeeting.Format(time.RFC3339), endMeeting.Format(time.RFC3339)
 }
 
 func getCalendarEvent(
@@ -233,23 +218,13 @@ func getCalendarEvent(
 	logger log.Logger,
 	repository model.Repository,
 	calendar calendar.Calendar,
+	workCalendar workcalendar.Calendar,
 	t *time.Time,
 	channelName string,
 	channelID string,
 ) (*model.Event, error) {
-	startMeeting, endMeeting, err := setMeetingDate(ctx, logger, t, config.Env.PostmortemGapDays, config.Env.Timezone)
-	if err != nil {
-		logger.Error(
-			ctx,
-			log.Trace(),
-			log.Reason("setMeetingDate"),
-			log.NewValue("error", err),
-		)
-		return nil, err
-	}
-
 	summary := "[Post Mortem] " + channelName
-	emails, _ := getUsersEmailsInConversation(ctx, client, logger, channelID)
+	emails, _ := GetUsersEmailsInConversation(ctx, client, logger, channelID)
 
 	inc, err := repository.GetIncident(ctx, channelID)
 	if err != nil {
@@ -263,6 +238,7 @@ func getCalendarEvent(
 		return nil, err
 	}
 
+	startMeeting, endMeeting := setMeetingDate(workCalendar.Region(inc.Product), t, config.Env.PostmortemGapDays)
 	calendarEvent, err := calendar.CreateCalendarEvent(ctx, startMeeting, endMeeting, summary, inc.CommanderEmail, *emails)
 	if err != nil {
 		logger.Error(
@@ -292,7 +268,7 @@ func createResolveChannelAttachment(inc model.Incident, userName string, event *
 		postMortemMessage = "A Post Mortem Meeting was not schedule, be sure to fill up the Post Mortem document."
 	} else {
 		messageText.WriteString("*Post Mortem Meeting Link:* `" + event.EventURL + "`\n\n")
-		postMortemMessage = "I have scheduled a Post Mortem Meeting for you!\nIt will be on `" + event.Start.Format(time.RFC1123) + "`.\nHere is the link: `" + event.EventURL + "`\n"
+		postMortemMessage = "I have scheduled a Post Mortem Meeting for you!\nIt will be on `" + event.Start.Format(time.RFC1123) + "`.\n" + meetingLinkText(event) + "\n"
 	}
 
 	return slack.Attachment{
@@ -333,6 +309,14 @@ func createResolveChannelAttachment(inc model.Incident, userName string, event *
 	}
 }
 
+// meetingLinkText points to the meeting, the calendars without a link to their events send the invite instead
+func meetingLinkText(event *model.Event) string {
+	if event.EventURL == "" {
+		return "The invite was sent to the participants."
+	}
+	return "Here is the link: `" + event.EventURL + "`"
+}
+
 func createResolvePrivateAttachment(inc model.Incident, event *model.Event) slack.Attachment {
 	var (
 		postMortemMessage string
@@ -346,7 +330,7 @@ func createResolvePrivateAttachment(inc model.Incident, event *model.Event) slac
 		postMortemMessage = "A Post Mortem Meeting was not schedule, be sure to fill up the Post Mortem document."
 	} else {
 		privateText.WriteString("*Post Mortem Meeting Link:* `" + event.EventURL + "`\n\n")
-		postMortemMessage = "I have scheduled a Post Mortem Meeting for you!\nIt will be on `" + event.Start.Format(time.RFC1123) + "`.\nHere is the link: `" + event.EventURL + "`\n"
+		postMortemMessage = "I have scheduled a Post Mortem Meeting for you!\nIt will be on `" + event.Start.Format(time.RFC1123) + "`.\n" + meetingLinkText(event) + "\n"
 	}
 
 	return slack.Attachment{
@@ -7,8 +7,11 @@ import (
 	"hellper/internal/bot"
 	calendar "hellper/internal/calendar"
 	"hellper/internal/commands"
+	"hellper/internal/concurrence"
+	filestorage "hellper/internal/file_storage"
 	"hellper/internal/log"
 	"hellper/internal/model"
+	"hellper/internal/workcalendar"
 	"testing"
 	"time"
 
@@ -22,11 +25,13 @@ type resolveCommandFixture struct {
 	expectError  bool
 	errorMessage string
 
-	ctx            context.Context
-	mockLogger     log.Logger
-	mockClient     bot.Client
-	mockRepository model.Repository
-	mockCalendar   calendar.Calendar
+	ctx             context.Context
+	mockLogger      log.Logger
+	mockClient      bot.Client
+	mockRepository  model.Repository
+	mockFileStorage filestorage.Driver
+	mockCalendar    calendar.Calendar
+	workCalendar    workcalendar.Calendar
 
 	triggerID       string
 	channelID       string
@@ -41,6 +46,7 @@ func (f *resolveCommandFixture) setup(t *testing.T) {
 		clientMock     = bot.NewClientMock()
 		repositoryMock = model.NewRepositoryMock()
 		calendarMock   = calendar.NewCalendarMock()
+		storageMock    = filestorage.NewFileStorageMock()
 		mockUser       = slack.User{}
 	)
 
@@ -60,6 +66,13 @@ func (f *resolveCommandFixture) setup(t *testing.T) {
 		mock.AnythingOfType("string"),      //msg
 		mock.AnythingOfType("[]log.Value"), //values
 	).Return()
+	// The timeline export runs on a context detached from the request
+	loggerMock.On(
+		"Info",
+		mock.Anything,                      //ctx
+		mock.AnythingOfType("string"),      //msg
+		mock.AnythingOfType("[]log.Value"), //values
+	).Return()
 
 	//Client Mock
 	clientMock.On(
@@ -87,6 +100,26 @@ func (f *resolveCommandFixture) setup(t *testing.T) {
 		f.ctx,                         //ctx
 		mock.AnythingOfType("string"), //userID
 	).Return(&mockUser, nil)
+	clientMock.On(
+		"GetConversationHistoryContext",
+		mock.Anything, //ctx
+		mock.AnythingOfType("*slack.GetConversationHistoryParameters"), //params
+	).Return(&slack.GetConversationHistoryResponse{}, nil)
+
+	//File Storage Mock
+	storageMock.On(
+		"UploadFile",
+		mock.Anything,                  //ctx
+		mock.AnythingOfType("string"),  //name
+		mock.AnythingOfType("string"),  //mimeType
+		mock.AnythingOfType("[]uint8"), //content
+	).Return("https://drive.example/timeline", nil)
+	storageMock.On(
+		"UpdatePostMortemDocument",
+		mock.Anything,                                     //ctx
+		mock.AnythingOfType("string"),                     //documentURL
+		mock.AnythingOfType("filestorage.PostMortemData"), //data
+	).Return(nil)
 
 	//Repository Mock
 	repositoryMock.On(
@@ -113,7 +146,12 @@ func (f *resolveCommandFixture) setup(t *testing.T) {
 	f.mockLogger = loggerMock
 	f.mockClient = clientMock
 	f.mockRepository = repositoryMock
+	f.mockFileStorage = storageMock
 	f.mockCalendar = calendarMock
+
+	workCalendar, err := workcalendar.Default("America/Sao_Paulo")
+	assert.NoError(t, err)
+	f.workCalendar = workCalendar
 }
 
 func TestResolveIncidentDialog(t *testing.T) {
@@ -181,7 +219,7 @@ func TestResolveIncidentByDialog(t *testing.T) {
 		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
 			f.setup(t)
 
-			err := commands.ResolveIncidentByDialog(f.ctx, f.mockClient, f.mockLogger, f.mockRepository, f.mockCalendar, f.incidentDetails)
+			err := commands.ResolveIncidentByDialog(f.ctx, f.mockClient, f.mockLogger, f.mockRepository, f.mockFileStorage, &concurrence.Background{}, f.mockCalendar, f.workCalendar, f.incidentDetails)
 
 			if f.expectError {
 				if err == nil {
@@ -0,0 +1,328 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"hellper/internal/bot"
+	"hellper/internal/log"
+	"hellper/internal/model"
+
+	"github.com/slack-go/slack"
+)
+
+var (
+	errInvalidRole     = errors.New("invalid role")
+	errInvalidRoleUser = errors.New("invalid user, mention the user with @")
+	userMentionParser  = regexp.MustCompile(`^<@(\w+)(\|[^>]*)?>$`)
+	roleCommandUsage   = "Usage: `/hellper_role assign <role> @user`, `/hellper_role release <role>` or `/hellper_role list`.\nRoles: `" + strings.Join(model.IncidentRoles, "`, `") + "`"
+)
+
+// IncidentRoleCommand assigns, releases or lists the roles of the incident of the channel, from the /hellper_role text
+func IncidentRoleCommand(
+	ctx context.Context,
+	client bot.Client,
+	logger log.Logger,
+	repository model.Repository,
+	channelID string,
+	userID string,
+	text string,
+) error {
+	logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("channelID", channelID),
+		log.NewValue("userID", userID),
+		log.NewValue("text", text),
+	)
+
+	inc, err := repository.GetIncident(ctx, channelID)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("GetIncident"),
+			log.NewValue("channelID", channelID),
+			log.NewValue("error", err),
+		)
+
+		PostErrorAttachment(ctx, client, logger, channelID, userID, err.Error())
+		return err
+	}
+
+	args := strings.Fields(text)
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	switch {
+	case args[0] == "list" && len(args) == 1:
+		return listIncidentRoles(ctx, client, logger, repository, inc, userID)
+	case args[0] == "assign" && len(args) == 3:
+		return assignIncidentRole(ctx, client, logger, repository, inc, userID, args[1], args[2])
+	case args[0] == "release" && len(args) == 2:
+		return releaseIncidentRole(ctx, client, logger, repository, inc, userID, args[1])
+	default:
+		PostInfoAttachment(ctx, client, channelID, userID, "Incident roles", roleCommandUsage)
+		return nil
+	}
+}
+
+func assignIncidentRole(
+	ctx context.Context,
+	client bot.Client,
+	logger log.Logger,
+	repository model.Repository,
+	inc model.Incident,
+	userID string,
+	role string,
+	mention string,
+) error {
+	if !model.IsIncidentRole(role) {
+		PostErrorAttachment(ctx, client, logger, inc.ChannelId, userID, errInvalidRole.Error()+" `"+role+"`\n"+roleCommandUsage)
+		return nil
+	}
+
+	matches := userMentionParser.FindStringSubmatch(mention)
+	if matches == nil {
+		PostErrorAttachment(ctx, client, logger, inc.ChannelId, userID, errInvalidRoleUser.Error())
+		return nil
+	}
+	holderID := matches[1]
+
+	err := repository.AssignIncidentRole(ctx, &model.IncidentRole{
+		IncidentId: inc.Id,
+		Role:       role,
+		UserId:     holderID,
+		AssignedBy: userID,
+	})
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("AssignIncidentRole"),
+			log.NewValue("channelID", inc.ChannelId),
+			log.NewValue("role", role),
+			log.NewValue("error", err),
+		)
+
+		PostErrorAttachment(ctx, client, logger, inc.ChannelId, userID, err.Error())
+		return err
+	}
+
+	postMessage(client, inc.ChannelId, "<@"+holderID+"> is now the *"+model.RoleName(role)+"* of this incident, assigned by <@"+userID+">")
+	updateRolesOnTopic(ctx, client, logger, repository, inc)
+	return nil
+}
+
+func releaseIncidentRole(
+	ctx context.Context,
+	client bot.Client,
+	logger log.Logger,
+	repository model.Repository,
+	inc model.Incident,
+	userID string,
+	role string,
+) error {
+	if !model.IsIncidentRole(role) {
+		PostErrorAttachment(ctx, client, logger, inc.ChannelId, userID, errInvalidRole.Error()+" `"+role+"`\n"+roleCommandUsage)
+		return nil
+	}
+
+	err := repository.ReleaseIncidentRole(ctx, inc.Id, role)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("ReleaseIncidentRole"),
+			log.NewValue("channelID", inc.ChannelId),
+			log.NewValue("role", role),
+			log.NewValue("error", err),
+		)
+
+		PostErrorAttachment(ctx, client, logger, inc.ChannelId, userID, err.Error())
+		return err
+	}
+
+	postMessage(client, inc.ChannelId, "The *"+model.RoleName(role)+"* role of this incident was released by <@"+userID+">")
+	updateRolesOnTopic(ctx, client, logger, repository, inc)
+	return nil
+}
+
+func listIncidentRoles(ctx context.Context, client bot.Client, logger log.Logger, repository model.Repository, inc model.Incident, userID string) error {
+	roles, err := repository.ListIncidentRoles(ctx, inc.Id)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("ListIncidentRoles"),
+			log.NewValue("channelID", inc.ChannelId),
+			log.NewValue("error", err),
+		)
+
+		PostErrorAttachment(ctx, client, logger, inc.ChannelId, userID, err.Error())
+		return err
+	}
+
+	PostInfoAttachment(ctx, client, inc.ChannelId, userID, "Incident roles", formatIncidentRoles(inc, roles))
+	return nil
+}
+
+// formatIncidentRoles returns one line per role holder, starting with the commander
+func formatIncidentRoles(inc model.Incident, roles []model.IncidentRole) string {
+	var text strings.Builder
+	if inc.CommanderId != "" {
+		text.WriteString("*Commander:* <@" + inc.CommanderId + ">\n")
+	}
+	for _, role := range sortIncidentRoles(roles) {
+		text.WriteString("*" + model.RoleName(role.Role) + ":* <@" + role.UserId + ">\n")
+	}
+	if text.Len() == 0 {
+		return "No roles assigned"
+	}
+	return text.String()
+}
+
+func sortIncidentRoles(roles []model.IncidentRole) []model.IncidentRole {
+	var sorted []model.IncidentRole
+	for _, name := range model.IncidentRoles {
+		for _, role := range roles {
+			if role.Role == name {
+				sorted = append(sorted, role)
+			}
+		}
+	}
+	return sorted
+}
+
+// createRolesAttachment lists the roles of the incident of the channel, for ShowStatus
+func createRolesAttachment(ctx context.Context, logger log.Logger, repository model.Repository, channelID string) (slack.Attachment, error) {
+	inc, err := repository.GetIncident(ctx, channelID)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("GetIncident"),
+			log.NewValue("channelID", channelID),
+			log.NewValue("error", err),
+		)
+		return slack.Attachment{}, err
+	}
+
+	roles, err := repository.ListIncidentRoles(ctx, inc.Id)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("ListIncidentRoles"),
+			log.NewValue("channelID", channelID),
+			log.NewValue("error", err),
+		)
+		return slack.Attachment{}, err
+	}
+
+	text := formatIncidentRoles(inc, roles)
+	return slack.Attachment{
+		Pretext:  "",
+		Fallback: text,
+		Text:     "",
+		Color:    "#4DA6FE",
+		Fields: []slack.AttachmentField{
+			{
+				Title: "Roles",
+				Value: text,
+			},
+		},
+	}, nil
+}
+
+const (
+	// topicMaxLength is the length Slack accepts for a channel topic, longer ones fail with too_long
+	topicMaxLength = 250
+	rolesLeftOut   = "_More roles on_ `/hellper_role list`"
+)
+
+// updateRolesOnTopic rewrites the channel topic with the roles of the incident. The topic is built from the incident
+// and the roles stored, rather than from the current topic, so it does not lose the post mortem written meanwhile
+func updateRolesOnTopic(ctx context.Context, client bot.Client, logger log.Logger, repository model.Repository, inc model.Incident) {
+	current, err := repository.GetIncident(ctx, inc.ChannelId)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("GetIncident"),
+			log.NewValue("channelID", inc.ChannelId),
+			log.NewValue("error", err),
+		)
+		return
+	}
+	inc = current
+
+	roles, err := repository.ListIncidentRoles(ctx, inc.Id)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("ListIncidentRoles"),
+			log.NewValue("channelID", inc.ChannelId),
+			log.NewValue("error", err),
+		)
+		return
+	}
+
+	topic := incidentTopic(inc, roles)
+	_, err = client.SetTopicOfConversation(inc.ChannelId, topic)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("SetTopicOfConversation"),
+			log.NewValue("channelID", inc.ChannelId),
+			log.NewValue("topic", topic),
+			log.NewValue("error", err),
+		)
+	}
+}
+
+// incidentTopic lists the war room, the post mortem, the commander and the roles of the incident.
+// The roles that do not fit on the topic are left to /hellper_role list
+func incidentTopic(inc model.Incident, roles []model.IncidentRole) string {
+	var lines []string
+	if inc.WarRoomUrl != "" {
+		lines = append(lines, "*WarRoom:* "+inc.WarRoomUrl)
+	}
+	if inc.PostMortemUrl != "" {
+		lines = append(lines, "*PostMortem:* "+inc.PostMortemUrl)
+	}
+	lines = append(lines, "*Commander:* <@"+inc.CommanderId+">")
+
+	sorted := sortIncidentRoles(roles)
+	for index, role := range sorted {
+		line := "*" + model.RoleName(role.Role) + ":* <@" + role.UserId + ">"
+		fits := topicLength(append(lines, line)) <= topicMaxLength
+		if index < len(sorted)-1 {
+			// the roles followed by others leave room for the notice of the roles left out
+			fits = topicLength(append(lines, line, rolesLeftOut)) <= topicMaxLength
+		}
+		if !fits {
+			if topicLength(append(lines, rolesLeftOut)) <= topicMaxLength {
+				lines = append(lines, rolesLeftOut)
+			}
+			break
+		}
+		lines = append(lines, line)
+	}
+
+	topic := []rune(strings.Join(lines, "\n\n"))
+	if len(topic) > topicMaxLength {
+		topic = topic[:topicMaxLength]
+	}
+	return string(topic)
+}
+
+func topicLength(lines []string) int {
+	return utf8.RuneCountInString(strings.Join(lines, "\n\n"))
+}
@@ -0,0 +1,156 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"hellper/internal/bot"
+	"hellper/internal/commands"
+	"hellper/internal/log"
+	"hellper/internal/model"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type roleCommandFixture struct {
+	testName     string
+	text         string
+	expectError  bool
+	errorMessage string
+	assignError  error
+	warRoomURL   string
+
+	expectedAssign  *model.IncidentRole
+	expectedRelease string
+	expectedTopic   string
+	expectEphemeral bool
+
+	ctx            context.Context
+	mockLogger     log.Logger
+	mockClient     *bot.ClientMock
+	mockRepository *model.RepositoryMock
+}
+
+func (f *roleCommandFixture) setup(t *testing.T) {
+	var (
+		loggerMock     = log.NewLoggerMock()
+		clientMock     = bot.NewClientMock()
+		repositoryMock = model.NewRepositoryMock()
+		channel        = &slack.Channel{}
+		warRoomURL     = "https://meet.example"
+	)
+
+	f.ctx = context.Background()
+	if f.warRoomURL != "" {
+		warRoomURL = f.warRoomURL
+	}
+
+	//Logger Mock
+	loggerMock.On("Info", f.ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+	loggerMock.On("Error", f.ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+
+	//Client Mock
+	clientMock.On("PostMessage", "C1", mock.AnythingOfType("[]slack.MsgOption")).Return("", "", nil)
+	clientMock.On("PostEphemeralContext", f.ctx, "C1", "U1", mock.AnythingOfType("[]slack.MsgOption")).Return("", nil)
+	clientMock.On("SetTopicOfConversation", "C1", mock.AnythingOfType("string")).Return(channel, nil)
+
+	//Repository Mock
+	repositoryMock.On("GetIncident", "C1").Return(model.Incident{Id: 42, ChannelId: "C1", CommanderId: "U1", WarRoomUrl: warRoomURL, PostMortemUrl: "https://docs.example"}, nil)
+	repositoryMock.On("AssignIncidentRole", f.ctx, mock.AnythingOfType("*model.IncidentRole")).Return(f.assignError)
+	repositoryMock.On("ReleaseIncidentRole", f.ctx, int64(42), mock.AnythingOfType("string")).Return(nil)
+	repositoryMock.On("ListIncidentRoles", f.ctx, int64(42)).Return([]model.IncidentRole{
+		{Role: model.RoleScribe, UserId: "U3"},
+		{Role: model.RoleCommsLead, UserId: "U2"},
+	}, nil)
+
+	f.mockLogger = loggerMock
+	f.mockClient = clientMock
+	f.mockRepository = repositoryMock
+}
+
+func TestIncidentRoleCommand(t *testing.T) {
+	table := []roleCommandFixture{
+		{
+			testName:       "Assign a role",
+			text:           "assign comms_lead <@U2|comms>",
+			expectedAssign: &model.IncidentRole{IncidentId: 42, Role: model.RoleCommsLead, UserId: "U2", AssignedBy: "U1"},
+			expectedTopic:  "*WarRoom:* https://meet.example\n\n*PostMortem:* https://docs.example\n\n*Commander:* <@U1>\n\n*Comms Lead:* <@U2>\n\n*Scribe:* <@U3>",
+		},
+		{
+			testName:        "Release a role",
+			text:            "release scribe",
+			expectedRelease: model.RoleScribe,
+			expectedTopic:   "*WarRoom:* https://meet.example\n\n*PostMortem:* https://docs.example\n\n*Commander:* <@U1>\n\n*Comms Lead:* <@U2>\n\n*Scribe:* <@U3>",
+		},
+		{
+			testName:       "Roles that do not fit on the topic",
+			text:           "assign comms_lead <@U2>",
+			warRoomURL:     "https://meet.example/" + strings.Repeat("r", 120),
+			expectedAssign: &model.IncidentRole{IncidentId: 42, Role: model.RoleCommsLead, UserId: "U2", AssignedBy: "U1"},
+			expectedTopic:  "*WarRoom:* https://meet.example/" + strings.Repeat("r", 120) + "\n\n*PostMortem:* https://docs.example\n\n*Commander:* <@U1>\n\n_More roles on_ `/hellper_role list`",
+		},
+		{
+			testName:        "List the roles",
+			text:            "list",
+			expectEphemeral: true,
+		},
+		{
+			testName:        "Unknown role",
+			text:            "assign boss <@U2>",
+			expectEphemeral: true,
+		},
+		{
+			testName:        "User without mention",
+			text:            "assign scribe someone",
+			expectEphemeral: true,
+		},
+		{
+			testName:        "Usage",
+			text:            "promote",
+			expectEphemeral: true,
+		},
+		{
+			testName:     "Assignment error",
+			text:         "assign scribe <@U2>",
+			assignError:  errors.New("database is down"),
+			expectError:  true,
+			errorMessage: "database is down",
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			f.setup(t)
+
+			err := commands.IncidentRoleCommand(f.ctx, f.mockClient, f.mockLogger, f.mockRepository, "C1", "U1", f.text)
+
+			if f.expectError {
+				assert.EqualError(t, err, f.errorMessage)
+				return
+			}
+			assert.NoError(t, err)
+
+			if f.expectedAssign != nil {
+				f.mockRepository.AssertCalled(t, "AssignIncidentRole", f.ctx, f.expectedAssign)
+			} else {
+				f.mockRepository.AssertNotCalled(t, "AssignIncidentRole", f.ctx, mock.Anything)
+			}
+			if f.expectedRelease != "" {
+				f.mockRepository.AssertCalled(t, "ReleaseIncidentRole", f.ctx, int64(42), f.expectedRelease)
+			}
+			if f.expectedTopic != "" {
+				f.mockClient.AssertCalled(t, "SetTopicOfConversation", "C1", f.expectedTopic)
+			} else {
+				f.mockClient.AssertNotCalled(t, "SetTopicOfConversation", "C1", mock.Anything)
+			}
+			if f.expectEphemeral {
+				f.mockClient.AssertCalled(t, "PostEphemeralContext", f.ctx, "C1", "U1", mock.Anything)
+			}
+		})
+	}
+}
@@ -208,11 +208,17 @@ func treatHere(msg string) string {
 }
 
 func treatUsersMentions(ctx context.Context, client bot.Client, logger log.Logger, msg string) (string, error) {
+	return treatUsersMentionsWithCache(ctx, client, logger, msg, map[string]string{})
+}
+
+// treatUsersMentionsWithCache replaces the user mentions by their names, keeping the names found on userNames,
+// so a long history doesn't look up the same user many times
+func treatUsersMentionsWithCache(ctx context.Context, client bot.Client, logger log.Logger, msg string, userNames map[string]string) (string, error) {
 	re := regexp.MustCompile(`<@(\w+)>`)
 	userIDs := re.FindAllStringSubmatch(msg, -1)
 
 	for _, id := range userIDs {
-		user, err := client.GetUserInfoContext(ctx, id[1])
+		name, err := getUserName(ctx, client, id[1], userNames)
 		if err != nil {
 			logger.Error(
 				ctx,
@@ -224,12 +230,26 @@ func treatUsersMentions(ctx context.Context, client bot.Client, logger log.Logge
 			return "", err
 		}
 
-		msg = strings.Replace(msg, id[0], "@"+user.Name, -1)
+		msg = strings.Replace(msg, id[0], "@"+name, -1)
 	}
 
 	return msg, nil
 }
 
+func getUserName(ctx context.Context, client bot.Client, userID string, userNames map[string]string) (string, error) {
+	if name, ok := userNames[userID]; ok {
+		return name, nil
+	}
+
+	user, err := client.GetUserInfoContext(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	userNames[userID] = user.Name
+	return user.Name, nil
+}
+
 // ShowStatus posts an attachment on the channel, with each pinned message from it
 func ShowStatus(
 	ctx context.Context,
@@ -263,6 +283,21 @@ func ShowStatus(
 		return err
 	}
 
-	postMessage(client, channelID, "", attachDates, attachStatus)
+	attachments := []slack.Attachment{attachDates}
+
+	// The roles are left out when they can't be read, the status is still worth posting
+	attachRoles, err := createRolesAttachment(ctx, logger, repository, channelID)
+	if err == nil {
+		attachments = append(attachments, attachRoles)
+	}
+
+	// The SLA is left out when no target applies to the incident
+	attachSLA, err := createSLAAttachment(ctx, logger, repository, channelID)
+	if err == nil && len(attachSLA.Fields) > 0 {
+		attachments = append(attachments, attachSLA)
+	}
+
+	attachments = append(attachments, attachStatus)
+	postMessage(client, channelID, "", attachments...)
 	return nil
 }
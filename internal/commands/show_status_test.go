@@ -66,6 +66,11 @@ func (f *statusCommandFixture) setup(t *testing.T) {
 		"GetIncident",
 		f.channelID, //channelID
 	).Return(model.Incident{}, nil)
+	repositoryMock.On(
+		"ListIncidentRoles",
+		f.ctx,
+		int64(0), //incidentID
+	).Return([]model.IncidentRole{{Role: model.RoleCommsLead, UserId: "U1"}}, nil)
 
 	f.mockLogger = loggerMock
 	f.mockClient = clientMock
@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/sla"
+
+	"github.com/slack-go/slack"
+)
+
+// createSLAAttachment shows the remaining time to each SLA target of the incident of the channel, for ShowStatus
+func createSLAAttachment(ctx context.Context, logger log.Logger, repository model.Repository, channelID string) (slack.Attachment, error) {
+	inc, err := repository.GetIncident(ctx, channelID)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("GetIncident"),
+			log.NewValue("channelID", channelID),
+			log.NewValue("error", err),
+		)
+		return slack.Attachment{}, err
+	}
+
+	var (
+		fields []slack.AttachmentField
+		color  = "#36a64f"
+	)
+	for _, status := range sla.Evaluate(sla.EnvTargets(), inc, time.Now()) {
+		if status.Breached() {
+			color = "#ff0000"
+		}
+		fields = append(fields, slack.AttachmentField{
+			Title: strings.ToUpper(status.Metric[:1]) + status.Metric[1:] + " SLA:",
+			Value: status.Text(),
+			Short: true,
+		})
+	}
+
+	return slack.Attachment{
+		Pretext:  "Incident SLA:",
+		Fallback: "Incident SLA",
+		Text:     "",
+		Color:    color,
+		Fields:   fields,
+	}, nil
+}
@@ -0,0 +1,142 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"hellper/internal/bot"
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/statuspage"
+)
+
+var statusPageCommandUsage = "Usage: `/hellper_statuspage` shows the public incident, `/hellper_statuspage <status> <message>` drafts an update for the " + model.RoleName(model.RoleCommsLead) + " to publish.\nStatuses: `" + strings.Join(statuspage.Statuses, "`, `") + "`"
+
+// StatusPageCommand shows the public incident of the incident of the channel or drafts an update of it, from the /hellper_statuspage text
+func StatusPageCommand(
+	ctx context.Context,
+	client bot.Client,
+	logger log.Logger,
+	repository model.Repository,
+	service *statuspage.Service,
+	channelID string,
+	userID string,
+	text string,
+) error {
+	logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("channelID", channelID),
+		log.NewValue("userID", userID),
+		log.NewValue("text", text),
+	)
+
+	if service == nil {
+		PostInfoAttachment(ctx, client, channelID, userID, "Status page", "No status page is configured, set `HELLPER_STATUSPAGE_PROVIDER` to publish the incidents.")
+		return nil
+	}
+
+	inc, err := repository.GetIncident(ctx, channelID)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("GetIncident"),
+			log.NewValue("channelID", channelID),
+			log.NewValue("error", err),
+		)
+
+		PostErrorAttachment(ctx, client, logger, channelID, userID, err.Error())
+		return err
+	}
+
+	args := strings.SplitN(strings.TrimSpace(text), " ", 2)
+	switch {
+	case args[0] == "" || args[0] == "status" && len(args) == 1:
+		summary, err := service.Summary(ctx, inc)
+		if err != nil {
+			logger.Error(
+				ctx,
+				log.Trace(),
+				log.Reason("Summary"),
+				log.NewValue("channelID", channelID),
+				log.NewValue("error", err),
+			)
+
+			PostErrorAttachment(ctx, client, logger, channelID, userID, err.Error())
+			return err
+		}
+
+		PostInfoAttachment(ctx, client, channelID, userID, "Status page", summary)
+		return nil
+	case statuspage.IsStatus(args[0]) && len(args) == 2 && strings.TrimSpace(args[1]) != "":
+		if inc.Status != model.StatusOpen {
+			PostErrorAttachment(ctx, client, logger, channelID, userID, "The incident is "+inc.Status+", the public incident is resolved with it.")
+			return nil
+		}
+
+		_, err = service.Draft(ctx, inc, userID, args[0], strings.TrimSpace(args[1]))
+		if err != nil {
+			logger.Error(
+				ctx,
+				log.Trace(),
+				log.Reason("Draft"),
+				log.NewValue("channelID", channelID),
+				log.NewValue("error", err),
+			)
+
+			PostErrorAttachment(ctx, client, logger, channelID, userID, err.Error())
+			return err
+		}
+		return nil
+	default:
+		PostInfoAttachment(ctx, client, channelID, userID, "Status page", statusPageCommandUsage)
+		return nil
+	}
+}
+
+// ReviewStatusPageUpdate publishes or discards the draft of the button pressed by the comms lead
+func ReviewStatusPageUpdate(ctx context.Context, client bot.Client, logger log.Logger, service *statuspage.Service, submission bot.DialogSubmission) error {
+	var (
+		channelID = submission.Channel.ID
+		userID    = submission.User.ID
+	)
+
+	logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("channelID", channelID),
+		log.NewValue("userID", userID),
+		log.NewValue("actions", submission.Actions),
+	)
+
+	if service == nil {
+		return errors.New("no status page is configured")
+	}
+	if len(submission.Actions) == 0 {
+		return errors.New("the status page review has no action")
+	}
+
+	action := submission.Actions[0]
+	updateID, err := strconv.ParseInt(action.Value, 10, 64)
+	if err != nil {
+		return errors.New("invalid status page update " + action.Value)
+	}
+
+	err = service.Review(ctx, channelID, userID, updateID, action.Name == statuspage.ActionPublish)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("Review"),
+			log.NewValue("channelID", channelID),
+			log.NewValue("updateID", updateID),
+			log.NewValue("error", err),
+		)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package commands_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"hellper/internal/bot"
+	"hellper/internal/commands"
+	"hellper/internal/concurrence"
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/statuspage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStatusPageCommand(t *testing.T) {
+	table := []struct {
+		testName        string
+		text            string
+		status          string
+		withoutService  bool
+		expectedDraft   *model.StatusPageUpdate
+		expectEphemeral bool
+	}{
+		{
+			testName:      "Draft an update",
+			text:          "identified  The payment gateway is down",
+			status:        model.StatusOpen,
+			expectedDraft: &model.StatusPageUpdate{IncidentId: 42, Status: statuspage.StatusIdentified, Body: "The payment gateway is down", State: model.StatusPageUpdatePending, DraftedBy: "U1"},
+		},
+		{
+			testName:        "Show the public incident",
+			status:          model.StatusOpen,
+			expectEphemeral: true,
+		},
+		{
+			testName:        "Invalid status shows the usage",
+			text:            "fixed it works",
+			status:          model.StatusOpen,
+			expectEphemeral: true,
+		},
+		{
+			testName:        "Update without message shows the usage",
+			text:            "monitoring",
+			status:          model.StatusOpen,
+			expectEphemeral: true,
+		},
+		{
+			testName:        "Resolved incident",
+			text:            "monitoring A fix is deployed",
+			status:          model.StatusResolved,
+			expectEphemeral: true,
+		},
+		{
+			testName:        "Status page not configured",
+			text:            "monitoring A fix is deployed",
+			withoutService:  true,
+			expectEphemeral: true,
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			var (
+				ctx            = context.Background()
+				loggerMock     = log.NewLoggerMock()
+				clientMock     = bot.NewClientMock()
+				repositoryMock = model.NewRepositoryMock()
+				service        *statuspage.Service
+			)
+
+			loggerMock.On("Info", ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+			loggerMock.On("Error", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+			clientMock.On("PostMessage", "C1", mock.AnythingOfType("[]slack.MsgOption")).Return("", "", nil)
+			clientMock.On("PostEphemeralContext", ctx, "C1", "U1", mock.AnythingOfType("[]slack.MsgOption")).Return("", nil)
+			repositoryMock.On("GetIncident", "C1").Return(model.Incident{Id: 42, ChannelId: "C1", Status: f.status}, nil)
+			repositoryMock.On("InsertStatusPageUpdate", ctx, mock.AnythingOfType("*model.StatusPageUpdate")).Return(nil)
+			repositoryMock.On("ListIncidentRoles", ctx, int64(42)).Return([]model.IncidentRole{{Role: model.RoleCommsLead, UserId: "U2"}}, nil)
+			repositoryMock.On("ListStatusPageUpdates", ctx, int64(42)).Return(nil, nil)
+
+			if !f.withoutService {
+				service = statuspage.NewService(loggerMock, clientMock, repositoryMock, &concurrence.Background{}, statuspage.NewFake(), statuspage.Components{})
+			}
+
+			err := commands.StatusPageCommand(ctx, clientMock, loggerMock, repositoryMock, service, "C1", "U1", f.text)
+			assert.NoError(t, err)
+
+			if f.expectedDraft != nil {
+				repositoryMock.AssertCalled(t, "InsertStatusPageUpdate", ctx, f.expectedDraft)
+				clientMock.AssertCalled(t, "PostMessage", "C1", mock.AnythingOfType("[]slack.MsgOption"))
+			} else {
+				repositoryMock.AssertNotCalled(t, "InsertStatusPageUpdate", mock.Anything, mock.Anything)
+			}
+			if f.expectEphemeral {
+				clientMock.AssertCalled(t, "PostEphemeralContext", ctx, "C1", "U1", mock.AnythingOfType("[]slack.MsgOption"))
+			}
+		})
+	}
+}
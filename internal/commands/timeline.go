@@ -0,0 +1,344 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"hellper/internal/bot"
+	filestorage "hellper/internal/file_storage"
+	"hellper/internal/log"
+	"hellper/internal/model"
+
+	"github.com/slack-go/slack"
+)
+
+const timelineVersion = 1
+
+type timelineExport struct {
+	Version    int                   `json:"version"`
+	IncidentID int64                 `json:"incident_id"`
+	Title      string                `json:"title"`
+	Product    string                `json:"product"`
+	Channel    string                `json:"channel"`
+	ExportedAt time.Time             `json:"exported_at"`
+	Entries    []model.TimelineEntry `json:"entries"`
+}
+
+// detachedContext keeps the transaction ID of the request on a context that is not canceled when the request ends,
+// so the work started by a Slack request can outlive it
+func detachedContext(ctx context.Context) context.Context {
+	return log.ContextWithTID(context.Background(), log.TIDFromContext(ctx))
+}
+
+// exportTimeline stores the channel history of the incident, in Markdown and JSON, next to the postmortem document
+// and posts the links on the incident channel. The history is returned once it is read, even when it fails to be stored
+func exportTimeline(ctx context.Context, logger log.Logger, client bot.Client, fileStorage filestorage.Driver, inc model.Incident) ([]model.TimelineEntry, error) {
+	logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("incident_id", inc.Id),
+		log.NewValue("channel_id", inc.ChannelId),
+	)
+
+	entries, err := getChannelTimeline(ctx, logger, client, inc.ChannelId)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("getChannelTimeline"),
+			log.Reason(err.Error()),
+			log.NewValue("channel_id", inc.ChannelId),
+		)
+		return nil, err
+	}
+
+	export := timelineExport{
+		Version:    timelineVersion,
+		IncidentID: inc.Id,
+		Title:      inc.Title,
+		Product:    inc.Product,
+		Channel:    inc.ChannelName,
+		ExportedAt: time.Now().UTC(),
+		Entries:    entries,
+	}
+
+	jsonContent, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("json.MarshalIndent"),
+			log.Reason(err.Error()),
+			log.NewValue("channel_id", inc.ChannelId),
+		)
+		return entries, err
+	}
+
+	timelineName := strconv.FormatInt(inc.Id, 10) + " - Timeline - " + inc.Title
+
+	markdownURL, err := fileStorage.UploadFile(ctx, timelineName+".md", "text/markdown", []byte(renderTimelineMarkdown(export)))
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("fileStorage.UploadFile"),
+			log.Reason(err.Error()),
+			log.NewValue("timeline_name", timelineName),
+		)
+		return entries, err
+	}
+
+	jsonURL, err := fileStorage.UploadFile(ctx, timelineName+".json", "application/json", jsonContent)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("fileStorage.UploadFile"),
+			log.Reason(err.Error()),
+			log.NewValue("timeline_name", timelineName),
+		)
+		return entries, err
+	}
+
+	attachment := slack.Attachment{
+		Pretext:  "",
+		Fallback: "*Timeline:* " + markdownURL + "\n",
+		Text:     "",
+		Color:    "#4DA6FE",
+		Fields: []slack.AttachmentField{
+			{
+				Title: "Markdown",
+				Value: markdownURL,
+			},
+			{
+				Title: "JSON",
+				Value: jsonURL,
+			},
+		},
+	}
+
+	return entries, postMessage(client, inc.ChannelId, "The incident timeline was exported to the Post Mortem storage", attachment)
+}
+
+// exportTimelineAndUpdatePostMortem exports the timeline of the incident and fills its post mortem with it,
+// telling the user when the export fails
+func exportTimelineAndUpdatePostMortem(ctx context.Context, logger log.Logger, client bot.Client, fileStorage filestorage.Driver, inc model.Incident, userID string) {
+	entries, err := exportTimeline(ctx, logger, client, fileStorage, inc)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Reason("exportTimeline"),
+			log.NewValue("channelID", inc.ChannelId),
+			log.NewValue("error", err),
+		)
+		PostErrorAttachment(ctx, client, logger, inc.ChannelId, userID, "The incident timeline could not be exported: "+err.Error())
+	}
+
+	updatePostMortem(ctx, logger, fileStorage, inc, entries)
+}
+
+// getChannelTimeline reads the whole channel history, including the threads, from the oldest message to the newest one
+func getChannelTimeline(ctx context.Context, logger log.Logger, client bot.Client, channelID string) ([]model.TimelineEntry, error) {
+	var (
+		entries   []model.TimelineEntry
+		userNames = map[string]string{}
+		params    = slack.GetConversationHistoryParameters{ChannelID: channelID, Limit: 200}
+	)
+
+	for {
+		history, err := client.GetConversationHistoryContext(ctx, &params)
+		if err != nil {
+			logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("client.GetConversationHistoryContext"),
+				log.Reason(err.Error()),
+				log.NewValue("channelID", channelID),
+			)
+			return nil, err
+		}
+
+		for _, msg := range history.Messages {
+			entry, err := newTimelineEntry(ctx, logger, client, msg, userNames)
+			if err != nil {
+				return nil, err
+			}
+
+			if msg.ReplyCount > 0 {
+				entry.Replies, err = getThreadTimeline(ctx, logger, client, channelID, msg.Timestamp, userNames)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			entries = append(entries, entry)
+		}
+
+		if !history.HasMore || history.ResponseMetaData.NextCursor == "" {
+			break
+		}
+		params.Cursor = history.ResponseMetaData.NextCursor
+	}
+
+	sortTimeline(entries)
+	return entries, nil
+}
+
+func getThreadTimeline(
+	ctx context.Context,
+	logger log.Logger,
+	client bot.Client,
+	channelID string,
+	threadTimestamp string,
+	userNames map[string]string,
+) ([]model.TimelineEntry, error) {
+	var (
+		replies []model.TimelineEntry
+		params  = slack.GetConversationRepliesParameters{ChannelID: channelID, Timestamp: threadTimestamp, Limit: 200}
+	)
+
+	for {
+		msgs, hasMore, cursor, err := client.GetConversationRepliesContext(ctx, &params)
+		if err != nil {
+			logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("client.GetConversationRepliesContext"),
+				log.Reason(err.Error()),
+				log.NewValue("channelID", channelID),
+				log.NewValue("threadTimestamp", threadTimestamp),
+			)
+			return nil, err
+		}
+
+		for _, msg := range msgs {
+			// The parent message is returned with its replies
+			if msg.Timestamp == threadTimestamp {
+				continue
+			}
+
+			entry, err := newTimelineEntry(ctx, logger, client, msg, userNames)
+			if err != nil {
+				return nil, err
+			}
+			replies = append(replies, entry)
+		}
+
+		if !hasMore || cursor == "" {
+			break
+		}
+		params.Cursor = cursor
+	}
+
+	sortTimeline(replies)
+	return replies, nil
+}
+
+func newTimelineEntry(ctx context.Context, logger log.Logger, client bot.Client, msg slack.Message, userNames map[string]string) (model.TimelineEntry, error) {
+	timestamp, err := convertTimestamp(msg.Timestamp)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("convertTimestamp"),
+			log.Reason(err.Error()),
+			log.NewValue("timestamp", msg.Timestamp),
+		)
+		return model.TimelineEntry{}, err
+	}
+
+	// Messages posted by hellper carry their content on the attachments
+	text := msg.Text
+	if text == "" && len(msg.Attachments) > 0 {
+		text = msg.Attachments[0].Pretext
+		if text == "" {
+			text = msg.Attachments[0].Fallback
+		}
+	}
+
+	text = treatHere(text)
+	if treated, err := treatUsersMentionsWithCache(ctx, client, logger, text, userNames); err == nil {
+		text = treated
+	} else {
+		// A mention that can't be resolved keeps its raw form instead of failing the whole export
+		logger.Info(
+			ctx,
+			log.Trace(),
+			log.Action("treatUsersMentionsWithCache"),
+			log.Reason(err.Error()),
+			log.NewValue("timestamp", msg.Timestamp),
+		)
+	}
+
+	userName := msg.Username
+	if msg.User != "" {
+		userName, err = getUserName(ctx, client, msg.User, userNames)
+		if err != nil {
+			// Authors that left the workspace still belong to the timeline
+			logger.Info(
+				ctx,
+				log.Trace(),
+				log.Action("getUserName"),
+				log.Reason(err.Error()),
+				log.NewValue("user", msg.User),
+			)
+			userName = msg.User
+			userNames[msg.User] = userName
+		}
+	}
+	if userName == "" {
+		userName = msg.BotID
+	}
+
+	return model.TimelineEntry{
+		Timestamp: timestamp.UTC(),
+		UserID:    msg.User,
+		UserName:  userName,
+		Text:      text,
+		Pinned:    len(msg.PinnedTo) > 0,
+	}, nil
+}
+
+func sortTimeline(entries []model.TimelineEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+}
+
+func renderTimelineMarkdown(export timelineExport) string {
+	var markdown strings.Builder
+
+	markdown.WriteString("# Timeline - " + export.Title + "\n\n")
+	markdown.WriteString("*Incident:* " + strconv.FormatInt(export.IncidentID, 10) + "\n")
+	markdown.WriteString("*Product:* " + export.Product + "\n")
+	markdown.WriteString("*Channel:* #" + export.Channel + "\n")
+	markdown.WriteString("*Exported at:* " + export.ExportedAt.Format(time.RFC1123) + "\n\n")
+
+	for _, entry := range export.Entries {
+		writeTimelineEntryMarkdown(&markdown, entry, "")
+		for _, reply := range entry.Replies {
+			writeTimelineEntryMarkdown(&markdown, reply, "  ")
+		}
+	}
+
+	return markdown.String()
+}
+
+func writeTimelineEntryMarkdown(markdown *strings.Builder, entry model.TimelineEntry, indent string) {
+	markdown.WriteString(indent + "- **" + entry.Timestamp.Format("2006-01-02 15:04:05 MST") + "** @" + entry.UserName)
+	if entry.Pinned {
+		markdown.WriteString(" :pushpin:")
+	}
+	markdown.WriteString(": ")
+
+	lines := strings.Split(entry.Text, "\n")
+	markdown.WriteString(lines[0] + "\n")
+	for _, line := range lines[1:] {
+		markdown.WriteString(indent + "  " + line + "\n")
+	}
+}
@@ -0,0 +1,144 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"hellper/internal/bot/slack/slackfake"
+	filestorage "hellper/internal/file_storage"
+	"hellper/internal/log"
+	"hellper/internal/model"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportTimeline(t *testing.T) {
+	var (
+		ctx         = context.Background()
+		server      = slackfake.NewServer()
+		client      = server.Client()
+		loggerMock  = log.NewLoggerMock()
+		storageMock = filestorage.NewFileStorageMock()
+		uploads     = map[string][]byte{}
+	)
+	defer server.Close()
+
+	loggerMock.On("Info", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+	loggerMock.On("Error", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+	storageMock.On(
+		"UploadFile",
+		ctx,
+		mock.AnythingOfType("string"),
+		mock.AnythingOfType("string"),
+		mock.AnythingOfType("[]uint8"),
+	).Run(func(args mock.Arguments) {
+		uploads[args.String(1)] = args.Get(3).([]byte)
+	}).Return("https://drive.example/timeline", nil)
+
+	server.AddUser(slack.User{ID: "U1", Name: "commander"})
+	server.AddUser(slack.User{ID: "U2", Name: "responder"})
+	channelID := server.AddChannel("inc-timeline", "U1", "U2")
+
+	parent := server.PostUserMessage(channelID, "U1", "<!here> checking the queue with <@U2>", "")
+	server.PostUserMessage(channelID, "U2", "found a stuck worker\nrestarting it", parent)
+	server.PostUserMessage(channelID, "U1", "back to normal, thanks <@U9>", "")
+
+	inc := model.Incident{Id: 7, Title: "Queue delayed", Product: "Product A", ChannelName: "inc-timeline", ChannelId: channelID}
+	entries, err := exportTimeline(ctx, loggerMock, client, storageMock, inc)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	var export timelineExport
+	require.NoError(t, json.Unmarshal(uploads["7 - Timeline - Queue delayed.json"], &export))
+	assert.Equal(t, timelineVersion, export.Version)
+	require.Len(t, export.Entries, 2)
+	assert.Equal(t, "commander", export.Entries[0].UserName)
+	assert.Equal(t, "@here checking the queue with @responder", export.Entries[0].Text)
+	require.Len(t, export.Entries[0].Replies, 1)
+	assert.Equal(t, "responder", export.Entries[0].Replies[0].UserName)
+	assert.Equal(t, "back to normal, thanks <@U9>", export.Entries[1].Text)
+
+	markdown := string(uploads["7 - Timeline - Queue delayed.md"])
+	assert.True(t, strings.HasPrefix(markdown, "# Timeline - Queue delayed\n"))
+	assert.Contains(t, markdown, "@commander: @here checking the queue with @responder\n")
+	assert.Contains(t, markdown, "  - **")
+	assert.Contains(t, markdown, "@responder: found a stuck worker\n    restarting it\n")
+
+	messages := server.Messages(channelID)
+	assert.Equal(t, "The incident timeline was exported to the Post Mortem storage", messages[len(messages)-1].Text)
+}
+
+func TestExportTimelineAndUpdatePostMortem(t *testing.T) {
+	table := []struct {
+		testName       string
+		postMortemURL  string
+		uploadError    error
+		expectedUpdate bool
+	}{
+		{
+			testName:       "Post mortem filled with the timeline",
+			postMortemURL:  "https://docs.example/postmortem",
+			expectedUpdate: true,
+		},
+		{
+			testName: "Incident without post mortem",
+		},
+		{
+			testName:       "Failed export is reported to the user",
+			postMortemURL:  "https://docs.example/postmortem",
+			uploadError:    errors.New("storage unavailable"),
+			expectedUpdate: true,
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			var (
+				ctx         = context.Background()
+				server      = slackfake.NewServer()
+				client      = server.Client()
+				loggerMock  = log.NewLoggerMock()
+				storageMock = filestorage.NewFileStorageMock()
+			)
+			defer server.Close()
+
+			loggerMock.On("Info", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+			loggerMock.On("Error", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+			storageMock.On("UploadFile", ctx, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("[]uint8")).Return("https://drive.example/timeline", f.uploadError)
+			storageMock.On("UpdatePostMortemDocument", ctx, f.postMortemURL, mock.AnythingOfType("filestorage.PostMortemData")).Return(nil)
+
+			server.AddUser(slack.User{ID: "U1", Name: "commander"})
+			channelID := server.AddChannel("inc-timeline", "U1")
+			server.PostUserMessage(channelID, "U1", "restarting the worker", "")
+
+			inc := model.Incident{Id: 7, Title: "Queue delayed", ChannelId: channelID, RootCause: "Stuck worker", PostMortemUrl: f.postMortemURL}
+			exportTimelineAndUpdatePostMortem(ctx, loggerMock, client, storageMock, inc, "U1")
+
+			var reported bool
+			for _, message := range server.Messages(channelID) {
+				if message.Ephemeral && len(message.Attachments) > 0 {
+					reported = strings.Contains(message.Attachments[0].Fields[0].Value, "storage unavailable")
+				}
+			}
+			assert.Equal(t, f.uploadError != nil, reported)
+
+			if !f.expectedUpdate {
+				storageMock.AssertNotCalled(t, "UpdatePostMortemDocument", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			data := storageMock.Calls[len(storageMock.Calls)-1].Arguments.Get(2).(filestorage.PostMortemData)
+			assert.Equal(t, "7 - PostMortem - Queue delayed", data.Name)
+			assert.Equal(t, "Stuck worker", data.RootCause)
+			require.Len(t, data.Timeline, 1)
+			assert.Contains(t, data.TimelineText, "commander: restarting the worker")
+		})
+	}
+}
@@ -118,7 +118,8 @@ func getUsersInConversation(
 	return &users, err
 }
 
-func getUsersEmailsInConversation(
+// GetUsersEmailsInConversation lists the emails of the members of the channel
+func GetUsersEmailsInConversation(
 	ctx context.Context,
 	client bot.Client,
 	logger log.Logger,
@@ -0,0 +1,32 @@
+package concurrence
+
+import (
+	"context"
+	"sync"
+)
+
+// Background runs the work that outlives the request starting it, so the shutdown can wait for it
+type Background struct {
+	wg sync.WaitGroup
+}
+
+// Go runs fn on a new goroutine tracked by the background
+func (b *Background) Go(fn func()) {
+	WithWaitGroup(&b.wg, fn)
+}
+
+// Wait blocks until the work started with Go is done or the context ends
+func (b *Background) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -14,28 +14,98 @@ type environment struct {
 	ProductList        string
 	Language           string
 	MatrixHost         string
+	MatrixRoomID       string
+	WarRoomProvider    string
+	WarRoomURLTemplate string
+	WarRoomMinutes     int
 	SupportTeam        string
 
 	BindAddress                   string
 	Database                      string
 	DSN                           string
 	GoogleCredentials             string
+	GoogleDelegatedUser           string
 	GoogleDriveToken              string
 	GoogleDriveFileID             string
 	GoogleCalendarToken           string
 	GoogleCalendarID              string
+	Calendar                      string
+	CalendarICSDelivery           string
+	CalendarICSChannelID          string
+	CalDAVURL                     string
+	CalDAVUsername                string
+	CalDAVPassword                string
 	PostmortemGapDays             int
 	ReminderOpenStatusSeconds     int
 	ReminderResolvedStatusSeconds int
 	ReminderOpenNotifyMsg         string
 	ReminderResolvedNotifyMsg     string
+	ReminderPolicyFile            string
 	Environment                   string
 	FileStorage                   string
+	FileStorageDir                string
+	PostMortemTemplateFile        string
+	PublicURL                     string
+	S3Endpoint                    string
+	S3Bucket                      string
+	S3Region                      string
+	S3AccessKeyID                 string
+	S3SecretAccessKey             string
 	NotifyOnResolve               bool
 	NotifyOnClose                 bool
 	NotifyOnCancel                bool
 	Timezone                      string
+	WorkCalendarFile              string
 	SLAHoursToClose               int
+	SLATargets                    string
+	SLAAlertChannelID             string
+	PostMortemRemindBeforeHours   int
+	SnoozeLimits                  string
+	SlackMaxRetries               int
+	AuthorizationPolicy           string
+	SchedulerEnabled              bool
+	SchedulerReminderSeconds      int
+	SchedulerSLASeconds           int
+	SchedulerSLABreachSeconds     int
+	SchedulerPostMortemSeconds    int
+	SchedulerReportSeconds        int
+	SchedulerReportChannelID      string
+	SchedulerDigestSeconds        int
+	SchedulerDigestChannelID      string
+	ShutdownTimeoutSeconds        int
+	WebhooksFile                  string
+	WebhookMaxAttempts            int
+	WebhookTimeoutSeconds         int
+	SMTPHost                      string
+	SMTPPort                      int
+	SMTPUsername                  string
+	SMTPPassword                  string
+	EmailFrom                     string
+	EmailLists                    string
+	EmailParticipants             bool
+	StatusPageProvider            string
+	StatusPageURL                 string
+	StatusPageID                  string
+	StatusPageAPIKey              string
+	StatusPageComponents          string
+	PagerProvider                 string
+	PagerURL                      string
+	PagerRoutingKeys              string
+	PagerEventURL                 string
+	AlertsToken                   string
+	AlertsCommander               string
+	AlertsProductLabel            string
+	AlertsDefaultProduct          string
+	AlertsSeverityLabel           string
+	AlertsSeverities              string
+	AlertsDefaultSeverity         int
+	AlertsAutoResolve             bool
+	IssueTrackerProvider          string
+	IssueTrackerURL               string
+	IssueTrackerProject           string
+	IssueTrackerToken             string
+	IssueTrackerLabels            string
+	SchedulerActionItemsSeconds   int
 }
 
 func newEnvironment() environment {
@@ -46,6 +116,10 @@ func newEnvironment() environment {
 
 	vars.StringVar(&env.BindAddress, "hellper_bind_address", ":8080", "Hellper local bind address")
 	vars.StringVar(&env.MatrixHost, "hellper_matrix_host", "", "Matrix host")
+	vars.StringVar(&env.MatrixRoomID, "hellper_matrix_room_id", "", "Matrix room shared by every incident, each incident has its own room when empty")
+	vars.StringVar(&env.WarRoomProvider, "hellper_warroom_provider", "", "War room of the incidents, matrix, template or google_meet, matrix in production and staging when empty and there is a Matrix host")
+	vars.StringVar(&env.WarRoomURLTemplate, "hellper_warroom_url_template", "", "URL of the template war rooms, {channel_name} and {channel_id} are replaced by those of the incident")
+	vars.IntVar(&env.WarRoomMinutes, "hellper_warroom_minutes", 60, "Minutes the Google Meet war room is booked for on the calendar")
 	vars.StringVar(&env.SupportTeam, "hellper_support_team", "", "Support team identifier")
 	vars.StringVar(&env.OAuthToken, "hellper_oauth_token", "", "Token to execute oauth actions")
 	vars.StringVar(&env.SlackSigningSecret, "hellper_slack_signing_secret", "", "Slack signs the requests confirm that each request comes from Slack by verifying its unique signature")
@@ -54,22 +128,92 @@ func newEnvironment() environment {
 	vars.StringVar(&env.Database, "hellper_database", "postgres", "Hellper database provider")
 	vars.StringVar(&env.DSN, "hellper_dsn", "", "Hellper database provider")
 	vars.StringVar(&env.GoogleCredentials, "hellper_google_credentials", "", "Google Credentials")
+	vars.StringVar(&env.GoogleDelegatedUser, "hellper_google_delegated_user", "", "User impersonated by the Google service account through the domain-wide delegation")
 	vars.StringVar(&env.GoogleDriveToken, "hellper_google_drive_token", "", "Google Drive Token")
 	vars.StringVar(&env.GoogleDriveFileID, "hellper_google_drive_file_id", "", "Google Drive FileId")
 	vars.StringVar(&env.GoogleCalendarToken, "hellper_google_calendar_token", "", "Google Calendar Token")
 	vars.StringVar(&env.GoogleCalendarID, "hellper_google_calendar_id", "", "Calendar ID to create a event")
+	vars.StringVar(&env.Calendar, "hellper_calendar", "google_calendar", "Calendar of the post mortem meetings (google_calendar, ics or none)")
+	vars.StringVar(&env.CalendarICSDelivery, "hellper_calendar_ics_delivery", "email", "How the ics calendar sends its invites (email, slack or caldav)")
+	vars.StringVar(&env.CalendarICSChannelID, "hellper_calendar_ics_channel_id", "", "Slack channel receiving the invites of the ics calendar")
+	vars.StringVar(&env.CalDAVURL, "hellper_caldav_url", "", "URL of the CalDAV calendar collection receiving the invites of the ics calendar")
+	vars.StringVar(&env.CalDAVUsername, "hellper_caldav_username", "", "CalDAV username, the server is used without authentication when empty")
+	vars.StringVar(&env.CalDAVPassword, "hellper_caldav_password", "", "CalDAV password")
 	vars.IntVar(&env.PostmortemGapDays, "hellper_postmortem_gap_days", 2, "Gap in days between resolve and postmortem event")
 	vars.IntVar(&env.ReminderOpenStatusSeconds, "hellper_reminder_open_status_seconds", 7200, "Contains the time for the stat reminder to be triggered when status is open, by default the time is 2 hours if there is no variable")
 	vars.IntVar(&env.ReminderResolvedStatusSeconds, "hellper_reminder_resolved_status_seconds", 86400, "Contains the time for the stat reminder to be triggered when status is resolved, by default the time is 24 hours if there is no variable")
 	vars.StringVar(&env.ReminderOpenNotifyMsg, "hellper_reminder_open_notify_msg", "Incident Status: Open - Update the status of this incident, just pin a message with status on the channel.", "Notify message when status is open")
 	vars.StringVar(&env.ReminderResolvedNotifyMsg, "hellper_reminder_resolved_notify_msg", "Incident Status: Resolved - Update the status of this incident, just pin a message with status on the channel.", "Notify message when status is resolved")
+	vars.StringVar(&env.ReminderPolicyFile, "hellper_reminder_policy_file", "", "YAML file with the reminder rules by status and severity, the HELLPER_REMINDER_* variables are used when empty")
 	vars.StringVar(&env.Environment, "hellper_environment", "", "Hellper current environment")
 	vars.StringVar(&env.FileStorage, "file_storage", "google_drive", "Hellper file storage for postmortem document")
+	vars.StringVar(&env.FileStorageDir, "hellper_file_storage_dir", "files", "Directory of the local file storage")
+	vars.StringVar(&env.PostMortemTemplateFile, "hellper_postmortem_template_file", "", "Markdown post mortem template of the local and s3 file storages, a built-in template is used when empty")
+	vars.StringVar(&env.PublicURL, "hellper_public_url", "", "URL where hellper is reached, serving the files of the local and s3 file storages")
+	vars.StringVar(&env.S3Endpoint, "hellper_s3_endpoint", "", "URL of the S3 compatible object store of the s3 file storage")
+	vars.StringVar(&env.S3Bucket, "hellper_s3_bucket", "", "Bucket of the s3 file storage")
+	vars.StringVar(&env.S3Region, "hellper_s3_region", "us-east-1", "Region of the S3 bucket")
+	vars.StringVar(&env.S3AccessKeyID, "hellper_s3_access_key_id", "", "Access key id of the S3 bucket")
+	vars.StringVar(&env.S3SecretAccessKey, "hellper_s3_secret_access_key", "", "Secret access key of the S3 bucket")
 	vars.BoolVar(&env.NotifyOnResolve, "hellper_notify_on_resolve", true, "Notify the Product channel when resolve the incident")
 	vars.BoolVar(&env.NotifyOnClose, "hellper_notify_on_close", true, "Notify the Product channel when close the incident")
 	vars.BoolVar(&env.NotifyOnCancel, "hellper_notify_on_cancel", true, "Notify the Product channel when cancel the incident")
 	vars.StringVar(&env.Timezone, "timezone", "America/Sao_Paulo", "The local time of a region or a country used to create a event.")
+	vars.StringVar(&env.WorkCalendarFile, "hellper_work_calendar_file", "", "YAML file with the business hours, quiet hours and holidays of each region, the timezone from Monday to Friday when empty")
 	vars.IntVar(&env.SLAHoursToClose, "hellper_sla_hours_to_close", 168, "SLA hours to close")
+	vars.StringVar(&env.SLATargets, "hellper_sla_targets", "", "SLA targets of each severity, e.g. sev0=acknowledge:15m,resolve:4h,postmortem:72h;*=close:168h, the close target defaults to HELLPER_SLA_HOURS_TO_CLOSE")
+	vars.StringVar(&env.SLAAlertChannelID, "hellper_sla_alert_channel_id", "", "Channel also receiving the SLA breach alerts, they are only sent to the incident channel when empty")
+	vars.IntVar(&env.PostMortemRemindBeforeHours, "hellper_postmortem_remind_before_hours", 24, "Hours before the post mortem due date the commander starts being reminded")
+	vars.StringVar(&env.SnoozeLimits, "hellper_snooze_limits", "open=1d;resolved=3d", "Longest notification pause by status, severity or * for all incidents, e.g. open=1d;resolved=3d;sev0=4h")
+	vars.IntVar(&env.SlackMaxRetries, "hellper_slack_max_retries", 3, "How many times a Slack call is retried after a rate limit or server error")
+
+	vars.StringVar(&env.AuthorizationPolicy, "hellper_authorization_policy", "", "Who may resolve, close or cancel an incident, e.g. resolve=commander,author,usergroup:S0123ABC;close=commander")
+
+	vars.BoolVar(&env.SchedulerEnabled, "hellper_scheduler_enabled", false, "Run the reminder, SLA and report jobs inside the HTTP server instead of an external cron")
+	vars.IntVar(&env.SchedulerReminderSeconds, "hellper_scheduler_reminder_seconds", 900, "Seconds between the reminders of open incidents, 0 disables the job")
+	vars.IntVar(&env.SchedulerSLASeconds, "hellper_scheduler_sla_seconds", 86400, "Seconds between the reminders to close resolved incidents, 0 disables the job")
+	vars.IntVar(&env.SchedulerSLABreachSeconds, "hellper_scheduler_sla_breach_seconds", 300, "Seconds between the checks of the SLA targets of active incidents, 0 disables the job")
+	vars.IntVar(&env.SchedulerPostMortemSeconds, "hellper_scheduler_postmortem_seconds", 86400, "Seconds between the reminders of the pending post mortems to the commanders, 0 disables the job")
+	vars.IntVar(&env.SchedulerReportSeconds, "hellper_scheduler_report_seconds", 86400, "Seconds between the reports of active incidents, 0 disables the job")
+	vars.StringVar(&env.SchedulerReportChannelID, "hellper_scheduler_report_channel_id", "", "Channel receiving the report of active incidents, the report job is disabled when empty")
+	vars.IntVar(&env.SchedulerDigestSeconds, "hellper_scheduler_digest_seconds", 604800, "Seconds between the incident digests, also the period they summarize, 0 disables the job")
+	vars.StringVar(&env.SchedulerDigestChannelID, "hellper_scheduler_digest_channel_id", "", "Channel receiving the incident digest, the digest job is disabled when empty")
+	vars.IntVar(&env.ShutdownTimeoutSeconds, "hellper_shutdown_timeout_seconds", 30, "Seconds the server waits for running requests and jobs on shutdown")
+
+	vars.StringVar(&env.WebhooksFile, "hellper_webhooks_file", "", "YAML file with the webhook subscriptions of the incident events, no webhook is sent when empty")
+	vars.IntVar(&env.WebhookMaxAttempts, "hellper_webhook_max_attempts", 5, "How many times a webhook delivery is tried before giving up")
+	vars.IntVar(&env.WebhookTimeoutSeconds, "hellper_webhook_timeout_seconds", 10, "Seconds to wait for the response of a webhook endpoint")
+
+	vars.StringVar(&env.SMTPHost, "hellper_smtp_host", "", "SMTP server sending the incident emails, no email is sent when empty")
+	vars.IntVar(&env.SMTPPort, "hellper_smtp_port", 587, "Port of the SMTP server")
+	vars.StringVar(&env.SMTPUsername, "hellper_smtp_username", "", "SMTP username, the server is used without authentication when empty")
+	vars.StringVar(&env.SMTPPassword, "hellper_smtp_password", "", "SMTP password")
+	vars.StringVar(&env.EmailFrom, "hellper_email_from", "", "Sender address of the incident emails")
+	vars.StringVar(&env.EmailLists, "hellper_email_lists", "", "Distribution lists receiving the emails of each product, e.g. Checkout=checkout@example.com;*=executives@example.com")
+	vars.BoolVar(&env.EmailParticipants, "hellper_email_participants", true, "Also email the members of the incident channel")
+	vars.StringVar(&env.StatusPageProvider, "hellper_statuspage_provider", "", "Status page publishing the public incidents, statuspageio or fake, nothing is published when empty")
+	vars.StringVar(&env.StatusPageURL, "hellper_statuspage_url", "https://api.statuspage.io/v1", "API of the Statuspage.io compatible status page")
+	vars.StringVar(&env.StatusPageID, "hellper_statuspage_page_id", "", "Id of the page of the status page")
+	vars.StringVar(&env.StatusPageAPIKey, "hellper_statuspage_api_key", "", "API key of the status page")
+	vars.StringVar(&env.StatusPageComponents, "hellper_statuspage_components", "", "Status page components affected by the incidents of each product, e.g. Checkout=cmp1,cmp2;Search=cmp3")
+	vars.StringVar(&env.PagerProvider, "hellper_pager_provider", "", "Pager paging the on-call responders of the incidents, pagerduty or fake, nobody is paged when empty")
+	vars.StringVar(&env.PagerURL, "hellper_pager_url", "https://events.pagerduty.com/v2/enqueue", "Events API v2 compatible endpoint of the pager")
+	vars.StringVar(&env.PagerRoutingKeys, "hellper_pager_routing_keys", "", "Routing keys by product, severity or both, e.g. Checkout/sev0=R1;Checkout=R2;sev1=R3;*=R4")
+	vars.StringVar(&env.PagerEventURL, "hellper_pager_event_url", "", "Link to the paged event kept on the incident, {dedup_key} is replaced by the incident id")
+	vars.StringVar(&env.AlertsToken, "hellper_alerts_token", "", "Token of the alerts webhook, as a bearer token or the basic auth password, the alerts are refused when empty")
+	vars.StringVar(&env.AlertsCommander, "hellper_alerts_commander", "", "Slack user id of the commander of the incidents opened by the alerts")
+	vars.StringVar(&env.AlertsProductLabel, "hellper_alerts_product_label", "product", "Alert label with the product of the incident")
+	vars.StringVar(&env.AlertsDefaultProduct, "hellper_alerts_default_product", "", "Product of the alerts without product label")
+	vars.StringVar(&env.AlertsSeverityLabel, "hellper_alerts_severity_label", "severity", "Alert label with the severity of the incident")
+	vars.StringVar(&env.AlertsSeverities, "hellper_alerts_severities", "critical=0;high=1;error=1;warning=2;info=3", "Severity levels of the severity label values")
+	vars.IntVar(&env.AlertsDefaultSeverity, "hellper_alerts_default_severity", 3, "Severity level of the alerts without known severity")
+	vars.BoolVar(&env.AlertsAutoResolve, "hellper_alerts_auto_resolve", false, "Resolve the incidents opened by the alerts once all their alerts cleared, otherwise they are only announced")
+	vars.StringVar(&env.IssueTrackerProvider, "hellper_issue_tracker_provider", "", "Issue tracker of the action items tickets, github or fake, no ticket is created when empty")
+	vars.StringVar(&env.IssueTrackerURL, "hellper_issue_tracker_url", "https://api.github.com", "API of the issue tracker")
+	vars.StringVar(&env.IssueTrackerProject, "hellper_issue_tracker_project", "", "Project of the tickets on the issue tracker, the owner/name repository on github")
+	vars.StringVar(&env.IssueTrackerToken, "hellper_issue_tracker_token", "", "Token of the issue tracker API")
+	vars.StringVar(&env.IssueTrackerLabels, "hellper_issue_tracker_labels", "incident", "Comma separated labels of the tickets, besides their priority")
+	vars.IntVar(&env.SchedulerActionItemsSeconds, "hellper_scheduler_action_items_seconds", 3600, "Seconds between the syncs of the action items with their closed tickets, 0 disables the job")
 
 	vars.Parse()
 	return env
@@ -0,0 +1,150 @@
+package digest
+
+import (
+	"sort"
+	"time"
+
+	"hellper/internal/model"
+	"hellper/internal/sla"
+)
+
+// Periods of the digest
+const (
+	Daily  = 24 * time.Hour
+	Weekly = 7 * 24 * time.Hour
+)
+
+// longestRunningLimit is how many active incidents are listed as the longest running
+const longestRunningLimit = 5
+
+// ProductTimes are the mean times of the incidents of a product resolved in the period
+type ProductTimes struct {
+	Product  string
+	Resolved int
+	MTTA     time.Duration
+	MTTR     time.Duration
+}
+
+// SeverityCount is how many incidents of a severity were opened in the period
+type SeverityCount struct {
+	SeverityLevel int64
+	Count         int
+}
+
+// Digest summarizes the incidents of a period
+type Digest struct {
+	From time.Time
+	To   time.Time
+
+	Opened   []model.Incident
+	Resolved []model.Incident
+	Closed   []model.Incident
+
+	Products           []ProductTimes
+	Severities         []SeverityCount
+	PostMortemsOverdue []model.Incident
+	LongestRunning     []model.Incident
+}
+
+// Build summarizes the incidents between from and to, the post mortems are overdue when the pending ones are past their due date
+func Build(incidents []model.Incident, pendingPostMortems []model.Incident, from time.Time, to time.Time, targets sla.Targets) Digest {
+	var (
+		digest     = Digest{From: from, To: to}
+		products   = map[string]*productSums{}
+		severities = map[int64]int{}
+	)
+
+	for _, inc := range incidents {
+		if within(inc.StartTimestamp, from, to) {
+			digest.Opened = append(digest.Opened, inc)
+			severities[inc.SeverityLevel]++
+		}
+
+		if within(inc.EndTimestamp, from, to) {
+			digest.Resolved = append(digest.Resolved, inc)
+			sums, ok := products[inc.Product]
+			if !ok {
+				sums = &productSums{}
+				products[inc.Product] = sums
+			}
+			sums.add(inc)
+		}
+
+		if within(inc.ClosedAt, from, to) {
+			digest.Closed = append(digest.Closed, inc)
+		}
+
+		if inc.Status == model.StatusOpen && inc.StartTimestamp != nil {
+			digest.LongestRunning = append(digest.LongestRunning, inc)
+		}
+	}
+
+	for _, inc := range pendingPostMortems {
+		if dueAt, ok := sla.DueAt(targets, inc, sla.MetricPostmortem); ok && dueAt.Before(to) {
+			digest.PostMortemsOverdue = append(digest.PostMortemsOverdue, inc)
+		}
+	}
+
+	for product, sums := range products {
+		digest.Products = append(digest.Products, sums.times(product))
+	}
+	sort.Slice(digest.Products, func(i, j int) bool {
+		return digest.Products[i].Product < digest.Products[j].Product
+	})
+
+	for severity, count := range severities {
+		digest.Severities = append(digest.Severities, SeverityCount{SeverityLevel: severity, Count: count})
+	}
+	sort.Slice(digest.Severities, func(i, j int) bool {
+		return digest.Severities[i].SeverityLevel < digest.Severities[j].SeverityLevel
+	})
+
+	sort.SliceStable(digest.PostMortemsOverdue, func(i, j int) bool {
+		return digest.PostMortemsOverdue[i].EndTimestamp.Before(*digest.PostMortemsOverdue[j].EndTimestamp)
+	})
+
+	sort.SliceStable(digest.LongestRunning, func(i, j int) bool {
+		return digest.LongestRunning[i].StartTimestamp.Before(*digest.LongestRunning[j].StartTimestamp)
+	})
+	if len(digest.LongestRunning) > longestRunningLimit {
+		digest.LongestRunning = digest.LongestRunning[:longestRunningLimit]
+	}
+
+	return digest
+}
+
+type productSums struct {
+	resolved     int
+	acknowledged int
+	acknowledge  time.Duration
+	repaired     int
+	repair       time.Duration
+}
+
+func (s *productSums) add(inc model.Incident) {
+	s.resolved++
+	if inc.StartTimestamp == nil {
+		return
+	}
+	if inc.IdentificationTimestamp != nil {
+		s.acknowledged++
+		s.acknowledge += inc.IdentificationTimestamp.Sub(*inc.StartTimestamp)
+	}
+	s.repaired++
+	s.repair += inc.EndTimestamp.Sub(*inc.StartTimestamp)
+}
+
+func (s *productSums) times(product string) ProductTimes {
+	times := ProductTimes{Product: product, Resolved: s.resolved}
+	if s.acknowledged > 0 {
+		times.MTTA = s.acknowledge / time.Duration(s.acknowledged)
+	}
+	if s.repaired > 0 {
+		times.MTTR = s.repair / time.Duration(s.repaired)
+	}
+	return times
+}
+
+func within(t *time.Time, from time.Time, to time.Time) bool {
+	return t != nil && !t.Before(from) && t.Before(to)
+}
@@ -0,0 +1,153 @@
+package digest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hellper/internal/bot"
+	"hellper/internal/digest"
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/sla"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var now = time.Date(2020, 10, 19, 12, 0, 0, 0, time.UTC)
+
+func at(d time.Duration) *time.Time {
+	t := now.Add(-d)
+	return &t
+}
+
+func incidents() []model.Incident {
+	return []model.Incident{
+		{
+			// Resolved and closed in the week, acknowledged after 10 minutes and resolved after 2 hours
+			Id: 1, ChannelId: "C1", Product: "Checkout", SeverityLevel: 1, Status: model.StatusClosed,
+			StartTimestamp: at(72 * time.Hour), IdentificationTimestamp: at(72*time.Hour - 10*time.Minute),
+			EndTimestamp: at(70 * time.Hour), ClosedAt: at(24 * time.Hour),
+		},
+		{
+			Id: 2, ChannelId: "C2", Product: "Checkout", SeverityLevel: 0, Status: model.StatusResolved,
+			StartTimestamp: at(48 * time.Hour), IdentificationTimestamp: at(48*time.Hour - 30*time.Minute),
+			EndTimestamp: at(44 * time.Hour),
+		},
+		{
+			// Resolved before the week and still waiting for the post mortem
+			Id: 3, ChannelId: "C3", Title: "Old outage", Product: "Search", SeverityLevel: 2, Status: model.StatusResolved,
+			StartTimestamp: at(20 * 24 * time.Hour), EndTimestamp: at(10 * 24 * time.Hour),
+		},
+		{
+			Id: 4, ChannelId: "C4", Title: "Slow search", Product: "Search", SeverityLevel: 2, Status: model.StatusOpen,
+			StartTimestamp: at(30 * time.Hour),
+		},
+		{
+			Id: 5, ChannelId: "C5", Title: "Login errors", Product: "Login", SeverityLevel: 0, Status: model.StatusOpen,
+			StartTimestamp: at(3 * time.Hour),
+		},
+	}
+}
+
+var targets = sla.Targets{"*": {sla.MetricPostmortem: 168 * time.Hour}}
+
+func pendingPostMortems() []model.Incident {
+	return []model.Incident{
+		incidents()[1],
+		incidents()[2],
+		{
+			// Closed without publishing the post mortem
+			Id: 6, ChannelId: "C6", Title: "Broken deploy", Product: "Checkout", SeverityLevel: 1, Status: model.StatusClosed,
+			PostMortemStatus: model.PostMortemDraft, StartTimestamp: at(30 * 24 * time.Hour), EndTimestamp: at(29 * 24 * time.Hour),
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	d := digest.Build(incidents(), pendingPostMortems(), now.Add(-digest.Weekly), now, targets)
+
+	assert.Len(t, d.Opened, 4)
+	assert.Len(t, d.Resolved, 2)
+	assert.Len(t, d.Closed, 1)
+
+	assert.Equal(t, []digest.ProductTimes{
+		{Product: "Checkout", Resolved: 2, MTTA: 20 * time.Minute, MTTR: 3 * time.Hour},
+	}, d.Products)
+
+	assert.Equal(t, []digest.SeverityCount{
+		{SeverityLevel: 0, Count: 2},
+		{SeverityLevel: 1, Count: 1},
+		{SeverityLevel: 2, Count: 1},
+	}, d.Severities)
+
+	if assert.Len(t, d.PostMortemsOverdue, 2) {
+		assert.Equal(t, int64(6), d.PostMortemsOverdue[0].Id)
+		assert.Equal(t, int64(3), d.PostMortemsOverdue[1].Id)
+	}
+	if assert.Len(t, d.LongestRunning, 2) {
+		assert.Equal(t, int64(4), d.LongestRunning[0].Id)
+		assert.Equal(t, int64(5), d.LongestRunning[1].Id)
+	}
+}
+
+func TestBuildDaily(t *testing.T) {
+	d := digest.Build(incidents(), pendingPostMortems(), now.Add(-digest.Daily), now, targets)
+
+	assert.Len(t, d.Opened, 1)
+	assert.Len(t, d.Resolved, 0)
+	assert.Len(t, d.Closed, 1)
+	assert.Empty(t, d.Products)
+	assert.Equal(t, "Incident digest: 1 opened, 0 resolved, 1 closed", d.Summary())
+}
+
+func TestBlocks(t *testing.T) {
+	d := digest.Build(incidents(), pendingPostMortems(), now.Add(-digest.Weekly), now, targets)
+	blocks := d.Blocks(digest.Weekly, time.UTC)
+
+	assert.Len(t, blocks, 8)
+	assert.Contains(t, blocks[4].(*slack.SectionBlock).Text.Text, "• Checkout: MTTA 20m, MTTR 3h (2 resolved)")
+	assert.Contains(t, blocks[6].(*slack.SectionBlock).Text.Text, "• <#C3> Old outage - Not started, resolved 10d ago")
+	assert.Contains(t, blocks[6].(*slack.SectionBlock).Text.Text, "• <#C6> Broken deploy - Draft, resolved 29d ago")
+	assert.Contains(t, blocks[7].(*slack.SectionBlock).Text.Text, "• <#C4> Slow search - open for 1d6h")
+}
+
+func TestSend(t *testing.T) {
+	table := []struct {
+		testName  string
+		listError error
+	}{
+		{testName: "Posts the digest"},
+		{testName: "Repository error", listError: errors.New("database is down")},
+	}
+
+	for _, f := range table {
+		t.Run(f.testName, func(t *testing.T) {
+			var (
+				ctx            = context.Background()
+				loggerMock     = log.NewLoggerMock()
+				clientMock     = bot.NewClientMock()
+				repositoryMock = model.NewRepositoryMock()
+			)
+
+			loggerMock.On("Info", ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+			loggerMock.On("Error", ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+			clientMock.On("PostMessage", "CDIGEST", mock.AnythingOfType("[]slack.MsgOption")).Return("", "", nil)
+			repositoryMock.On("ListIncidentsSince", ctx, mock.AnythingOfType("time.Time")).Return(incidents(), f.listError)
+			repositoryMock.On("ListPendingPostMortems", ctx).Return(pendingPostMortems(), nil)
+
+			err := digest.Send(ctx, clientMock, loggerMock, repositoryMock, "CDIGEST", digest.Weekly)
+			if f.listError != nil {
+				assert.EqualError(t, err, f.listError.Error())
+				clientMock.AssertNotCalled(t, "PostMessage", "CDIGEST", mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			clientMock.AssertCalled(t, "PostMessage", "CDIGEST", mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package digest
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"hellper/internal/bot"
+	"hellper/internal/config"
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/sla"
+
+	"github.com/slack-go/slack"
+)
+
+// Send posts the digest of the last period to the channel
+func Send(ctx context.Context, client bot.Client, logger log.Logger, repository model.Repository, channelID string, period time.Duration) error {
+	logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("channelID", channelID),
+		log.NewValue("period", period.String()),
+	)
+
+	var (
+		to   = time.Now()
+		from = to.Add(-period)
+	)
+
+	incidents, err := repository.ListIncidentsSince(ctx, from)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("repository.ListIncidentsSince"),
+			log.Reason(err.Error()),
+		)
+		return err
+	}
+
+	pendingPostMortems, err := repository.ListPendingPostMortems(ctx)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("repository.ListPendingPostMortems"),
+			log.Reason(err.Error()),
+		)
+		return err
+	}
+
+	digest := Build(incidents, pendingPostMortems, from, to, sla.EnvTargets())
+	_, _, err = client.PostMessage(
+		channelID,
+		slack.MsgOptionText(digest.Summary(), false),
+		slack.MsgOptionBlocks(digest.Blocks(period, location())...),
+	)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("client.PostMessage"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", channelID),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// Summary is the text of the digest shown on notifications
+func (d Digest) Summary() string {
+	return "Incident digest: " + strconv.Itoa(len(d.Opened)) + " opened, " + strconv.Itoa(len(d.Resolved)) + " resolved, " + strconv.Itoa(len(d.Closed)) + " closed"
+}
+
+// Blocks renders the digest as Slack blocks
+func (d Digest) Blocks(period time.Duration, loc *time.Location) []slack.Block {
+	title := "Incident digest"
+	switch period {
+	case Daily:
+		title = "Daily incident digest"
+	case Weekly:
+		title = "Weekly incident digest"
+	}
+
+	return []slack.Block{
+		slack.NewHeaderBlock(plainText(title)),
+		slack.NewContextBlock("", markdown(d.From.In(loc).Format("Mon, 02 Jan 15:04")+" - "+d.To.In(loc).Format("Mon, 02 Jan 15:04 MST"))),
+		slack.NewSectionBlock(nil, []*slack.TextBlockObject{
+			markdown("*Opened*\n" + strconv.Itoa(len(d.Opened))),
+			markdown("*Resolved*\n" + strconv.Itoa(len(d.Resolved))),
+			markdown("*Closed*\n" + strconv.Itoa(len(d.Closed))),
+			markdown("*Overdue post mortems*\n" + strconv.Itoa(len(d.PostMortemsOverdue))),
+		}, nil),
+		slack.NewDividerBlock(),
+		slack.NewSectionBlock(markdown("*MTTA / MTTR by product*\n"+d.productsText()), nil, nil),
+		slack.NewSectionBlock(markdown("*Severity of the opened incidents*\n"+d.severitiesText()), nil, nil),
+		slack.NewSectionBlock(markdown("*Overdue post mortems*\n"+incidentsText(d.PostMortemsOverdue, func(inc model.Incident) string {
+			return model.PostMortemStatusName(inc.PostMortemStatus) + ", resolved " + formatDuration(d.To.Sub(*inc.EndTimestamp)) + " ago"
+		})), nil, nil),
+		slack.NewSectionBlock(markdown("*Longest running incidents*\n"+incidentsText(d.LongestRunning, func(inc model.Incident) string {
+			return "open for " + formatDuration(d.To.Sub(*inc.StartTimestamp))
+		})), nil, nil),
+	}
+}
+
+func (d Digest) productsText() string {
+	if len(d.Products) == 0 {
+		return "No incident resolved"
+	}
+
+	var text strings.Builder
+	for _, product := range d.Products {
+		name := product.Product
+		if name == "" {
+			name = "No product"
+		}
+		text.WriteString("• " + name + ": MTTA " + formatDuration(product.MTTA) + ", MTTR " + formatDuration(product.MTTR) + " (" + strconv.Itoa(product.Resolved) + " resolved)\n")
+	}
+	return text.String()
+}
+
+func (d Digest) severitiesText() string {
+	if len(d.Severities) == 0 {
+		return "No incident opened"
+	}
+
+	var text strings.Builder
+	for _, severity := range d.Severities {
+		text.WriteString("• SEV" + strconv.FormatInt(severity.SeverityLevel, 10) + ": " + strconv.Itoa(severity.Count) + "\n")
+	}
+	return text.String()
+}
+
+func incidentsText(incidents []model.Incident, describe func(model.Incident) string) string {
+	if len(incidents) == 0 {
+		return "None"
+	}
+
+	var text strings.Builder
+	for _, inc := range incidents {
+		text.WriteString("• <#" + inc.ChannelId + "> " + inc.Title + " - " + describe(inc) + "\n")
+	}
+	return text.String()
+}
+
+// formatDuration shows days, hours and minutes, e.g. 2d3h or 45m
+func formatDuration(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+
+	var (
+		days    = int(d / (24 * time.Hour))
+		hours   = int(d % (24 * time.Hour) / time.Hour)
+		minutes = int(d % time.Hour / time.Minute)
+		text    strings.Builder
+	)
+	if days > 0 {
+		text.WriteString(strconv.Itoa(days) + "d")
+	}
+	if hours > 0 {
+		text.WriteString(strconv.Itoa(hours) + "h")
+	}
+	if minutes > 0 && days == 0 {
+		text.WriteString(strconv.Itoa(minutes) + "m")
+	}
+	if text.Len() == 0 {
+		return "<1m"
+	}
+	return text.String()
+}
+
+func location() *time.Location {
+	loc, err := time.LoadLocation(config.Env.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+func markdown(text string) *slack.TextBlockObject {
+	return slack.NewTextBlockObject(slack.MarkdownType, text, false, false)
+}
+
+func plainText(text string) *slack.TextBlockObject {
+	return slack.NewTextBlockObject(slack.PlainTextType, text, false, false)
+}
@@ -0,0 +1,266 @@
+package email_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"hellper/internal/concurrence"
+	"hellper/internal/email"
+	"hellper/internal/lifecycle"
+	"hellper/internal/log"
+	"hellper/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParseLists(t *testing.T) {
+	table := []struct {
+		testName      string
+		value         string
+		expected      email.Lists
+		expectedError string
+	}{
+		{
+			testName: "Lists by product",
+			value:    "Checkout=a@example.com, b@example.com;*=execs@example.com",
+			expected: email.Lists{"Checkout": {"a@example.com", "b@example.com"}, "*": {"execs@example.com"}},
+		},
+		{
+			testName: "Empty",
+			expected: email.Lists{},
+		},
+		{
+			testName:      "Missing product",
+			value:         "a@example.com",
+			expectedError: `invalid email distribution lists: "a@example.com" has no product`,
+		},
+		{
+			testName:      "Invalid address",
+			value:         "Checkout=checkout",
+			expectedError: `invalid email distribution lists: invalid address "checkout" of Checkout`,
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			lists, err := email.ParseLists(f.value)
+			if f.expectedError != "" {
+				assert.EqualError(t, err, f.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, f.expected, lists)
+		})
+	}
+}
+
+func TestMessage(t *testing.T) {
+	started := time.Date(2020, 10, 19, 12, 0, 0, 0, time.UTC)
+	inc := model.Incident{
+		Title: "Checkout errors", Product: "Checkout", SeverityLevel: 1, Status: model.StatusOpen,
+		ChannelId: "C1", CommanderEmail: "commander@example.com", StartTimestamp: &started,
+		DescriptionStarted: "Payments are failing",
+	}
+
+	msg, err := email.Message(lifecycle.EventOpened, inc, "hellper@example.com", []string{"a@example.com", "b@example.com"}, time.UTC, started)
+	assert.NoError(t, err)
+
+	text := string(msg)
+	assert.Contains(t, text, "To: a@example.com, b@example.com\r\n")
+	assert.Contains(t, text, "Subject: [SEV1] Incident opened: Checkout errors\r\n")
+	assert.Contains(t, text, "\r\n\r\nIncident opened on Checkout.\r\n")
+	assert.Contains(t, text, "Commander: commander@example.com\r\nStarted at: Mon, 19 Oct 2020 12:00 UTC\r\n")
+	assert.Contains(t, text, "Payments are failing")
+	assert.Contains(t, text, "https://slack.com/app_redirect?channel=C1")
+	assert.NotContains(t, text, "Resolved at")
+
+	msg, err = email.Message(lifecycle.EventOpened, inc, "hellper@example.com", nil, time.UTC, started)
+	assert.NoError(t, err)
+	assert.Contains(t, string(msg), "To: undisclosed-recipients:;\r\n")
+}
+
+type senderStub struct {
+	mu       sync.Mutex
+	to       [][]string
+	messages []string
+	err      error
+}
+
+func (s *senderStub) Send(from string, to []string, msg []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.to = append(s.to, to)
+	s.messages = append(s.messages, string(msg))
+	return s.err
+}
+
+func TestNotifier(t *testing.T) {
+	table := []struct {
+		testName     string
+		product      string
+		participants email.Participants
+		sendError    error
+		expectedTo   [][]string
+		expectedHead string
+	}{
+		{
+			testName: "Product lists and commander",
+			product:  "Checkout",
+			expectedTo: [][]string{
+				{"checkout@example.com", "execs@example.com", "commander@example.com"},
+			},
+			expectedHead: "To: checkout@example.com, execs@example.com\r\n",
+		},
+		{
+			testName: "Channel members without duplicates",
+			product:  "Search",
+			participants: func(ctx context.Context, channelID string) ([]string, error) {
+				return []string{"Commander@example.com", "", "dev@example.com"}, nil
+			},
+			expectedTo: [][]string{
+				{"execs@example.com", "commander@example.com", "dev@example.com"},
+			},
+			expectedHead: "To: execs@example.com\r\n",
+		},
+		{
+			testName: "Members lookup failure still emails the lists",
+			product:  "Search",
+			participants: func(ctx context.Context, channelID string) ([]string, error) {
+				return nil, errors.New("channel_not_found")
+			},
+			expectedTo: [][]string{
+				{"execs@example.com", "commander@example.com"},
+			},
+		},
+		{
+			testName:   "Send failure is logged",
+			product:    "Search",
+			sendError:  errors.New("connection refused"),
+			expectedTo: [][]string{{"execs@example.com", "commander@example.com"}},
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			var (
+				ctx        = context.Background()
+				loggerMock = log.NewLoggerMock()
+				sender     = &senderStub{err: f.sendError}
+				lists      = email.Lists{"Checkout": {"checkout@example.com"}, "*": {"execs@example.com"}}
+			)
+
+			loggerMock.On("Info", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+			loggerMock.On("Error", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+
+			background := &concurrence.Background{}
+			notifier := email.NewNotifier(loggerMock, background, sender, "hellper@example.com", lists, f.participants, time.UTC)
+			assert.True(t, notifier.Wants(lifecycle.EventResolved))
+			assert.False(t, notifier.Wants(lifecycle.EventCanceled))
+
+			notifier.Dispatch(ctx, lifecycle.EventResolved, model.Incident{ChannelId: "C1", Product: f.product, CommanderEmail: "commander@example.com"})
+			assert.NoError(t, background.Wait(ctx))
+
+			assert.Equal(t, f.expectedTo, sender.to)
+			if f.expectedHead != "" {
+				header := strings.SplitN(sender.messages[0], "\r\n\r\n", 2)[0]
+				assert.Contains(t, header+"\r\n", f.expectedHead)
+				assert.NotContains(t, header, "commander@example.com", "the commander is a blind copy")
+				assert.NotContains(t, header, "dev@example.com", "the participants are blind copies")
+			}
+			if f.sendError != nil {
+				loggerMock.AssertCalled(t, "Error", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value"))
+			}
+		})
+	}
+}
+
+// smtpSink is a local SMTP server keeping the messages it receives
+type smtpSink struct {
+	listener net.Listener
+	messages chan string
+}
+
+func newSMTPSink(t *testing.T) *smtpSink {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sink := &smtpSink{listener: listener, messages: make(chan string, 1)}
+	go sink.serve()
+	return sink
+}
+
+func (s *smtpSink) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	var (
+		reader = bufio.NewReader(conn)
+		data   strings.Builder
+		inData bool
+	)
+	fmt.Fprint(conn, "220 localhost sink\r\n")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		if inData {
+			if line == ".\r\n" {
+				inData = false
+				s.messages <- data.String()
+				fmt.Fprint(conn, "250 OK\r\n")
+				continue
+			}
+			data.WriteString(line)
+			continue
+		}
+
+		switch command := strings.ToUpper(strings.TrimSpace(line)); {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			fmt.Fprint(conn, "250 localhost\r\n")
+		case command == "DATA":
+			inData = true
+			fmt.Fprint(conn, "354 End data with <CR><LF>.<CR><LF>\r\n")
+		case command == "QUIT":
+			fmt.Fprint(conn, "221 Bye\r\n")
+			return
+		default:
+			fmt.Fprint(conn, "250 OK\r\n")
+		}
+	}
+}
+
+func TestSMTPSender(t *testing.T) {
+	sink := newSMTPSink(t)
+	defer sink.listener.Close()
+
+	addr := sink.listener.Addr().(*net.TCPAddr)
+	sender := email.NewSMTPSender("127.0.0.1", addr.Port, "", "")
+
+	msg, err := email.Message(lifecycle.EventClosed, model.Incident{Title: "Checkout errors", ChannelId: "C1"}, "hellper@example.com", []string{"a@example.com"}, time.UTC, time.Now())
+	assert.NoError(t, err)
+
+	err = sender.Send("hellper@example.com", []string{"a@example.com"}, msg)
+	assert.NoError(t, err)
+
+	select {
+	case received := <-sink.messages:
+		assert.Contains(t, received, "Subject: [SEV0] Incident closed: Checkout errors\r\n")
+	case <-time.After(5 * time.Second):
+		t.Fatal("the sink received no message")
+	}
+}
@@ -0,0 +1,55 @@
+package email
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// AllProducts is the product of the lists receiving the emails of every incident
+const AllProducts = "*"
+
+// ErrInvalidLists is returned when the distribution lists can not be parsed
+var ErrInvalidLists = errors.New("invalid email distribution lists")
+
+// Lists are the email addresses receiving the incidents of each product
+type Lists map[string][]string
+
+// ParseLists reads the lists in the format product=email,email;product=email, the product * receives every incident, e.g.
+// Checkout=checkout-stakeholders@example.com;*=executives@example.com
+func ParseLists(value string) (Lists, error) {
+	lists := Lists{}
+
+	for _, statement := range strings.Split(value, ";") {
+		statement = strings.TrimSpace(statement)
+		if statement == "" {
+			continue
+		}
+
+		parts := strings.SplitN(statement, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("%w: %q has no product", ErrInvalidLists, statement)
+		}
+
+		product := strings.TrimSpace(parts[0])
+		for _, address := range strings.Split(parts[1], ",") {
+			address = strings.TrimSpace(address)
+			if !strings.Contains(address, "@") {
+				return nil, fmt.Errorf("%w: invalid address %q of %s", ErrInvalidLists, address, product)
+			}
+			lists[product] = append(lists[product], address)
+		}
+	}
+
+	return lists, nil
+}
+
+// For lists the addresses receiving the incidents of the product
+func (l Lists) For(product string) []string {
+	var addresses []string
+	addresses = append(addresses, l[product]...)
+	if product != AllProducts {
+		addresses = append(addresses, l[AllProducts]...)
+	}
+	return addresses
+}
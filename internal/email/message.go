@@ -0,0 +1,109 @@
+package email
+
+import (
+	"bytes"
+	"mime"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"hellper/internal/lifecycle"
+	"hellper/internal/model"
+)
+
+var headlines = map[string]string{
+	lifecycle.EventOpened:          "Incident opened",
+	lifecycle.EventSeverityChanged: "Incident severity changed",
+	lifecycle.EventResolved:        "Incident resolved",
+	lifecycle.EventClosed:          "Incident closed",
+}
+
+var (
+	subjectTemplate = template.Must(template.New("subject").Parse(
+		`[{{.Severity}}] {{.Headline}}: {{.Incident.Title}}`,
+	))
+	bodyTemplate = template.Must(template.New("body").Parse(`{{.Headline}}{{with .Incident.Product}} on {{.}}{{end}}.
+
+Title: {{.Incident.Title}}
+Severity: {{.Severity}}
+Status: {{.Incident.Status}}
+{{- with .Incident.CommanderEmail}}
+Commander: {{.}}{{end}}
+{{- with .StartedAt}}
+Started at: {{.}}{{end}}
+{{- with .ResolvedAt}}
+Resolved at: {{.}}{{end}}
+{{- with .Incident.StatusPageUrl}}
+Status page: {{.}}{{end}}
+{{- with .Incident.PostMortemUrl}}
+Post mortem: {{.}}{{end}}
+{{with .Description}}
+{{.}}
+{{end}}
+Follow the incident on Slack: https://slack.com/app_redirect?channel={{.Incident.ChannelId}}
+`))
+)
+
+type messageData struct {
+	Headline    string
+	Incident    model.Incident
+	Severity    string
+	StartedAt   string
+	ResolvedAt  string
+	Description string
+}
+
+// undisclosedRecipients is the To header of the emails sent to no distribution list (RFC 5322 section 3.4)
+const undisclosedRecipients = "undisclosed-recipients:;"
+
+// Message builds the email of the event, its To header shows the distribution lists only. The commander and the
+// participants receive it as blind copies, their addresses are only on the SMTP envelope
+func Message(event string, inc model.Incident, from string, lists []string, loc *time.Location, now time.Time) ([]byte, error) {
+	data := messageData{
+		Headline:    headlines[event],
+		Incident:    inc,
+		Severity:    "SEV" + strconv.FormatInt(inc.SeverityLevel, 10),
+		StartedAt:   formatTime(inc.StartTimestamp, loc),
+		ResolvedAt:  formatTime(inc.EndTimestamp, loc),
+		Description: inc.DescriptionStarted,
+	}
+	if event == lifecycle.EventResolved || event == lifecycle.EventClosed {
+		data.Description = inc.DescriptionResolved
+	}
+
+	var subject, body bytes.Buffer
+	err := subjectTemplate.Execute(&subject, data)
+	if err != nil {
+		return nil, err
+	}
+	err = bodyTemplate.Execute(&body, data)
+	if err != nil {
+		return nil, err
+	}
+
+	to := undisclosedRecipients
+	if len(lists) > 0 {
+		to = strings.Join(lists, ", ")
+	}
+
+	var msg bytes.Buffer
+	msg.WriteString("From: " + from + "\r\n")
+	msg.WriteString("To: " + to + "\r\n")
+	msg.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject.String()) + "\r\n")
+	msg.WriteString("Date: " + now.Format(time.RFC1123Z) + "\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(body.String(), "\n", "\r\n"))
+
+	return msg.Bytes(), nil
+}
+
+func formatTime(t *time.Time, loc *time.Location) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.In(loc).Format("Mon, 02 Jan 2006 15:04 MST")
+}
@@ -0,0 +1,140 @@
+// Package email sends the incident lifecycle events to the stakeholders outside Slack
+package email
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"hellper/internal/concurrence"
+	"hellper/internal/log"
+	"hellper/internal/model"
+)
+
+// Participants lists the emails of the members of the incident channel
+type Participants func(ctx context.Context, channelID string) ([]string, error)
+
+// Notifier emails the opened, severity changed, resolved and closed incidents
+type Notifier struct {
+	logger       log.Logger
+	background   *concurrence.Background
+	sender       Sender
+	from         string
+	lists        Lists
+	participants Participants
+	location     *time.Location
+	now          func() time.Time
+}
+
+// NewNotifier creates a Notifier sending to the lists of the incident product and to its commander,
+// and to the members of the incident channel when participants is not nil. The emails are sent on the background
+func NewNotifier(logger log.Logger, background *concurrence.Background, sender Sender, from string, lists Lists, participants Participants, location *time.Location) *Notifier {
+	return &Notifier{
+		logger:       logger,
+		background:   background,
+		sender:       sender,
+		from:         from,
+		lists:        lists,
+		participants: participants,
+		location:     location,
+		now:          time.Now,
+	}
+}
+
+// Wants tells whether the event is emailed
+func (n *Notifier) Wants(event string) bool {
+	_, ok := headlines[event]
+	return ok
+}
+
+// Dispatch emails the event in background
+func (n *Notifier) Dispatch(ctx context.Context, event string, incident model.Incident) {
+	n.background.Go(func() {
+		n.send(context.Background(), event, incident)
+	})
+}
+
+func (n *Notifier) send(ctx context.Context, event string, incident model.Incident) {
+	to := n.recipients(ctx, incident)
+	if len(to) == 0 {
+		n.logger.Info(
+			ctx,
+			log.Trace(),
+			log.Action("do_not_email"),
+			log.Reason("no recipients"),
+			log.NewValue("event", event),
+			log.NewValue("channelID", incident.ChannelId),
+		)
+		return
+	}
+
+	msg, err := Message(event, incident, n.from, n.lists.For(incident.Product), n.location, n.now())
+	if err != nil {
+		n.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("email.Message"),
+			log.Reason(err.Error()),
+			log.NewValue("event", event),
+			log.NewValue("channelID", incident.ChannelId),
+		)
+		return
+	}
+
+	err = n.sender.Send(n.from, to, msg)
+	if err != nil {
+		n.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("sender.Send"),
+			log.Reason(err.Error()),
+			log.NewValue("event", event),
+			log.NewValue("channelID", incident.ChannelId),
+			log.NewValue("recipients", len(to)),
+		)
+		return
+	}
+
+	n.logger.Info(
+		ctx,
+		log.Trace(),
+		log.Action("email_sent"),
+		log.NewValue("event", event),
+		log.NewValue("channelID", incident.ChannelId),
+		log.NewValue("recipients", len(to)),
+	)
+}
+
+// recipients are the product lists, the commander and the channel members, without duplicates
+func (n *Notifier) recipients(ctx context.Context, incident model.Incident) []string {
+	addresses := n.lists.For(incident.Product)
+	addresses = append(addresses, incident.CommanderEmail)
+
+	if n.participants != nil {
+		emails, err := n.participants(ctx, incident.ChannelId)
+		if err != nil {
+			n.logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("participants"),
+				log.Reason(err.Error()),
+				log.NewValue("channelID", incident.ChannelId),
+			)
+		}
+		addresses = append(addresses, emails...)
+	}
+
+	var (
+		recipients []string
+		seen       = map[string]bool{}
+	)
+	for _, address := range addresses {
+		key := strings.ToLower(strings.TrimSpace(address))
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		recipients = append(recipients, strings.TrimSpace(address))
+	}
+	return recipients
+}
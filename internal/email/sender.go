@@ -0,0 +1,30 @@
+package email
+
+import (
+	"net"
+	"net/smtp"
+	"strconv"
+)
+
+// Sender delivers an email message to the recipients
+type Sender interface {
+	Send(from string, to []string, msg []byte) error
+}
+
+type smtpSender struct {
+	addr string
+	auth smtp.Auth
+}
+
+// NewSMTPSender creates a Sender of the SMTP server, it authenticates only when the username is set
+func NewSMTPSender(host string, port int, username string, password string) Sender {
+	sender := &smtpSender{addr: net.JoinHostPort(host, strconv.Itoa(port))}
+	if username != "" {
+		sender.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return sender
+}
+
+func (s *smtpSender) Send(from string, to []string, msg []byte) error {
+	return smtp.SendMail(s.addr, s.auth, from, to, msg)
+}
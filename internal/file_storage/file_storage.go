@@ -6,5 +6,7 @@ import (
 
 // Driver interface for File Storage
 type Driver interface {
-	CreatePostMortemDocument(ctx context.Context, postMortemName string) (string, error)
+	CreatePostMortemDocument(ctx context.Context, data PostMortemData) (string, error)
+	UpdatePostMortemDocument(ctx context.Context, documentURL string, data PostMortemData) error
+	UploadFile(ctx context.Context, name string, mimeType string, content []byte) (string, error)
 }
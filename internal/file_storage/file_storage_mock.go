@@ -14,7 +14,17 @@ func NewFileStorageMock() *FileStorageMock {
 	return new(FileStorageMock)
 }
 
-func (mock *FileStorageMock) CreatePostMortemDocument(ctx context.Context, postMortemName string) (string, error) {
-	args := mock.Called(ctx, postMortemName)
+func (mock *FileStorageMock) CreatePostMortemDocument(ctx context.Context, data PostMortemData) (string, error) {
+	args := mock.Called(ctx, data)
+	return args.Get(0).(string), args.Error(1)
+}
+
+func (mock *FileStorageMock) UpdatePostMortemDocument(ctx context.Context, documentURL string, data PostMortemData) error {
+	args := mock.Called(ctx, documentURL, data)
+	return args.Error(0)
+}
+
+func (mock *FileStorageMock) UploadFile(ctx context.Context, name string, mimeType string, content []byte) (string, error) {
+	args := mock.Called(ctx, name, mimeType, content)
 	return args.Get(0).(string), args.Error(1)
 }
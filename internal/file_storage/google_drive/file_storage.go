@@ -1,16 +1,24 @@
 package googledrive
 
 import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+
 	filestorage "hellper/internal/file_storage"
 	googleauth "hellper/internal/google_auth"
 	"hellper/internal/log"
 
 	"golang.org/x/net/context"
+	"google.golang.org/api/docs/v1"
 	"google.golang.org/api/drive/v3"
 
 	"hellper/internal/config"
 )
 
+var documentIDPattern = regexp.MustCompile(`/document/d/([^/]+)`)
+
 type storage struct {
 	logger log.Logger
 }
@@ -22,6 +30,34 @@ func NewFileStorage(logger log.Logger) filestorage.Driver {
 	}
 }
 
+func (s *storage) newDriveService(ctx context.Context) (*drive.Service, error) {
+	driveTokenBytes := []byte(config.Env.GoogleDriveToken)
+
+	gClient, err := googleauth.Struct.GetGClient(ctx, s.logger, driveTokenBytes, drive.DriveScope)
+	if err != nil {
+		s.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("googleauth.Struct.GetGClient"),
+			log.Reason(err.Error()),
+		)
+		return nil, err
+	}
+
+	driveService, err := drive.New(gClient)
+	if err != nil {
+		s.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("drive.New"),
+			log.Reason(err.Error()),
+		)
+		return nil, err
+	}
+
+	return driveService, nil
+}
+
 func (s *storage) copyFile(ctx context.Context, d *drive.Service, fileID string, title string) (*drive.File, error) {
 	f := &drive.File{Name: title}
 	r, err := d.Files.Copy(fileID, f).Do()
@@ -39,22 +75,22 @@ func (s *storage) copyFile(ctx context.Context, d *drive.Service, fileID string,
 	return r, nil
 }
 
-// CreatePostMortemDocument creates a document on Google Drive from the PostMortem template.
-func (s *storage) CreatePostMortemDocument(ctx context.Context, postMortemName string) (string, error) {
+// CreatePostMortemDocument creates a document on Google Drive from the PostMortem template, with the placeholders
+// of the incident data replaced.
+func (s *storage) CreatePostMortemDocument(ctx context.Context, data filestorage.PostMortemData) (string, error) {
+	postMortemName := data.Name
 	s.logger.Info(
 		ctx,
 		log.Trace(),
 		log.NewValue("postMortemName", postMortemName),
 	)
 
-	driveTokenBytes := []byte(config.Env.GoogleDriveToken)
-
-	gClient, err := googleauth.Struct.GetGClient(ctx, s.logger, driveTokenBytes, drive.DriveScope)
+	driveService, err := s.newDriveService(ctx)
 	if err != nil {
 		s.logger.Error(
 			ctx,
 			log.Trace(),
-			log.Action("googleauth.Struct.GetGClient"),
+			log.Action("s.newDriveService"),
 			log.Reason(err.Error()),
 			log.NewValue("postMortemName", postMortemName),
 		)
@@ -62,12 +98,12 @@ func (s *storage) CreatePostMortemDocument(ctx context.Context, postMortemName s
 		return "", err
 	}
 
-	driveService, err := drive.New(gClient)
+	file, err := s.copyFile(ctx, driveService, config.Env.GoogleDriveFileID, postMortemName)
 	if err != nil {
 		s.logger.Error(
 			ctx,
 			log.Trace(),
-			log.Action("drive.New"),
+			log.Action("s.copyFile"),
 			log.Reason(err.Error()),
 			log.NewValue("postMortemName", postMortemName),
 		)
@@ -75,27 +111,131 @@ func (s *storage) CreatePostMortemDocument(ctx context.Context, postMortemName s
 		return "", err
 	}
 
-	file, err := s.copyFile(ctx, driveService, config.Env.GoogleDriveFileID, postMortemName)
+	if file != nil {
+		s.logger.Info(
+			ctx,
+			log.Trace(),
+			log.NewValue("postMortemName", postMortemName),
+			log.NewValue("file", "https://docs.google.com/document/d/"+file.Id+"/edit"),
+		)
+	}
+
+	err = s.replacePlaceholders(ctx, file.Id, data)
 	if err != nil {
 		s.logger.Error(
 			ctx,
 			log.Trace(),
-			log.Action("s.copyFile"),
+			log.Action("s.replacePlaceholders"),
 			log.Reason(err.Error()),
 			log.NewValue("postMortemName", postMortemName),
 		)
+	}
+
+	return "https://docs.google.com/document/d/" + file.Id + "/edit", nil
+}
 
+// UpdatePostMortemDocument replaces the placeholders still on the document, keeping what was written on it.
+func (s *storage) UpdatePostMortemDocument(ctx context.Context, documentURL string, data filestorage.PostMortemData) error {
+	match := documentIDPattern.FindStringSubmatch(documentURL)
+	if match == nil {
+		return fmt.Errorf("%w: %s", filestorage.ErrFileNotFound, documentURL)
+	}
+
+	err := s.replacePlaceholders(ctx, match[1], data)
+	if err != nil {
+		s.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("s.replacePlaceholders"),
+			log.Reason(err.Error()),
+			log.NewValue("documentURL", documentURL),
+		)
+		return err
+	}
+	return nil
+}
+
+// replacePlaceholders replaces the {{placeholder}} texts of the document by the known values of the incident
+func (s *storage) replacePlaceholders(ctx context.Context, documentID string, data filestorage.PostMortemData) error {
+	placeholders := data.Placeholders()
+	if len(placeholders) == 0 {
+		return nil
+	}
+
+	gClient, err := googleauth.Struct.GetGClient(ctx, s.logger, []byte(config.Env.GoogleDriveToken), drive.DriveScope)
+	if err != nil {
+		return err
+	}
+	docsService, err := docs.New(gClient)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(placeholders))
+	for name := range placeholders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	requests := make([]*docs.Request, 0, len(names))
+	for _, name := range names {
+		requests = append(requests, &docs.Request{
+			ReplaceAllText: &docs.ReplaceAllTextRequest{
+				ContainsText: &docs.SubstringMatchCriteria{Text: name, MatchCase: true},
+				ReplaceText:  placeholders[name],
+			},
+		})
+	}
+
+	_, err = docsService.Documents.BatchUpdate(documentID, &docs.BatchUpdateDocumentRequest{Requests: requests}).Context(ctx).Do()
+	return err
+}
+
+// UploadFile stores a file on the same Google Drive folder of the PostMortem template.
+func (s *storage) UploadFile(ctx context.Context, name string, mimeType string, content []byte) (string, error) {
+	s.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("name", name),
+		log.NewValue("mimeType", mimeType),
+	)
+
+	driveService, err := s.newDriveService(ctx)
+	if err != nil {
+		s.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("s.newDriveService"),
+			log.Reason(err.Error()),
+			log.NewValue("name", name),
+		)
 		return "", err
 	}
 
-	if file != nil {
-		s.logger.Info(
+	template, err := driveService.Files.Get(config.Env.GoogleDriveFileID).Fields("parents").Do()
+	if err != nil {
+		s.logger.Error(
 			ctx,
 			log.Trace(),
-			log.NewValue("postMortemName", postMortemName),
-			log.NewValue("file", "https://docs.google.com/document/d/"+file.Id+"/edit"),
+			log.Action("driveService.Files.Get"),
+			log.Reason(err.Error()),
+			log.NewValue("fileID", config.Env.GoogleDriveFileID),
 		)
+		return "", err
 	}
 
-	return "https://docs.google.com/document/d/" + file.Id + "/edit", nil
+	f := &drive.File{Name: name, MimeType: mimeType, Parents: template.Parents}
+	file, err := driveService.Files.Create(f).Media(bytes.NewReader(content)).Fields("id", "webViewLink").Do()
+	if err != nil {
+		s.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("driveService.Files.Create"),
+			log.Reason(err.Error()),
+			log.NewValue("name", name),
+		)
+		return "", err
+	}
+
+	return file.WebViewLink, nil
 }
@@ -0,0 +1,141 @@
+package localdisk
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	filestorage "hellper/internal/file_storage"
+	"hellper/internal/log"
+)
+
+type storage struct {
+	logger   log.Logger
+	dir      string
+	baseURL  string
+	template *template.Template
+}
+
+// NewFileStorage initialize the file storage on the dir of the local disk, its files are served by hellper on baseURL
+func NewFileStorage(logger log.Logger, dir string, baseURL string, template *template.Template) filestorage.Driver {
+	return &storage{
+		logger:   logger,
+		dir:      dir,
+		baseURL:  baseURL,
+		template: template,
+	}
+}
+
+// CreatePostMortemDocument writes the Markdown post mortem of the template on the disk.
+func (s *storage) CreatePostMortemDocument(ctx context.Context, data filestorage.PostMortemData) (string, error) {
+	s.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("postMortemName", data.Name),
+	)
+
+	content, err := filestorage.RenderPostMortem(s.template, data)
+	if err != nil {
+		s.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("filestorage.RenderPostMortem"),
+			log.Reason(err.Error()),
+			log.NewValue("postMortemName", data.Name),
+		)
+		return "", err
+	}
+
+	return s.UploadFile(ctx, data.Name+".md", "text/markdown", content)
+}
+
+// UpdatePostMortemDocument renders the Markdown post mortem again over the file of the document, which
+// can't be edited other than by hellper.
+func (s *storage) UpdatePostMortemDocument(ctx context.Context, documentURL string, data filestorage.PostMortemData) error {
+	key, ok := filestorage.FileKeyOf(documentURL)
+	if !ok {
+		return fmt.Errorf("%w: %s", filestorage.ErrFileNotFound, documentURL)
+	}
+
+	content, err := filestorage.RenderPostMortem(s.template, data)
+	if err != nil {
+		s.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("filestorage.RenderPostMortem"),
+			log.Reason(err.Error()),
+			log.NewValue("postMortemName", data.Name),
+		)
+		return err
+	}
+
+	return s.write(ctx, key, content)
+}
+
+// UploadFile writes a file on the disk, the mime type is told by its extension when it is served.
+func (s *storage) UploadFile(ctx context.Context, name string, mimeType string, content []byte) (string, error) {
+	key, err := filestorage.NewFileKey(name)
+	if err != nil {
+		s.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("filestorage.NewFileKey"),
+			log.Reason(err.Error()),
+			log.NewValue("name", name),
+		)
+		return "", err
+	}
+
+	err = s.write(ctx, key, content)
+	if err != nil {
+		return "", err
+	}
+
+	return filestorage.FileURL(s.baseURL, key), nil
+}
+
+func (s *storage) write(ctx context.Context, key string, content []byte) error {
+	err := os.MkdirAll(s.dir, 0755)
+	if err != nil {
+		s.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("os.MkdirAll"),
+			log.Reason(err.Error()),
+			log.NewValue("dir", s.dir),
+		)
+		return err
+	}
+
+	err = ioutil.WriteFile(filepath.Join(s.dir, key), content, 0644)
+	if err != nil {
+		s.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("ioutil.WriteFile"),
+			log.Reason(err.Error()),
+			log.NewValue("key", key),
+		)
+		return err
+	}
+	return nil
+}
+
+// ReadFile reads the file of the key from the disk.
+func (s *storage) ReadFile(ctx context.Context, key string) ([]byte, string, error) {
+	if !filestorage.IsFileKey(key) {
+		return nil, "", filestorage.ErrFileNotFound
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(s.dir, key))
+	if os.IsNotExist(err) {
+		return nil, "", filestorage.ErrFileNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return content, filestorage.ContentType(key), nil
+}
@@ -0,0 +1,66 @@
+package localdisk_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	filestorage "hellper/internal/file_storage"
+	localdisk "hellper/internal/file_storage/local_disk"
+	"hellper/internal/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFileStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hellper-files")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	loggerMock := log.NewLoggerMock()
+	loggerMock.On("Info", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+
+	template, err := filestorage.ParsePostMortemTemplate("")
+	assert.NoError(t, err)
+
+	var (
+		ctx     = context.Background()
+		storage = localdisk.NewFileStorage(loggerMock, filepath.Join(dir, "files"), "https://hellper.example.com", template)
+		reader  = storage.(filestorage.Reader)
+	)
+
+	data := filestorage.PostMortemData{Name: "42 - PostMortem - Checkout errors", Title: "Checkout errors"}
+	postMortemURL, err := storage.CreatePostMortemDocument(ctx, data)
+	assert.NoError(t, err)
+	assert.Regexp(t, `^https://hellper\.example\.com/files/42-postmortem-checkout-errors-[0-9a-f]{16}\.md$`, postMortemURL)
+
+	key := postMortemURL[strings.LastIndex(postMortemURL, "/")+1:]
+	content, contentType, err := reader.ReadFile(ctx, key)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "# 42 - PostMortem - Checkout errors\n"))
+	assert.Contains(t, string(content), "## Root cause")
+	assert.Contains(t, string(content), "| Title | Checkout errors |")
+	assert.Equal(t, "text/markdown; charset=utf-8", contentType)
+
+	data.RootCause = "Expired certificate"
+	err = storage.UpdatePostMortemDocument(ctx, postMortemURL, data)
+	assert.NoError(t, err)
+
+	content, _, err = reader.ReadFile(ctx, key)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "## Root cause\n\nExpired certificate\n")
+
+	err = storage.UpdatePostMortemDocument(ctx, "https://docs.google.com/document/d/abc/edit", data)
+	assert.True(t, errors.Is(err, filestorage.ErrFileNotFound))
+
+	_, _, err = reader.ReadFile(ctx, "missing-0123456789abcdef.md")
+	assert.True(t, errors.Is(err, filestorage.ErrFileNotFound))
+
+	_, _, err = reader.ReadFile(ctx, "..")
+	assert.True(t, errors.Is(err, filestorage.ErrFileNotFound))
+}
@@ -0,0 +1,209 @@
+package filestorage
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"hellper/internal/model"
+)
+
+// DefaultPostMortemTemplate is the Markdown post mortem of the drivers without a template of their own
+const DefaultPostMortemTemplate = `# {{.Name}}
+
+_Created at {{.CreatedAt.Format "2006-01-02 15:04 MST"}}_
+
+| | |
+|---|---|
+| Title | {{.Title}} |
+| Severity | {{.Severity}} |
+| Product | {{.Product}} |
+| Status | {{.Status}} |
+| Commander | {{.Commander}} |
+| Started at | {{.StartedAt}} |
+| Identified at | {{.IdentifiedAt}} |
+| Resolved at | {{.ResolvedAt}} |
+| Closed at | {{.ClosedAt}} |
+| Duration | {{.Duration}} |
+| Customer impact | {{.CustomerImpact}} |
+| Team | {{.Team}} |
+| Functionality | {{.Functionality}} |
+| Responsibility | {{.Responsibility}} |
+| Status page | {{.StatusPageURL}} |
+
+## Summary
+
+{{with .DescriptionStarted}}{{.}}{{else}}What happened, in a few sentences.{{end}}
+
+## Impact
+
+Who was affected, how and for how long.
+
+## Timeline
+
+{{with .TimelineText}}{{.}}{{else}}The incident timeline is filled in when the incident is resolved.{{end}}
+
+## Root cause
+
+{{.RootCause}}
+
+## Resolution
+
+{{.DescriptionResolved}}
+
+## Action items
+
+Track them with ` + "`/hellper_action`" + ` on the incident channel.
+
+## Lessons learned
+
+- What went well
+- What went wrong
+- Where we got lucky
+`
+
+const postMortemTimeLayout = "2006-01-02 15:04 MST"
+
+// PostMortemData is what the post mortems are filled with, the Markdown templates are rendered with it and
+// the placeholders of the Google Docs are replaced by its values
+type PostMortemData struct {
+	Name                string
+	CreatedAt           time.Time
+	Title               string
+	Severity            string
+	Product             string
+	Status              string
+	Commander           string
+	StartedAt           string
+	IdentifiedAt        string
+	ResolvedAt          string
+	ClosedAt            string
+	Duration            string
+	CustomerImpact      string
+	Team                string
+	Functionality       string
+	Responsibility      string
+	RootCause           string
+	DescriptionStarted  string
+	DescriptionResolved string
+	StatusPageURL       string
+	WarRoomURL          string
+	ChannelName         string
+	Timeline            []model.TimelineEntry
+	// TimelineText is the timeline as a list with one line per message, the replies nested under their message
+	TimelineText string
+}
+
+// NewPostMortemData gathers what is known of the incident, with the dates on the location
+func NewPostMortemData(name string, inc model.Incident, timeline []model.TimelineEntry, loc *time.Location, now time.Time) PostMortemData {
+	data := PostMortemData{
+		Name:                name,
+		CreatedAt:           now.In(loc),
+		Title:               inc.Title,
+		Severity:            "SEV" + strconv.FormatInt(inc.SeverityLevel, 10),
+		Product:             inc.Product,
+		Status:              inc.Status,
+		Commander:           inc.CommanderEmail,
+		StartedAt:           formatTime(inc.StartTimestamp, loc),
+		IdentifiedAt:        formatTime(inc.IdentificationTimestamp, loc),
+		ResolvedAt:          formatTime(inc.EndTimestamp, loc),
+		ClosedAt:            formatTime(inc.ClosedAt, loc),
+		Team:                inc.Team,
+		Functionality:       inc.Functionality,
+		Responsibility:      inc.Responsibility,
+		RootCause:           inc.RootCause,
+		DescriptionStarted:  inc.DescriptionStarted,
+		DescriptionResolved: inc.DescriptionResolved,
+		StatusPageURL:       inc.StatusPageUrl,
+		WarRoomURL:          inc.WarRoomUrl,
+		ChannelName:         inc.ChannelName,
+		Timeline:            timeline,
+		TimelineText:        timelineText(timeline, loc),
+	}
+	if inc.CustomerImpact.Valid {
+		data.CustomerImpact = strconv.FormatInt(inc.CustomerImpact.Int64, 10)
+	}
+	if inc.StartTimestamp != nil && inc.EndTimestamp != nil {
+		data.Duration = inc.EndTimestamp.Sub(*inc.StartTimestamp).Round(time.Minute).String()
+	}
+	return data
+}
+
+// Placeholders maps the placeholders of the post mortem documents to their values, leaving out the unknown
+// ones so they are still in the document when it is updated later
+func (d PostMortemData) Placeholders() map[string]string {
+	values := map[string]string{
+		"title":                d.Title,
+		"severity":             d.Severity,
+		"product":              d.Product,
+		"commander":            d.Commander,
+		"started_at":           d.StartedAt,
+		"identified_at":        d.IdentifiedAt,
+		"resolved_at":          d.ResolvedAt,
+		"closed_at":            d.ClosedAt,
+		"duration":             d.Duration,
+		"customer_impact":      d.CustomerImpact,
+		"team":                 d.Team,
+		"functionality":        d.Functionality,
+		"responsibility":       d.Responsibility,
+		"root_cause":           d.RootCause,
+		"description_started":  d.DescriptionStarted,
+		"description_resolved": d.DescriptionResolved,
+		"status_page_url":      d.StatusPageURL,
+		"war_room_url":         d.WarRoomURL,
+		"channel_name":         d.ChannelName,
+		"timeline":             d.TimelineText,
+	}
+
+	placeholders := map[string]string{}
+	for name, value := range values {
+		if value != "" {
+			placeholders["{{"+name+"}}"] = value
+		}
+	}
+	return placeholders
+}
+
+func formatTime(t *time.Time, loc *time.Location) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.In(loc).Format(postMortemTimeLayout)
+}
+
+func timelineText(timeline []model.TimelineEntry, loc *time.Location) string {
+	var text strings.Builder
+	writeTimeline(&text, timeline, loc, "")
+	return strings.TrimSuffix(text.String(), "\n")
+}
+
+func writeTimeline(text *strings.Builder, timeline []model.TimelineEntry, loc *time.Location, indent string) {
+	for _, entry := range timeline {
+		text.WriteString(indent + "- " + entry.Timestamp.In(loc).Format(postMortemTimeLayout) + " " + entry.UserName + ": " + strings.ReplaceAll(entry.Text, "\n", " "))
+		if entry.Pinned {
+			text.WriteString(" (pinned)")
+		}
+		text.WriteString("\n")
+		writeTimeline(text, entry.Replies, loc, indent+"  ")
+	}
+}
+
+// ParsePostMortemTemplate parses a Markdown post mortem template, DefaultPostMortemTemplate when the text is empty
+func ParsePostMortemTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		text = DefaultPostMortemTemplate
+	}
+	return template.New("postmortem").Option("missingkey=error").Parse(text)
+}
+
+// RenderPostMortem renders the Markdown post mortem of the template
+func RenderPostMortem(tmpl *template.Template, data PostMortemData) ([]byte, error) {
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, data)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
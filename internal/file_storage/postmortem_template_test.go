@@ -0,0 +1,63 @@
+package filestorage_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	filestorage "hellper/internal/file_storage"
+	"hellper/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPostMortemData(t *testing.T) {
+	var (
+		started  = time.Date(2020, 10, 19, 12, 0, 0, 0, time.UTC)
+		resolved = started.Add(95 * time.Minute)
+		timeline = []model.TimelineEntry{
+			{
+				Timestamp: started.Add(time.Minute), UserName: "alice", Text: "checkout\nis down", Pinned: true,
+				Replies: []model.TimelineEntry{{Timestamp: started.Add(2 * time.Minute), UserName: "bob", Text: "looking"}},
+			},
+		}
+		inc = model.Incident{
+			Title: "Checkout errors", SeverityLevel: 1, Product: "Checkout", CommanderEmail: "commander@example.com",
+			StartTimestamp: &started, EndTimestamp: &resolved, CustomerImpact: sql.NullInt64{Int64: 120, Valid: true},
+			RootCause: "Expired certificate",
+		}
+	)
+
+	data := filestorage.NewPostMortemData("1 - PostMortem - Checkout errors", inc, timeline, time.UTC, resolved)
+	assert.Equal(t, "SEV1", data.Severity)
+	assert.Equal(t, "2020-10-19 12:00 UTC", data.StartedAt)
+	assert.Equal(t, "2020-10-19 13:35 UTC", data.ResolvedAt)
+	assert.Equal(t, "", data.ClosedAt)
+	assert.Equal(t, "1h35m0s", data.Duration)
+	assert.Equal(t, "120", data.CustomerImpact)
+	assert.Equal(t, "- 2020-10-19 12:01 UTC alice: checkout is down (pinned)\n  - 2020-10-19 12:02 UTC bob: looking", data.TimelineText)
+
+	placeholders := data.Placeholders()
+	assert.Equal(t, "Checkout errors", placeholders["{{title}}"])
+	assert.Equal(t, "Expired certificate", placeholders["{{root_cause}}"])
+	assert.Equal(t, data.TimelineText, placeholders["{{timeline}}"])
+	assert.NotContains(t, placeholders, "{{closed_at}}", "the unknown values are left for a later update")
+
+	template, err := filestorage.ParsePostMortemTemplate("")
+	assert.NoError(t, err)
+
+	document, err := filestorage.RenderPostMortem(template, data)
+	assert.NoError(t, err)
+	assert.Contains(t, string(document), "# 1 - PostMortem - Checkout errors\n")
+	assert.Contains(t, string(document), "| Severity | SEV1 |\n")
+	assert.Contains(t, string(document), "## Root cause\n\nExpired certificate\n")
+	assert.Contains(t, string(document), data.TimelineText)
+}
+
+func TestParsePostMortemTemplate(t *testing.T) {
+	template, err := filestorage.ParsePostMortemTemplate("{{.Unknown}}")
+	assert.NoError(t, err)
+
+	_, err = filestorage.RenderPostMortem(template, filestorage.PostMortemData{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,197 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	filestorage "hellper/internal/file_storage"
+	"hellper/internal/log"
+)
+
+// Settings tells where the files are stored on the S3 compatible object store
+type Settings struct {
+	// Endpoint is the URL of the object store, the buckets are addressed on its path
+	Endpoint        string
+	Bucket          string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	Timeout         time.Duration
+}
+
+type storage struct {
+	logger     log.Logger
+	settings   Settings
+	baseURL    string
+	template   *template.Template
+	httpClient *http.Client
+}
+
+// NewFileStorage initialize the file storage on the bucket of an S3 compatible object store, its files are served
+// by hellper on baseURL
+func NewFileStorage(logger log.Logger, settings Settings, baseURL string, template *template.Template) filestorage.Driver {
+	settings.Endpoint = strings.TrimSuffix(settings.Endpoint, "/")
+	return &storage{
+		logger:     logger,
+		settings:   settings,
+		baseURL:    baseURL,
+		template:   template,
+		httpClient: &http.Client{Timeout: settings.Timeout},
+	}
+}
+
+// CreatePostMortemDocument stores the Markdown post mortem of the template on the bucket.
+func (s *storage) CreatePostMortemDocument(ctx context.Context, data filestorage.PostMortemData) (string, error) {
+	s.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("postMortemName", data.Name),
+	)
+
+	content, err := filestorage.RenderPostMortem(s.template, data)
+	if err != nil {
+		s.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("filestorage.RenderPostMortem"),
+			log.Reason(err.Error()),
+			log.NewValue("postMortemName", data.Name),
+		)
+		return "", err
+	}
+
+	return s.UploadFile(ctx, data.Name+".md", filestorage.ContentType(".md"), content)
+}
+
+// UpdatePostMortemDocument renders the Markdown post mortem again over the object of the document, which
+// can't be edited other than by hellper.
+func (s *storage) UpdatePostMortemDocument(ctx context.Context, documentURL string, data filestorage.PostMortemData) error {
+	key, ok := filestorage.FileKeyOf(documentURL)
+	if !ok {
+		return fmt.Errorf("%w: %s", filestorage.ErrFileNotFound, documentURL)
+	}
+
+	content, err := filestorage.RenderPostMortem(s.template, data)
+	if err != nil {
+		s.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("filestorage.RenderPostMortem"),
+			log.Reason(err.Error()),
+			log.NewValue("postMortemName", data.Name),
+		)
+		return err
+	}
+
+	return s.put(ctx, key, filestorage.ContentType(".md"), content)
+}
+
+// UploadFile stores a file on the bucket with its mime type.
+func (s *storage) UploadFile(ctx context.Context, name string, mimeType string, content []byte) (string, error) {
+	key, err := filestorage.NewFileKey(name)
+	if err != nil {
+		s.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("filestorage.NewFileKey"),
+			log.Reason(err.Error()),
+			log.NewValue("name", name),
+		)
+		return "", err
+	}
+
+	err = s.put(ctx, key, mimeType, content)
+	if err != nil {
+		return "", err
+	}
+
+	return filestorage.FileURL(s.baseURL, key), nil
+}
+
+func (s *storage) put(ctx context.Context, key string, mimeType string, content []byte) error {
+	resp, err := s.send(ctx, http.MethodPut, key, mimeType, content)
+	if err != nil {
+		s.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("s.send"),
+			log.Reason(err.Error()),
+			log.NewValue("key", key),
+		)
+		return err
+	}
+	defer resp.Body.Close()
+
+	err = checkStatus(resp)
+	if err != nil {
+		s.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("PutObject"),
+			log.Reason(err.Error()),
+			log.NewValue("key", key),
+		)
+		return err
+	}
+	return nil
+}
+
+// ReadFile reads the file of the key and its mime type from the bucket.
+func (s *storage) ReadFile(ctx context.Context, key string) ([]byte, string, error) {
+	if !filestorage.IsFileKey(key) {
+		return nil, "", filestorage.ErrFileNotFound
+	}
+
+	resp, err := s.send(ctx, http.MethodGet, key, "", nil)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, "", filestorage.ErrFileNotFound
+	}
+	err = checkStatus(resp)
+	if err != nil {
+		return nil, "", err
+	}
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = filestorage.ContentType(key)
+	}
+	return content, contentType, nil
+}
+
+func (s *storage) send(ctx context.Context, method string, key string, contentType string, content []byte) (*http.Response, error) {
+	url := s.settings.Endpoint + "/" + s.settings.Bucket + "/" + key
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	signRequest(req, hashHex(content), s.settings.Region, s.settings.AccessKeyID, s.settings.SecretAccessKey, time.Now())
+
+	return s.httpClient.Do(req)
+}
+
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return nil
+	}
+	content, _ := ioutil.ReadAll(resp.Body)
+	return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(content)))
+}
@@ -0,0 +1,143 @@
+package s3_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	filestorage "hellper/internal/file_storage"
+	"hellper/internal/file_storage/s3"
+	"hellper/internal/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type storedObject struct {
+	content     []byte
+	contentType string
+}
+
+// objectStore is a local stand-in of an S3 compatible object store, keeping the objects of its buckets in memory
+type objectStore struct {
+	mu      sync.Mutex
+	objects map[string]storedObject
+}
+
+func (o *objectStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=hellper/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		content, _ := ioutil.ReadAll(r.Body)
+		sum := sha256.Sum256(content)
+		if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(sum[:]) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		o.objects[r.URL.Path] = storedObject{content: content, contentType: r.Header.Get("Content-Type")}
+	case http.MethodGet:
+		object, ok := o.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("<Error><Code>NoSuchKey</Code></Error>"))
+			return
+		}
+		w.Header().Set("Content-Type", object.contentType)
+		w.Write(object.content)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newStorage(t *testing.T, endpoint string, secretAccessKey string) filestorage.Driver {
+	loggerMock := log.NewLoggerMock()
+	loggerMock.On("Info", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+	loggerMock.On("Error", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+
+	template, err := filestorage.ParsePostMortemTemplate("# {{.Name}}\n")
+	assert.NoError(t, err)
+
+	settings := s3.Settings{
+		Endpoint:        endpoint,
+		Bucket:          "postmortems",
+		Region:          "us-east-1",
+		AccessKeyID:     "hellper",
+		SecretAccessKey: secretAccessKey,
+		Timeout:         time.Second,
+	}
+	return s3.NewFileStorage(loggerMock, settings, "https://hellper.example.com/", template)
+}
+
+func TestFileStorage(t *testing.T) {
+	store := &objectStore{objects: map[string]storedObject{}}
+	server := httptest.NewServer(store)
+	defer server.Close()
+
+	var (
+		ctx     = context.Background()
+		storage = newStorage(t, server.URL+"/", "s3cr3t")
+		reader  = storage.(filestorage.Reader)
+	)
+
+	data := filestorage.PostMortemData{Name: "42 - PostMortem - Checkout errors"}
+	postMortemURL, err := storage.CreatePostMortemDocument(ctx, data)
+	assert.NoError(t, err)
+	assert.Regexp(t, `^https://hellper\.example\.com/files/42-postmortem-checkout-errors-[0-9a-f]{16}\.md$`, postMortemURL)
+
+	key := postMortemURL[strings.LastIndex(postMortemURL, "/")+1:]
+	assert.Contains(t, store.objects, "/postmortems/"+key)
+
+	content, contentType, err := reader.ReadFile(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, "# 42 - PostMortem - Checkout errors\n", string(content))
+	assert.Equal(t, "text/markdown; charset=utf-8", contentType)
+
+	data.Name = "42 - PostMortem - Checkout errors (resolved)"
+	err = storage.UpdatePostMortemDocument(ctx, postMortemURL, data)
+	assert.NoError(t, err)
+	assert.Len(t, store.objects, 1)
+
+	content, _, err = reader.ReadFile(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, "# 42 - PostMortem - Checkout errors (resolved)\n", string(content))
+
+	timelineURL, err := storage.UploadFile(ctx, "42 - Timeline - Checkout errors.json", "application/json", []byte(`{"version":1}`))
+	assert.NoError(t, err)
+
+	content, contentType, err = reader.ReadFile(ctx, timelineURL[strings.LastIndex(timelineURL, "/")+1:])
+	assert.NoError(t, err)
+	assert.Equal(t, `{"version":1}`, string(content))
+	assert.Equal(t, "application/json", contentType)
+
+	_, _, err = reader.ReadFile(ctx, "missing-0123456789abcdef.md")
+	assert.True(t, errors.Is(err, filestorage.ErrFileNotFound))
+
+	_, _, err = reader.ReadFile(ctx, "../secrets")
+	assert.True(t, errors.Is(err, filestorage.ErrFileNotFound))
+}
+
+func TestFileStorageFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<Error><Code>SignatureDoesNotMatch</Code></Error>"))
+	}))
+	defer server.Close()
+
+	_, err := newStorage(t, server.URL, "wrong").UploadFile(context.Background(), "timeline.md", "text/markdown", []byte("# Timeline"))
+	assert.EqualError(t, err, "unexpected status 403: <Error><Code>SignatureDoesNotMatch</Code></Error>")
+}
@@ -0,0 +1,82 @@
+package filestorage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"mime"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// ErrFileNotFound is returned by a Reader without the file of the key
+var ErrFileNotFound = errors.New("file not found")
+
+// Reader is a Driver whose files are served by hellper on /files/<key>
+type Reader interface {
+	ReadFile(ctx context.Context, key string) (content []byte, contentType string, err error)
+}
+
+var (
+	slugInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+	fileKeyPattern   = regexp.MustCompile(`^[a-z0-9-]+(\.[a-z0-9]+)?$`)
+)
+
+var contentTypes = map[string]string{
+	".md":   "text/markdown; charset=utf-8",
+	".json": "application/json",
+}
+
+// NewFileKey names the stored file after the name, with a random suffix keeping its URL unguessable
+func NewFileKey(name string) (string, error) {
+	suffix := make([]byte, 8)
+	_, err := rand.Read(suffix)
+	if err != nil {
+		return "", err
+	}
+
+	ext := strings.ToLower(path.Ext(name))
+	if !fileKeyPattern.MatchString("x" + ext) {
+		ext = ""
+	}
+
+	slug := strings.Trim(slugInvalidChars.ReplaceAllString(strings.ToLower(strings.TrimSuffix(name, path.Ext(name))), "-"), "-")
+	if slug == "" {
+		slug = "file"
+	}
+	return slug + "-" + hex.EncodeToString(suffix) + ext, nil
+}
+
+// IsFileKey tells whether the key could have been made by NewFileKey, which keeps it inside the storage
+func IsFileKey(key string) bool {
+	return fileKeyPattern.MatchString(key)
+}
+
+// FileURL is the URL where hellper serves the file of the key
+func FileURL(baseURL string, key string) string {
+	return strings.TrimSuffix(baseURL, "/") + "/files/" + key
+}
+
+// FileKeyOf returns the key of a file served by hellper from its URL
+func FileKeyOf(fileURL string) (string, bool) {
+	index := strings.LastIndex(fileURL, "/files/")
+	if index < 0 {
+		return "", false
+	}
+	key := fileURL[index+len("/files/"):]
+	return key, IsFileKey(key)
+}
+
+// ContentType guesses the type of the file from the extension of its key
+func ContentType(key string) string {
+	ext := path.Ext(key)
+	if contentType, ok := contentTypes[ext]; ok {
+		return contentType
+	}
+	if contentType := mime.TypeByExtension(ext); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
@@ -2,16 +2,31 @@ package googleauth
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"hellper/internal/config"
 	"hellper/internal/log"
 	"net/http"
+	"sync"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 )
 
-type googleAuthStruct struct{}
+const serviceAccountType = "service_account"
+
+// TokenStore keeps the refreshed OAuth tokens, so a restart does not go back to the token of the environment
+type TokenStore interface {
+	GetGoogleToken(ctx context.Context, key string) (string, bool, error)
+	SaveGoogleToken(ctx context.Context, key string, token string) error
+}
+
+type googleAuthStruct struct {
+	credentials   []byte
+	delegatedUser string
+	store         TokenStore
+}
 
 // Interface interfaces the public methods from package
 type Interface interface {
@@ -20,14 +35,53 @@ type Interface interface {
 
 var (
 	//Struct creates the interface for the usage of googleauth package
-	Struct Interface = &googleAuthStruct{}
+	Struct Interface = New([]byte(config.Env.GoogleCredentials), config.Env.GoogleDelegatedUser, nil)
 )
 
-// GetGClient generates a google Client, given a token and a scope
+// New creates the Google authentication of the credentials, either an OAuth client or a service account.
+// The service account impersonates the delegated user when there is one, through the domain-wide delegation,
+// and the refreshed OAuth tokens are saved on the store when there is one
+func New(credentials []byte, delegatedUser string, store TokenStore) Interface {
+	return &googleAuthStruct{
+		credentials:   credentials,
+		delegatedUser: delegatedUser,
+		store:         store,
+	}
+}
+
+// GetGClient generates a google Client, given a token and a scope. The token is ignored by the service accounts
 func (gs *googleAuthStruct) GetGClient(ctx context.Context, logger log.Logger, token []byte, scope string) (*http.Client, error) {
-	googleCredentialBytes := []byte(config.Env.GoogleCredentials)
+	var credentials struct {
+		Type string `json:"type"`
+	}
+	// the OAuth clients have no type, an invalid JSON is reported by google.ConfigFromJSON
+	_ = json.Unmarshal(gs.credentials, &credentials)
+
+	if credentials.Type == serviceAccountType {
+		return gs.serviceAccountClient(ctx, logger, scope)
+	}
+	return gs.oauthClient(ctx, logger, token, scope)
+}
+
+func (gs *googleAuthStruct) serviceAccountClient(ctx context.Context, logger log.Logger, scope string) (*http.Client, error) {
+	jwtConfig, err := google.JWTConfigFromJSON(gs.credentials, scope)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("google.JWTConfigFromJSON"),
+			log.Reason(err.Error()),
+		)
 
-	gConfig, err := google.ConfigFromJSON(googleCredentialBytes, scope)
+		return nil, err
+	}
+	jwtConfig.Subject = gs.delegatedUser
+
+	return jwtConfig.Client(ctx), nil
+}
+
+func (gs *googleAuthStruct) oauthClient(ctx context.Context, logger log.Logger, token []byte, scope string) (*http.Client, error) {
+	gConfig, err := google.ConfigFromJSON(gs.credentials, scope)
 	if err != nil {
 		logger.Error(
 			ctx,
@@ -52,7 +106,103 @@ func (gs *googleAuthStruct) GetGClient(ctx context.Context, logger log.Logger, t
 		return nil, err
 	}
 
-	gClient := gConfig.Client(ctx, googleToken)
+	if gs.store == nil {
+		return gConfig.Client(ctx, googleToken), nil
+	}
+
+	key := tokenKey(scope, googleToken)
+	savedToken := gs.savedToken(ctx, logger, key)
+	if savedToken != nil {
+		googleToken = savedToken
+	}
+
+	source := &savingTokenSource{
+		ctx:         ctx,
+		logger:      logger,
+		store:       gs.store,
+		key:         key,
+		source:      gConfig.TokenSource(ctx, googleToken),
+		accessToken: googleToken.AccessToken,
+	}
+	return oauth2.NewClient(ctx, source), nil
+}
+
+// savedToken returns the last refresh of the token, nil when it was never refreshed or cannot be read
+func (gs *googleAuthStruct) savedToken(ctx context.Context, logger log.Logger, key string) *oauth2.Token {
+	saved, found, err := gs.store.GetGoogleToken(ctx, key)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("store.GetGoogleToken"),
+			log.Reason(err.Error()),
+		)
+		return nil
+	}
+	if !found {
+		return nil
+	}
+
+	savedToken := &oauth2.Token{}
+	err = json.Unmarshal([]byte(saved), savedToken)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("json.Unmarshal"),
+			log.Reason(err.Error()),
+		)
+		return nil
+	}
+	return savedToken
+}
+
+// tokenKey identifies the refreshes of the token of the environment, a new token of the environment starts
+// afresh instead of going on with the refreshes of the previous one
+func tokenKey(scope string, token *oauth2.Token) string {
+	sum := sha256.Sum256([]byte(token.RefreshToken))
+	return scope + " " + hex.EncodeToString(sum[:])
+}
+
+// savingTokenSource saves the token on the store whenever the source refreshes it
+type savingTokenSource struct {
+	ctx    context.Context
+	logger log.Logger
+	store  TokenStore
+	key    string
+	source oauth2.TokenSource
+
+	mu          sync.Mutex
+	accessToken string
+}
+
+func (s *savingTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.source.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if token.AccessToken == s.accessToken {
+		return token, nil
+	}
+	s.accessToken = token.AccessToken
+
+	tokenBytes, err := json.Marshal(token)
+	if err == nil {
+		err = s.store.SaveGoogleToken(s.ctx, s.key, string(tokenBytes))
+	}
+	if err != nil {
+		// the token is still good for this run, the next restart refreshes it again
+		s.logger.Error(
+			s.ctx,
+			log.Trace(),
+			log.Action("store.SaveGoogleToken"),
+			log.Reason(err.Error()),
+		)
+	}
 
-	return gClient, nil
+	return token, nil
 }
@@ -0,0 +1,255 @@
+package googleauth_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	googleauth "hellper/internal/google_auth"
+	"hellper/internal/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// tokenServer answers the token requests with a new access token each time, keeping the requests it receives
+type tokenServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []map[string]string
+}
+
+func newTokenServer(refused bool) *tokenServer {
+	s := &tokenServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		form := map[string]string{}
+		for name := range r.PostForm {
+			form[name] = r.PostForm.Get(name)
+		}
+		s.requests = append(s.requests, form)
+
+		w.Header().Set("Content-Type", "application/json")
+		if refused {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error": "invalid_grant"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"access_token": "access-%d", "token_type": "Bearer", "expires_in": 3600}`, len(s.requests))
+	}))
+	return s
+}
+
+// apiServer answers with the authorization header of the request
+func newAPIServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Header.Get("Authorization"))
+	}))
+}
+
+func get(t *testing.T, client *http.Client, url string) string {
+	response, err := client.Get(url)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+type tokenStoreStub struct {
+	tokens map[string]string
+	saved  []string
+}
+
+func (s *tokenStoreStub) GetGoogleToken(ctx context.Context, key string) (string, bool, error) {
+	token, found := s.tokens[key]
+	return token, found, nil
+}
+
+func (s *tokenStoreStub) SaveGoogleToken(ctx context.Context, key string, token string) error {
+	s.tokens[key] = token
+	s.saved = append(s.saved, key)
+	return nil
+}
+
+func serviceAccountCredentials(t *testing.T, tokenURL string) []byte {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	credentials, err := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"client_email":   "hellper@project.iam.gserviceaccount.com",
+		"private_key_id": "key-1",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		"token_uri":      tokenURL,
+	})
+	require.NoError(t, err)
+	return credentials
+}
+
+func oauthCredentials(tokenURL string) []byte {
+	return []byte(fmt.Sprintf(`{"installed": {
+		"client_id": "client-id",
+		"client_secret": "client-secret",
+		"auth_uri": "https://accounts.example.com/auth",
+		"token_uri": %q,
+		"redirect_uris": ["urn:ietf:wg:oauth:2.0:oob"]
+	}}`, tokenURL))
+}
+
+func TestServiceAccount(t *testing.T) {
+	table := []struct {
+		testName        string
+		delegatedUser   string
+		expectedSubject string
+	}{
+		{
+			testName: "Service account acting as itself",
+		},
+		{
+			testName:        "Domain-wide delegation",
+			delegatedUser:   "incidents@example.com",
+			expectedSubject: "incidents@example.com",
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			var (
+				ctx        = context.Background()
+				loggerMock = log.NewLoggerMock()
+				tokens     = newTokenServer(false)
+				api        = newAPIServer()
+			)
+			defer tokens.Close()
+			defer api.Close()
+
+			auth := googleauth.New(serviceAccountCredentials(t, tokens.URL), f.delegatedUser, nil)
+			client, err := auth.GetGClient(ctx, loggerMock, nil, "https://www.googleapis.com/auth/drive")
+			require.NoError(t, err)
+
+			assert.Equal(t, "Bearer access-1", get(t, client, api.URL))
+			require.Len(t, tokens.requests, 1)
+			assert.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", tokens.requests[0]["grant_type"])
+
+			parts := strings.Split(tokens.requests[0]["assertion"], ".")
+			require.Len(t, parts, 3)
+			payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+			require.NoError(t, err)
+
+			var claims struct {
+				Issuer  string `json:"iss"`
+				Scope   string `json:"scope"`
+				Subject string `json:"sub"`
+			}
+			require.NoError(t, json.Unmarshal(payload, &claims))
+			assert.Equal(t, "hellper@project.iam.gserviceaccount.com", claims.Issuer)
+			assert.Equal(t, "https://www.googleapis.com/auth/drive", claims.Scope)
+			assert.Equal(t, f.expectedSubject, claims.Subject)
+		})
+	}
+}
+
+func TestOAuthTokenRefresh(t *testing.T) {
+	var (
+		ctx        = context.Background()
+		loggerMock = log.NewLoggerMock()
+		tokens     = newTokenServer(false)
+		api        = newAPIServer()
+		store      = &tokenStoreStub{tokens: map[string]string{}}
+		scope      = "https://www.googleapis.com/auth/calendar"
+		envToken   = []byte(`{"access_token": "expired", "refresh_token": "refresh-1", "token_type": "Bearer", "expiry": "2020-01-01T00:00:00Z"}`)
+	)
+	defer tokens.Close()
+	defer api.Close()
+
+	auth := googleauth.New(oauthCredentials(tokens.URL), "", store)
+
+	client, err := auth.GetGClient(ctx, loggerMock, envToken, scope)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer access-1", get(t, client, api.URL))
+	assert.Equal(t, "Bearer access-1", get(t, client, api.URL), "the token is refreshed once")
+	require.Len(t, tokens.requests, 1)
+	assert.Equal(t, "refresh-1", tokens.requests[0]["refresh_token"])
+	require.Len(t, store.saved, 1)
+
+	var saved oauth2.Token
+	require.NoError(t, json.Unmarshal([]byte(store.tokens[store.saved[0]]), &saved))
+	assert.Equal(t, "access-1", saved.AccessToken)
+	assert.Equal(t, "refresh-1", saved.RefreshToken, "the refresh token survives the refresh")
+
+	// after a restart the saved token is used instead of the expired one of the environment
+	client, err = googleauth.New(oauthCredentials(tokens.URL), "", store).GetGClient(ctx, loggerMock, envToken, scope)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer access-1", get(t, client, api.URL))
+	assert.Len(t, tokens.requests, 1)
+
+	// a new token on the environment is not replaced by the refreshes of the previous one
+	newToken := []byte(`{"access_token": "new", "refresh_token": "refresh-2", "token_type": "Bearer", "expiry": "2999-01-01T00:00:00Z"}`)
+	client, err = auth.GetGClient(ctx, loggerMock, newToken, scope)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer new", get(t, client, api.URL))
+}
+
+func TestCheck(t *testing.T) {
+	var (
+		ctx        = context.Background()
+		loggerMock = log.NewLoggerMock()
+		authMock   = googleauth.NewAuthMock()
+		tokens     = newTokenServer(true)
+		api        = newAPIServer()
+	)
+	defer tokens.Close()
+	defer api.Close()
+
+	loggerMock.On("Info", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+	loggerMock.On("Error", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+
+	expiredToken := []byte(`{"access_token": "expired", "refresh_token": "revoked", "expiry": "2020-01-01T00:00:00Z"}`)
+	expiredClient, err := googleauth.New(oauthCredentials(tokens.URL), "", nil).GetGClient(ctx, loggerMock, expiredToken, "drive")
+	require.NoError(t, err)
+
+	authMock.On("GetGClient", ctx, loggerMock, []byte("drive"), "drive").Return(http.DefaultClient, nil)
+	authMock.On("GetGClient", ctx, loggerMock, []byte("expired"), "drive").Return(expiredClient, nil)
+	authMock.On("GetGClient", ctx, loggerMock, []byte("calendar"), "calendar").Return(nil, errors.New("oauth2/google: missing redirect URL in the client_credentials.json"))
+
+	probe := func(ctx context.Context, client *http.Client) error {
+		response, err := client.Get(api.URL)
+		if err != nil {
+			return err
+		}
+		return response.Body.Close()
+	}
+
+	statuses := googleauth.Check(ctx, loggerMock, authMock, []googleauth.Capability{
+		{Name: "google_drive", Scope: "drive", Token: []byte("drive"), Probe: probe},
+		{Name: "google_docs", Scope: "drive", Token: []byte("expired"), Probe: probe},
+		{Name: "google_calendar", Scope: "calendar", Token: []byte("calendar"), Probe: probe},
+	})
+
+	require.Len(t, statuses, 3)
+	assert.Equal(t, googleauth.Status{Name: "google_drive", Usable: true}, statuses[0])
+	assert.False(t, statuses[1].Usable)
+	assert.Contains(t, statuses[1].Reason, "the token was refused, it expired or was revoked")
+	assert.Equal(t, googleauth.Status{Name: "google_calendar", Reason: "oauth2/google: missing redirect URL in the client_credentials.json"}, statuses[2])
+	loggerMock.AssertNumberOfCalls(t, "Error", 2)
+}
@@ -0,0 +1,132 @@
+package googleauth
+
+import (
+	"context"
+	"errors"
+	"hellper/internal/log"
+	"net/http"
+
+	"golang.org/x/oauth2"
+	gCalendar "google.golang.org/api/calendar/v3"
+	"google.golang.org/api/docs/v1"
+	"google.golang.org/api/drive/v3"
+)
+
+// Capability is a Google API hellper depends on, probed with a cheap request on its scope
+type Capability struct {
+	Name  string
+	Scope string
+	Token []byte
+	Probe func(context.Context, *http.Client) error
+}
+
+// Status tells whether a capability can be used, with the reason when it cannot
+type Status struct {
+	Name   string
+	Usable bool
+	Reason string
+}
+
+// Check probes the capabilities, logging which of them are usable, so expired tokens and missing
+// permissions show up when hellper starts instead of when the next incident needs them
+func Check(ctx context.Context, logger log.Logger, auth Interface, capabilities []Capability) []Status {
+	statuses := make([]Status, 0, len(capabilities))
+	for _, capability := range capabilities {
+		status := Status{Name: capability.Name, Usable: true}
+
+		client, err := auth.GetGClient(ctx, logger, capability.Token, capability.Scope)
+		if err == nil {
+			err = capability.Probe(ctx, client)
+		}
+		if err != nil {
+			status.Usable = false
+			status.Reason = checkReason(err)
+			logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("capability.Probe"),
+				log.Reason(status.Reason),
+				log.NewValue("capability", capability.Name),
+			)
+		} else {
+			logger.Info(
+				ctx,
+				log.Trace(),
+				log.NewValue("capability", capability.Name),
+				log.NewValue("usable", true),
+			)
+		}
+
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// checkReason tells the token refusals apart, they need a new authorization rather than a permission
+func checkReason(err error) string {
+	var retrieveError *oauth2.RetrieveError
+	if errors.As(err, &retrieveError) {
+		return "the token was refused, it expired or was revoked and must be authorized again: " + err.Error()
+	}
+	return err.Error()
+}
+
+// DriveCapability probes the Google Drive copying the post mortem template
+func DriveCapability(token []byte, fileID string) Capability {
+	return Capability{
+		Name:  "google_drive",
+		Scope: drive.DriveScope,
+		Token: token,
+		Probe: func(ctx context.Context, client *http.Client) error {
+			if fileID == "" {
+				return errors.New("no post mortem template file id")
+			}
+			service, err := drive.New(client)
+			if err != nil {
+				return err
+			}
+			_, err = service.Files.Get(fileID).Fields("id").Context(ctx).Do()
+			return err
+		},
+	}
+}
+
+// DocsCapability probes the Google Docs filling the post mortems, which share the Google Drive token
+func DocsCapability(token []byte, documentID string) Capability {
+	return Capability{
+		Name:  "google_docs",
+		Scope: drive.DriveScope,
+		Token: token,
+		Probe: func(ctx context.Context, client *http.Client) error {
+			if documentID == "" {
+				return errors.New("no post mortem template file id")
+			}
+			service, err := docs.New(client)
+			if err != nil {
+				return err
+			}
+			_, err = service.Documents.Get(documentID).Fields("documentId").Context(ctx).Do()
+			return err
+		},
+	}
+}
+
+// CalendarCapability probes the Google Calendar booking the post mortem meetings and the Google Meet war rooms
+func CalendarCapability(token []byte, calendarID string) Capability {
+	return Capability{
+		Name:  "google_calendar",
+		Scope: gCalendar.CalendarScope,
+		Token: token,
+		Probe: func(ctx context.Context, client *http.Client) error {
+			if calendarID == "" {
+				return errors.New("no calendar id")
+			}
+			service, err := gCalendar.New(client)
+			if err != nil {
+				return err
+			}
+			_, err = service.Calendars.Get(calendarID).Fields("id").Context(ctx).Do()
+			return err
+		},
+	}
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"hellper/internal/alert"
+	"hellper/internal/bot"
+	"hellper/internal/calendar"
+	"hellper/internal/commands"
+	"hellper/internal/concurrence"
+	"hellper/internal/config"
+	filestorage "hellper/internal/file_storage"
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/warroom"
+	"hellper/internal/workcalendar"
+)
+
+type handlerAlerts struct {
+	logger       log.Logger
+	client       bot.Client
+	repository   model.Repository
+	fileStorage  filestorage.Driver
+	background   *concurrence.Background
+	warRoom      warroom.Provider
+	calendar     calendar.Calendar
+	workCalendar workcalendar.Calendar
+	settings     alert.Settings
+}
+
+// maxAlertsBodySize bounds the payloads read from the alerts webhook, a notification of the monitoring tools is far smaller
+const maxAlertsBodySize = 1 << 20
+
+func newHandlerAlerts(
+	logger log.Logger,
+	client bot.Client,
+	repository model.Repository,
+	fileStorage filestorage.Driver,
+	background *concurrence.Background,
+	warRoom warroom.Provider,
+	calendar calendar.Calendar,
+	workCalendar workcalendar.Calendar,
+	settings alert.Settings,
+) *handlerAlerts {
+	return &handlerAlerts{
+		logger:       logger,
+		client:       client,
+		repository:   repository,
+		fileStorage:  fileStorage,
+		background:   background,
+		warRoom:      warRoom,
+		calendar:     calendar,
+		workCalendar: workCalendar,
+		settings:     settings,
+	}
+}
+
+// verifyAlertsToken accepts the token as a bearer token or as the basic auth password, the alerts are refused without token
+func verifyAlertsToken(r *http.Request) bool {
+	token := config.Env.AlertsToken
+	if token == "" {
+		return false
+	}
+
+	received := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if _, password, ok := r.BasicAuth(); ok {
+		received = password
+	}
+	return subtle.ConstantTimeCompare([]byte(received), []byte(token)) == 1
+}
+
+func (h *handlerAlerts) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		logger = h.logger
+		source = r.URL.Query().Get("source")
+	)
+
+	if !verifyAlertsToken(r) {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("verifyAlertsToken"),
+			log.Reason("invalid token"),
+			log.NewValue("remoteAddr", r.RemoteAddr),
+		)
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	if source == "" {
+		source = alert.SourceGeneric
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxAlertsBodySize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	alerts, err := alert.Parse(source, body)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("alert.Parse"),
+			log.Reason(err.Error()),
+			log.NewValue("source", source),
+		)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// the payloads carry the labels and annotations of the alerts, which may hold secrets, so only their count is logged
+	logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("source", source),
+		log.NewValue("alerts", len(alerts)),
+	)
+
+	err = commands.ReceiveAlerts(ctx, h.client, logger, h.repository, h.fileStorage, h.background, h.warRoom, h.calendar, h.workCalendar, h.settings, source, alerts)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("commands.ReceiveAlerts"),
+			log.Reason(err.Error()),
+			log.NewValue("source", source),
+		)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
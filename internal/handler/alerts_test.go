@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"hellper/internal/alert"
+	"hellper/internal/bot"
+	"hellper/internal/config"
+	"hellper/internal/log/zap"
+	"hellper/internal/model"
+	"hellper/internal/workcalendar"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerAlerts(test *testing.T) {
+	scenarios := []struct {
+		name           string
+		token          string
+		authorize      func(r *http.Request)
+		url            string
+		body           string
+		responseStatus int
+	}{
+		{
+			name:           "Bearer token",
+			token:          "s3cr3t",
+			authorize:      func(r *http.Request) { r.Header.Set("Authorization", "Bearer s3cr3t") },
+			url:            "/alerts?source=alertmanager",
+			body:           `{"alerts": []}`,
+			responseStatus: http.StatusOK,
+		},
+		{
+			name:           "Basic auth password",
+			token:          "s3cr3t",
+			authorize:      func(r *http.Request) { r.SetBasicAuth("grafana", "s3cr3t") },
+			url:            "/alerts?source=grafana",
+			body:           `{"ruleId": 7, "state": "pending"}`,
+			responseStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid token",
+			token:          "s3cr3t",
+			authorize:      func(r *http.Request) { r.Header.Set("Authorization", "Bearer other") },
+			url:            "/alerts",
+			body:           `{"alerts": []}`,
+			responseStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Webhook without token is disabled",
+			authorize:      func(r *http.Request) { r.Header.Set("Authorization", "Bearer ") },
+			url:            "/alerts",
+			body:           `{"alerts": []}`,
+			responseStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Unknown source",
+			token:          "s3cr3t",
+			authorize:      func(r *http.Request) { r.Header.Set("Authorization", "Bearer s3cr3t") },
+			url:            "/alerts?source=nagios",
+			body:           `{}`,
+			responseStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid payload",
+			token:          "s3cr3t",
+			authorize:      func(r *http.Request) { r.Header.Set("Authorization", "Bearer s3cr3t") },
+			url:            "/alerts",
+			body:           `{"alerts": [{"status": "firing"}]}`,
+			responseStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Payload too large",
+			token:          "s3cr3t",
+			authorize:      func(r *http.Request) { r.Header.Set("Authorization", "Bearer s3cr3t") },
+			url:            "/alerts",
+			body:           `{"alerts": [], "padding": "` + strings.Repeat("x", maxAlertsBodySize) + `"}`,
+			responseStatus: http.StatusBadRequest,
+		},
+	}
+
+	for index, scenario := range scenarios {
+		test.Run(
+			fmt.Sprintf("%d-%s", index, scenario.name),
+			func(t *testing.T) {
+				token := config.Env.AlertsToken
+				config.Env.AlertsToken = scenario.token
+				defer func() { config.Env.AlertsToken = token }()
+
+				r := httptest.NewRequest("POST", scenario.url, strings.NewReader(scenario.body))
+				scenario.authorize(r)
+				response := httptest.NewRecorder()
+
+				h := newHandlerAlerts(zap.NewDefault(), bot.NewClientMock(), model.NewRepositoryMock(), nil, nil, nil, nil, workcalendar.Calendar{}, alert.Settings{})
+				h.ServeHTTP(response, r)
+				require.Equal(t, scenario.responseStatus, response.Result().StatusCode)
+			},
+		)
+	}
+}
@@ -7,12 +7,13 @@ import (
 	"hellper/internal/commands"
 	"hellper/internal/log"
 	"hellper/internal/model"
+	"hellper/internal/pager"
 
 	"github.com/slack-go/slack/slackevents"
 )
 
 func replyCallbackEvent(
-	ctx context.Context, logger log.Logger, client bot.Client, repository model.Repository, event slackevents.EventsAPIEvent,
+	ctx context.Context, logger log.Logger, client bot.Client, repository model.Repository, pager *pager.Service, event slackevents.EventsAPIEvent,
 ) error {
 	var (
 		innerEvent = event.InnerEvent
@@ -42,7 +43,42 @@ func replyCallbackEvent(
 			log.Trace(),
 			log.NewValue("callbackEvent", callbackEvent),
 		)
-		return nil
+		// Only the messages the commander posts acknowledge the page, not their edits, joins or the bot messages
+		if pager == nil || callbackEvent.SubType != "" || callbackEvent.BotID != "" {
+			return nil
+		}
+		return pager.Acknowledge(ctx, callbackEvent.Channel, callbackEvent.User)
+	case *slackevents.AppHomeOpenedEvent:
+		logger.Info(
+			ctx,
+			log.Trace(),
+			log.NewValue("callbackEvent", callbackEvent),
+		)
+		if callbackEvent.Tab != "home" {
+			return nil
+		}
+		return commands.PublishAppHome(ctx, client, logger, repository, callbackEvent.User)
+	case *slackevents.MemberJoinedChannelEvent:
+		logger.Info(
+			ctx,
+			log.Trace(),
+			log.NewValue("callbackEvent", callbackEvent),
+		)
+		// Hellper invites the commander when the incident is opened, only joining the channel on their own acknowledges the page
+		if pager == nil || callbackEvent.Inviter != "" {
+			return nil
+		}
+		return pager.Acknowledge(ctx, callbackEvent.Channel, callbackEvent.User)
+	case *slackevents.PinAddedEvent:
+		logger.Info(
+			ctx,
+			log.Trace(),
+			log.NewValue("callbackEvent", callbackEvent),
+		)
+		if pager == nil {
+			return nil
+		}
+		return pager.Acknowledge(ctx, callbackEvent.Channel, callbackEvent.User)
 	case *slackevents.AppUninstalledEvent:
 		logger.Info(
 			ctx,
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"net/http"
 
+	"hellper/internal/authorization"
 	"hellper/internal/bot"
 	"hellper/internal/commands"
 	"hellper/internal/log"
@@ -14,13 +15,15 @@ type handlerClose struct {
 	logger     log.Logger
 	client     bot.Client
 	repository model.Repository
+	policy     authorization.Policy
 }
 
-func newHandlerClose(logger log.Logger, client bot.Client, repository model.Repository) *handlerClose {
+func newHandlerClose(logger log.Logger, client bot.Client, repository model.Repository, policy authorization.Policy) *handlerClose {
 	return &handlerClose{
 		logger:     logger,
 		client:     client,
 		repository: repository,
+		policy:     policy,
 	}
 }
 
@@ -55,7 +58,25 @@ func (h *handlerClose) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	channelID := r.FormValue("channel_id")
 	userID := r.FormValue("user_id")
 
-	err := commands.CloseIncidentDialog(ctx, logger, h.client, h.repository, channelID, userID, triggerID)
+	allowed, err := commands.AuthorizeIncidentAction(ctx, h.client, logger, h.repository, h.policy, authorization.ActionClose, channelID, userID)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("commands.AuthorizeIncidentAction"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", channelID),
+			log.NewValue("userID", userID),
+		)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !allowed {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	err = commands.CloseIncidentDialog(ctx, logger, h.client, h.repository, channelID, userID, triggerID)
 	if err != nil {
 		logger.Error(
 			ctx,
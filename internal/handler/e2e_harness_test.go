@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"hellper/internal"
+	"hellper/internal/authorization"
+	"hellper/internal/bot"
+	"hellper/internal/bot/slack/slackfake"
+	"hellper/internal/calendar"
+	"hellper/internal/concurrence"
+	"hellper/internal/config"
+	filestorage "hellper/internal/file_storage"
+	"hellper/internal/log/zap"
+	"hellper/internal/model"
+	"hellper/internal/model/sql"
+	"hellper/internal/model/sql/postgres"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	e2eSigningSecret = "e2e-signing-secret"
+	e2ePolicy        = "resolve=commander,author;close=commander,usergroup:SINCIDENTS;cancel=commander,author"
+)
+
+// e2eHarness runs NewHandlerRoute against the fake Slack API and a real Postgres database.
+// The database is taken from HELLPER_E2E_DSN, with the schema of internal/model/sql/postgres/schema loaded.
+type e2eHarness struct {
+	t *testing.T
+
+	slack       *slackfake.Server
+	sender      *slackfake.Sender
+	hellper     *httptest.Server
+	db          sql.DB
+	repository  model.Repository
+	fileStorage *filestorage.FileStorageMock
+	background  *concurrence.Background
+	calendar    *calendar.CalendarMock
+	policy      authorization.Policy
+
+	productChannelID string
+}
+
+func newE2EHarness(t *testing.T) *e2eHarness {
+	dsn := os.Getenv("HELLPER_E2E_DSN")
+	if dsn == "" {
+		t.Skip("HELLPER_E2E_DSN is not set, skipping the end-to-end test")
+	}
+
+	h := &e2eHarness{
+		t:           t,
+		slack:       slackfake.NewServer(),
+		db:          sql.NewDBWithDSN("postgres", dsn),
+		fileStorage: filestorage.NewFileStorageMock(),
+		background:  &concurrence.Background{},
+		calendar:    calendar.NewCalendarMock(),
+	}
+	require.NoError(t, h.db.Ping())
+
+	policy, err := authorization.ParsePolicy(e2ePolicy)
+	require.NoError(t, err)
+	h.policy = policy
+
+	logger := zap.NewDefault()
+	h.repository = postgres.NewRepository(logger, h.db)
+	h.productChannelID = h.slack.AddChannel("incidents")
+
+	h.fileStorage.On("CreatePostMortemDocument", mock.Anything, mock.AnythingOfType("filestorage.PostMortemData")).Return("https://postmortem.example/doc", nil)
+	h.fileStorage.On("UpdatePostMortemDocument", mock.Anything, "https://postmortem.example/doc", mock.AnythingOfType("filestorage.PostMortemData")).Return(nil)
+	h.fileStorage.On(
+		"UploadFile",
+		mock.Anything,
+		mock.AnythingOfType("string"),
+		mock.AnythingOfType("string"),
+		mock.AnythingOfType("[]uint8"),
+	).Return("https://postmortem.example/timeline", nil)
+	h.calendar.On(
+		"CreateCalendarEvent",
+		mock.Anything,
+		mock.AnythingOfType("string"),
+		mock.AnythingOfType("string"),
+		mock.AnythingOfType("string"),
+		mock.AnythingOfType("string"),
+		mock.AnythingOfType("[]string"),
+	).Return(&model.Event{EventURL: "https://calendar.example/event", Start: &time.Time{}, End: &time.Time{}}, nil)
+
+	config.Env.SlackSigningSecret = e2eSigningSecret
+	config.Env.ProductChannelID = h.productChannelID
+
+	initHandlers(
+		logger,
+		h.slack.Client(),
+		h.repository,
+		h.fileStorage,
+		h.background,
+		h.calendar,
+		h.policy,
+		internal.NewSnoozeLimits(),
+		internal.NewWorkCalendar(),
+		internal.NewStatusPage(logger, h.slack.Client(), h.repository, h.background),
+		internal.NewPager(logger, h.slack.Client(), h.repository, h.background),
+		internal.NewAlertSettings(),
+		internal.NewIssueTracker(),
+		internal.NewWarRoom(h.calendar),
+	)
+	h.hellper = httptest.NewServer(http.HandlerFunc(NewHandlerRoute()))
+	h.sender = slackfake.NewSender(h.hellper.URL, e2eSigningSecret)
+
+	return h
+}
+
+func (h *e2eHarness) close() {
+	h.hellper.Close()
+	h.slack.Close()
+	h.db.Close()
+}
+
+// addUser registers a workspace member on the fake Slack
+func (h *e2eHarness) addUser(id, name, email string) {
+	user := slack.User{ID: id, Name: name}
+	user.Profile.Email = email
+	h.slack.AddUser(user)
+}
+
+func (h *e2eHarness) slashCommand(path, command, channelID, userID string) {
+	resp, err := h.sender.SlashCommand(path, command, channelID, userID, "")
+	require.NoError(h.t, err)
+	ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+}
+
+func (h *e2eHarness) submitDialog(callbackID, channelID, userID string, submission bot.Submission) {
+	channel, _ := h.slack.Channel(channelID)
+
+	resp, err := h.sender.Interaction("/interactive", bot.DialogSubmission{
+		Type:       "dialog_submission",
+		CallbackID: callbackID,
+		User:       bot.User{ID: userID, Name: userID},
+		Channel:    bot.Channel{ID: channel.ID, Name: channel.Name},
+		Submission: submission,
+	})
+	require.NoError(h.t, err)
+	ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+}
+
+// eventually waits for the asynchronous work started by a request, like the postmortem creation
+func (h *e2eHarness) eventually(condition func() bool, msg string) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if condition() {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	h.t.Fatal("condition not met: " + msg)
+}
+
+// waitBackground waits for the timeline exports and channel archives started by the requests
+func (h *e2eHarness) waitBackground() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(h.t, h.background.Wait(ctx))
+}
+
+func (h *e2eHarness) incident(channelID string) model.Incident {
+	inc, err := h.repository.GetIncident(context.Background(), channelID)
+	require.NoError(h.t, err)
+	return inc
+}
+
+func (h *e2eHarness) cleanupIncident(channelID string) {
+	h.db.Exec(`DELETE FROM incident WHERE channel_id = $1`, channelID)
+	h.db.Exec(`DELETE FROM audit_log WHERE channel_id = $1`, channelID)
+}
+
+// auditLog returns the authorization decisions recorded for a channel, as action:user:allowed
+func (h *e2eHarness) auditLog(channelID string) []string {
+	rows, err := h.db.Query(`SELECT action, user_id, allowed FROM audit_log WHERE channel_id = $1 ORDER BY id`, channelID)
+	require.NoError(h.t, err)
+	defer rows.Close()
+
+	var records []string
+	for rows.Next() {
+		var (
+			action, userID string
+			allowed        bool
+		)
+		require.NoError(h.t, rows.Scan(&action, &userID, &allowed))
+		records = append(records, fmt.Sprintf("%s:%s:%t", action, userID, allowed))
+	}
+	return records
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"hellper/internal/bot"
+	"hellper/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestE2EIncidentLifecycle(t *testing.T) {
+	h := newE2EHarness(t)
+	defer h.close()
+
+	h.addUser("UAUTHOR", "author", "author@example.com")
+	h.addUser("UCOMMANDER", "commander", "commander@example.com")
+
+	channelName := fmt.Sprintf("inc-e2e-%d", time.Now().UnixNano()%1000000)
+
+	h.slashCommand("/open", "/hellper_incident", h.productChannelID, "UAUTHOR")
+	require.Len(t, h.slack.Dialogs(), 1)
+	assert.Equal(t, "inc-open", h.slack.Dialogs()[0].Dialog.CallbackID)
+
+	h.submitDialog("inc-open", h.productChannelID, "UAUTHOR", bot.Submission{
+		IncidentTitle:       "Email delivery delayed",
+		ChannelName:         channelName,
+		WarRoomURL:          "https://meet.example/room",
+		SeverityLevel:       "1",
+		Product:             "Product A",
+		IncidentCommander:   "UCOMMANDER",
+		IncidentDescription: "Campaign emails are delayed",
+	})
+
+	channel, ok := h.slack.Channel(channelName)
+	require.True(t, ok, "incident channel was not created")
+	defer h.cleanupIncident(channel.ID)
+
+	assert.Contains(t, channel.Members, "UCOMMANDER")
+	assert.NotEmpty(t, h.slack.PinnedMessages(channel.ID))
+	assert.NotEmpty(t, h.slack.PinnedMessages(h.productChannelID))
+	h.eventually(func() bool {
+		channel, _ := h.slack.Channel(channel.ID)
+		return strings.Contains(channel.Topic, "https://postmortem.example/doc")
+	}, "topic with the postmortem document")
+
+	inc := h.incident(channel.ID)
+	assert.Equal(t, model.StatusOpen, inc.Status)
+	assert.Equal(t, "Email delivery delayed", inc.Title)
+	assert.Equal(t, int64(1), inc.SeverityLevel)
+	assert.Equal(t, "UCOMMANDER", inc.CommanderId)
+	assert.Equal(t, "commander@example.com", inc.CommanderEmail)
+	assert.Equal(t, "https://postmortem.example/doc", inc.PostMortemUrl)
+
+	h.submitDialog("inc-resolve", channel.ID, "UCOMMANDER", bot.Submission{
+		StatusIO:            "https://status.example/incident",
+		IncidentDescription: "Queue drained",
+		PostMortemMeeting:   "false",
+	})
+	inc = h.incident(channel.ID)
+	assert.Equal(t, model.StatusResolved, inc.Status)
+	assert.Equal(t, "Queue drained", inc.DescriptionResolved)
+
+	h.submitDialog("inc-dates", channel.ID, "UCOMMANDER", bot.Submission{
+		TimeZone:           "0",
+		InitDate:           "01/02/2021 10:00:00",
+		IdentificationDate: "01/02/2021 10:15:00",
+		EndDate:            "01/02/2021 11:00:00",
+	})
+	inc = h.incident(channel.ID)
+	require.NotNil(t, inc.StartTimestamp)
+	assert.Equal(t, time.Date(2021, 2, 1, 10, 0, 0, 0, time.UTC), inc.StartTimestamp.UTC())
+
+	h.slashCommand("/close", "/hellper_close", channel.ID, "UCOMMANDER")
+	assert.Equal(t, "inc-close", h.slack.Dialogs()[len(h.slack.Dialogs())-1].Dialog.CallbackID)
+
+	h.submitDialog("inc-close", channel.ID, "UCOMMANDER", bot.Submission{
+		Impact:         "42",
+		Team:           "Mushin",
+		Feature:        "Email",
+		SeverityLevel:  "2",
+		Responsibility: "0",
+		RootCause:      "Stuck worker",
+	})
+	inc = h.incident(channel.ID)
+	assert.Equal(t, model.StatusClosed, inc.Status)
+	assert.Equal(t, int64(42), inc.CustomerImpact.Int64)
+	assert.Equal(t, "Stuck worker", inc.RootCause)
+
+	h.waitBackground()
+	archived, _ := h.slack.Channel(channel.ID)
+	assert.True(t, archived.Archived)
+
+	h.fileStorage.AssertCalled(t, "UploadFile", mock.Anything, fmt.Sprintf("%d - Timeline - Email delivery delayed.md", inc.Id), "text/markdown", mock.Anything)
+	h.fileStorage.AssertCalled(t, "UploadFile", mock.Anything, fmt.Sprintf("%d - Timeline - Email delivery delayed.json", inc.Id), "application/json", mock.Anything)
+}
+
+func TestE2ELifecycleCommandsAuthorization(t *testing.T) {
+	h := newE2EHarness(t)
+	defer h.close()
+
+	h.addUser("UAUTHOR", "author", "author@example.com")
+	h.addUser("UCOMMANDER", "commander", "commander@example.com")
+	h.addUser("UOPS", "ops", "ops@example.com")
+	h.addUser("UBYSTANDER", "bystander", "bystander@example.com")
+	h.slack.AddUserGroup("SINCIDENTS", "UOPS")
+
+	channelName := fmt.Sprintf("inc-e2e-auth-%d", time.Now().UnixNano()%1000000)
+	h.slashCommand("/open", "/hellper_incident", h.productChannelID, "UAUTHOR")
+	h.submitDialog("inc-open", h.productChannelID, "UAUTHOR", bot.Submission{
+		IncidentTitle:       "Checkout errors",
+		ChannelName:         channelName,
+		SeverityLevel:       "2",
+		Product:             "Product A",
+		IncidentCommander:   "UCOMMANDER",
+		IncidentDescription: "Payments are failing",
+	})
+
+	channel, ok := h.slack.Channel(channelName)
+	require.True(t, ok, "incident channel was not created")
+	defer h.cleanupIncident(channel.ID)
+	assert.Equal(t, "UAUTHOR", h.incident(channel.ID).IncidentAuthor)
+
+	dialogs := len(h.slack.Dialogs())
+	h.slashCommand("/close", "/hellper_close", channel.ID, "UBYSTANDER")
+	assert.Len(t, h.slack.Dialogs(), dialogs, "the close dialog was opened for a bystander")
+
+	messages := h.slack.Messages(channel.ID)
+	denial := messages[len(messages)-1]
+	assert.True(t, denial.Ephemeral)
+	require.Len(t, denial.Attachments, 1)
+	assert.Equal(t, "Permission denied", denial.Attachments[0].Fields[0].Title)
+
+	h.slashCommand("/resolve", "/hellper_resolve", channel.ID, "UAUTHOR")
+	h.slashCommand("/close", "/hellper_close", channel.ID, "UOPS")
+	assert.Len(t, h.slack.Dialogs(), dialogs+2)
+
+	assert.Equal(t, []string{
+		"close:UBYSTANDER:false",
+		"resolve:UAUTHOR:true",
+		"close:UOPS:true",
+	}, h.auditLog(channel.ID))
+}
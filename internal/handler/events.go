@@ -10,6 +10,7 @@ import (
 	"hellper/internal/bot"
 	"hellper/internal/log"
 	"hellper/internal/model"
+	"hellper/internal/pager"
 
 	"github.com/slack-go/slack/slackevents"
 )
@@ -20,17 +21,19 @@ type handlerEvents struct {
 	logger     log.Logger
 	client     bot.Client
 	repository model.Repository
+	pager      *pager.Service
 }
 
 func stringSha1(v string) string {
 	return fmt.Sprintf("%x", sha1.Sum([]byte(v)))
 }
 
-func newHandlerEvents(logger log.Logger, client bot.Client, repository model.Repository) *handlerEvents {
+func newHandlerEvents(logger log.Logger, client bot.Client, repository model.Repository, pager *pager.Service) *handlerEvents {
 	return &handlerEvents{
 		logger:     logger,
 		client:     client,
 		repository: repository,
+		pager:      pager,
 	}
 }
 
@@ -89,7 +92,7 @@ func (h *handlerEvents) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			log.NewValue("message", msgsCache),
 		)
 
-		err = replyCallbackEvent(ctx, h.logger, h.client, h.repository, event)
+		err = replyCallbackEvent(ctx, h.logger, h.client, h.repository, h.pager, event)
 		if err != nil {
 			logger.Error(
 				ctx,
@@ -9,8 +9,10 @@ import (
 	"time"
 
 	"hellper/internal/bot"
+	"hellper/internal/concurrence"
 	"hellper/internal/log/zap"
 	"hellper/internal/model"
+	"hellper/internal/pager"
 
 	"github.com/slack-go/slack"
 	"github.com/stretchr/testify/mock"
@@ -296,7 +298,7 @@ func TestHandler(test *testing.T) {
 			fmt.Sprintf("%d-%s", index, scenario.name),
 			func(t *testing.T) {
 				scenario.setup(t)
-				h := newHandlerEvents(zap.NewDefault(), scenario.mockClient, scenario.mockRepository)
+				h := newHandlerEvents(zap.NewDefault(), scenario.mockClient, scenario.mockRepository, nil)
 				h.ServeHTTP(scenario.response, scenario.request)
 				result := scenario.response.Result()
 				require.Equal(t, scenario.responseStatus, result.StatusCode, "invalid statuscode value")
@@ -304,3 +306,73 @@ func TestHandler(test *testing.T) {
 		)
 	}
 }
+
+func TestHandlerAcknowledgesPage(test *testing.T) {
+	scenarios := []struct {
+		name              string
+		event             string
+		expectAcknowledge bool
+	}{
+		{
+			name:              "Commander pins an update",
+			event:             `{"type":"pin_added","user":"U1","channel_id":"C1","item":{"type":"message","channel":"C1"},"event_ts":"1545096726.001100"}`,
+			expectAcknowledge: true,
+		},
+		{
+			name:              "Commander joins the channel",
+			event:             `{"type":"member_joined_channel","user":"U1","channel":"C1","channel_type":"C","team":"T1"}`,
+			expectAcknowledge: true,
+		},
+		{
+			name:  "Commander invited to the channel",
+			event: `{"type":"member_joined_channel","user":"U1","channel":"C1","channel_type":"C","team":"T1","inviter":"UHELLPER"}`,
+		},
+		{
+			name:              "Commander posts an update",
+			event:             `{"type":"message","user":"U1","text":"Rolling back the deploy","channel":"C1","channel_type":"channel","ts":"1545096726.001200","event_ts":"1545096726.001200"}`,
+			expectAcknowledge: true,
+		},
+		{
+			name:  "Another user posts an update",
+			event: `{"type":"message","user":"U2","text":"Looking into it","channel":"C1","channel_type":"channel","ts":"1545096726.001300","event_ts":"1545096726.001300"}`,
+		},
+		{
+			name:  "Commander edits a message",
+			event: `{"type":"message","subtype":"message_changed","channel":"C1","channel_type":"channel","message":{"type":"message","user":"U1","text":"Rolled back"},"ts":"1545096726.001400","event_ts":"1545096726.001400"}`,
+		},
+		{
+			name:  "Another user pins an update",
+			event: `{"type":"pin_added","user":"U2","channel_id":"C1","item":{"type":"message","channel":"C1"},"event_ts":"1545096726.001100"}`,
+		},
+	}
+
+	for index, scenario := range scenarios {
+		test.Run(
+			fmt.Sprintf("%d-%s", index, scenario.name),
+			func(t *testing.T) {
+				msgsCache = map[string]struct{}{}
+				var (
+					logger         = zap.NewDefault()
+					repositoryMock = model.NewRepositoryMock()
+					fake           = pager.NewFake()
+					response       = httptest.NewRecorder()
+				)
+				repositoryMock.On("GetIncident", "C1").Return(model.Incident{Id: 42, ChannelId: "C1", Product: "Checkout", Status: model.StatusOpen, CommanderId: "U1"}, nil)
+
+				body := `{"token":"t","team_id":"T1","api_app_id":"A1","type":"event_callback","event_id":"Ev1","event_time":1545096726,"event":` + scenario.event + `}`
+				r := httptest.NewRequest("POST", "/events", strings.NewReader(body))
+				r.Header.Set("content-type", "application/json")
+
+				h := newHandlerEvents(logger, bot.NewClientMock(), repositoryMock, pager.NewService(logger, bot.NewClientMock(), repositoryMock, &concurrence.Background{}, fake, pager.Routes{"*": "R1"}))
+				h.ServeHTTP(response, r)
+				require.Equal(t, http.StatusAccepted, response.Result().StatusCode)
+
+				if !scenario.expectAcknowledge {
+					require.Empty(t, fake.Events())
+					return
+				}
+				require.Equal(t, []pager.Event{{RoutingKey: "R1", Action: pager.ActionAcknowledge, DedupKey: "42"}}, fake.Events())
+			},
+		)
+	}
+}
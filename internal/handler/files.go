@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"path"
+
+	filestorage "hellper/internal/file_storage"
+	"hellper/internal/log"
+)
+
+type handlerFiles struct {
+	logger log.Logger
+	reader filestorage.Reader
+}
+
+func newHandlerFiles(logger log.Logger, fileStorage filestorage.Driver) *handlerFiles {
+	reader, _ := fileStorage.(filestorage.Reader)
+	return &handlerFiles{
+		logger: logger,
+		reader: reader,
+	}
+}
+
+// ServeHTTP serves the post mortems and timelines of the file storages whose files are not served by themselves
+func (h *handlerFiles) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = r.Context()
+		key = path.Base(r.URL.Path)
+	)
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if h.reader == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	content, contentType, err := h.reader.ReadFile(ctx, key)
+	if errors.Is(err, filestorage.ErrFileNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("reader.ReadFile"),
+			log.Reason(err.Error()),
+			log.NewValue("key", key),
+		)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		w.Write(content)
+	}
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	filestorage "hellper/internal/file_storage"
+	"hellper/internal/log/zap"
+
+	"github.com/stretchr/testify/require"
+)
+
+type readerStub struct {
+	*filestorage.FileStorageMock
+	files map[string]string
+	err   error
+}
+
+func (r *readerStub) ReadFile(ctx context.Context, key string) ([]byte, string, error) {
+	if r.err != nil {
+		return nil, "", r.err
+	}
+	content, ok := r.files[key]
+	if !ok {
+		return nil, "", filestorage.ErrFileNotFound
+	}
+	return []byte(content), "text/markdown; charset=utf-8", nil
+}
+
+func TestHandlerFiles(test *testing.T) {
+	scenarios := []struct {
+		name           string
+		fileStorage    filestorage.Driver
+		method         string
+		url            string
+		responseStatus int
+		responseBody   string
+	}{
+		{
+			name:           "Stored file",
+			fileStorage:    &readerStub{files: map[string]string{"postmortem-0123456789abcdef.md": "# Post Mortem"}},
+			method:         http.MethodGet,
+			url:            "/files/postmortem-0123456789abcdef.md",
+			responseStatus: http.StatusOK,
+			responseBody:   "# Post Mortem",
+		},
+		{
+			name:           "Missing file",
+			fileStorage:    &readerStub{},
+			method:         http.MethodGet,
+			url:            "/files/postmortem-0123456789abcdef.md",
+			responseStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Storage failure",
+			fileStorage:    &readerStub{err: errors.New("connection refused")},
+			method:         http.MethodGet,
+			url:            "/files/postmortem-0123456789abcdef.md",
+			responseStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "Storage serving its own files",
+			fileStorage:    filestorage.NewFileStorageMock(),
+			method:         http.MethodGet,
+			url:            "/files/postmortem-0123456789abcdef.md",
+			responseStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Write method",
+			fileStorage:    &readerStub{},
+			method:         http.MethodPost,
+			url:            "/files/postmortem-0123456789abcdef.md",
+			responseStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for index, scenario := range scenarios {
+		test.Run(
+			fmt.Sprintf("%d-%s", index, scenario.name),
+			func(t *testing.T) {
+				r := httptest.NewRequest(scenario.method, scenario.url, nil)
+				response := httptest.NewRecorder()
+
+				h := newHandlerFiles(zap.NewDefault(), scenario.fileStorage)
+				h.ServeHTTP(response, r)
+				require.Equal(t, scenario.responseStatus, response.Result().StatusCode)
+				require.Equal(t, scenario.responseBody, response.Body.String())
+				if scenario.responseStatus == http.StatusOK {
+					require.Equal(t, "text/markdown; charset=utf-8", response.Header().Get("Content-Type"))
+					require.Equal(t, "nosniff", response.Header().Get("X-Content-Type-Options"))
+				}
+			},
+		)
+	}
+}
@@ -8,26 +8,52 @@ import (
 	"hellper/internal/bot"
 	calendar "hellper/internal/calendar"
 	"hellper/internal/commands"
+	"hellper/internal/concurrence"
 	filestorage "hellper/internal/file_storage"
 	"hellper/internal/log"
 	"hellper/internal/model"
+	"hellper/internal/snooze"
+	"hellper/internal/statuspage"
+	"hellper/internal/warroom"
+	"hellper/internal/workcalendar"
 )
 
 type handlerInteractive struct {
-	logger      log.Logger
-	client      bot.Client
-	repository  model.Repository
-	fileStorage filestorage.Driver
-	calendar    calendar.Calendar
+	logger       log.Logger
+	client       bot.Client
+	repository   model.Repository
+	fileStorage  filestorage.Driver
+	background   *concurrence.Background
+	calendar     calendar.Calendar
+	limits       snooze.Limits
+	workCalendar workcalendar.Calendar
+	statusPage   *statuspage.Service
+	warRoom      warroom.Provider
 }
 
-func newHandlerInteractive(logger log.Logger, client bot.Client, repository model.Repository, fileStorage filestorage.Driver, calendar calendar.Calendar) *handlerInteractive {
+func newHandlerInteractive(
+	logger log.Logger,
+	client bot.Client,
+	repository model.Repository,
+	fileStorage filestorage.Driver,
+	background *concurrence.Background,
+	calendar calendar.Calendar,
+	limits snooze.Limits,
+	workCalendar workcalendar.Calendar,
+	statusPage *statuspage.Service,
+	warRoom warroom.Provider,
+) *handlerInteractive {
 	return &handlerInteractive{
-		logger:      logger,
-		client:      client,
-		repository:  repository,
-		fileStorage: fileStorage,
-		calendar:    calendar,
+		logger:       logger,
+		client:       client,
+		repository:   repository,
+		fileStorage:  fileStorage,
+		background:   background,
+		calendar:     calendar,
+		limits:       limits,
+		workCalendar: workCalendar,
+		statusPage:   statusPage,
+		warRoom:      warRoom,
 	}
 }
 
@@ -75,17 +101,26 @@ func (h *handlerInteractive) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	switch callbackID {
 	case "inc-close":
-		err = commands.CloseIncidentByDialog(ctx, h.client, h.logger, h.repository, dialogSubmission)
+		err = commands.CloseIncidentByDialog(ctx, h.client, h.logger, h.repository, h.fileStorage, h.background, dialogSubmission)
 	case "inc-cancel":
 		err = commands.CancelIncidentByDialog(ctx, h.logger, h.client, h.repository, dialogSubmission)
 	case "inc-open":
-		err = commands.StartIncidentByDialog(ctx, h.client, h.logger, h.repository, h.fileStorage, dialogSubmission)
+		err = commands.StartIncidentByDialog(ctx, h.client, h.logger, h.repository, h.fileStorage, h.warRoom, dialogSubmission)
 	case "inc-resolve":
-		err = commands.ResolveIncidentByDialog(ctx, h.client, h.logger, h.repository, h.calendar, dialogSubmission)
+		err = commands.ResolveIncidentByDialog(ctx, h.client, h.logger, h.repository, h.fileStorage, h.background, h.calendar, h.workCalendar, dialogSubmission)
 	case "inc-dates":
 		err = commands.UpdateDatesByDialog(ctx, h.client, h.logger, h.repository, dialogSubmission)
 	case "inc-pausenotify":
-		err = commands.PauseNotifyIncidentByDialog(ctx, h.client, h.logger, h.repository, dialogSubmission)
+		err = commands.PauseNotifyIncidentByDialog(ctx, h.client, h.logger, h.repository, h.limits, dialogSubmission)
+	case "inc-ack":
+		// The button may be on a DM, its value is the incident channel
+		channelID := dialogSubmission.Channel.ID
+		if len(dialogSubmission.Actions) > 0 && dialogSubmission.Actions[0].Value != "" {
+			channelID = dialogSubmission.Actions[0].Value
+		}
+		err = commands.AcknowledgeEscalation(ctx, h.client, h.logger, h.repository, channelID, dialogSubmission.User.ID)
+	case statuspage.ReviewCallbackID:
+		err = commands.ReviewStatusPageUpdate(ctx, h.client, h.logger, h.statusPage, dialogSubmission)
 	default:
 		commands.PostErrorAttachment(
 			ctx,
@@ -3,24 +3,28 @@ package handler
 import (
 	"bytes"
 	"net/http"
+	"strings"
 
 	"hellper/internal/bot"
 	"hellper/internal/commands"
 	"hellper/internal/log"
 	"hellper/internal/model"
+	"hellper/internal/snooze"
 )
 
 type handlerPauseNotify struct {
 	logger     log.Logger
 	client     bot.Client
 	repository model.Repository
+	limits     snooze.Limits
 }
 
-func newHandlerPauseNotify(logger log.Logger, client bot.Client, repository model.Repository) *handlerPauseNotify {
+func newHandlerPauseNotify(logger log.Logger, client bot.Client, repository model.Repository, limits snooze.Limits) *handlerPauseNotify {
 	return &handlerPauseNotify{
 		logger:     logger,
 		client:     client,
 		repository: repository,
+		limits:     limits,
 	}
 }
 
@@ -56,8 +60,17 @@ func (h *handlerPauseNotify) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	channelID := r.FormValue("channel_id")
 	userID := r.FormValue("user_id")
 	triggerID := r.FormValue("trigger_id")
+	text := strings.TrimSpace(r.FormValue("text"))
 
-	err := commands.PauseNotifyIncidentDialog(ctx, logger, client, repository, channelID, userID, triggerID)
+	var err error
+	switch text {
+	case "":
+		err = commands.PauseNotifyIncidentDialog(ctx, logger, client, repository, h.limits, channelID, userID, triggerID)
+	case "history":
+		err = commands.PauseNotifyHistory(ctx, client, logger, repository, channelID, userID)
+	default:
+		commands.PostInfoAttachment(ctx, client, channelID, userID, "Ops! That's not possible", "Usage: `/hellper_pause_notify` pauses the notifications, `/hellper_pause_notify history` lists the previous pauses")
+	}
 	if err != nil {
 		logger.Error(
 			ctx,
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"net/http"
 
+	"hellper/internal/authorization"
 	"hellper/internal/bot"
 	"hellper/internal/commands"
 	"hellper/internal/log"
@@ -14,13 +15,15 @@ type handlerResolve struct {
 	logger     log.Logger
 	client     bot.Client
 	repository model.Repository
+	policy     authorization.Policy
 }
 
-func newHandlerResolve(logger log.Logger, client bot.Client, repository model.Repository) *handlerResolve {
+func newHandlerResolve(logger log.Logger, client bot.Client, repository model.Repository, policy authorization.Policy) *handlerResolve {
 	return &handlerResolve{
 		logger:     logger,
 		client:     client,
 		repository: repository,
+		policy:     policy,
 	}
 }
 
@@ -52,8 +55,28 @@ func (h *handlerResolve) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	)
 
 	triggerID := r.FormValue("trigger_id")
+	channelID := r.FormValue("channel_id")
+	userID := r.FormValue("user_id")
 
-	err := commands.ResolveIncidentDialog(h.client, triggerID)
+	allowed, err := commands.AuthorizeIncidentAction(ctx, h.client, logger, h.repository, h.policy, authorization.ActionResolve, channelID, userID)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("commands.AuthorizeIncidentAction"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", channelID),
+			log.NewValue("userID", userID),
+		)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !allowed {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	err = commands.ResolveIncidentDialog(h.client, triggerID)
 	if err != nil {
 		logger.Error(
 			ctx,
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+
+	"hellper/internal/bot"
+	"hellper/internal/commands"
+	"hellper/internal/log"
+	"hellper/internal/model"
+)
+
+type handlerResumeNotify struct {
+	logger     log.Logger
+	client     bot.Client
+	repository model.Repository
+}
+
+func newHandlerResumeNotify(logger log.Logger, client bot.Client, repository model.Repository) *handlerResumeNotify {
+	return &handlerResumeNotify{
+		logger:     logger,
+		client:     client,
+		repository: repository,
+	}
+}
+
+func (h *handlerResumeNotify) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx        = r.Context()
+		logger     = h.logger
+		client     = h.client
+		repository = h.repository
+
+		buf        bytes.Buffer
+		formValues []log.Value
+	)
+
+	r.ParseForm()
+	buf.ReadFrom(r.Body)
+	body := buf.String()
+	logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("requestbody", body),
+	)
+
+	for key, value := range r.Form {
+		formValues = append(formValues, log.NewValue(key, value))
+	}
+	logger.Info(
+		ctx,
+		log.Trace(),
+		formValues...,
+	)
+
+	channelID := r.FormValue("channel_id")
+	userID := r.FormValue("user_id")
+
+	err := commands.ResumeNotifyCommand(ctx, client, logger, repository, channelID, userID)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("commands.ResumeNotifyCommand"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", channelID),
+		)
+
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -6,41 +6,115 @@ import (
 	"path"
 
 	"hellper/internal"
+	"hellper/internal/alert"
+	"hellper/internal/authorization"
 	"hellper/internal/bot"
+	"hellper/internal/calendar"
+	"hellper/internal/concurrence"
+	filestorage "hellper/internal/file_storage"
+	"hellper/internal/issuetracker"
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/pager"
+	"hellper/internal/snooze"
+	"hellper/internal/statuspage"
+	"hellper/internal/warroom"
+	"hellper/internal/workcalendar"
 )
 
 var (
-	openHandler        http.Handler
-	eventsHandler      http.Handler
-	interactiveHandler http.Handler
-	statusHandler      http.Handler
-	closeHandler       http.Handler
-	cancelHandler      http.Handler
-	resolveHandler     http.Handler
-	datesHandler       http.Handler
-	pauseNotifyHandler http.Handler
+	openHandler         http.Handler
+	eventsHandler       http.Handler
+	interactiveHandler  http.Handler
+	statusHandler       http.Handler
+	closeHandler        http.Handler
+	cancelHandler       http.Handler
+	resolveHandler      http.Handler
+	datesHandler        http.Handler
+	pauseNotifyHandler  http.Handler
+	resumeNotifyHandler http.Handler
+	roleHandler         http.Handler
+	postMortemHandler   http.Handler
+	statusPageHandler   http.Handler
+	alertsHandler       http.Handler
+	actionItemHandler   http.Handler
+	filesHandler        http.Handler
 )
 
-func init() {
-	logger, client, repository, fileStorage, calendar := internal.New()
+// Init builds the handlers served by NewHandlerRoute with the dependencies of the app
+func Init(app *internal.App) {
+	initHandlers(
+		app.Logger,
+		app.Client,
+		app.Repository,
+		app.FileStorage,
+		app.Background,
+		app.Calendar,
+		internal.NewAuthorizationPolicy(),
+		internal.NewSnoozeLimits(),
+		internal.NewWorkCalendar(),
+		app.StatusPage,
+		app.Pager,
+		internal.NewAlertSettings(),
+		app.IssueTracker,
+		app.WarRoom,
+	)
+}
+
+// initHandlers builds the handlers served by NewHandlerRoute with the given dependencies
+func initHandlers(
+	logger log.Logger,
+	client bot.Client,
+	repository model.Repository,
+	fileStorage filestorage.Driver,
+	background *concurrence.Background,
+	calendar calendar.Calendar,
+	policy authorization.Policy,
+	limits snooze.Limits,
+	workCalendar workcalendar.Calendar,
+	statusPage *statuspage.Service,
+	pager *pager.Service,
+	alertSettings alert.Settings,
+	tracker issuetracker.Provider,
+	warRoom warroom.Provider,
+) {
 	openHandler = newHandlerOpen(logger, client, repository)
-	eventsHandler = newHandlerEvents(logger, client, repository)
-	interactiveHandler = newHandlerInteractive(logger, client, repository, fileStorage, calendar)
+	eventsHandler = newHandlerEvents(logger, client, repository, pager)
+	interactiveHandler = newHandlerInteractive(logger, client, repository, fileStorage, background, calendar, limits, workCalendar, statusPage, warRoom)
 	statusHandler = newHandlerStatus(logger, client, repository)
 	datesHandler = newHandlerDates(logger, client, repository)
-	closeHandler = newHandlerClose(logger, client, repository)
-	cancelHandler = newHandlerCancel(logger, client, repository)
-	resolveHandler = newHandlerResolve(logger, client, repository)
-	pauseNotifyHandler = newHandlerPauseNotify(logger, client, repository)
+	closeHandler = newHandlerClose(logger, client, repository, policy)
+	cancelHandler = newHandlerCancel(logger, client, repository, policy)
+	resolveHandler = newHandlerResolve(logger, client, repository, policy)
+	pauseNotifyHandler = newHandlerPauseNotify(logger, client, repository, limits)
+	resumeNotifyHandler = newHandlerResumeNotify(logger, client, repository)
+	roleHandler = newHandlerRole(logger, client, repository)
+	postMortemHandler = newHandlerPostMortem(logger, client, repository)
+	statusPageHandler = newHandlerStatusPage(logger, client, repository, statusPage)
+	alertsHandler = newHandlerAlerts(logger, client, repository, fileStorage, background, warRoom, calendar, workCalendar, alertSettings)
+	actionItemHandler = newHandlerActionItem(logger, client, repository, background, tracker)
+	filesHandler = newHandlerFiles(logger, fileStorage)
 }
 
 // NewHandlerRoute handles the http requests received and calls the correct handler.
 func NewHandlerRoute() func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusAccepted)
-
 		lastPath := path.Base(r.URL.Path)
 
+		// the alert sources retry on the status code, so the alerts handler answers it by itself
+		if lastPath == "alerts" {
+			alertsHandler.ServeHTTP(w, r)
+			return
+		}
+
+		// the files are read by browsers, which need the status code and the content type
+		if path.Base(path.Dir(r.URL.Path)) == "files" {
+			filesHandler.ServeHTTP(w, r)
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+
 		switch lastPath {
 		case "healthz":
 			fmt.Fprintf(w, "I'm working!!")
@@ -62,6 +136,16 @@ func NewHandlerRoute() func(http.ResponseWriter, *http.Request) {
 			bot.VerifyRequests(r, w, resolveHandler)
 		case "pause-notify":
 			bot.VerifyRequests(r, w, pauseNotifyHandler)
+		case "resume-notify":
+			bot.VerifyRequests(r, w, resumeNotifyHandler)
+		case "role":
+			bot.VerifyRequests(r, w, roleHandler)
+		case "postmortem":
+			bot.VerifyRequests(r, w, postMortemHandler)
+		case "statuspage":
+			bot.VerifyRequests(r, w, statusPageHandler)
+		case "action":
+			bot.VerifyRequests(r, w, actionItemHandler)
 		default:
 			fmt.Fprintf(w, "invalid path, %s!", lastPath)
 			w.WriteHeader(http.StatusBadRequest)
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+
+	"hellper/internal/bot"
+	"hellper/internal/commands"
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/statuspage"
+)
+
+type handlerStatusPage struct {
+	logger     log.Logger
+	client     bot.Client
+	repository model.Repository
+	service    *statuspage.Service
+}
+
+func newHandlerStatusPage(logger log.Logger, client bot.Client, repository model.Repository, service *statuspage.Service) *handlerStatusPage {
+	return &handlerStatusPage{
+		logger:     logger,
+		client:     client,
+		repository: repository,
+		service:    service,
+	}
+}
+
+func (h *handlerStatusPage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx        = r.Context()
+		logger     = h.logger
+		client     = h.client
+		repository = h.repository
+
+		buf        bytes.Buffer
+		formValues []log.Value
+	)
+
+	r.ParseForm()
+	buf.ReadFrom(r.Body)
+	body := buf.String()
+	logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("requestbody", body),
+	)
+
+	for key, value := range r.Form {
+		formValues = append(formValues, log.NewValue(key, value))
+	}
+	logger.Info(
+		ctx,
+		log.Trace(),
+		formValues...,
+	)
+
+	channelID := r.FormValue("channel_id")
+	userID := r.FormValue("user_id")
+	text := r.FormValue("text")
+
+	err := commands.StatusPageCommand(ctx, client, logger, repository, h.service, channelID, userID, text)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("commands.StatusPageCommand"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", channelID),
+			log.NewValue("text", text),
+		)
+
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -3,37 +3,108 @@ package internal
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"text/template"
+	"time"
 
+	"hellper/internal/alert"
+	"hellper/internal/authorization"
 	"hellper/internal/bot"
 	"hellper/internal/bot/slack"
 	"hellper/internal/calendar"
 	googlecalendar "hellper/internal/calendar/google_calendar"
+	icscalendar "hellper/internal/calendar/ics_calendar"
+	"hellper/internal/commands"
+	"hellper/internal/concurrence"
 	"hellper/internal/config"
+	"hellper/internal/digest"
+	"hellper/internal/email"
 	filestorage "hellper/internal/file_storage"
 	googledrive "hellper/internal/file_storage/google_drive"
+	localdisk "hellper/internal/file_storage/local_disk"
+	"hellper/internal/file_storage/s3"
+	googleauth "hellper/internal/google_auth"
+	"hellper/internal/issuetracker"
+	"hellper/internal/job"
+	"hellper/internal/lifecycle"
 	"hellper/internal/log"
 	"hellper/internal/log/zap"
 	"hellper/internal/model"
 	"hellper/internal/model/sql"
 	"hellper/internal/model/sql/postgres"
+	"hellper/internal/pager"
+	"hellper/internal/postmortem"
+	"hellper/internal/reminder"
+	"hellper/internal/sla"
+	"hellper/internal/snooze"
+	"hellper/internal/statuspage"
+	"hellper/internal/warroom"
+	"hellper/internal/webhook"
+	"hellper/internal/workcalendar"
 )
 
-func New() (log.Logger, bot.Client, model.Repository, filestorage.Driver, calendar.Calendar) {
+// App holds the dependencies built once at startup, shared by the handlers and the scheduler
+type App struct {
+	Logger       log.Logger
+	Client       bot.Client
+	Repository   model.Repository
+	FileStorage  filestorage.Driver
+	Background   *concurrence.Background
+	Calendar     calendar.Calendar
+	StatusPage   *statuspage.Service
+	Pager        *pager.Service
+	IssueTracker issuetracker.Provider
+	WarRoom      warroom.Provider
+	Webhooks     *webhook.Dispatcher
+	Email        *email.Notifier
+}
+
+// New builds the dependencies of the server, with a single database pool and a single set of lifecycle listeners
+func New(logger log.Logger) *App {
 	ctx := context.Background()
-	logger := NewLogger()
-	return logger, NewClient(logger), NewRepository(logger), NewFileStorage(logger), NewCalendar(ctx, logger)
+	app := newApp(logger, NewClient(logger))
+	googleauth.Struct = NewGoogleAuth(app.Repository)
+
+	app.FileStorage = NewFileStorage(logger)
+	app.Calendar = NewCalendar(ctx, logger, app.Client)
+	app.IssueTracker = NewIssueTracker()
+	app.WarRoom = NewWarRoom(app.Calendar)
+	return app
+}
+
+// Shutdown waits for the work started in background by the requests and the lifecycle listeners, such as the timeline
+// exports, the channel archives and the webhook deliveries, until the context ends
+func (a *App) Shutdown(ctx context.Context) error {
+	if a.Background == nil {
+		return nil
+	}
+	return a.Background.Wait(ctx)
 }
 
 func NewLogger() log.Logger {
 	return zap.NewDefault()
 }
 
+// NewCLILogger creates the logger of the command line tools, it writes to stderr to keep stdout for their output
+func NewCLILogger() log.Logger {
+	zapLogger, err := zap.NewZapLogger(log.DEBUG, log.STDERR)
+	if err != nil {
+		panic(err)
+	}
+	return zap.New(zap.NewZapLoggerDelegate(zapLogger))
+}
+
+// NewClient creates the Slack client, paced by the Slack rate limits and retrying transient failures.
+// The limits are per workspace, so a single client is shared by everything posting to Slack
 func NewClient(logger log.Logger) bot.Client {
-	return slack.NewClient(config.Env.OAuthToken)
+	client := slack.NewClient(config.Env.OAuthToken)
+	return bot.NewRateLimitedClient(logger, client, config.Env.SlackMaxRetries)
 }
 
+// NewRepository creates the repository on the configured database, without the lifecycle listeners of the server
 func NewRepository(logger log.Logger) model.Repository {
-	fmt.Printf("Configured database: %s", config.Env.Database)
+	fmt.Fprintf(os.Stderr, "Configured database: %s\n", config.Env.Database)
 	switch config.Env.Database {
 	case "postgres":
 		db := sql.NewDBWithDSN(config.Env.Database, config.Env.DSN)
@@ -46,31 +117,579 @@ func NewRepository(logger log.Logger) model.Repository {
 	}
 }
 
+// newApp builds the repository with its lifecycle listeners, the status page and pager services are the same
+// instances the handlers use
+func newApp(logger log.Logger, client bot.Client) *App {
+	var (
+		repository = NewRepository(logger)
+		background = &concurrence.Background{}
+	)
+	app := &App{
+		Logger:     logger,
+		Client:     client,
+		Repository: repository,
+		Background: background,
+		StatusPage: NewStatusPage(logger, client, repository, background),
+		Pager:      NewPager(logger, client, repository, background),
+	}
+
+	var listeners []lifecycle.Listener
+	if targets := NewSLATargets(); len(targets) > 0 {
+		listeners = append(listeners, sla.NewRecorder(logger, repository, targets))
+	}
+	if config.Env.WebhooksFile != "" {
+		app.Webhooks = NewWebhookDispatcher(logger, repository, background)
+		listeners = append(listeners, app.Webhooks)
+	}
+	if config.Env.SMTPHost != "" {
+		app.Email = NewEmailNotifier(logger, client, background)
+		listeners = append(listeners, app.Email)
+	}
+	if app.StatusPage != nil {
+		listeners = append(listeners, app.StatusPage)
+	}
+	if app.Pager != nil {
+		listeners = append(listeners, app.Pager)
+	}
+	if len(listeners) > 0 {
+		app.Repository = lifecycle.NewRepository(logger, repository, listeners...)
+	}
+	return app
+}
+
 // NewFileStorage creates a new connection with the file storage for postmortem document
 func NewFileStorage(logger log.Logger) filestorage.Driver {
 	fileStorage := config.Env.FileStorage
 	switch fileStorage {
 	case "google_drive":
 		return googledrive.NewFileStorage(logger)
+	case "local":
+		return localdisk.NewFileStorage(logger, config.Env.FileStorageDir, servedFilesURL(fileStorage), NewPostMortemTemplate())
+	case "s3":
+		if config.Env.S3Endpoint == "" || config.Env.S3Bucket == "" {
+			panic("invalid file storage configuration: HELLPER_S3_ENDPOINT and HELLPER_S3_BUCKET are required with s3")
+		}
+		settings := s3.Settings{
+			Endpoint:        config.Env.S3Endpoint,
+			Bucket:          config.Env.S3Bucket,
+			Region:          config.Env.S3Region,
+			AccessKeyID:     config.Env.S3AccessKeyID,
+			SecretAccessKey: config.Env.S3SecretAccessKey,
+			Timeout:         10 * time.Second,
+		}
+		return s3.NewFileStorage(logger, settings, servedFilesURL(fileStorage), NewPostMortemTemplate())
 	default:
 		panic(fmt.Sprintf(
-			"invalid file storage option: option=%s valid_options=[google_drive]",
+			"invalid file storage option: option=%s valid_options=[google_drive local s3]",
 			fileStorage,
 		))
 	}
 }
 
-// NewCalendar creates a new connection with the calendar service
-func NewCalendar(ctx context.Context, logger log.Logger) calendar.Calendar {
-	var (
-		calendarToken = config.Env.GoogleCalendarToken
-		calendarID    = config.Env.GoogleCalendarID
+// servedFilesURL is the URL of hellper serving the files of the storages without URLs of their own
+func servedFilesURL(fileStorage string) string {
+	if config.Env.PublicURL == "" {
+		panic("invalid file storage configuration: HELLPER_PUBLIC_URL is required with " + fileStorage)
+	}
+	return config.Env.PublicURL
+}
+
+// NewPostMortemTemplate reads the Markdown post mortem template of the file storages, the built-in one when there is no file
+func NewPostMortemTemplate() *template.Template {
+	var text []byte
+	if config.Env.PostMortemTemplateFile != "" {
+		var err error
+		text, err = ioutil.ReadFile(config.Env.PostMortemTemplateFile)
+		if err != nil {
+			panic(fmt.Sprintf(
+				"invalid post mortem template: file=%s error=%s",
+				config.Env.PostMortemTemplateFile,
+				err.Error(),
+			))
+		}
+	}
+
+	tmpl, err := filestorage.ParsePostMortemTemplate(string(text))
+	if err != nil {
+		panic(fmt.Sprintf(
+			"invalid post mortem template: file=%s error=%s",
+			config.Env.PostMortemTemplateFile,
+			err.Error(),
+		))
+	}
+	return tmpl
+}
+
+// NewCalendar creates a new connection with the calendar service, the post mortem meetings are not scheduled
+// when the Google Calendar cannot be reached
+func NewCalendar(ctx context.Context, logger log.Logger, client bot.Client) calendar.Calendar {
+	switch config.Env.Calendar {
+	case "google_calendar":
+		var (
+			calendarToken = config.Env.GoogleCalendarToken
+			calendarID    = config.Env.GoogleCalendarID
+		)
+		googleCalendar, err := googlecalendar.NewCalendar(ctx, logger, calendarToken, calendarID)
+		if err != nil {
+			logger.Error(ctx, log.Trace(), log.Action("NewCalendar"), log.Reason(err.Error()))
+			return calendar.NewNoop()
+		}
+		return googleCalendar
+	case "ics":
+		return icscalendar.NewCalendar(logger, NewICSDelivery(client))
+	case "none":
+		return calendar.NewNoop()
+	default:
+		panic(fmt.Sprintf(
+			"invalid calendar option: option=%s valid_options=[google_calendar ics none]",
+			config.Env.Calendar,
+		))
+	}
+}
+
+// NewICSDelivery creates how the ics calendar sends its invites
+func NewICSDelivery(client bot.Client) icscalendar.Delivery {
+	switch config.Env.CalendarICSDelivery {
+	case "email":
+		if config.Env.SMTPHost == "" || config.Env.EmailFrom == "" {
+			panic("invalid calendar configuration: HELLPER_SMTP_HOST and HELLPER_EMAIL_FROM are required with email")
+		}
+		sender := email.NewSMTPSender(config.Env.SMTPHost, config.Env.SMTPPort, config.Env.SMTPUsername, config.Env.SMTPPassword)
+		return icscalendar.NewEmailDelivery(sender, config.Env.EmailFrom)
+	case "slack":
+		if config.Env.CalendarICSChannelID == "" {
+			panic("invalid calendar configuration: HELLPER_CALENDAR_ICS_CHANNEL_ID is required with slack")
+		}
+		return icscalendar.NewSlackDelivery(client, config.Env.CalendarICSChannelID)
+	case "caldav":
+		if config.Env.CalDAVURL == "" {
+			panic("invalid calendar configuration: HELLPER_CALDAV_URL is required with caldav")
+		}
+		return icscalendar.NewCalDAVDelivery(config.Env.CalDAVURL, config.Env.CalDAVUsername, config.Env.CalDAVPassword, 10*time.Second)
+	default:
+		panic(fmt.Sprintf(
+			"invalid calendar ics delivery option: option=%s valid_options=[email slack caldav]",
+			config.Env.CalendarICSDelivery,
+		))
+	}
+}
+
+// NewGoogleAuth creates the Google authentication of HELLPER_GOOGLE_CREDENTIALS, saving the refreshed OAuth tokens on the store
+func NewGoogleAuth(store googleauth.TokenStore) googleauth.Interface {
+	return googleauth.New([]byte(config.Env.GoogleCredentials), config.Env.GoogleDelegatedUser, store)
+}
+
+// CheckGoogle reports which of the configured Google capabilities are usable with googleauth.Struct, set by New
+func CheckGoogle(ctx context.Context, logger log.Logger) []googleauth.Status {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var capabilities []googleauth.Capability
+	if config.Env.FileStorage == "google_drive" {
+		driveToken := []byte(config.Env.GoogleDriveToken)
+		capabilities = append(
+			capabilities,
+			googleauth.DriveCapability(driveToken, config.Env.GoogleDriveFileID),
+			googleauth.DocsCapability(driveToken, config.Env.GoogleDriveFileID),
+		)
+	}
+	if config.Env.Calendar == "google_calendar" {
+		capabilities = append(capabilities, googleauth.CalendarCapability([]byte(config.Env.GoogleCalendarToken), config.Env.GoogleCalendarID))
+	}
+	return googleauth.Check(ctx, logger, googleauth.Struct, capabilities)
+}
+
+// NewAuthorizationPolicy reads the policy that guards the incident lifecycle commands
+func NewAuthorizationPolicy() authorization.Policy {
+	policy, err := authorization.ParsePolicy(config.Env.AuthorizationPolicy)
+	if err != nil {
+		panic(fmt.Sprintf(
+			"invalid authorization policy: policy=%s error=%s",
+			config.Env.AuthorizationPolicy,
+			err.Error(),
+		))
+	}
+	return policy
+}
+
+// NewSLATargets reads the SLA targets of each severity
+func NewSLATargets() sla.Targets {
+	targets, err := sla.ParseTargets(config.Env.SLATargets, time.Duration(config.Env.SLAHoursToClose)*time.Hour)
+	if err != nil {
+		panic(fmt.Sprintf(
+			"invalid SLA targets: targets=%s error=%s",
+			config.Env.SLATargets,
+			err.Error(),
+		))
+	}
+	return targets
+}
+
+// NewSnoozeLimits reads the longest notification pauses by status and severity
+func NewSnoozeLimits() snooze.Limits {
+	limits, err := snooze.ParseLimits(config.Env.SnoozeLimits)
+	if err != nil {
+		panic(fmt.Sprintf(
+			"invalid snooze limits: limits=%s error=%s",
+			config.Env.SnoozeLimits,
+			err.Error(),
+		))
+	}
+	return limits
+}
+
+// NewWebhookDispatcher reads the webhook subscriptions file, the attempts are recorded on the repository
+func NewWebhookDispatcher(logger log.Logger, repository model.Repository, background *concurrence.Background) *webhook.Dispatcher {
+	subscriptions, err := webhook.LoadSubscriptions(config.Env.WebhooksFile)
+	if err != nil {
+		panic(fmt.Sprintf(
+			"invalid webhooks file: file=%s error=%s",
+			config.Env.WebhooksFile,
+			err.Error(),
+		))
+	}
+	return webhook.NewDispatcher(
+		logger,
+		repository,
+		background,
+		subscriptions,
+		config.Env.WebhookMaxAttempts,
+		time.Duration(config.Env.WebhookTimeoutSeconds)*time.Second,
 	)
-	calendar, err := googlecalendar.NewCalendar(ctx, logger, calendarToken, calendarID)
+}
+
+// NewEmailNotifier creates the notifier emailing the incidents through the SMTP server
+func NewEmailNotifier(logger log.Logger, client bot.Client, background *concurrence.Background) *email.Notifier {
+	lists, err := email.ParseLists(config.Env.EmailLists)
 	if err != nil {
-		logger.Error(ctx, log.Trace(), log.Action("NewCalendar"), log.Reason(err.Error()))
+		panic(fmt.Sprintf(
+			"invalid email lists: lists=%s error=%s",
+			config.Env.EmailLists,
+			err.Error(),
+		))
+	}
+
+	if config.Env.EmailFrom == "" {
+		panic("invalid email configuration: HELLPER_EMAIL_FROM is required with HELLPER_SMTP_HOST")
+	}
+
+	var participants email.Participants
+	if config.Env.EmailParticipants {
+		participants = func(ctx context.Context, channelID string) ([]string, error) {
+			emails, err := commands.GetUsersEmailsInConversation(ctx, client, logger, channelID)
+			if err != nil {
+				return nil, err
+			}
+			return *emails, nil
+		}
+	}
+
+	return email.NewNotifier(
+		logger,
+		background,
+		email.NewSMTPSender(config.Env.SMTPHost, config.Env.SMTPPort, config.Env.SMTPUsername, config.Env.SMTPPassword),
+		config.Env.EmailFrom,
+		lists,
+		participants,
+		NewLocation(),
+	)
+}
+
+// NewStatusPage creates the service publishing the incidents on the configured status page, nil when there is none
+func NewStatusPage(logger log.Logger, client bot.Client, repository model.Repository, background *concurrence.Background) *statuspage.Service {
+	var provider statuspage.Provider
+	switch config.Env.StatusPageProvider {
+	case "":
 		return nil
+	case "statuspageio":
+		if config.Env.StatusPageID == "" || config.Env.StatusPageAPIKey == "" {
+			panic("invalid status page configuration: HELLPER_STATUSPAGE_PAGE_ID and HELLPER_STATUSPAGE_API_KEY are required with statuspageio")
+		}
+		provider = statuspage.NewStatuspageIO(config.Env.StatusPageURL, config.Env.StatusPageID, config.Env.StatusPageAPIKey, 10*time.Second)
+	case "fake":
+		provider = statuspage.NewFake()
+	default:
+		panic(fmt.Sprintf(
+			"invalid status page option: option=%s valid_options=[statuspageio fake]",
+			config.Env.StatusPageProvider,
+		))
+	}
+
+	components, err := statuspage.ParseComponents(config.Env.StatusPageComponents)
+	if err != nil {
+		panic(fmt.Sprintf(
+			"invalid status page components: components=%s error=%s",
+			config.Env.StatusPageComponents,
+			err.Error(),
+		))
 	}
 
+	return statuspage.NewService(logger, client, repository, background, provider, components)
+}
+
+// NewPager creates the service paging the on-call responders through the configured pager, nil when there is none
+func NewPager(logger log.Logger, client bot.Client, repository model.Repository, background *concurrence.Background) *pager.Service {
+	var provider pager.Provider
+	switch config.Env.PagerProvider {
+	case "":
+		return nil
+	case "pagerduty":
+		provider = pager.NewPagerDuty(config.Env.PagerURL, config.Env.PagerEventURL, 10*time.Second)
+	case "fake":
+		provider = pager.NewFake()
+	default:
+		panic(fmt.Sprintf(
+			"invalid pager option: option=%s valid_options=[pagerduty fake]",
+			config.Env.PagerProvider,
+		))
+	}
+
+	routes, err := pager.ParseRoutes(config.Env.PagerRoutingKeys)
+	if err != nil {
+		panic(fmt.Sprintf(
+			"invalid pager routing keys: routing_keys=%s error=%s",
+			config.Env.PagerRoutingKeys,
+			err.Error(),
+		))
+	}
+
+	return pager.NewService(logger, client, repository, background, provider, routes)
+}
+
+// NewIssueTracker creates the issue tracker of the action items tickets, nil when there is none
+func NewIssueTracker() issuetracker.Provider {
+	switch config.Env.IssueTrackerProvider {
+	case "":
+		return nil
+	case "github":
+		if config.Env.IssueTrackerProject == "" || config.Env.IssueTrackerToken == "" {
+			panic("invalid issue tracker configuration: HELLPER_ISSUE_TRACKER_PROJECT and HELLPER_ISSUE_TRACKER_TOKEN are required with github")
+		}
+		return issuetracker.NewGitHub(config.Env.IssueTrackerURL, config.Env.IssueTrackerProject, config.Env.IssueTrackerToken, 10*time.Second)
+	case "fake":
+		return issuetracker.NewFake()
+	default:
+		panic(fmt.Sprintf(
+			"invalid issue tracker option: option=%s valid_options=[github fake]",
+			config.Env.IssueTrackerProvider,
+		))
+	}
+}
+
+// The Matrix room shared by the incidents of the staging environment
+const (
+	stagingMatrixRoomID   = "dc82e346-639c-44ee-a470-63f7545ae8e4"
+	stagingMatrixRoomName = "hellper-staging"
+)
+
+// NewWarRoom creates the provider of the war rooms of the new incidents, nil when the incidents have no war room.
+// Without provider, the production incidents get a Matrix room each and the staging ones share the staging room
+func NewWarRoom(calendar calendar.Calendar) warroom.Provider {
+	var (
+		provider = config.Env.WarRoomProvider
+		roomID   = config.Env.MatrixRoomID
+		roomName = "hellper-" + config.Env.MatrixRoomID
+	)
+	if provider == "" && config.Env.MatrixHost != "" {
+		switch config.Env.Environment {
+		case "production":
+			provider = "matrix"
+		case "staging":
+			provider = "matrix"
+			if roomID == "" {
+				roomID, roomName = stagingMatrixRoomID, stagingMatrixRoomName
+			}
+		}
+	}
+
+	switch provider {
+	case "":
+		return nil
+	case "matrix":
+		if config.Env.MatrixHost == "" {
+			panic("invalid war room configuration: HELLPER_MATRIX_HOST is required with matrix")
+		}
+		return warroom.NewMatrix(config.Env.MatrixHost, roomID, roomName)
+	case "template":
+		if config.Env.WarRoomURLTemplate == "" {
+			panic("invalid war room configuration: HELLPER_WARROOM_URL_TEMPLATE is required with template")
+		}
+		return warroom.NewTemplate(config.Env.WarRoomURLTemplate)
+	case "google_meet":
+		if config.Env.Calendar != "google_calendar" {
+			panic("invalid war room configuration: google_meet requires HELLPER_CALENDAR=google_calendar")
+		}
+		return warroom.NewGoogleMeet(calendar, time.Duration(config.Env.WarRoomMinutes)*time.Minute)
+	default:
+		panic(fmt.Sprintf(
+			"invalid war room option: option=%s valid_options=[matrix template google_meet]",
+			provider,
+		))
+	}
+}
+
+// NewAlertSettings reads how the alerts of the webhook are turned into incidents
+func NewAlertSettings() alert.Settings {
+	severities, err := alert.ParseSeverities(config.Env.AlertsSeverities)
+	if err != nil {
+		panic(fmt.Sprintf(
+			"invalid alerts severities: severities=%s error=%s",
+			config.Env.AlertsSeverities,
+			err.Error(),
+		))
+	}
+
+	if config.Env.AlertsToken != "" && config.Env.AlertsCommander == "" {
+		panic("invalid alerts configuration: HELLPER_ALERTS_COMMANDER is required with HELLPER_ALERTS_TOKEN")
+	}
+
+	return alert.Settings{
+		ProductLabel:    config.Env.AlertsProductLabel,
+		DefaultProduct:  config.Env.AlertsDefaultProduct,
+		SeverityLabel:   config.Env.AlertsSeverityLabel,
+		Severities:      severities,
+		DefaultSeverity: int64(config.Env.AlertsDefaultSeverity),
+		Commander:       config.Env.AlertsCommander,
+		AutoResolve:     config.Env.AlertsAutoResolve,
+	}
+}
+
+// NewLocation loads the timezone the dates are shown in, UTC when it is invalid
+func NewLocation() *time.Location {
+	location, err := time.LoadLocation(config.Env.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// NewReminderPolicy reads the reminder policy file, or builds the default policy from the environment
+func NewReminderPolicy() reminder.Policy {
+	if config.Env.ReminderPolicyFile == "" {
+		policy := reminder.DefaultPolicy()
+		policy.Calendar = NewWorkCalendar()
+		return policy
+	}
+
+	policy, err := reminder.LoadPolicy(config.Env.ReminderPolicyFile)
+	if err != nil {
+		panic(fmt.Sprintf(
+			"invalid reminder policy: file=%s error=%s",
+			config.Env.ReminderPolicyFile,
+			err.Error(),
+		))
+	}
+	policy.Calendar = NewWorkCalendar()
+	return policy
+}
+
+// NewWorkCalendar reads the work calendar file, or builds a calendar of the timezone without holidays
+func NewWorkCalendar() workcalendar.Calendar {
+	if config.Env.WorkCalendarFile == "" {
+		calendar, err := workcalendar.Default(config.Env.Timezone)
+		if err != nil {
+			panic(fmt.Sprintf(
+				"invalid work calendar: timezone=%s error=%s",
+				config.Env.Timezone,
+				err.Error(),
+			))
+		}
+		return calendar
+	}
+
+	calendar, err := workcalendar.Load(config.Env.WorkCalendarFile)
+	if err != nil {
+		panic(fmt.Sprintf(
+			"invalid work calendar: file=%s error=%s",
+			config.Env.WorkCalendarFile,
+			err.Error(),
+		))
+	}
 	return calendar
 }
+
+// NewScheduler registers the reminder, SLA, SLA breach, post mortem, report, digest and action items jobs enabled on the environment
+func NewScheduler(app *App) *job.Scheduler {
+	var (
+		logger     = app.Logger
+		client     = app.Client
+		repository = app.Repository
+		scheduler  = job.NewScheduler(logger, repository)
+		policy     = NewReminderPolicy()
+	)
+
+	if config.Env.SchedulerReminderSeconds > 0 {
+		scheduler.Add(job.Task{
+			Name:       "reminder",
+			Recurrence: time.Duration(config.Env.SchedulerReminderSeconds) * time.Second,
+			Run: func(ctx context.Context) {
+				reminder.RemindIncidents(ctx, client, logger, repository, policy, model.StatusOpen)
+			},
+		})
+	}
+
+	if config.Env.SchedulerSLASeconds > 0 {
+		scheduler.Add(job.Task{
+			Name:       "sla",
+			Recurrence: time.Duration(config.Env.SchedulerSLASeconds) * time.Second,
+			Run: func(ctx context.Context) {
+				reminder.RemindIncidents(ctx, client, logger, repository, policy, model.StatusResolved)
+			},
+		})
+	}
+
+	if config.Env.SchedulerSLABreachSeconds > 0 {
+		targets := NewSLATargets()
+		scheduler.Add(job.Task{
+			Name:       "sla_breach",
+			Recurrence: time.Duration(config.Env.SchedulerSLABreachSeconds) * time.Second,
+			Run: func(ctx context.Context) {
+				sla.CheckIncidents(ctx, client, logger, repository, targets, config.Env.SLAAlertChannelID)
+			},
+		})
+	}
+
+	if config.Env.SchedulerPostMortemSeconds > 0 {
+		targets := NewSLATargets()
+		scheduler.Add(job.Task{
+			Name:       "postmortem",
+			Recurrence: time.Duration(config.Env.SchedulerPostMortemSeconds) * time.Second,
+			Run: func(ctx context.Context) {
+				postmortem.RemindCommanders(ctx, client, logger, repository, targets, time.Duration(config.Env.PostMortemRemindBeforeHours)*time.Hour, NewLocation())
+			},
+		})
+	}
+
+	if config.Env.SchedulerReportSeconds > 0 && config.Env.SchedulerReportChannelID != "" {
+		scheduler.Add(job.Task{
+			Name:       "report",
+			Recurrence: time.Duration(config.Env.SchedulerReportSeconds) * time.Second,
+			Run: func(ctx context.Context) {
+				reminder.SendReport(ctx, client, logger, repository, config.Env.SchedulerReportChannelID, "all")
+			},
+		})
+	}
+
+	if config.Env.SchedulerDigestSeconds > 0 && config.Env.SchedulerDigestChannelID != "" {
+		period := time.Duration(config.Env.SchedulerDigestSeconds) * time.Second
+		scheduler.Add(job.Task{
+			Name:       "digest",
+			Recurrence: period,
+			Run: func(ctx context.Context) {
+				digest.Send(ctx, client, logger, repository, config.Env.SchedulerDigestChannelID, period)
+			},
+		})
+	}
+
+	if tracker := app.IssueTracker; tracker != nil && config.Env.SchedulerActionItemsSeconds > 0 {
+		scheduler.Add(job.Task{
+			Name:       "action_items",
+			Recurrence: time.Duration(config.Env.SchedulerActionItemsSeconds) * time.Second,
+			Run: func(ctx context.Context) {
+				issuetracker.Sync(ctx, client, logger, repository, tracker)
+			},
+		})
+	}
+
+	return scheduler
+}
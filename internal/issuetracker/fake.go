@@ -0,0 +1,55 @@
+package issuetracker
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+)
+
+// Fake keeps the tickets in memory, to try the action items flow without an issue tracker
+type Fake struct {
+	mu     sync.Mutex
+	issues []Issue
+	closed map[string]bool
+}
+
+// NewFake creates an empty Fake
+func NewFake() *Fake {
+	return &Fake{closed: map[string]bool{}}
+}
+
+func (f *Fake) CreateIssue(ctx context.Context, issue Issue) (Ticket, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.issues = append(f.issues, issue)
+	key := strconv.Itoa(len(f.issues))
+	f.closed[key] = false
+	return Ticket{Key: key, URL: "https://issues.example.com/" + key}, nil
+}
+
+func (f *Fake) IsClosed(ctx context.Context, key string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	closed, ok := f.closed[key]
+	if !ok {
+		return false, errors.New("ticket " + key + " not found")
+	}
+	return closed, nil
+}
+
+// Close closes the ticket, as someone would on the issue tracker
+func (f *Fake) Close(key string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed[key] = true
+}
+
+// Issues returns the tickets created
+func (f *Fake) Issues() []Issue {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Issue(nil), f.issues...)
+}
@@ -0,0 +1,94 @@
+package issuetracker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GitHubURL is the API of GitHub
+const GitHubURL = "https://api.github.com"
+
+type gitHub struct {
+	baseURL    string
+	repository string
+	token      string
+	httpClient *http.Client
+}
+
+type gitHubIssue struct {
+	Number  int64  `json:"number"`
+	HTMLURL string `json:"html_url"`
+	State   string `json:"state"`
+}
+
+// NewGitHub creates a Provider on the issues of the owner/name repository of a GitHub compatible API
+func NewGitHub(baseURL string, repository string, token string, timeout time.Duration) Provider {
+	return &gitHub{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		repository: strings.Trim(repository, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (g *gitHub) CreateIssue(ctx context.Context, issue Issue) (Ticket, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"title":  issue.Title,
+		"body":   issue.Body,
+		"labels": issue.Labels,
+	})
+	if err != nil {
+		return Ticket{}, err
+	}
+
+	var created gitHubIssue
+	err = g.send(ctx, http.MethodPost, g.baseURL+"/repos/"+g.repository+"/issues", body, &created)
+	if err != nil {
+		return Ticket{}, err
+	}
+	return Ticket{Key: strconv.FormatInt(created.Number, 10), URL: created.HTMLURL}, nil
+}
+
+func (g *gitHub) IsClosed(ctx context.Context, key string) (bool, error) {
+	var issue gitHubIssue
+	err := g.send(ctx, http.MethodGet, g.baseURL+"/repos/"+g.repository+"/issues/"+key, nil, &issue)
+	if err != nil {
+		return false, err
+	}
+	return issue.State == "closed", nil
+}
+
+func (g *gitHub) send(ctx context.Context, method string, url string, body []byte, response interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("Authorization", "token "+g.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(content)))
+	}
+
+	return json.Unmarshal(content, response)
+}
@@ -0,0 +1,62 @@
+package issuetracker_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"hellper/internal/issuetracker"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitHub(t *testing.T) {
+	var (
+		requests []*http.Request
+		bodies   []map[string]interface{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, _ := ioutil.ReadAll(r.Body)
+		body := map[string]interface{}{}
+		_ = json.Unmarshal(content, &body)
+
+		requests = append(requests, r)
+		bodies = append(bodies, body)
+		switch r.URL.Path {
+		case "/repos/acme/incidents/issues":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"number":12,"html_url":"https://github.com/acme/incidents/issues/12","state":"open"}`))
+		case "/repos/acme/incidents/issues/12":
+			w.Write([]byte(`{"number":12,"html_url":"https://github.com/acme/incidents/issues/12","state":"closed"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Not Found"}`))
+		}
+	}))
+	defer server.Close()
+
+	var (
+		ctx      = context.Background()
+		provider = issuetracker.NewGitHub(server.URL+"/", "acme/incidents", "token1", time.Second)
+	)
+
+	ticket, err := provider.CreateIssue(ctx, issuetracker.Issue{Title: "Add a retry", Body: "Action item #3", Labels: []string{"incident", "priority:high"}})
+	assert.NoError(t, err)
+	assert.Equal(t, issuetracker.Ticket{Key: "12", URL: "https://github.com/acme/incidents/issues/12"}, ticket)
+	assert.Equal(t, http.MethodPost, requests[0].Method)
+	assert.Equal(t, "token token1", requests[0].Header.Get("Authorization"))
+	assert.Equal(t, "Add a retry", bodies[0]["title"])
+	assert.Equal(t, []interface{}{"incident", "priority:high"}, bodies[0]["labels"])
+
+	closed, err := provider.IsClosed(ctx, "12")
+	assert.NoError(t, err)
+	assert.True(t, closed)
+	assert.Equal(t, http.MethodGet, requests[1].Method)
+
+	_, err = provider.IsClosed(ctx, "13")
+	assert.EqualError(t, err, `unexpected status 404: {"message":"Not Found"}`)
+}
@@ -0,0 +1,132 @@
+// Package issuetracker exports the action items of the incidents as tickets of an issue tracker and follows their status
+package issuetracker
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"hellper/internal/bot"
+	"hellper/internal/log"
+	"hellper/internal/model"
+
+	"github.com/slack-go/slack"
+)
+
+// Issue is the ticket created for an action item
+type Issue struct {
+	Title  string
+	Body   string
+	Labels []string
+}
+
+// Ticket is the ticket of an action item on the issue tracker
+type Ticket struct {
+	Key string
+	URL string
+}
+
+// Provider creates the tickets of the action items and tells when they are closed
+type Provider interface {
+	CreateIssue(ctx context.Context, issue Issue) (Ticket, error)
+	IsClosed(ctx context.Context, key string) (bool, error)
+}
+
+// NewIssue builds the ticket of the action item of the incident, labeled with the labels and the item priority
+func NewIssue(item model.ActionItem, inc model.Incident, labels []string) Issue {
+	var body strings.Builder
+	body.WriteString("Action item #" + strconv.FormatInt(item.Id, 10) + " of the incident *" + inc.Title + "*")
+	if inc.ChannelName != "" {
+		body.WriteString(" (#" + inc.ChannelName + ")")
+	}
+	body.WriteString(".\n\n")
+	body.WriteString("- Priority: " + item.Priority + "\n")
+	if item.OwnerId != "" {
+		body.WriteString("- Owner: Slack user " + item.OwnerId + "\n")
+	}
+	if item.DueDate != nil {
+		body.WriteString("- Due date: " + item.DueDate.Format("2006-01-02") + "\n")
+	}
+	if inc.ChannelId != "" {
+		body.WriteString("- Incident channel: https://slack.com/app_redirect?channel=" + inc.ChannelId + "\n")
+	}
+
+	return Issue{
+		Title:  item.Title,
+		Body:   body.String(),
+		Labels: append(append([]string(nil), labels...), "priority:"+item.Priority),
+	}
+}
+
+// ParseLabels reads the comma separated labels of the tickets
+func ParseLabels(value string) []string {
+	var labels []string
+	for _, label := range strings.Split(value, ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// Sync completes the action items whose ticket was closed on the issue tracker and announces them on their incident
+func Sync(ctx context.Context, client bot.Client, logger log.Logger, repository model.Repository, provider Provider) {
+	items, err := repository.ListTrackedActionItems(ctx)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("repository.ListTrackedActionItems"),
+			log.Reason(err.Error()),
+		)
+		return
+	}
+
+	for _, item := range items {
+		closed, err := provider.IsClosed(ctx, item.IssueKey)
+		if err != nil {
+			logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("provider.IsClosed"),
+				log.Reason(err.Error()),
+				log.NewValue("actionItemID", item.Id),
+				log.NewValue("issueKey", item.IssueKey),
+			)
+			continue
+		}
+		if !closed {
+			continue
+		}
+
+		now := time.Now().UTC()
+		item.Status = model.ActionItemDone
+		item.CompletedAt = &now
+		err = repository.UpdateActionItem(ctx, &item)
+		if err != nil {
+			logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("repository.UpdateActionItem"),
+				log.Reason(err.Error()),
+				log.NewValue("actionItemID", item.Id),
+			)
+			continue
+		}
+
+		_, _, err = client.PostMessage(item.ChannelId, slack.MsgOptionText(
+			":white_check_mark: The action item #"+strconv.FormatInt(item.Id, 10)+" *"+item.Title+"* is done, its ticket <"+item.IssueUrl+"|"+item.IssueKey+"> was closed.",
+			false,
+		))
+		if err != nil {
+			logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("client.PostMessage"),
+				log.Reason(err.Error()),
+				log.NewValue("channelID", item.ChannelId),
+			)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package issuetracker_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hellper/internal/bot"
+	"hellper/internal/issuetracker"
+	"hellper/internal/log"
+	"hellper/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewIssue(t *testing.T) {
+	due := time.Date(2020, time.October, 30, 0, 0, 0, 0, time.UTC)
+	item := model.ActionItem{Id: 3, Title: "Add a retry to the payment client", OwnerId: "U1", DueDate: &due, Priority: model.ActionItemHigh}
+	inc := model.Incident{Title: "Checkout errors", ChannelId: "C1", ChannelName: "inc-checkout"}
+
+	issue := issuetracker.NewIssue(item, inc, []string{"incident"})
+	assert.Equal(t, "Add a retry to the payment client", issue.Title)
+	assert.Equal(t, []string{"incident", "priority:high"}, issue.Labels)
+	assert.Contains(t, issue.Body, "Action item #3 of the incident *Checkout errors* (#inc-checkout).")
+	assert.Contains(t, issue.Body, "- Owner: Slack user U1\n- Due date: 2020-10-30\n")
+	assert.Contains(t, issue.Body, "https://slack.com/app_redirect?channel=C1")
+}
+
+func TestParseLabels(t *testing.T) {
+	assert.Equal(t, []string{"incident", "follow-up"}, issuetracker.ParseLabels(" incident, ,follow-up"))
+	assert.Empty(t, issuetracker.ParseLabels(""))
+}
+
+func TestSync(t *testing.T) {
+	var (
+		ctx            = context.Background()
+		loggerMock     = log.NewLoggerMock()
+		clientMock     = bot.NewClientMock()
+		repositoryMock = model.NewRepositoryMock()
+		fake           = issuetracker.NewFake()
+		updated        []model.ActionItem
+	)
+
+	open, err := fake.CreateIssue(ctx, issuetracker.Issue{Title: "Open"})
+	assert.NoError(t, err)
+	closed, err := fake.CreateIssue(ctx, issuetracker.Issue{Title: "Closed"})
+	assert.NoError(t, err)
+	fake.Close(closed.Key)
+
+	loggerMock.On("Error", ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+	clientMock.On("PostMessage", "C1", mock.AnythingOfType("[]slack.MsgOption")).Return("", "", nil)
+	repositoryMock.On("ListTrackedActionItems", ctx).Return([]model.ActionItem{
+		{Id: 1, ChannelId: "C1", Title: "Open", Status: model.ActionItemOpen, IssueKey: open.Key, IssueUrl: open.URL},
+		{Id: 2, ChannelId: "C1", Title: "Closed", Status: model.ActionItemOpen, IssueKey: closed.Key, IssueUrl: closed.URL},
+		{Id: 3, ChannelId: "C1", Title: "Missing", Status: model.ActionItemOpen, IssueKey: "404"},
+	}, nil)
+	repositoryMock.On("UpdateActionItem", ctx, mock.AnythingOfType("*model.ActionItem")).Return(nil).Run(func(args mock.Arguments) {
+		updated = append(updated, *args.Get(1).(*model.ActionItem))
+	})
+
+	issuetracker.Sync(ctx, clientMock, loggerMock, repositoryMock, fake)
+
+	assert.Len(t, updated, 1)
+	assert.Equal(t, int64(2), updated[0].Id)
+	assert.Equal(t, model.ActionItemDone, updated[0].Status)
+	assert.NotNil(t, updated[0].CompletedAt)
+	clientMock.AssertNumberOfCalls(t, "PostMessage", 1)
+	loggerMock.AssertCalled(t, "Error", ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value"))
+}
+
+func TestSyncListFailure(t *testing.T) {
+	var (
+		ctx            = context.Background()
+		loggerMock     = log.NewLoggerMock()
+		repositoryMock = model.NewRepositoryMock()
+	)
+
+	loggerMock.On("Error", ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+	repositoryMock.On("ListTrackedActionItems", ctx).Return(nil, errors.New("connection refused"))
+
+	issuetracker.Sync(ctx, bot.NewClientMock(), loggerMock, repositoryMock, issuetracker.NewFake())
+	repositoryMock.AssertNotCalled(t, "UpdateActionItem", ctx, mock.Anything)
+}
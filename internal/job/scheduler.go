@@ -0,0 +1,119 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"hellper/internal/log"
+)
+
+// Locker coordinates the replicas of the server, AcquireJobLock succeeds on a single replica per recurrence of a job
+type Locker interface {
+	AcquireJobLock(ctx context.Context, name string, recurrence time.Duration) (func(), bool, error)
+}
+
+// Task is a named function the scheduler runs on every recurrence
+type Task struct {
+	Name       string
+	Recurrence time.Duration
+	Run        func(ctx context.Context)
+}
+
+// Scheduler runs the tasks on their recurrences until it is shut down
+type Scheduler struct {
+	logger log.Logger
+	locker Locker
+	tasks  []Task
+	jobs   []Job
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewScheduler creates a scheduler that coordinates the runs through the locker
+func NewScheduler(logger log.Logger, locker Locker) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		logger: logger,
+		locker: locker,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Add registers a task, it must be called before Start
+func (s *Scheduler) Add(task Task) {
+	s.tasks = append(s.tasks, task)
+}
+
+// Tasks returns the registered tasks
+func (s *Scheduler) Tasks() []Task {
+	return s.tasks
+}
+
+// Start starts a job for each task
+func (s *Scheduler) Start() {
+	for _, task := range s.tasks {
+		task := task
+		s.logger.Info(
+			s.ctx,
+			log.Trace(),
+			log.Action("schedule"),
+			log.NewValue("job", task.Name),
+			log.NewValue("recurrence", task.Recurrence.String()),
+		)
+		s.jobs = append(s.jobs, New(task.Recurrence, func(Job) { s.run(task) }))
+	}
+}
+
+// Shutdown stops the jobs and waits for the running tasks, their context is canceled when ctx is done first
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	defer s.cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		// Stop blocks until the job is back waiting for its next recurrence
+		for i := range s.jobs {
+			Stop(&s.jobs[i])
+		}
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) run(task Task) {
+	ctx := log.ContextWithTID(s.ctx, "")
+
+	release, acquired, err := s.locker.AcquireJobLock(ctx, task.Name, task.Recurrence)
+	if err != nil {
+		s.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("locker.AcquireJobLock"),
+			log.Reason(err.Error()),
+			log.NewValue("job", task.Name),
+		)
+		return
+	}
+	if !acquired {
+		s.logger.Info(
+			ctx,
+			log.Trace(),
+			log.Action("skip"),
+			log.Reason("job ran on another replica"),
+			log.NewValue("job", task.Name),
+		)
+		return
+	}
+	defer release()
+
+	s.logger.Info(ctx, log.Trace(), log.Action("running"), log.NewValue("job", task.Name))
+	task.Run(ctx)
+	s.logger.Info(ctx, log.Trace(), log.Action("done"), log.NewValue("job", task.Name))
+}
@@ -0,0 +1,131 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"hellper/internal/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type lockerStub struct {
+	acquired bool
+	err      error
+	releases int32
+}
+
+func (l *lockerStub) AcquireJobLock(ctx context.Context, name string, recurrence time.Duration) (func(), bool, error) {
+	if l.err != nil || !l.acquired {
+		return nil, false, l.err
+	}
+	return func() { atomic.AddInt32(&l.releases, 1) }, true, nil
+}
+
+func newLoggerStub() log.Logger {
+	logger := log.NewLoggerMock()
+	logger.On("Info", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+	logger.On("Error", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+	return logger
+}
+
+func TestSchedulerRunsWhenLocked(t *testing.T) {
+	var (
+		locker = &lockerStub{acquired: true}
+		runs   int32
+	)
+
+	scheduler := NewScheduler(newLoggerStub(), locker)
+	scheduler.Add(Task{Name: "reminder", Recurrence: time.Millisecond, Run: func(ctx context.Context) {
+		atomic.AddInt32(&runs, 1)
+	}})
+	scheduler.Start()
+
+	time.Sleep(time.Millisecond * 50)
+	assert.NoError(t, scheduler.Shutdown(context.Background()))
+
+	assert.True(t, atomic.LoadInt32(&runs) > 0, "job not executed")
+	assert.Equal(t, atomic.LoadInt32(&runs), atomic.LoadInt32(&locker.releases), "every run releases the lock")
+}
+
+func TestSchedulerSkipsWithoutLock(t *testing.T) {
+	table := []struct {
+		testName string
+		locker   *lockerStub
+	}{
+		{testName: "Locked by another replica", locker: &lockerStub{acquired: false}},
+		{testName: "Lock error", locker: &lockerStub{err: errors.New("database is down")}},
+	}
+
+	for _, f := range table {
+		t.Run(f.testName, func(t *testing.T) {
+			var runs int32
+
+			scheduler := NewScheduler(newLoggerStub(), f.locker)
+			scheduler.Add(Task{Name: "reminder", Recurrence: time.Millisecond, Run: func(ctx context.Context) {
+				atomic.AddInt32(&runs, 1)
+			}})
+			scheduler.Start()
+
+			time.Sleep(time.Millisecond * 20)
+			assert.NoError(t, scheduler.Shutdown(context.Background()))
+			assert.Equal(t, int32(0), atomic.LoadInt32(&runs))
+		})
+	}
+}
+
+func TestSchedulerShutdownWaitsForRunningTask(t *testing.T) {
+	var (
+		started  = make(chan struct{})
+		finished int32
+	)
+
+	scheduler := NewScheduler(newLoggerStub(), &lockerStub{acquired: true})
+	scheduler.Add(Task{Name: "report", Recurrence: time.Millisecond, Run: func(ctx context.Context) {
+		if atomic.LoadInt32(&finished) == 0 {
+			close(started)
+		}
+		time.Sleep(time.Millisecond * 30)
+		atomic.StoreInt32(&finished, 1)
+	}})
+	scheduler.Start()
+
+	<-started
+	assert.NoError(t, scheduler.Shutdown(context.Background()))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&finished), "shutdown returned before the running task")
+}
+
+func TestSchedulerShutdownTimeout(t *testing.T) {
+	var (
+		started  = make(chan struct{})
+		canceled = make(chan struct{})
+	)
+
+	scheduler := NewScheduler(newLoggerStub(), &lockerStub{acquired: true})
+	scheduler.Add(Task{Name: "sla", Recurrence: time.Millisecond, Run: func(ctx context.Context) {
+		select {
+		case <-started:
+			return
+		default:
+		}
+		close(started)
+		<-ctx.Done()
+		close(canceled)
+	}})
+	scheduler.Start()
+
+	<-started
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+
+	assert.Equal(t, context.DeadlineExceeded, scheduler.Shutdown(ctx))
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("the running task context was not canceled")
+	}
+}
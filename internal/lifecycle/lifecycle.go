@@ -0,0 +1,38 @@
+// Package lifecycle tells the listeners about the incident changes saved on the repository
+package lifecycle
+
+import (
+	"context"
+
+	"hellper/internal/model"
+)
+
+// Events of the incident lifecycle
+const (
+	EventOpened          = "opened"
+	EventUpdated         = "updated"
+	EventSeverityChanged = "severity_changed"
+	EventResolved        = "resolved"
+	EventClosed          = "closed"
+	EventCanceled        = "canceled"
+)
+
+var events = map[string]bool{
+	EventOpened:          true,
+	EventUpdated:         true,
+	EventSeverityChanged: true,
+	EventResolved:        true,
+	EventClosed:          true,
+	EventCanceled:        true,
+}
+
+// IsEvent tells whether the name is an event of the incident lifecycle
+func IsEvent(event string) bool {
+	return events[event]
+}
+
+// Listener receives the lifecycle events, Dispatch must not block the command that changed the incident
+type Listener interface {
+	Wants(event string) bool
+	Dispatch(ctx context.Context, event string, incident model.Incident)
+}
@@ -0,0 +1,131 @@
+package lifecycle
+
+import (
+	"context"
+
+	"hellper/internal/log"
+	"hellper/internal/model"
+)
+
+type dispatchingRepository struct {
+	model.Repository
+	logger    log.Logger
+	listeners []Listener
+}
+
+// NewRepository wraps a Repository, dispatching the lifecycle event of each incident change it saves to the listeners
+func NewRepository(logger log.Logger, repository model.Repository, listeners ...Listener) model.Repository {
+	return &dispatchingRepository{
+		Repository: repository,
+		logger:     logger,
+		listeners:  listeners,
+	}
+}
+
+func (r *dispatchingRepository) InsertIncident(ctx context.Context, inc *model.Incident) (int64, error) {
+	id, err := r.Repository.InsertIncident(ctx, inc)
+	if err != nil {
+		return id, err
+	}
+
+	r.dispatch(ctx, EventOpened, inc.ChannelId)
+	return id, nil
+}
+
+func (r *dispatchingRepository) UpdateIncidentDates(ctx context.Context, inc *model.Incident) error {
+	err := r.Repository.UpdateIncidentDates(ctx, inc)
+	if err != nil {
+		return err
+	}
+
+	r.dispatch(ctx, EventUpdated, inc.ChannelId)
+	return nil
+}
+
+func (r *dispatchingRepository) ResolveIncident(ctx context.Context, inc *model.Incident) error {
+	err := r.Repository.ResolveIncident(ctx, inc)
+	if err != nil {
+		return err
+	}
+
+	r.dispatch(ctx, EventResolved, inc.ChannelId)
+	return nil
+}
+
+func (r *dispatchingRepository) CancelIncident(ctx context.Context, inc *model.Incident) error {
+	err := r.Repository.CancelIncident(ctx, inc)
+	if err != nil {
+		return err
+	}
+
+	r.dispatch(ctx, EventCanceled, inc.ChannelId)
+	return nil
+}
+
+// CloseIncident also dispatches severity_changed when the severity was reviewed on close
+func (r *dispatchingRepository) CloseIncident(ctx context.Context, inc *model.Incident) error {
+	if !r.wants(EventClosed) && !r.wants(EventSeverityChanged) {
+		return r.Repository.CloseIncident(ctx, inc)
+	}
+
+	before, beforeErr := r.Repository.GetIncident(ctx, inc.ChannelId)
+
+	err := r.Repository.CloseIncident(ctx, inc)
+	if err != nil {
+		return err
+	}
+
+	closed, ok := r.incident(ctx, inc.ChannelId)
+	if !ok {
+		return nil
+	}
+	if beforeErr == nil && before.SeverityLevel != closed.SeverityLevel {
+		r.notify(ctx, EventSeverityChanged, closed)
+	}
+	r.notify(ctx, EventClosed, closed)
+	return nil
+}
+
+func (r *dispatchingRepository) dispatch(ctx context.Context, event string, channelID string) {
+	if !r.wants(event) {
+		return
+	}
+
+	inc, ok := r.incident(ctx, channelID)
+	if ok {
+		r.notify(ctx, event, inc)
+	}
+}
+
+func (r *dispatchingRepository) notify(ctx context.Context, event string, inc model.Incident) {
+	for _, listener := range r.listeners {
+		if listener.Wants(event) {
+			listener.Dispatch(ctx, event, inc)
+		}
+	}
+}
+
+func (r *dispatchingRepository) wants(event string) bool {
+	for _, listener := range r.listeners {
+		if listener.Wants(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// incident reads the saved incident, the one given to the repository may only have the changed fields
+func (r *dispatchingRepository) incident(ctx context.Context, channelID string) (model.Incident, bool) {
+	inc, err := r.Repository.GetIncident(ctx, channelID)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("repository.GetIncident"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", channelID),
+		)
+		return inc, false
+	}
+	return inc, true
+}
@@ -0,0 +1,109 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"hellper/internal/lifecycle"
+	"hellper/internal/log"
+	"hellper/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type listenerStub struct {
+	events     map[string]bool
+	dispatched []string
+}
+
+func (l *listenerStub) Wants(event string) bool {
+	return l.events[event]
+}
+
+func (l *listenerStub) Dispatch(ctx context.Context, event string, incident model.Incident) {
+	l.dispatched = append(l.dispatched, event+":"+incident.ChannelId)
+}
+
+func TestRepositoryResolveIncident(t *testing.T) {
+	table := []struct {
+		testName           string
+		resolveError       error
+		events             map[string]bool
+		expectedDispatched []string
+	}{
+		{
+			testName:           "Dispatches the saved incident",
+			events:             map[string]bool{lifecycle.EventResolved: true},
+			expectedDispatched: []string{"resolved:C1"},
+		},
+		{
+			testName:     "Nothing is dispatched when the change fails",
+			resolveError: errors.New("rows not affected"),
+			events:       map[string]bool{lifecycle.EventResolved: true},
+		},
+		{
+			testName: "Nothing is dispatched without listeners of the event",
+			events:   map[string]bool{lifecycle.EventClosed: true},
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			var (
+				ctx            = context.Background()
+				loggerMock     = log.NewLoggerMock()
+				repositoryMock = model.NewRepositoryMock()
+				listener       = &listenerStub{events: f.events}
+				inc            = &model.Incident{ChannelId: "C1"}
+			)
+
+			repositoryMock.On("ResolveIncident", ctx, inc).Return(f.resolveError)
+			repositoryMock.On("GetIncident", "C1").Return(model.Incident{Id: 42, ChannelId: "C1", Status: model.StatusResolved}, nil)
+
+			err := lifecycle.NewRepository(loggerMock, repositoryMock, listener).ResolveIncident(ctx, inc)
+			assert.Equal(t, f.resolveError, err)
+			assert.Equal(t, f.expectedDispatched, listener.dispatched)
+		})
+	}
+}
+
+func TestRepositoryCloseIncident(t *testing.T) {
+	table := []struct {
+		testName           string
+		severityBefore     int64
+		expectedDispatched []string
+	}{
+		{
+			testName:           "Closed with the same severity",
+			severityBefore:     2,
+			expectedDispatched: []string{"closed:C1"},
+		},
+		{
+			testName:           "Severity reviewed on close",
+			severityBefore:     1,
+			expectedDispatched: []string{"severity_changed:C1", "closed:C1"},
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			var (
+				ctx            = context.Background()
+				loggerMock     = log.NewLoggerMock()
+				repositoryMock = model.NewRepositoryMock()
+				listener       = &listenerStub{events: map[string]bool{lifecycle.EventSeverityChanged: true, lifecycle.EventClosed: true}}
+				inc            = &model.Incident{ChannelId: "C1", SeverityLevel: 2}
+			)
+
+			repositoryMock.On("GetIncident", "C1").Return(model.Incident{Id: 42, ChannelId: "C1", SeverityLevel: f.severityBefore}, nil).Once()
+			repositoryMock.On("GetIncident", "C1").Return(model.Incident{Id: 42, ChannelId: "C1", SeverityLevel: 2, Status: model.StatusClosed}, nil)
+			repositoryMock.On("CloseIncident", inc).Return(nil)
+
+			err := lifecycle.NewRepository(loggerMock, repositoryMock, listener).CloseIncident(ctx, inc)
+			assert.NoError(t, err)
+			assert.Equal(t, f.expectedDispatched, listener.dispatched)
+		})
+	}
+}
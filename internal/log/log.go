@@ -8,6 +8,8 @@ import (
 const (
 	//STDOUT any message to stdout
 	STDOUT Out = "stdout"
+	//STDERR any message to stderr
+	STDERR Out = "stderr"
 
 	//ERROR is the error level logger
 	ERROR Level = "error"
@@ -29,6 +31,8 @@ func (o *Out) Set(value string) error {
 	switch value {
 	case "stdout", "STDOUT", "":
 		*o = STDOUT
+	case "stderr", "STDERR":
+		*o = STDERR
 	default:
 		*o = Out(value)
 	}
@@ -30,6 +30,11 @@ func TestOut(test *testing.T) {
 			output:   "STDOUT",
 			expected: STDOUT,
 		},
+		{
+			name:     "Creates a stderr Out",
+			output:   "stderr",
+			expected: STDERR,
+		},
 		{
 			name:     "Creates a file Out",
 			output:   "pathtoafile",
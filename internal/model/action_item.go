@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// Statuses of an action item
+const (
+	ActionItemOpen = "open"
+	ActionItemDone = "done"
+)
+
+// Priorities of an action item, from the lowest
+const (
+	ActionItemLow    = "low"
+	ActionItemMedium = "medium"
+	ActionItemHigh   = "high"
+)
+
+// ActionItemPriorities lists the action item priorities, from the lowest
+var ActionItemPriorities = []string{
+	ActionItemLow,
+	ActionItemMedium,
+	ActionItemHigh,
+}
+
+// ActionItem is a follow-up of an incident, optionally tracked by a ticket of the issue tracker
+type ActionItem struct {
+	Id          int64      `db:"id,omitempty"`
+	IncidentId  int64      `db:"incident_id,omitempty"`
+	ChannelId   string     `db:"channel_id,omitempty"`
+	Title       string     `db:"title,omitempty"`
+	OwnerId     string     `db:"owner_id,omitempty"`
+	DueDate     *time.Time `db:"due_date,omitempty"`
+	Status      string     `db:"status,omitempty"`
+	Priority    string     `db:"priority,omitempty"`
+	IssueKey    string     `db:"issue_key,omitempty"`
+	IssueUrl    string     `db:"issue_url,omitempty"`
+	CreatedBy   string     `db:"created_by,omitempty"`
+	CreatedAt   *time.Time `db:"created_at,omitempty"`
+	CompletedAt *time.Time `db:"completed_at,omitempty"`
+}
+
+// IsActionItemPriority tells if the priority is an action item priority
+func IsActionItemPriority(priority string) bool {
+	for _, p := range ActionItemPriorities {
+		if p == priority {
+			return true
+		}
+	}
+	return false
+}
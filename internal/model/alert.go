@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// Statuses of an alert
+const (
+	AlertFiring   = "firing"
+	AlertResolved = "resolved"
+)
+
+// Alert is an alert received on the alerts webhook, the alerts with the same fingerprint are the same alert
+type Alert struct {
+	Id          int64      `db:"id,omitempty"`
+	Fingerprint string     `db:"fingerprint,omitempty"`
+	IncidentId  int64      `db:"incident_id,omitempty"`
+	ChannelId   string     `db:"channel_id,omitempty"`
+	Source      string     `db:"source,omitempty"`
+	Name        string     `db:"name,omitempty"`
+	Status      string     `db:"status,omitempty"`
+	StartsAt    *time.Time `db:"starts_at,omitempty"`
+	LastSeenAt  *time.Time `db:"last_seen_at,omitempty"`
+	ResolvedAt  *time.Time `db:"resolved_at,omitempty"`
+}
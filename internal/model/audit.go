@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// AuditLog records who tried to run an action on an incident, and whether it was allowed
+type AuditLog struct {
+	Id        int64      `db:"id,omitempty"`
+	ChannelId string     `db:"channel_id,omitempty"`
+	UserId    string     `db:"user_id,omitempty"`
+	Action    string     `db:"action,omitempty"`
+	Allowed   bool       `db:"allowed,omitempty"`
+	Reason    string     `db:"reason,omitempty"`
+	CreatedAt *time.Time `db:"created_at,omitempty"`
+}
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// Escalation is a step of the reminder escalation chain sent to an incident, acknowledging it restarts the chain
+type Escalation struct {
+	Id             int64      `db:"id,omitempty"`
+	IncidentId     int64      `db:"incident_id,omitempty"`
+	Step           int        `db:"step,omitempty"`
+	EscalatedAt    *time.Time `db:"escalated_at,omitempty"`
+	AcknowledgedAt *time.Time `db:"acknowledged_at,omitempty"`
+	AcknowledgedBy string     `db:"acknowledged_by,omitempty"`
+}
@@ -3,8 +3,9 @@ package model
 import "time"
 
 type Event struct {
-	EventURL string
-	Start    *time.Time
-	End      *time.Time
-	Summary  string
+	EventURL      string
+	ConferenceURL string
+	Start         *time.Time
+	End           *time.Time
+	Summary       string
 }
@@ -24,12 +24,17 @@ type Incident struct {
 	RootCause               string        `db:"root_cause,omitempty"`
 	CustomerImpact          sql.NullInt64 `db:"customer_impact,omitempty"`
 	StatusPageUrl           string        `db:"status_page_url,omitempty"`
+	PagerEventUrl           string        `db:"pager_event_url,omitempty"`
+	WarRoomUrl              string        `db:"war_room_url,omitempty"`
 	PostMortemUrl           string        `db:"post_mortem_url,omitempty"`
+	PostMortemStatus        string        `db:"postmortem_status,omitempty"`
 	Status                  string        `db:"status,omitempty"`
 	Product                 string        `db:"product,omitempty"`
 	SeverityLevel           int64         `db:"severity_level,omitempty"`
 	ChannelName             string        `db:"channel_name,omitempty"`
 	UpdatedAt               *time.Time    `db:"updated_at,omitempty"`
+	ClosedAt                *time.Time    `db:"closed_at,omitempty"`
+	PostMortemPublishedAt   *time.Time    `db:"postmortem_published_at,omitempty"`
 	SnoozedUntil            sql.NullTime  `db:"snoozed_until,omitempty"`
 	DescriptionStarted      string        `db:"description_started,omitempty"`
 	DescriptionCancelled    string        `db:"description_cancelled,omitempty"`
@@ -0,0 +1,55 @@
+package model
+
+// Post mortem statuses, in the order they are written
+const (
+	PostMortemNotStarted = "not_started"
+	PostMortemDraft      = "draft"
+	PostMortemInReview   = "in_review"
+	PostMortemPublished  = "published"
+)
+
+// PostMortemStatuses lists the post mortem statuses, in the order they are written
+var PostMortemStatuses = []string{
+	PostMortemNotStarted,
+	PostMortemDraft,
+	PostMortemInReview,
+	PostMortemPublished,
+}
+
+var postMortemStatusNames = map[string]string{
+	PostMortemNotStarted: "Not started",
+	PostMortemDraft:      "Draft",
+	PostMortemInReview:   "In review",
+	PostMortemPublished:  "Published",
+}
+
+// IsPostMortemStatus tells if the status is a post mortem status
+func IsPostMortemStatus(status string) bool {
+	_, ok := postMortemStatusNames[status]
+	return ok
+}
+
+// CanMovePostMortem tells if the post mortem moves from a status to the next one or back to an earlier one, skipping
+// statuses is an explicit decision
+func CanMovePostMortem(from string, to string) bool {
+	return postMortemStatusIndex(to) <= postMortemStatusIndex(from)+1
+}
+
+// postMortemStatusIndex is the position of the status in the order they are written, the post mortems without status
+// are not started
+func postMortemStatusIndex(status string) int {
+	for index, s := range PostMortemStatuses {
+		if s == status {
+			return index
+		}
+	}
+	return 0
+}
+
+// PostMortemStatusName returns the name of a post mortem status, as shown on Slack
+func PostMortemStatusName(status string) string {
+	if name, ok := postMortemStatusNames[status]; ok {
+		return name
+	}
+	return postMortemStatusNames[PostMortemNotStarted]
+}
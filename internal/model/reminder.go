@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// Reminder is a reminder sent to an incident by a rule of the reminder policy
+type Reminder struct {
+	Id         int64      `db:"id,omitempty"`
+	IncidentId int64      `db:"incident_id,omitempty"`
+	Rule       string     `db:"rule,omitempty"`
+	RemindedAt *time.Time `db:"reminded_at,omitempty"`
+}
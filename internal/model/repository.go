@@ -1,15 +1,53 @@
 package model
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Repository interface {
 	AddPostMortemUrl(context.Context, string, string) error
+	AddPagerEventUrl(ctx context.Context, channelID string, pagerEventURL string) error
+	UpdatePostMortemStatus(ctx context.Context, channelID string, status string) error
+	ListPendingPostMortems(context.Context) ([]Incident, error)
 	InsertIncident(context.Context, *Incident) (int64, error)
 	GetIncident(context.Context, string) (Incident, error)
 	UpdateIncidentDates(context.Context, *Incident) error
 	CancelIncident(context.Context, *Incident) error
 	CloseIncident(context.Context, *Incident) error
 	ListActiveIncidents(context.Context) ([]Incident, error)
+	ListIncidentsSince(ctx context.Context, since time.Time) ([]Incident, error)
 	ResolveIncident(context.Context, *Incident) error
 	PauseNotifyIncident(context.Context, *Incident) error
+	InsertSnooze(context.Context, *Snooze) error
+	ResumeSnoozes(ctx context.Context, incidentID int64, userID string) error
+	ListSnoozes(ctx context.Context, incidentID int64) ([]Snooze, error)
+	InsertAuditLog(context.Context, *AuditLog) error
+	AssignIncidentRole(context.Context, *IncidentRole) error
+	ReleaseIncidentRole(ctx context.Context, incidentID int64, role string) error
+	ListIncidentRoles(ctx context.Context, incidentID int64) ([]IncidentRole, error)
+	InsertEscalation(context.Context, *Escalation) error
+	ListEscalations(ctx context.Context, incidentID int64) ([]Escalation, error)
+	AcknowledgeEscalations(ctx context.Context, incidentID int64, userID string) error
+	InsertReminder(context.Context, *Reminder) error
+	ListReminders(ctx context.Context, incidentID int64) ([]Reminder, error)
+	InsertStatusPageUpdate(context.Context, *StatusPageUpdate) error
+	GetStatusPageUpdate(ctx context.Context, id int64) (StatusPageUpdate, error)
+	ReviewStatusPageUpdate(context.Context, *StatusPageUpdate) error
+	ListStatusPageUpdates(ctx context.Context, incidentID int64) ([]StatusPageUpdate, error)
+	LockAlerts(ctx context.Context, fingerprints []string) (func(), error)
+	FindAlert(ctx context.Context, fingerprint string) (Alert, bool, error)
+	InsertAlert(context.Context, *Alert) error
+	UpdateAlertStatus(context.Context, *Alert) error
+	ListIncidentAlerts(ctx context.Context, incidentID int64) ([]Alert, error)
+	InsertActionItem(context.Context, *ActionItem) error
+	GetActionItem(ctx context.Context, id int64) (ActionItem, error)
+	ListActionItems(ctx context.Context, incidentID int64) ([]ActionItem, error)
+	ListTrackedActionItems(context.Context) ([]ActionItem, error)
+	UpdateActionItem(context.Context, *ActionItem) error
+	InsertWebhookDelivery(context.Context, *WebhookDelivery) error
+	InsertSLABreach(context.Context, *SLABreach) (bool, error)
+	GetGoogleToken(ctx context.Context, key string) (string, bool, error)
+	SaveGoogleToken(ctx context.Context, key string, token string) error
+	AcquireJobLock(ctx context.Context, name string, recurrence time.Duration) (func(), bool, error)
 }
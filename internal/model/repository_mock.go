@@ -2,6 +2,7 @@ package model
 
 import (
 	"context"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -40,6 +41,27 @@ func (mock *RepositoryMock) AddPostMortemUrl(ctx context.Context, channelName st
 	return args.Error(0)
 }
 
+func (mock *RepositoryMock) AddPagerEventUrl(ctx context.Context, channelID string, pagerEventURL string) error {
+	args := mock.Called(ctx, channelID, pagerEventURL)
+	return args.Error(0)
+}
+
+func (mock *RepositoryMock) UpdatePostMortemStatus(ctx context.Context, channelID string, status string) error {
+	args := mock.Called(ctx, channelID, status)
+	return args.Error(0)
+}
+
+func (mock *RepositoryMock) ListPendingPostMortems(ctx context.Context) ([]Incident, error) {
+	var (
+		args   = mock.Called(ctx)
+		result = args.Get(0)
+	)
+	if result == nil {
+		return nil, args.Error(1)
+	}
+	return result.([]Incident), args.Error(1)
+}
+
 func (mock *RepositoryMock) CancelIncident(ctx context.Context, inc *Incident) error {
 	args := mock.Called(ctx, inc.ChannelId, inc.DescriptionCancelled)
 	return args.Error(0)
@@ -69,3 +91,229 @@ func (mock *RepositoryMock) PauseNotifyIncident(ctx context.Context, inc *Incide
 	args := mock.Called(ctx, inc)
 	return args.Error(0)
 }
+
+func (mock *RepositoryMock) InsertAuditLog(ctx context.Context, audit *AuditLog) error {
+	args := mock.Called(ctx, audit)
+	return args.Error(0)
+}
+
+func (mock *RepositoryMock) AssignIncidentRole(ctx context.Context, role *IncidentRole) error {
+	args := mock.Called(ctx, role)
+	return args.Error(0)
+}
+
+func (mock *RepositoryMock) ReleaseIncidentRole(ctx context.Context, incidentID int64, role string) error {
+	args := mock.Called(ctx, incidentID, role)
+	return args.Error(0)
+}
+
+func (mock *RepositoryMock) ListIncidentRoles(ctx context.Context, incidentID int64) ([]IncidentRole, error) {
+	var (
+		args   = mock.Called(ctx, incidentID)
+		result = args.Get(0)
+	)
+	if result == nil {
+		return nil, args.Error(1)
+	}
+	return result.([]IncidentRole), args.Error(1)
+}
+
+func (mock *RepositoryMock) ListIncidentsSince(ctx context.Context, since time.Time) ([]Incident, error) {
+	var (
+		args   = mock.Called(ctx, since)
+		result = args.Get(0)
+	)
+	if result == nil {
+		return nil, args.Error(1)
+	}
+	return result.([]Incident), args.Error(1)
+}
+
+func (mock *RepositoryMock) InsertSnooze(ctx context.Context, snooze *Snooze) error {
+	args := mock.Called(ctx, snooze)
+	return args.Error(0)
+}
+
+func (mock *RepositoryMock) ResumeSnoozes(ctx context.Context, incidentID int64, userID string) error {
+	args := mock.Called(ctx, incidentID, userID)
+	return args.Error(0)
+}
+
+func (mock *RepositoryMock) ListSnoozes(ctx context.Context, incidentID int64) ([]Snooze, error) {
+	var (
+		args   = mock.Called(ctx, incidentID)
+		result = args.Get(0)
+	)
+	if result == nil {
+		return nil, args.Error(1)
+	}
+	return result.([]Snooze), args.Error(1)
+}
+
+func (mock *RepositoryMock) InsertEscalation(ctx context.Context, escalation *Escalation) error {
+	args := mock.Called(ctx, escalation)
+	return args.Error(0)
+}
+
+func (mock *RepositoryMock) ListEscalations(ctx context.Context, incidentID int64) ([]Escalation, error) {
+	var (
+		args   = mock.Called(ctx, incidentID)
+		result = args.Get(0)
+	)
+	if result == nil {
+		return nil, args.Error(1)
+	}
+	return result.([]Escalation), args.Error(1)
+}
+
+func (mock *RepositoryMock) InsertReminder(ctx context.Context, reminder *Reminder) error {
+	args := mock.Called(ctx, reminder)
+	return args.Error(0)
+}
+
+func (mock *RepositoryMock) ListReminders(ctx context.Context, incidentID int64) ([]Reminder, error) {
+	var (
+		args   = mock.Called(ctx, incidentID)
+		result = args.Get(0)
+	)
+	if result == nil {
+		return nil, args.Error(1)
+	}
+	return result.([]Reminder), args.Error(1)
+}
+
+func (mock *RepositoryMock) InsertStatusPageUpdate(ctx context.Context, update *StatusPageUpdate) error {
+	args := mock.Called(ctx, update)
+	return args.Error(0)
+}
+
+func (mock *RepositoryMock) GetStatusPageUpdate(ctx context.Context, id int64) (StatusPageUpdate, error) {
+	args := mock.Called(ctx, id)
+	return args.Get(0).(StatusPageUpdate), args.Error(1)
+}
+
+func (mock *RepositoryMock) ReviewStatusPageUpdate(ctx context.Context, update *StatusPageUpdate) error {
+	args := mock.Called(ctx, update)
+	return args.Error(0)
+}
+
+func (mock *RepositoryMock) ListStatusPageUpdates(ctx context.Context, incidentID int64) ([]StatusPageUpdate, error) {
+	var (
+		args   = mock.Called(ctx, incidentID)
+		result = args.Get(0)
+	)
+	if result == nil {
+		return nil, args.Error(1)
+	}
+	return result.([]StatusPageUpdate), args.Error(1)
+}
+
+func (mock *RepositoryMock) LockAlerts(ctx context.Context, fingerprints []string) (func(), error) {
+	var (
+		args    = mock.Called(ctx, fingerprints)
+		release = args.Get(0)
+	)
+	if release != nil {
+		return release.(func()), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *RepositoryMock) FindAlert(ctx context.Context, fingerprint string) (Alert, bool, error) {
+	args := mock.Called(ctx, fingerprint)
+	return args.Get(0).(Alert), args.Bool(1), args.Error(2)
+}
+
+func (mock *RepositoryMock) InsertAlert(ctx context.Context, alert *Alert) error {
+	args := mock.Called(ctx, alert)
+	return args.Error(0)
+}
+
+func (mock *RepositoryMock) UpdateAlertStatus(ctx context.Context, alert *Alert) error {
+	args := mock.Called(ctx, alert)
+	return args.Error(0)
+}
+
+func (mock *RepositoryMock) ListIncidentAlerts(ctx context.Context, incidentID int64) ([]Alert, error) {
+	var (
+		args   = mock.Called(ctx, incidentID)
+		result = args.Get(0)
+	)
+	if result == nil {
+		return nil, args.Error(1)
+	}
+	return result.([]Alert), args.Error(1)
+}
+
+func (mock *RepositoryMock) InsertActionItem(ctx context.Context, item *ActionItem) error {
+	args := mock.Called(ctx, item)
+	return args.Error(0)
+}
+
+func (mock *RepositoryMock) GetActionItem(ctx context.Context, id int64) (ActionItem, error) {
+	args := mock.Called(ctx, id)
+	return args.Get(0).(ActionItem), args.Error(1)
+}
+
+func (mock *RepositoryMock) ListActionItems(ctx context.Context, incidentID int64) ([]ActionItem, error) {
+	var (
+		args   = mock.Called(ctx, incidentID)
+		result = args.Get(0)
+	)
+	if result == nil {
+		return nil, args.Error(1)
+	}
+	return result.([]ActionItem), args.Error(1)
+}
+
+func (mock *RepositoryMock) ListTrackedActionItems(ctx context.Context) ([]ActionItem, error) {
+	var (
+		args   = mock.Called(ctx)
+		result = args.Get(0)
+	)
+	if result == nil {
+		return nil, args.Error(1)
+	}
+	return result.([]ActionItem), args.Error(1)
+}
+
+func (mock *RepositoryMock) UpdateActionItem(ctx context.Context, item *ActionItem) error {
+	args := mock.Called(ctx, item)
+	return args.Error(0)
+}
+
+func (mock *RepositoryMock) InsertWebhookDelivery(ctx context.Context, delivery *WebhookDelivery) error {
+	args := mock.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func (mock *RepositoryMock) InsertSLABreach(ctx context.Context, breach *SLABreach) (bool, error) {
+	args := mock.Called(ctx, breach)
+	return args.Bool(0), args.Error(1)
+}
+
+func (mock *RepositoryMock) GetGoogleToken(ctx context.Context, key string) (string, bool, error) {
+	args := mock.Called(ctx, key)
+	return args.String(0), args.Bool(1), args.Error(2)
+}
+
+func (mock *RepositoryMock) SaveGoogleToken(ctx context.Context, key string, token string) error {
+	args := mock.Called(ctx, key, token)
+	return args.Error(0)
+}
+
+func (mock *RepositoryMock) AcknowledgeEscalations(ctx context.Context, incidentID int64, userID string) error {
+	args := mock.Called(ctx, incidentID, userID)
+	return args.Error(0)
+}
+
+func (mock *RepositoryMock) AcquireJobLock(ctx context.Context, name string, recurrence time.Duration) (func(), bool, error) {
+	var (
+		args    = mock.Called(ctx, name, recurrence)
+		release = args.Get(0)
+	)
+	if release != nil {
+		return release.(func()), args.Bool(1), args.Error(2)
+	}
+	return nil, args.Bool(1), args.Error(2)
+}
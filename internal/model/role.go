@@ -0,0 +1,64 @@
+package model
+
+import "time"
+
+// Roles that can be assigned on an incident, besides the commander
+const (
+	RoleCommsLead       = "comms_lead"
+	RoleScribe          = "scribe"
+	RoleOpsLead         = "ops_lead"
+	RoleSubjectExpert   = "sme"
+	RoleCustomerLiaison = "customer_liaison"
+)
+
+// IncidentRoles lists the assignable roles, in the order they are shown
+var IncidentRoles = []string{
+	RoleCommsLead,
+	RoleOpsLead,
+	RoleScribe,
+	RoleSubjectExpert,
+	RoleCustomerLiaison,
+}
+
+var roleNames = map[string]string{
+	RoleCommsLead:       "Comms Lead",
+	RoleScribe:          "Scribe",
+	RoleOpsLead:         "Ops Lead",
+	RoleSubjectExpert:   "Subject Matter Expert",
+	RoleCustomerLiaison: "Customer Liaison",
+}
+
+// IncidentRole is a role held by a user on an incident
+type IncidentRole struct {
+	Id         int64      `db:"id,omitempty"`
+	IncidentId int64      `db:"incident_id,omitempty"`
+	Role       string     `db:"role,omitempty"`
+	UserId     string     `db:"user_id,omitempty"`
+	AssignedBy string     `db:"assigned_by,omitempty"`
+	AssignedAt *time.Time `db:"assigned_at,omitempty"`
+	ReleasedAt *time.Time `db:"released_at,omitempty"`
+}
+
+// IsIncidentRole tells if the role can be assigned on an incident
+func IsIncidentRole(role string) bool {
+	_, ok := roleNames[role]
+	return ok
+}
+
+// RoleName returns the name of a role, as shown on Slack
+func RoleName(role string) string {
+	if name, ok := roleNames[role]; ok {
+		return name
+	}
+	return role
+}
+
+// RoleHolder returns the user holding the role, or an empty string if nobody holds it
+func RoleHolder(roles []IncidentRole, role string) string {
+	for _, r := range roles {
+		if r.Role == role {
+			return r.UserId
+		}
+	}
+	return ""
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// SLABreach records an incident that took longer than the SLA target of a metric, once per metric
+type SLABreach struct {
+	Id         int64         `db:"id,omitempty"`
+	IncidentId int64         `db:"incident_id,omitempty"`
+	Metric     string        `db:"metric,omitempty"`
+	Target     time.Duration `db:"target_seconds,omitempty"`
+	BreachedAt *time.Time    `db:"breached_at,omitempty"`
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// Snooze is a pause of the notifications of an incident, resuming it early ends it before SnoozedUntil
+type Snooze struct {
+	Id           int64      `db:"id,omitempty"`
+	IncidentId   int64      `db:"incident_id,omitempty"`
+	SnoozedBy    string     `db:"snoozed_by,omitempty"`
+	Reason       string     `db:"reason,omitempty"`
+	SnoozedAt    *time.Time `db:"snoozed_at,omitempty"`
+	SnoozedUntil *time.Time `db:"snoozed_until,omitempty"`
+	ResumedAt    *time.Time `db:"resumed_at,omitempty"`
+	ResumedBy    string     `db:"resumed_by,omitempty"`
+}
@@ -0,0 +1,244 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/model/sql"
+)
+
+func (r *repository) InsertActionItem(ctx context.Context, item *model.ActionItem) error {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("incidentID", item.IncidentId),
+		log.NewValue("title", item.Title),
+	)
+
+	err := r.db.QueryRow(
+		`INSERT INTO action_item
+			( incident_id
+			, channel_id
+			, title
+			, owner_id
+			, due_date
+			, status
+			, priority
+			, issue_key
+			, issue_url
+			, created_by)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''), $10)
+		RETURNING id, created_at`,
+		item.IncidentId,
+		item.ChannelId,
+		item.Title,
+		item.OwnerId,
+		item.DueDate,
+		item.Status,
+		item.Priority,
+		item.IssueKey,
+		item.IssueUrl,
+		item.CreatedBy,
+	).Scan(&item.Id, &item.CreatedAt)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.QueryRow"),
+			log.Reason(err.Error()),
+			log.NewValue("incidentID", item.IncidentId),
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (r *repository) GetActionItem(ctx context.Context, id int64) (model.ActionItem, error) {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("id", id),
+	)
+
+	rows, err := r.db.Query(
+		actionItemSelect()+`
+		WHERE id = $1`,
+		id,
+	)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.Query"),
+			log.Reason(err.Error()),
+			log.NewValue("id", id),
+		)
+		return model.ActionItem{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		err = errors.New("action item " + strconv.FormatInt(id, 10) + " not found")
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("rows.Next"),
+			log.Reason(err.Error()),
+			log.NewValue("id", id),
+		)
+		return model.ActionItem{}, err
+	}
+
+	item, err := scanActionItem(rows)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("rows.Scan"),
+			log.Reason(err.Error()),
+			log.NewValue("id", id),
+		)
+		return model.ActionItem{}, err
+	}
+
+	return item, nil
+}
+
+func (r *repository) ListActionItems(ctx context.Context, incidentID int64) ([]model.ActionItem, error) {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("incidentID", incidentID),
+	)
+
+	return r.listActionItems(ctx, `
+		WHERE incident_id = $1
+		ORDER BY id`,
+		incidentID,
+	)
+}
+
+// ListTrackedActionItems returns the open action items with a ticket on the issue tracker
+func (r *repository) ListTrackedActionItems(ctx context.Context) ([]model.ActionItem, error) {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+	)
+
+	return r.listActionItems(ctx, `
+		WHERE status = $1
+		AND issue_key IS NOT NULL
+		ORDER BY id`,
+		model.ActionItemOpen,
+	)
+}
+
+func (r *repository) listActionItems(ctx context.Context, where string, args ...interface{}) ([]model.ActionItem, error) {
+	rows, err := r.db.Query(actionItemSelect()+where, args...)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.Query"),
+			log.Reason(err.Error()),
+		)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.ActionItem
+	for rows.Next() {
+		item, err := scanActionItem(rows)
+		if err != nil {
+			r.logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("rows.Scan"),
+				log.Reason(err.Error()),
+			)
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// UpdateActionItem saves the status and the ticket of the action item
+func (r *repository) UpdateActionItem(ctx context.Context, item *model.ActionItem) error {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("id", item.Id),
+		log.NewValue("status", item.Status),
+		log.NewValue("issueKey", item.IssueKey),
+	)
+
+	_, err := r.db.Exec(
+		`UPDATE action_item SET
+			status = $2
+			, completed_at = $3
+			, issue_key = NULLIF($4, '')
+			, issue_url = NULLIF($5, '')
+		WHERE id = $1`,
+		item.Id,
+		item.Status,
+		item.CompletedAt,
+		item.IssueKey,
+		item.IssueUrl,
+	)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.Exec"),
+			log.Reason(err.Error()),
+			log.NewValue("id", item.Id),
+		)
+		return err
+	}
+
+	return nil
+}
+
+func actionItemSelect() string {
+	return `SELECT
+			id
+			, incident_id
+			, channel_id
+			, title
+			, CASE WHEN owner_id IS NULL THEN '' ELSE owner_id END owner_id
+			, due_date
+			, status
+			, priority
+			, CASE WHEN issue_key IS NULL THEN '' ELSE issue_key END issue_key
+			, CASE WHEN issue_url IS NULL THEN '' ELSE issue_url END issue_url
+			, created_by
+			, created_at
+			, completed_at
+		FROM action_item`
+}
+
+func scanActionItem(rows sql.Rows) (model.ActionItem, error) {
+	var item model.ActionItem
+	err := rows.Scan(
+		&item.Id,
+		&item.IncidentId,
+		&item.ChannelId,
+		&item.Title,
+		&item.OwnerId,
+		&item.DueDate,
+		&item.Status,
+		&item.Priority,
+		&item.IssueKey,
+		&item.IssueUrl,
+		&item.CreatedBy,
+		&item.CreatedAt,
+		&item.CompletedAt,
+	)
+	return item, err
+}
@@ -0,0 +1,259 @@
+package postgres
+
+import (
+	"context"
+	"sort"
+
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/model/sql"
+)
+
+// LockAlerts takes the advisory locks of the fingerprints on a dedicated session, the notifications of the same alert
+// received at once wait for each other so a single one opens its incident. The locks are taken in order, two
+// notifications sharing alerts do not deadlock
+func (r *repository) LockAlerts(ctx context.Context, fingerprints []string) (func(), error) {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.Conn"),
+			log.Reason(err.Error()),
+		)
+		return nil, err
+	}
+
+	release := func() {
+		_, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock_all()`)
+		if err != nil {
+			r.logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("pg_advisory_unlock_all"),
+				log.Reason(err.Error()),
+			)
+		}
+		conn.Close()
+	}
+
+	sorted := append([]string(nil), fingerprints...)
+	sort.Strings(sorted)
+	for _, fingerprint := range sorted {
+		// The alerts have their own key space, apart from the job locks
+		_, err = conn.ExecContext(ctx, `SELECT pg_advisory_lock(hashtext('alert'), hashtext($1))`, fingerprint)
+		if err != nil {
+			r.logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("pg_advisory_lock"),
+				log.Reason(err.Error()),
+				log.NewValue("fingerprint", fingerprint),
+			)
+			release()
+			return nil, err
+		}
+	}
+
+	return release, nil
+}
+
+// FindAlert returns the last alert received with the fingerprint, false when it was never received
+func (r *repository) FindAlert(ctx context.Context, fingerprint string) (model.Alert, bool, error) {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("fingerprint", fingerprint),
+	)
+
+	rows, err := r.db.Query(
+		alertSelect()+`
+		WHERE fingerprint = $1
+		ORDER BY id DESC
+		LIMIT 1`,
+		fingerprint,
+	)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.Query"),
+			log.Reason(err.Error()),
+			log.NewValue("fingerprint", fingerprint),
+		)
+		return model.Alert{}, false, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return model.Alert{}, false, nil
+	}
+
+	alert, err := scanAlert(rows)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("rows.Scan"),
+			log.Reason(err.Error()),
+			log.NewValue("fingerprint", fingerprint),
+		)
+		return model.Alert{}, false, err
+	}
+
+	return alert, true, nil
+}
+
+func (r *repository) InsertAlert(ctx context.Context, alert *model.Alert) error {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("fingerprint", alert.Fingerprint),
+		log.NewValue("incidentID", alert.IncidentId),
+		log.NewValue("status", alert.Status),
+	)
+
+	err := r.db.QueryRow(
+		`INSERT INTO alert
+			( fingerprint
+			, incident_id
+			, channel_id
+			, source
+			, name
+			, status
+			, starts_at
+			, resolved_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, last_seen_at`,
+		alert.Fingerprint,
+		alert.IncidentId,
+		alert.ChannelId,
+		alert.Source,
+		alert.Name,
+		alert.Status,
+		alert.StartsAt,
+		alert.ResolvedAt,
+	).Scan(&alert.Id, &alert.LastSeenAt)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.QueryRow"),
+			log.Reason(err.Error()),
+			log.NewValue("fingerprint", alert.Fingerprint),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateAlertStatus saves the status of the alert, each update is a new sighting of the alert
+func (r *repository) UpdateAlertStatus(ctx context.Context, alert *model.Alert) error {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("id", alert.Id),
+		log.NewValue("status", alert.Status),
+	)
+
+	err := r.db.QueryRow(
+		`UPDATE alert SET
+			status = $2
+			, resolved_at = $3
+			, last_seen_at = now()
+		WHERE id = $1
+		RETURNING last_seen_at`,
+		alert.Id,
+		alert.Status,
+		alert.ResolvedAt,
+	).Scan(&alert.LastSeenAt)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.QueryRow"),
+			log.Reason(err.Error()),
+			log.NewValue("id", alert.Id),
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (r *repository) ListIncidentAlerts(ctx context.Context, incidentID int64) ([]model.Alert, error) {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("incidentID", incidentID),
+	)
+
+	rows, err := r.db.Query(
+		alertSelect()+`
+		WHERE incident_id = $1
+		ORDER BY id`,
+		incidentID,
+	)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.Query"),
+			log.Reason(err.Error()),
+			log.NewValue("incidentID", incidentID),
+		)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var alerts []model.Alert
+	for rows.Next() {
+		alert, err := scanAlert(rows)
+		if err != nil {
+			r.logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("rows.Scan"),
+				log.Reason(err.Error()),
+				log.NewValue("incidentID", incidentID),
+			)
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func alertSelect() string {
+	return `SELECT
+			id
+			, fingerprint
+			, incident_id
+			, channel_id
+			, source
+			, CASE WHEN name IS NULL THEN '' ELSE name END name
+			, status
+			, starts_at
+			, last_seen_at
+			, resolved_at
+		FROM alert`
+}
+
+func scanAlert(rows sql.Rows) (model.Alert, error) {
+	var alert model.Alert
+	err := rows.Scan(
+		&alert.Id,
+		&alert.Fingerprint,
+		&alert.IncidentId,
+		&alert.ChannelId,
+		&alert.Source,
+		&alert.Name,
+		&alert.Status,
+		&alert.StartsAt,
+		&alert.LastSeenAt,
+		&alert.ResolvedAt,
+	)
+	return alert, err
+}
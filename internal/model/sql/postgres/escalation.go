@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+
+	"hellper/internal/log"
+	"hellper/internal/model"
+)
+
+func (r *repository) InsertEscalation(ctx context.Context, escalation *model.Escalation) error {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("incidentID", escalation.IncidentId),
+		log.NewValue("step", escalation.Step),
+	)
+
+	err := r.db.QueryRow(
+		`INSERT INTO incident_escalation
+			( incident_id
+			, step)
+		VALUES ($1, $2)
+		RETURNING id, escalated_at`,
+		escalation.IncidentId,
+		escalation.Step,
+	).Scan(&escalation.Id, &escalation.EscalatedAt)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.QueryRow"),
+			log.Reason(err.Error()),
+			log.NewValue("incidentID", escalation.IncidentId),
+			log.NewValue("step", escalation.Step),
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (r *repository) ListEscalations(ctx context.Context, incidentID int64) ([]model.Escalation, error) {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("incidentID", incidentID),
+	)
+
+	rows, err := r.db.Query(
+		`SELECT
+			id
+			, incident_id
+			, step
+			, escalated_at
+			, acknowledged_at
+			, CASE WHEN acknowledged_by IS NULL THEN '' ELSE acknowledged_by END acknowledged_by
+		FROM incident_escalation
+		WHERE incident_id = $1
+		ORDER BY escalated_at`,
+		incidentID,
+	)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.Query"),
+			log.Reason(err.Error()),
+			log.NewValue("incidentID", incidentID),
+		)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var escalations []model.Escalation
+	for rows.Next() {
+		var escalation model.Escalation
+		err = rows.Scan(
+			&escalation.Id,
+			&escalation.IncidentId,
+			&escalation.Step,
+			&escalation.EscalatedAt,
+			&escalation.AcknowledgedAt,
+			&escalation.AcknowledgedBy,
+		)
+		if err != nil {
+			r.logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("rows.Scan"),
+				log.Reason(err.Error()),
+				log.NewValue("incidentID", incidentID),
+			)
+			return nil, err
+		}
+		escalations = append(escalations, escalation)
+	}
+
+	return escalations, nil
+}
+
+func (r *repository) AcknowledgeEscalations(ctx context.Context, incidentID int64, userID string) error {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("incidentID", incidentID),
+		log.NewValue("userID", userID),
+	)
+
+	_, err := r.db.Exec(
+		`UPDATE incident_escalation SET
+			acknowledged_at = now()
+			, acknowledged_by = $2
+		WHERE incident_id = $1
+			AND acknowledged_at IS NULL`,
+		incidentID,
+		userID,
+	)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.Exec"),
+			log.Reason(err.Error()),
+			log.NewValue("incidentID", incidentID),
+			log.NewValue("userID", userID),
+		)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+
+	"hellper/internal/log"
+)
+
+// GetGoogleToken returns the last Google OAuth token saved on the key, false when none was saved
+func (r *repository) GetGoogleToken(ctx context.Context, key string) (string, bool, error) {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("key", key),
+	)
+
+	rows, err := r.db.Query(`SELECT token FROM google_token WHERE key = $1`, key)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.Query"),
+			log.Reason(err.Error()),
+			log.NewValue("key", key),
+		)
+		return "", false, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return "", false, nil
+	}
+
+	var token string
+	err = rows.Scan(&token)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("rows.Scan"),
+			log.Reason(err.Error()),
+			log.NewValue("key", key),
+		)
+		return "", false, err
+	}
+
+	return token, true, nil
+}
+
+// SaveGoogleToken keeps the refreshed Google OAuth token of the key, replacing the previous one
+func (r *repository) SaveGoogleToken(ctx context.Context, key string, token string) error {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("key", key),
+	)
+
+	_, err := r.db.Exec(
+		`INSERT INTO google_token (key, token, updated_at) VALUES ($1, $2, now())
+		ON CONFLICT (key) DO UPDATE SET token = EXCLUDED.token, updated_at = now()`,
+		key,
+		token,
+	)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.Exec"),
+			log.Reason(err.Error()),
+			log.NewValue("key", key),
+		)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"hellper/internal/log"
+	"hellper/internal/model"
+)
+
+// ListIncidentsSince lists the incidents started, resolved or closed since the given time, and every active incident
+func (r *repository) ListIncidentsSince(ctx context.Context, since time.Time) ([]model.Incident, error) {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("since", since),
+	)
+
+	rows, err := r.db.Query(
+		`SELECT
+			id
+			, CASE WHEN title IS NULL THEN '' ELSE title END title
+			, start_ts
+			, end_ts
+			, identification_ts
+			, closed_at
+			, postmortem_published_at
+			, CASE WHEN postmortem_status IS NULL THEN 'not_started' ELSE postmortem_status END postmortem_status
+			, CASE WHEN status IS NULL THEN '' ELSE status END status
+			, CASE WHEN product IS NULL THEN '' ELSE product END product
+			, CASE WHEN severity_level IS NULL THEN 0 ELSE severity_level END AS severity_level
+			, CASE WHEN post_mortem_url IS NULL THEN '' ELSE post_mortem_url END post_mortem_url
+			, CASE WHEN channel_name IS NULL THEN '' ELSE channel_name END AS channel_name
+			, CASE WHEN channel_id IS NULL THEN '' ELSE channel_id END AS channel_id
+			, CASE WHEN commander_id IS NULL THEN '' ELSE commander_id END commander_id
+		FROM incident
+		WHERE start_ts >= $1
+			OR end_ts >= $1
+			OR closed_at >= $1
+			OR status IN ($2, $3)
+		ORDER BY start_ts`,
+		since,
+		model.StatusOpen,
+		model.StatusResolved,
+	)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.Query"),
+			log.Reason(err.Error()),
+			log.NewValue("since", since),
+		)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var incidents []model.Incident
+	for rows.Next() {
+		var inc model.Incident
+		err = rows.Scan(
+			&inc.Id,
+			&inc.Title,
+			&inc.StartTimestamp,
+			&inc.EndTimestamp,
+			&inc.IdentificationTimestamp,
+			&inc.ClosedAt,
+			&inc.PostMortemPublishedAt,
+			&inc.PostMortemStatus,
+			&inc.Status,
+			&inc.Product,
+			&inc.SeverityLevel,
+			&inc.PostMortemUrl,
+			&inc.ChannelName,
+			&inc.ChannelId,
+			&inc.CommanderId,
+		)
+		if err != nil {
+			r.logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("rows.Scan"),
+				log.Reason(err.Error()),
+				log.NewValue("since", since),
+			)
+			return nil, err
+		}
+		incidents = append(incidents, inc)
+	}
+
+	return incidents, nil
+}
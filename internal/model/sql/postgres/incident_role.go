@@ -0,0 +1,177 @@
+package postgres
+
+import (
+	"context"
+
+	"hellper/internal/log"
+	"hellper/internal/model"
+)
+
+const releaseIncidentRoleQuery = `UPDATE incident_role SET
+			released_at = now()
+		WHERE incident_id = $1
+			AND role = $2
+			AND released_at IS NULL`
+
+func (r *repository) AssignIncidentRole(ctx context.Context, role *model.IncidentRole) error {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("incidentID", role.IncidentId),
+		log.NewValue("role", role.Role),
+		log.NewValue("userID", role.UserId),
+	)
+
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.BeginTx"),
+			log.Reason(err.Error()),
+			log.NewValue("incidentID", role.IncidentId),
+		)
+		return err
+	}
+	// Rollback does nothing once the transaction is committed
+	defer tx.Rollback()
+
+	// A role has a single holder, the previous one is released on the same transaction as the assignment
+	_, err = tx.ExecContext(ctx, releaseIncidentRoleQuery, role.IncidentId, role.Role)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("tx.ExecContext"),
+			log.Reason(err.Error()),
+			log.NewValue("incidentID", role.IncidentId),
+			log.NewValue("role", role.Role),
+		)
+		return err
+	}
+
+	err = tx.QueryRowContext(
+		ctx,
+		`INSERT INTO incident_role
+			( incident_id
+			, role
+			, user_id
+			, assigned_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, assigned_at`,
+		role.IncidentId,
+		role.Role,
+		role.UserId,
+		role.AssignedBy,
+	).Scan(&role.Id, &role.AssignedAt)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("tx.QueryRowContext"),
+			log.Reason(err.Error()),
+			log.NewValue("incidentID", role.IncidentId),
+			log.NewValue("role", role.Role),
+		)
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("tx.Commit"),
+			log.Reason(err.Error()),
+			log.NewValue("incidentID", role.IncidentId),
+			log.NewValue("role", role.Role),
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (r *repository) ReleaseIncidentRole(ctx context.Context, incidentID int64, role string) error {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("incidentID", incidentID),
+		log.NewValue("role", role),
+	)
+
+	_, err := r.db.Exec(releaseIncidentRoleQuery, incidentID, role)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.Exec"),
+			log.Reason(err.Error()),
+			log.NewValue("incidentID", incidentID),
+			log.NewValue("role", role),
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (r *repository) ListIncidentRoles(ctx context.Context, incidentID int64) ([]model.IncidentRole, error) {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("incidentID", incidentID),
+	)
+
+	rows, err := r.db.Query(
+		`SELECT
+			id
+			, incident_id
+			, role
+			, user_id
+			, CASE WHEN assigned_by IS NULL THEN '' ELSE assigned_by END assigned_by
+			, assigned_at
+		FROM incident_role
+		WHERE incident_id = $1
+			AND released_at IS NULL
+		ORDER BY assigned_at`,
+		incidentID,
+	)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.Query"),
+			log.Reason(err.Error()),
+			log.NewValue("incidentID", incidentID),
+		)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []model.IncidentRole
+	for rows.Next() {
+		var role model.IncidentRole
+		err = rows.Scan(
+			&role.Id,
+			&role.IncidentId,
+			&role.Role,
+			&role.UserId,
+			&role.AssignedBy,
+			&role.AssignedAt,
+		)
+		if err != nil {
+			r.logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("rows.Scan"),
+				log.Reason(err.Error()),
+				log.NewValue("incidentID", incidentID),
+			)
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+
+	return roles, nil
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"hellper/internal/log"
+)
+
+// AcquireJobLock takes the advisory lock of the job on a dedicated session and claims its run when the last one is
+// older than the recurrence, so a single replica runs the job on each recurrence
+func (r *repository) AcquireJobLock(ctx context.Context, name string, recurrence time.Duration) (func(), bool, error) {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.Conn"),
+			log.Reason(err.Error()),
+			log.NewValue("job", name),
+		)
+		return nil, false, err
+	}
+
+	var locked bool
+	err = conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock(hashtext($1))`, name).Scan(&locked)
+	if err != nil || !locked {
+		conn.Close()
+		if err != nil {
+			r.logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("pg_try_advisory_lock"),
+				log.Reason(err.Error()),
+				log.NewValue("job", name),
+			)
+		}
+		return nil, false, err
+	}
+
+	release := func() {
+		_, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext($1))`, name)
+		if err != nil {
+			r.logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("pg_advisory_unlock"),
+				log.Reason(err.Error()),
+				log.NewValue("job", name),
+			)
+		}
+		conn.Close()
+	}
+
+	// The replicas tick at slightly different times, a tenth of the recurrence absorbs the drift
+	var claimed bool
+	err = conn.QueryRowContext(
+		ctx,
+		`WITH claim AS (
+			INSERT INTO job_run (name, last_run_at) VALUES ($1, now())
+			ON CONFLICT (name) DO UPDATE SET last_run_at = now()
+			WHERE job_run.last_run_at <= now() - make_interval(secs => $2)
+			RETURNING name)
+		SELECT EXISTS (SELECT 1 FROM claim)`,
+		name,
+		(recurrence - recurrence/10).Seconds(),
+	).Scan(&claimed)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("conn.QueryRowContext"),
+			log.Reason(err.Error()),
+			log.NewValue("job", name),
+		)
+		release()
+		return nil, false, err
+	}
+
+	if !claimed {
+		release()
+		return nil, false, nil
+	}
+
+	return release, true, nil
+}
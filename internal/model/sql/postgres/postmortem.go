@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"hellper/internal/log"
+	"hellper/internal/model"
+)
+
+// UpdatePostMortemStatus moves the post mortem of the incident of the channel, publishing it stops the post mortem SLA
+func (r *repository) UpdatePostMortemStatus(ctx context.Context, channelID string, status string) error {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("channelID", channelID),
+		log.NewValue("status", status),
+	)
+
+	result, err := r.db.Exec(
+		`UPDATE incident SET
+			postmortem_status = $1,
+			postmortem_published_at = CASE WHEN $1 = $2 THEN COALESCE(postmortem_published_at, now()) ELSE NULL END
+		WHERE channel_id = $3`,
+		status,
+		model.PostMortemPublished,
+		channelID,
+	)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.Exec"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", channelID),
+			log.NewValue("status", status),
+		)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err == nil && rowsAffected == 0 {
+		err = errors.New("rows not affected")
+	}
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("result.RowsAffected"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", channelID),
+			log.NewValue("status", status),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// ListPendingPostMortems lists the resolved and closed incidents whose post mortem is not published yet
+func (r *repository) ListPendingPostMortems(ctx context.Context) ([]model.Incident, error) {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+	)
+
+	rows, err := r.db.Query(
+		`SELECT
+			id
+			, CASE WHEN title IS NULL THEN '' ELSE title END title
+			, start_ts
+			, end_ts
+			, closed_at
+			, CASE WHEN status IS NULL THEN '' ELSE status END status
+			, CASE WHEN product IS NULL THEN '' ELSE product END product
+			, CASE WHEN severity_level IS NULL THEN 0 ELSE severity_level END AS severity_level
+			, CASE WHEN post_mortem_url IS NULL THEN '' ELSE post_mortem_url END post_mortem_url
+			, COALESCE(postmortem_status, $1) postmortem_status
+			, CASE WHEN channel_name IS NULL THEN '' ELSE channel_name END AS channel_name
+			, CASE WHEN channel_id IS NULL THEN '' ELSE channel_id END AS channel_id
+			, CASE WHEN commander_id IS NULL THEN '' ELSE commander_id END commander_id
+		FROM incident
+		WHERE status IN ($2, $3)
+			AND end_ts IS NOT NULL
+			AND COALESCE(postmortem_status, $1) <> $4
+		ORDER BY end_ts`,
+		model.PostMortemNotStarted,
+		model.StatusResolved,
+		model.StatusClosed,
+		model.PostMortemPublished,
+	)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.Query"),
+			log.Reason(err.Error()),
+		)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var incidents []model.Incident
+	for rows.Next() {
+		var inc model.Incident
+		err = rows.Scan(
+			&inc.Id,
+			&inc.Title,
+			&inc.StartTimestamp,
+			&inc.EndTimestamp,
+			&inc.ClosedAt,
+			&inc.Status,
+			&inc.Product,
+			&inc.SeverityLevel,
+			&inc.PostMortemUrl,
+			&inc.PostMortemStatus,
+			&inc.ChannelName,
+			&inc.ChannelId,
+			&inc.CommanderId,
+		)
+		if err != nil {
+			r.logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("rows.Scan"),
+				log.Reason(err.Error()),
+			)
+			return nil, err
+		}
+		incidents = append(incidents, inc)
+	}
+
+	return incidents, nil
+}
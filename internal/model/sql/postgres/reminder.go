@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+
+	"hellper/internal/log"
+	"hellper/internal/model"
+)
+
+func (r *repository) InsertReminder(ctx context.Context, reminder *model.Reminder) error {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("incidentID", reminder.IncidentId),
+		log.NewValue("rule", reminder.Rule),
+	)
+
+	err := r.db.QueryRow(
+		`INSERT INTO incident_reminder
+			( incident_id
+			, rule)
+		VALUES ($1, $2)
+		RETURNING id, reminded_at`,
+		reminder.IncidentId,
+		reminder.Rule,
+	).Scan(&reminder.Id, &reminder.RemindedAt)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.QueryRow"),
+			log.Reason(err.Error()),
+			log.NewValue("incidentID", reminder.IncidentId),
+			log.NewValue("rule", reminder.Rule),
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (r *repository) ListReminders(ctx context.Context, incidentID int64) ([]model.Reminder, error) {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("incidentID", incidentID),
+	)
+
+	rows, err := r.db.Query(
+		`SELECT
+			id
+			, incident_id
+			, rule
+			, reminded_at
+		FROM incident_reminder
+		WHERE incident_id = $1
+		ORDER BY reminded_at`,
+		incidentID,
+	)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.Query"),
+			log.Reason(err.Error()),
+			log.NewValue("incidentID", incidentID),
+		)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reminders []model.Reminder
+	for rows.Next() {
+		var reminder model.Reminder
+		err = rows.Scan(
+			&reminder.Id,
+			&reminder.IncidentId,
+			&reminder.Rule,
+			&reminder.RemindedAt,
+		)
+		if err != nil {
+			r.logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("rows.Scan"),
+				log.Reason(err.Error()),
+				log.NewValue("incidentID", incidentID),
+			)
+			return nil, err
+		}
+		reminders = append(reminders, reminder)
+	}
+
+	return reminders, nil
+}
@@ -39,6 +39,7 @@ func incidentLogValues(inc *model.Incident) []log.Value {
 		log.NewValue("rootCause", inc.RootCause),
 		log.NewValue("customerImpact", inc.CustomerImpact),
 		log.NewValue("statusPageURL", inc.StatusPageUrl),
+		log.NewValue("warRoomURL", inc.WarRoomUrl),
 		log.NewValue("postMortemURL", inc.PostMortemUrl),
 		log.NewValue("team", inc.Team),
 		log.NewValue("product", inc.Product),
@@ -48,6 +49,7 @@ func incidentLogValues(inc *model.Incident) []log.Value {
 		log.NewValue("channelID", inc.ChannelId),
 		log.NewValue("commanderID", inc.CommanderId),
 		log.NewValue("commanderEmail", inc.CommanderEmail),
+		log.NewValue("incidentAuthor", inc.IncidentAuthor),
 	}
 }
 
@@ -71,6 +73,7 @@ func (r *repository) InsertIncident(ctx context.Context, inc *model.Incident) (i
 		, root_cause
 		, customer_impact
 		, status_page_url
+		, war_room_url
 		, post_mortem_url
 		, status
 		, product
@@ -78,8 +81,9 @@ func (r *repository) InsertIncident(ctx context.Context, inc *model.Incident) (i
 		, channel_name
 		, channel_id
 		, commander_id
-		, commander_email)
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+		, commander_email
+		, incident_author_id)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NULLIF($13, ''), $14, $15, $16, $17, $18, $19, $20, $21, $22)
 	RETURNING id`
 
 	id := int64(0)
@@ -98,6 +102,7 @@ func (r *repository) InsertIncident(ctx context.Context, inc *model.Incident) (i
 		inc.RootCause,
 		inc.CustomerImpact,
 		inc.StatusPageUrl,
+		inc.WarRoomUrl,
 		inc.PostMortemUrl,
 		inc.Status,
 		inc.Product,
@@ -105,7 +110,8 @@ func (r *repository) InsertIncident(ctx context.Context, inc *model.Incident) (i
 		inc.ChannelName,
 		inc.ChannelId,
 		inc.CommanderId,
-		inc.CommanderEmail)
+		inc.CommanderEmail,
+		inc.IncidentAuthor)
 
 	switch err := idResult.Scan(&id); err {
 	case nil:
@@ -164,6 +170,33 @@ func (r *repository) AddPostMortemUrl(ctx context.Context, channelName string, p
 	return err
 }
 
+func (r *repository) AddPagerEventUrl(ctx context.Context, channelID string, pagerEventURL string) error {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("channelID", channelID),
+		log.NewValue("pagerEventURL", pagerEventURL),
+	)
+
+	_, err := r.db.Exec(
+		`UPDATE incident SET pager_event_url = $1 WHERE channel_id = $2`,
+		pagerEventURL,
+		channelID,
+	)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.Exec"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", channelID),
+			log.NewValue("pagerEventURL", pagerEventURL),
+		)
+	}
+
+	return err
+}
+
 func (r *repository) GetIncident(ctx context.Context, channelID string) (inc model.Incident, err error) {
 	r.logger.Info(
 		ctx,
@@ -216,6 +249,8 @@ func (r *repository) GetIncident(ctx context.Context, channelID string) (inc mod
 		&inc.RootCause,
 		&inc.CustomerImpact,
 		&inc.StatusPageUrl,
+		&inc.PagerEventUrl,
+		&inc.WarRoomUrl,
 		&inc.PostMortemUrl,
 		&inc.Status,
 		&inc.Product,
@@ -224,6 +259,10 @@ func (r *repository) GetIncident(ctx context.Context, channelID string) (inc mod
 		&inc.ChannelId,
 		&inc.CommanderId,
 		&inc.CommanderEmail,
+		&inc.IncidentAuthor,
+		&inc.ClosedAt,
+		&inc.PostMortemPublishedAt,
+		&inc.PostMortemStatus,
 	)
 
 	r.logger.Info(
@@ -250,6 +289,8 @@ func GetIncidentByChannelID() string {
 		, root_cause
 		, customer_impact
 		, status_page_url
+		, CASE WHEN pager_event_url IS NULL THEN '' ELSE pager_event_url END pager_event_url
+		, CASE WHEN war_room_url IS NULL THEN '' ELSE war_room_url END war_room_url
 		, post_mortem_url
 		, status
 		, product
@@ -258,6 +299,10 @@ func GetIncidentByChannelID() string {
 		, CASE WHEN channel_id IS NULL THEN '' ELSE channel_id END AS channel_id
 		, CASE WHEN commander_id IS NULL THEN '' ELSE commander_id END commander_id
 		, CASE WHEN commander_email IS NULL THEN '' ELSE commander_email END commander_email
+		, CASE WHEN incident_author_id IS NULL THEN '' ELSE incident_author_id END incident_author_id
+		, closed_at
+		, postmortem_published_at
+		, CASE WHEN postmortem_status IS NULL THEN 'not_started' ELSE postmortem_status END postmortem_status
 	FROM incident
 	WHERE channel_id = $1
 	LIMIT 1`
@@ -408,7 +453,8 @@ func (r *repository) CloseIncident(ctx context.Context, inc *model.Incident) err
 			customer_impact = $4,
 			severity_level = $5,
 			status = $6,
-			responsibility = $7
+			responsibility = $7,
+			closed_at = now()
 		WHERE channel_id = $8`,
 		inc.RootCause,
 		inc.Functionality,
@@ -584,6 +630,8 @@ func (r *repository) ListActiveIncidents(ctx context.Context) ([]model.Incident,
 			&inc.RootCause,
 			&inc.CustomerImpact,
 			&inc.StatusPageUrl,
+			&inc.PagerEventUrl,
+			&inc.WarRoomUrl,
 			&inc.PostMortemUrl,
 			&inc.Status,
 			&inc.Product,
@@ -592,6 +640,10 @@ func (r *repository) ListActiveIncidents(ctx context.Context) ([]model.Incident,
 			&inc.ChannelId,
 			&inc.CommanderId,
 			&inc.CommanderEmail,
+			&inc.IncidentAuthor,
+			&inc.ClosedAt,
+			&inc.PostMortemPublishedAt,
+			&inc.PostMortemStatus,
 		)
 		if err != nil {
 			r.logger.Error(
@@ -631,6 +683,8 @@ func GetIncidentStatusFilterQuery() string {
 		, root_cause
 		, customer_impact
 		, status_page_url
+		, CASE WHEN pager_event_url IS NULL THEN '' ELSE pager_event_url END pager_event_url
+		, CASE WHEN war_room_url IS NULL THEN '' ELSE war_room_url END war_room_url
 		, post_mortem_url
 		, status
 		, product
@@ -639,6 +693,10 @@ func GetIncidentStatusFilterQuery() string {
 		, CASE WHEN channel_id IS NULL THEN '' ELSE channel_id END AS channel_id
 		, CASE WHEN commander_id IS NULL THEN '' ELSE commander_id END commander_id
 		, CASE WHEN commander_email IS NULL THEN '' ELSE commander_email END commander_email
+		, CASE WHEN incident_author_id IS NULL THEN '' ELSE incident_author_id END incident_author_id
+		, closed_at
+		, postmortem_published_at
+		, CASE WHEN postmortem_status IS NULL THEN 'not_started' ELSE postmortem_status END postmortem_status
 	FROM incident
 	WHERE status IN ($1, $2)
 	LIMIT 100`
@@ -655,7 +713,7 @@ func (r *repository) PauseNotifyIncident(ctx context.Context, inc *model.Inciden
 		`UPDATE incident SET
 			snoozed_until = $1
 		WHERE channel_id = $2`,
-		inc.SnoozedUntil.Time,
+		inc.SnoozedUntil,
 		inc.ChannelId,
 	)
 	if err != nil {
@@ -704,3 +762,38 @@ func (r *repository) PauseNotifyIncident(ctx context.Context, inc *model.Inciden
 
 	return nil
 }
+
+func (r *repository) InsertAuditLog(ctx context.Context, audit *model.AuditLog) error {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("audit", audit),
+	)
+
+	_, err := r.db.Exec(
+		`INSERT INTO audit_log
+			( channel_id
+			, user_id
+			, action
+			, allowed
+			, reason)
+		VALUES ($1, $2, $3, $4, $5)`,
+		audit.ChannelId,
+		audit.UserId,
+		audit.Action,
+		audit.Allowed,
+		audit.Reason,
+	)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.Exec"),
+			log.Reason(err.Error()),
+			log.NewValue("audit", audit),
+		)
+		return err
+	}
+
+	return nil
+}
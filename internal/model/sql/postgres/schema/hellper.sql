@@ -1,7 +1,7 @@
 -- public.incident definition
 -- Drop table
 -- DROP TABLE public.incident;
-CREATE TABLE public.incident (
+CREATE TABLE IF NOT EXISTS public.incident (
 	id serial NOT NULL,
 	title text NULL,
 	start_ts timestamptz NULL,
@@ -13,6 +13,8 @@ CREATE TABLE public.incident (
 	root_cause text NULL,
 	customer_impact int4 NULL,
 	status_page_url text NULL,
+	pager_event_url text NULL,
+	war_room_url text NULL,
 	post_mortem_url text NULL,
 	status varchar(50) NULL,
 	product varchar(50) NULL,
@@ -26,9 +28,213 @@ CREATE TABLE public.incident (
 	channel_id varchar(50) NULL,
   commander_id text NULL,
   commander_email text NULL,
+  incident_author_id text NULL,
+	closed_at timestamptz NULL,
+	postmortem_status varchar(50) NULL DEFAULT 'not_started',
+	postmortem_published_at timestamptz NULL,
 	CONSTRAINT firstkey PRIMARY KEY (id)
 );
 
+-- Columns added after the first release, databases created before them get
+-- them when this file is run again
+ALTER TABLE public.incident ADD COLUMN IF NOT EXISTS pager_event_url text NULL;
+ALTER TABLE public.incident ADD COLUMN IF NOT EXISTS war_room_url text NULL;
+ALTER TABLE public.incident ADD COLUMN IF NOT EXISTS incident_author_id text NULL;
+ALTER TABLE public.incident ADD COLUMN IF NOT EXISTS closed_at timestamptz NULL;
+ALTER TABLE public.incident ADD COLUMN IF NOT EXISTS postmortem_status varchar(50) NULL DEFAULT 'not_started';
+ALTER TABLE public.incident ADD COLUMN IF NOT EXISTS postmortem_published_at timestamptz NULL;
+
+-- public.audit_log definition
+-- Drop table
+-- DROP TABLE public.audit_log;
+CREATE TABLE IF NOT EXISTS public.audit_log (
+	id serial NOT NULL,
+	channel_id varchar(50) NULL,
+	user_id varchar(50) NOT NULL,
+	"action" varchar(50) NOT NULL,
+	allowed boolean NOT NULL,
+	reason text NULL,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	CONSTRAINT audit_log_pkey PRIMARY KEY (id)
+);
+
+-- public.incident_role definition
+-- Drop table
+-- DROP TABLE public.incident_role;
+CREATE TABLE IF NOT EXISTS public.incident_role (
+	id serial NOT NULL,
+	incident_id int4 NOT NULL,
+	"role" varchar(50) NOT NULL,
+	user_id varchar(50) NOT NULL,
+	assigned_by varchar(50) NULL,
+	assigned_at timestamptz NOT NULL DEFAULT now(),
+	released_at timestamptz NULL,
+	CONSTRAINT incident_role_pkey PRIMARY KEY (id),
+	CONSTRAINT incident_role_incident_fkey FOREIGN KEY (incident_id) REFERENCES public.incident(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS incident_role_active_idx ON public.incident_role (incident_id, "role") WHERE released_at IS NULL;
+
+-- public.incident_escalation definition
+-- Drop table
+-- DROP TABLE public.incident_escalation;
+CREATE TABLE IF NOT EXISTS public.incident_escalation (
+	id serial NOT NULL,
+	incident_id int4 NOT NULL,
+	step int4 NOT NULL,
+	escalated_at timestamptz NOT NULL DEFAULT now(),
+	acknowledged_at timestamptz NULL,
+	acknowledged_by varchar(50) NULL,
+	CONSTRAINT incident_escalation_pkey PRIMARY KEY (id),
+	CONSTRAINT incident_escalation_incident_fkey FOREIGN KEY (incident_id) REFERENCES public.incident(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS incident_escalation_incident_idx ON public.incident_escalation (incident_id);
+
+-- public.incident_reminder definition
+-- Drop table
+-- DROP TABLE public.incident_reminder;
+CREATE TABLE IF NOT EXISTS public.incident_reminder (
+	id serial NOT NULL,
+	incident_id int4 NOT NULL,
+	rule varchar(100) NOT NULL,
+	reminded_at timestamptz NOT NULL DEFAULT now(),
+	CONSTRAINT incident_reminder_pkey PRIMARY KEY (id),
+	CONSTRAINT incident_reminder_incident_fkey FOREIGN KEY (incident_id) REFERENCES public.incident(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS incident_reminder_incident_idx ON public.incident_reminder (incident_id, rule);
+
+-- public.job_run definition
+-- Drop table
+-- DROP TABLE public.job_run;
+CREATE TABLE IF NOT EXISTS public.job_run (
+	"name" varchar(100) NOT NULL,
+	last_run_at timestamptz NOT NULL,
+	CONSTRAINT job_run_pkey PRIMARY KEY ("name")
+);
+
+-- public.webhook_delivery definition
+-- Drop table
+-- DROP TABLE public.webhook_delivery;
+CREATE TABLE IF NOT EXISTS public.webhook_delivery (
+	id serial NOT NULL,
+	event_id varchar(32) NOT NULL,
+	"event" varchar(50) NOT NULL,
+	incident_id int4 NOT NULL,
+	subscription varchar(100) NOT NULL,
+	url text NOT NULL,
+	attempt int4 NOT NULL,
+	status_code int4 NOT NULL DEFAULT 0,
+	error text NULL,
+	delivered bool NOT NULL DEFAULT false,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	CONSTRAINT webhook_delivery_pkey PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS webhook_delivery_incident_idx ON public.webhook_delivery (incident_id);
+CREATE INDEX IF NOT EXISTS webhook_delivery_event_idx ON public.webhook_delivery (event_id);
+
+-- public.incident_sla_breach definition
+-- Drop table
+-- DROP TABLE public.incident_sla_breach;
+CREATE TABLE IF NOT EXISTS public.incident_sla_breach (
+	id serial NOT NULL,
+	incident_id int4 NOT NULL,
+	metric varchar(50) NOT NULL,
+	target_seconds int8 NOT NULL,
+	breached_at timestamptz NOT NULL DEFAULT now(),
+	CONSTRAINT incident_sla_breach_pkey PRIMARY KEY (id),
+	CONSTRAINT incident_sla_breach_incident_fkey FOREIGN KEY (incident_id) REFERENCES public.incident(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS incident_sla_breach_metric_idx ON public.incident_sla_breach (incident_id, metric);
+
+-- public.incident_snooze definition
+-- Drop table
+-- DROP TABLE public.incident_snooze;
+CREATE TABLE IF NOT EXISTS public.incident_snooze (
+	id serial NOT NULL,
+	incident_id int4 NOT NULL,
+	snoozed_by varchar(50) NOT NULL,
+	reason text NULL,
+	snoozed_at timestamptz NOT NULL DEFAULT now(),
+	snoozed_until timestamptz NOT NULL,
+	resumed_at timestamptz NULL,
+	resumed_by varchar(50) NULL,
+	CONSTRAINT incident_snooze_pkey PRIMARY KEY (id),
+	CONSTRAINT incident_snooze_incident_fkey FOREIGN KEY (incident_id) REFERENCES public.incident(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS incident_snooze_incident_idx ON public.incident_snooze (incident_id);
+
+-- public.statuspage_update definition
+-- Drop table
+-- DROP TABLE public.statuspage_update;
+CREATE TABLE IF NOT EXISTS public.statuspage_update (
+	id serial NOT NULL,
+	incident_id int4 NOT NULL,
+	status varchar(50) NOT NULL,
+	body text NOT NULL,
+	state varchar(50) NOT NULL DEFAULT 'pending',
+	drafted_by varchar(50) NULL,
+	drafted_at timestamptz NOT NULL DEFAULT now(),
+	reviewed_by varchar(50) NULL,
+	reviewed_at timestamptz NULL,
+	page_incident_id varchar(100) NULL,
+	page_url text NULL,
+	error text NULL,
+	CONSTRAINT statuspage_update_pkey PRIMARY KEY (id),
+	CONSTRAINT statuspage_update_incident_fkey FOREIGN KEY (incident_id) REFERENCES public.incident(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS statuspage_update_incident_idx ON public.statuspage_update (incident_id);
+
+-- public.alert definition
+-- Drop table
+-- DROP TABLE public.alert;
+CREATE TABLE IF NOT EXISTS public.alert (
+	id serial NOT NULL,
+	fingerprint varchar(255) NOT NULL,
+	incident_id int4 NOT NULL,
+	channel_id varchar(50) NOT NULL,
+	"source" varchar(50) NOT NULL,
+	"name" text NULL,
+	status varchar(50) NOT NULL,
+	starts_at timestamptz NULL,
+	last_seen_at timestamptz NOT NULL DEFAULT now(),
+	resolved_at timestamptz NULL,
+	CONSTRAINT alert_pkey PRIMARY KEY (id),
+	CONSTRAINT alert_incident_fkey FOREIGN KEY (incident_id) REFERENCES public.incident(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS alert_fingerprint_idx ON public.alert (fingerprint);
+CREATE INDEX IF NOT EXISTS alert_incident_idx ON public.alert (incident_id);
+
+-- public.action_item definition
+-- Drop table
+-- DROP TABLE public.action_item;
+CREATE TABLE IF NOT EXISTS public.action_item (
+	id serial NOT NULL,
+	incident_id int4 NOT NULL,
+	channel_id varchar(50) NOT NULL,
+	title text NOT NULL,
+	owner_id varchar(50) NULL,
+	due_date date NULL,
+	status varchar(50) NOT NULL DEFAULT 'open',
+	priority varchar(50) NOT NULL DEFAULT 'medium',
+	issue_key varchar(100) NULL,
+	issue_url text NULL,
+	created_by varchar(50) NOT NULL,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	completed_at timestamptz NULL,
+	CONSTRAINT action_item_pkey PRIMARY KEY (id),
+	CONSTRAINT action_item_incident_fkey FOREIGN KEY (incident_id) REFERENCES public.incident(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS action_item_incident_idx ON public.action_item (incident_id);
+
+-- public.google_token definition
+-- Drop table
+-- DROP TABLE public.google_token;
+CREATE TABLE IF NOT EXISTS public.google_token (
+	"key" text NOT NULL,
+	token text NOT NULL,
+	updated_at timestamptz NOT NULL DEFAULT now(),
+	CONSTRAINT google_token_pkey PRIMARY KEY ("key")
+);
+
 -- View table
 -- DROP VIEW public.metrics;
 CREATE OR REPLACE VIEW public.metrics
@@ -60,7 +266,14 @@ CREATE OR REPLACE VIEW public.metrics
     COALESCE(incident.customer_impact, 0) AS customer_impact,
     COALESCE(date_part('epoch'::text, incident.identification_ts - incident.start_ts), 0::double precision) AS acknowledgetime,
     COALESCE(date_part('epoch'::text, incident.end_ts - incident.identification_ts), 0::double precision) AS solutiontime,
-    COALESCE(date_part('epoch'::text, incident.end_ts - incident.start_ts), 0::double precision) AS downtime
+    COALESCE(date_part('epoch'::text, incident.end_ts - incident.start_ts), 0::double precision) AS downtime,
+    to_char(incident.closed_at, 'YYYY-MM-DD HH24:MI:SS'::text) AS closed_at,
+    to_char(incident.postmortem_published_at, 'YYYY-MM-DD HH24:MI:SS'::text) AS postmortem_published_at,
+    COALESCE(incident.postmortem_status, 'not_started') AS postmortem_status,
+    EXISTS (SELECT 1 FROM incident_sla_breach WHERE incident_sla_breach.incident_id = incident.id AND incident_sla_breach.metric = 'acknowledge') AS acknowledge_sla_breached,
+    EXISTS (SELECT 1 FROM incident_sla_breach WHERE incident_sla_breach.incident_id = incident.id AND incident_sla_breach.metric = 'resolve') AS resolve_sla_breached,
+    EXISTS (SELECT 1 FROM incident_sla_breach WHERE incident_sla_breach.incident_id = incident.id AND incident_sla_breach.metric = 'postmortem') AS postmortem_sla_breached,
+    EXISTS (SELECT 1 FROM incident_sla_breach WHERE incident_sla_breach.incident_id = incident.id AND incident_sla_breach.metric = 'close') AS close_sla_breached
    FROM incident
   WHERE incident.start_ts IS NOT NULL AND incident.end_ts IS NOT NULL AND incident.identification_ts IS NOT NULL AND incident.end_ts::date >= '2020-01-01'::date
   ORDER BY (incident.end_ts::date);
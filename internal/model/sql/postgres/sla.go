@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+
+	"hellper/internal/log"
+	"hellper/internal/model"
+)
+
+// InsertSLABreach records the breach once per incident and metric, telling whether it was not recorded yet
+func (r *repository) InsertSLABreach(ctx context.Context, breach *model.SLABreach) (bool, error) {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("incidentID", breach.IncidentId),
+		log.NewValue("metric", breach.Metric),
+	)
+
+	result, err := r.db.Exec(
+		`INSERT INTO incident_sla_breach
+			( incident_id
+			, metric
+			, target_seconds)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (incident_id, metric) DO NOTHING`,
+		breach.IncidentId,
+		breach.Metric,
+		int64(breach.Target.Seconds()),
+	)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.Exec"),
+			log.Reason(err.Error()),
+			log.NewValue("incidentID", breach.IncidentId),
+			log.NewValue("metric", breach.Metric),
+		)
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("result.RowsAffected"),
+			log.Reason(err.Error()),
+			log.NewValue("incidentID", breach.IncidentId),
+			log.NewValue("metric", breach.Metric),
+		)
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
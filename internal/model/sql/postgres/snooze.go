@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+
+	"hellper/internal/log"
+	"hellper/internal/model"
+)
+
+func (r *repository) InsertSnooze(ctx context.Context, snooze *model.Snooze) error {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("incidentID", snooze.IncidentId),
+		log.NewValue("snoozedBy", snooze.SnoozedBy),
+		log.NewValue("snoozedUntil", snooze.SnoozedUntil),
+	)
+
+	err := r.db.QueryRow(
+		`INSERT INTO incident_snooze
+			( incident_id
+			, snoozed_by
+			, reason
+			, snoozed_until)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, snoozed_at`,
+		snooze.IncidentId,
+		snooze.SnoozedBy,
+		snooze.Reason,
+		snooze.SnoozedUntil,
+	).Scan(&snooze.Id, &snooze.SnoozedAt)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.QueryRow"),
+			log.Reason(err.Error()),
+			log.NewValue("incidentID", snooze.IncidentId),
+			log.NewValue("snoozedBy", snooze.SnoozedBy),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// ResumeSnoozes ends the snoozes of the incident still running, the expired ones are kept as they are
+func (r *repository) ResumeSnoozes(ctx context.Context, incidentID int64, userID string) error {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("incidentID", incidentID),
+		log.NewValue("userID", userID),
+	)
+
+	_, err := r.db.Exec(
+		`UPDATE incident_snooze SET
+			resumed_at = now()
+			, resumed_by = $2
+		WHERE incident_id = $1
+			AND resumed_at IS NULL
+			AND snoozed_until > now()`,
+		incidentID,
+		userID,
+	)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.Exec"),
+			log.Reason(err.Error()),
+			log.NewValue("incidentID", incidentID),
+			log.NewValue("userID", userID),
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (r *repository) ListSnoozes(ctx context.Context, incidentID int64) ([]model.Snooze, error) {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("incidentID", incidentID),
+	)
+
+	rows, err := r.db.Query(
+		`SELECT
+			id
+			, incident_id
+			, snoozed_by
+			, CASE WHEN reason IS NULL THEN '' ELSE reason END reason
+			, snoozed_at
+			, snoozed_until
+			, resumed_at
+			, CASE WHEN resumed_by IS NULL THEN '' ELSE resumed_by END resumed_by
+		FROM incident_snooze
+		WHERE incident_id = $1
+		ORDER BY snoozed_at`,
+		incidentID,
+	)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.Query"),
+			log.Reason(err.Error()),
+			log.NewValue("incidentID", incidentID),
+		)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snoozes []model.Snooze
+	for rows.Next() {
+		var snooze model.Snooze
+		err = rows.Scan(
+			&snooze.Id,
+			&snooze.IncidentId,
+			&snooze.SnoozedBy,
+			&snooze.Reason,
+			&snooze.SnoozedAt,
+			&snooze.SnoozedUntil,
+			&snooze.ResumedAt,
+			&snooze.ResumedBy,
+		)
+		if err != nil {
+			r.logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("rows.Scan"),
+				log.Reason(err.Error()),
+				log.NewValue("incidentID", incidentID),
+			)
+			return nil, err
+		}
+		snoozes = append(snoozes, snooze)
+	}
+
+	return snoozes, nil
+}
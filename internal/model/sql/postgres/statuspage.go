@@ -0,0 +1,259 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/model/sql"
+)
+
+func (r *repository) InsertStatusPageUpdate(ctx context.Context, update *model.StatusPageUpdate) error {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("incidentID", update.IncidentId),
+		log.NewValue("status", update.Status),
+		log.NewValue("state", update.State),
+	)
+
+	err := r.db.QueryRow(
+		`INSERT INTO statuspage_update
+			( incident_id
+			, status
+			, body
+			, state
+			, drafted_by
+			, reviewed_by
+			, reviewed_at
+			, page_incident_id
+			, page_url
+			, error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, drafted_at`,
+		update.IncidentId,
+		update.Status,
+		update.Body,
+		update.State,
+		update.DraftedBy,
+		update.ReviewedBy,
+		update.ReviewedAt,
+		update.PageIncidentId,
+		update.PageURL,
+		update.Error,
+	).Scan(&update.Id, &update.DraftedAt)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.QueryRow"),
+			log.Reason(err.Error()),
+			log.NewValue("incidentID", update.IncidentId),
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (r *repository) GetStatusPageUpdate(ctx context.Context, id int64) (model.StatusPageUpdate, error) {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("id", id),
+	)
+
+	rows, err := r.db.Query(
+		statusPageUpdateSelect()+`
+		WHERE id = $1`,
+		id,
+	)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.Query"),
+			log.Reason(err.Error()),
+			log.NewValue("id", id),
+		)
+		return model.StatusPageUpdate{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		err = errors.New("status page update " + strconv.FormatInt(id, 10) + " not found")
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("rows.Next"),
+			log.Reason(err.Error()),
+			log.NewValue("id", id),
+		)
+		return model.StatusPageUpdate{}, err
+	}
+
+	update, err := scanStatusPageUpdate(rows)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("rows.Scan"),
+			log.Reason(err.Error()),
+			log.NewValue("id", id),
+		)
+		return model.StatusPageUpdate{}, err
+	}
+
+	return update, nil
+}
+
+// ReviewStatusPageUpdate saves the review of a pending update, it fails when the update was already reviewed
+// reviewedFrom is the state an update must be in to move to each review state, the publication starts by claiming the
+// pending draft so a single review calls the status page
+var reviewedFrom = map[string]string{
+	model.StatusPageUpdatePublishing: model.StatusPageUpdatePending,
+	model.StatusPageUpdateDiscarded:  model.StatusPageUpdatePending,
+	model.StatusPageUpdatePublished:  model.StatusPageUpdatePublishing,
+	model.StatusPageUpdateFailed:     model.StatusPageUpdatePublishing,
+}
+
+// ReviewStatusPageUpdate moves the update to its review state, ErrStatusPageUpdateReviewed when another review moved it first
+func (r *repository) ReviewStatusPageUpdate(ctx context.Context, update *model.StatusPageUpdate) error {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("id", update.Id),
+		log.NewValue("state", update.State),
+		log.NewValue("reviewedBy", update.ReviewedBy),
+	)
+
+	result, err := r.db.Exec(
+		`UPDATE statuspage_update SET
+			state = $2
+			, reviewed_by = $3
+			, reviewed_at = now()
+			, page_incident_id = $4
+			, page_url = $5
+			, error = $6
+		WHERE id = $1
+			AND state = $7`,
+		update.Id,
+		update.State,
+		update.ReviewedBy,
+		update.PageIncidentId,
+		update.PageURL,
+		update.Error,
+		reviewedFrom[update.State],
+	)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.Exec"),
+			log.Reason(err.Error()),
+			log.NewValue("id", update.Id),
+		)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		err = fmt.Errorf("status page update %d: %w", update.Id, model.ErrStatusPageUpdateReviewed)
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("result.RowsAffected"),
+			log.Reason(err.Error()),
+			log.NewValue("id", update.Id),
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (r *repository) ListStatusPageUpdates(ctx context.Context, incidentID int64) ([]model.StatusPageUpdate, error) {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("incidentID", incidentID),
+	)
+
+	rows, err := r.db.Query(
+		statusPageUpdateSelect()+`
+		WHERE incident_id = $1
+		ORDER BY drafted_at, id`,
+		incidentID,
+	)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.Query"),
+			log.Reason(err.Error()),
+			log.NewValue("incidentID", incidentID),
+		)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var updates []model.StatusPageUpdate
+	for rows.Next() {
+		update, err := scanStatusPageUpdate(rows)
+		if err != nil {
+			r.logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("rows.Scan"),
+				log.Reason(err.Error()),
+				log.NewValue("incidentID", incidentID),
+			)
+			return nil, err
+		}
+		updates = append(updates, update)
+	}
+
+	return updates, nil
+}
+
+func statusPageUpdateSelect() string {
+	return `SELECT
+			id
+			, incident_id
+			, status
+			, body
+			, state
+			, CASE WHEN drafted_by IS NULL THEN '' ELSE drafted_by END drafted_by
+			, drafted_at
+			, CASE WHEN reviewed_by IS NULL THEN '' ELSE reviewed_by END reviewed_by
+			, reviewed_at
+			, CASE WHEN page_incident_id IS NULL THEN '' ELSE page_incident_id END page_incident_id
+			, CASE WHEN page_url IS NULL THEN '' ELSE page_url END page_url
+			, CASE WHEN error IS NULL THEN '' ELSE error END error
+		FROM statuspage_update`
+}
+
+func scanStatusPageUpdate(rows sql.Rows) (model.StatusPageUpdate, error) {
+	var update model.StatusPageUpdate
+	err := rows.Scan(
+		&update.Id,
+		&update.IncidentId,
+		&update.Status,
+		&update.Body,
+		&update.State,
+		&update.DraftedBy,
+		&update.DraftedAt,
+		&update.ReviewedBy,
+		&update.ReviewedAt,
+		&update.PageIncidentId,
+		&update.PageURL,
+		&update.Error,
+	)
+	return update, err
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+
+	"hellper/internal/log"
+	"hellper/internal/model"
+)
+
+func (r *repository) InsertWebhookDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	r.logger.Info(
+		ctx,
+		log.Trace(),
+		log.NewValue("eventID", delivery.EventId),
+		log.NewValue("event", delivery.Event),
+		log.NewValue("subscription", delivery.Subscription),
+		log.NewValue("attempt", delivery.Attempt),
+	)
+
+	err := r.db.QueryRow(
+		`INSERT INTO webhook_delivery
+			( event_id
+			, event
+			, incident_id
+			, subscription
+			, url
+			, attempt
+			, status_code
+			, error
+			, delivered)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at`,
+		delivery.EventId,
+		delivery.Event,
+		delivery.IncidentId,
+		delivery.Subscription,
+		delivery.URL,
+		delivery.Attempt,
+		delivery.StatusCode,
+		delivery.Error,
+		delivery.Delivered,
+	).Scan(&delivery.Id, &delivery.CreatedAt)
+	if err != nil {
+		r.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("r.db.QueryRow"),
+			log.Reason(err.Error()),
+			log.NewValue("eventID", delivery.EventId),
+			log.NewValue("subscription", delivery.Subscription),
+		)
+		return err
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package sql
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 )
@@ -13,10 +14,27 @@ type DB interface {
 	Query(string, ...interface{}) (Rows, error)
 	QueryRow(string, ...interface{}) Row
 	Exec(string, ...interface{}) (Result, error)
+	Conn(context.Context) (Conn, error)
+	BeginTx(context.Context) (Tx, error)
 	Ping() error
 	Close() error
 }
 
+// Conn is a single database session, needed by session scoped features such as advisory locks
+type Conn interface {
+	QueryRowContext(context.Context, string, ...interface{}) Row
+	ExecContext(context.Context, string, ...interface{}) (Result, error)
+	Close() error
+}
+
+// Tx is a database transaction, its statements are applied together on Commit or dropped on Rollback
+type Tx interface {
+	QueryRowContext(context.Context, string, ...interface{}) Row
+	ExecContext(context.Context, string, ...interface{}) (Result, error)
+	Commit() error
+	Rollback() error
+}
+
 type Row interface {
 	Scan(...interface{}) error
 }
@@ -52,6 +70,46 @@ func (db *db) Exec(sql string, arguments ...interface{}) (Result, error) {
 	return db.DB.Exec(sql, arguments...)
 }
 
+func (db *db) Conn(ctx context.Context) (Conn, error) {
+	conn, err := db.DB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlConn{Conn: conn}, nil
+}
+
+type sqlConn struct {
+	*sql.Conn
+}
+
+func (conn *sqlConn) QueryRowContext(ctx context.Context, sql string, arguments ...interface{}) Row {
+	return conn.Conn.QueryRowContext(ctx, sql, arguments...)
+}
+
+func (conn *sqlConn) ExecContext(ctx context.Context, sql string, arguments ...interface{}) (Result, error) {
+	return conn.Conn.ExecContext(ctx, sql, arguments...)
+}
+
+func (db *db) BeginTx(ctx context.Context) (Tx, error) {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlTx{Tx: tx}, nil
+}
+
+type sqlTx struct {
+	*sql.Tx
+}
+
+func (tx *sqlTx) QueryRowContext(ctx context.Context, sql string, arguments ...interface{}) Row {
+	return tx.Tx.QueryRowContext(ctx, sql, arguments...)
+}
+
+func (tx *sqlTx) ExecContext(ctx context.Context, sql string, arguments ...interface{}) (Result, error) {
+	return tx.Tx.ExecContext(ctx, sql, arguments...)
+}
+
 func newSQLDB(driver, dsn string) (*sql.DB, error) {
 	return sql.Open(driver, dsn)
 }
@@ -1,6 +1,7 @@
 package sql
 
 import (
+	"context"
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
@@ -69,6 +70,28 @@ func (mock *DBMock) Exec(query string, params ...interface{}) (Result, error) {
 	return nil, args.Error(1)
 }
 
+func (mock *DBMock) Conn(ctx context.Context) (Conn, error) {
+	var (
+		args   = mock.Called(ctx)
+		result = args.Get(0)
+	)
+	if result != nil {
+		return result.(Conn), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *DBMock) BeginTx(ctx context.Context) (Tx, error) {
+	var (
+		args   = mock.Called(ctx)
+		result = args.Get(0)
+	)
+	if result != nil {
+		return result.(Tx), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (mock *DBMock) Ping() error {
 	args := mock.Called()
 	return args.Error(0)
@@ -79,6 +102,81 @@ func (mock *DBMock) Close() error {
 	return args.Error(0)
 }
 
+func NewConnMock() *ConnMock {
+	return new(ConnMock)
+}
+
+type ConnMock struct {
+	mock.Mock
+}
+
+func (mock *ConnMock) QueryRowContext(ctx context.Context, sql string, params ...interface{}) Row {
+	var (
+		args   = mock.Called(ctx, sql, params)
+		result = args.Get(0)
+	)
+	if result != nil {
+		return result.(Row)
+	}
+	return nil
+}
+
+func (mock *ConnMock) ExecContext(ctx context.Context, query string, params ...interface{}) (Result, error) {
+	var (
+		args   = mock.Called(ctx, query, params)
+		result = args.Get(0)
+	)
+	if result != nil {
+		return result.(Result), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *ConnMock) Close() error {
+	args := mock.Called()
+	return args.Error(0)
+}
+
+func NewTxMock() *TxMock {
+	return new(TxMock)
+}
+
+type TxMock struct {
+	mock.Mock
+}
+
+func (mock *TxMock) QueryRowContext(ctx context.Context, sql string, params ...interface{}) Row {
+	var (
+		args   = mock.Called(ctx, sql, params)
+		result = args.Get(0)
+	)
+	if result != nil {
+		return result.(Row)
+	}
+	return nil
+}
+
+func (mock *TxMock) ExecContext(ctx context.Context, query string, params ...interface{}) (Result, error) {
+	var (
+		args   = mock.Called(ctx, query, params)
+		result = args.Get(0)
+	)
+	if result != nil {
+		return result.(Result), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *TxMock) Commit() error {
+	args := mock.Called()
+	return args.Error(0)
+}
+
+func (mock *TxMock) Rollback() error {
+	args := mock.Called()
+	return args.Error(0)
+}
+
 func NewRowMock() *RowMock {
 	return new(RowMock)
 }
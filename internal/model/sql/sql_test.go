@@ -1,6 +1,7 @@
 package sql
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"errors"
@@ -356,3 +357,58 @@ func TestExec(test *testing.T) {
 		)
 	}
 }
+
+func TestBeginTx(test *testing.T) {
+	scenarios := []struct {
+		name   string
+		commit bool
+		err    error
+	}{
+		{
+			name:   "Commits the statements",
+			commit: true,
+		},
+		{
+			name: "Rolls back the statements when a statement fails",
+			err:  errors.New("err_mockexec"),
+		},
+	}
+
+	for index, scenario := range scenarios {
+		test.Run(
+			fmt.Sprintf("[%d]-%s", index, scenario.name),
+			func(t *testing.T) {
+				sqlDB, mock, err := sqlmock.New()
+				require.Nil(t, err, "sqlmock error")
+				defer sqlDB.Close()
+
+				mock.ExpectBegin()
+				exec := mock.ExpectExec("update mock")
+				if scenario.err == nil {
+					exec.WillReturnResult(sqlmock.NewResult(0, 1))
+					mock.ExpectCommit()
+				} else {
+					exec.WillReturnError(scenario.err)
+					mock.ExpectRollback()
+				}
+
+				db, err := NewDB(sqlDB)
+				require.Nil(t, err, "newDB error")
+
+				ctx := context.Background()
+				tx, err := db.BeginTx(ctx)
+				require.Nil(t, err, "begin error")
+
+				_, err = tx.ExecContext(ctx, "update mock")
+				require.Equal(t, scenario.err, err, "exec error")
+				if scenario.commit {
+					require.Nil(t, tx.Commit(), "commit error")
+					require.Equal(t, sql.ErrTxDone, tx.Rollback(), "rollback after commit")
+				} else {
+					require.Nil(t, tx.Rollback(), "rollback error")
+				}
+				require.Nil(t, mock.ExpectationsWereMet(), "sqlmock invalid expectations")
+			},
+		)
+	}
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// States of a status page update, drafts are pending until the comms lead publishes or discards them. A draft being
+// published is claimed as publishing until the status page answers
+const (
+	StatusPageUpdatePending    = "pending"
+	StatusPageUpdatePublishing = "publishing"
+	StatusPageUpdatePublished  = "published"
+	StatusPageUpdateDiscarded  = "discarded"
+	StatusPageUpdateFailed     = "failed"
+)
+
+// ErrStatusPageUpdateReviewed is returned when the update left the state its review starts from
+var ErrStatusPageUpdateReviewed = errors.New("the status page update was already reviewed")
+
+// StatusPageUpdate is an update of the public incident of an incident on the status page
+type StatusPageUpdate struct {
+	Id             int64      `db:"id,omitempty"`
+	IncidentId     int64      `db:"incident_id,omitempty"`
+	Status         string     `db:"status,omitempty"`
+	Body           string     `db:"body,omitempty"`
+	State          string     `db:"state,omitempty"`
+	DraftedBy      string     `db:"drafted_by,omitempty"`
+	DraftedAt      *time.Time `db:"drafted_at,omitempty"`
+	ReviewedBy     string     `db:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time `db:"reviewed_at,omitempty"`
+	PageIncidentId string     `db:"page_incident_id,omitempty"`
+	PageURL        string     `db:"page_url,omitempty"`
+	Error          string     `db:"error,omitempty"`
+}
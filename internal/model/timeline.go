@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// TimelineEntry is a message posted on the incident channel, with the replies of its thread
+type TimelineEntry struct {
+	Timestamp time.Time       `json:"timestamp"`
+	UserID    string          `json:"user_id,omitempty"`
+	UserName  string          `json:"user_name"`
+	Text      string          `json:"text"`
+	Pinned    bool            `json:"pinned"`
+	Replies   []TimelineEntry `json:"replies,omitempty"`
+}
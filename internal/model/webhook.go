@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// WebhookDelivery is an attempt to deliver an incident event to a webhook subscription
+type WebhookDelivery struct {
+	Id           int64      `db:"id,omitempty"`
+	EventId      string     `db:"event_id,omitempty"`
+	Event        string     `db:"event,omitempty"`
+	IncidentId   int64      `db:"incident_id,omitempty"`
+	Subscription string     `db:"subscription,omitempty"`
+	URL          string     `db:"url,omitempty"`
+	Attempt      int        `db:"attempt,omitempty"`
+	StatusCode   int        `db:"status_code,omitempty"`
+	Error        string     `db:"error,omitempty"`
+	Delivered    bool       `db:"delivered,omitempty"`
+	CreatedAt    *time.Time `db:"created_at,omitempty"`
+}
@@ -2,54 +2,76 @@ package notify
 
 import (
 	"context"
-	"errors"
 	"hellper/internal/log"
 	"hellper/internal/model"
 	"hellper/internal/reminder"
+	"time"
 )
 
-func channelsNotify(ctx context.Context) {
-
-	var msg string
-
-	logger.Info(ctx, log.Trace(), log.Action("running"))
-
-	if arg.statusFlag == "" {
-		logger.Error(ctx, log.Trace(), log.NewValue("error", errors.New("Must have a status")))
-		return
+func (n notifier) channelsNotify(ctx context.Context) []Record {
+	incidents, err := n.repository.ListActiveIncidents(ctx)
+	if err != nil {
+		return []Record{n.record(ctx, Record{}, err)}
 	}
 
-	if arg.toFlag != "" {
-		logger.Error(ctx, log.Trace(), log.NewValue("error", errors.New("Forbidden to use the --to option with --type=channels")))
-		return
+	var records []Record
+	for _, incident := range incidents {
+		if n.arg.statusFlag == incident.Status || n.arg.statusFlag == "all" {
+			records = append(records, n.notifyChannels(ctx, incident))
+		}
 	}
+	return records
+}
 
-	incidents, err := repository.ListActiveIncidents(ctx)
-	if err != nil {
-		logger.Error(ctx, log.Trace(), log.NewValue("error", err))
+func (n notifier) notifyChannels(ctx context.Context, incident model.Incident) Record {
+	severity := incident.SeverityLevel
+	record := Record{
+		IncidentID: incident.Id,
+		ChannelID:  incident.ChannelId,
+		Status:     incident.Status,
+		Severity:   &severity,
 	}
 
-	for _, incident := range incidents {
+	reminder.AnnounceSnoozeExpiry(ctx, n.client, n.logger, n.repository, incident, time.Now())
 
-		if arg.msgFlag != "" {
-			msg = arg.msgFlag
-		} else {
-			msg = statusNotify(incident)
-		}
+	decision := reminder.Evaluate(ctx, n.client, n.logger, n.repository, n.policy, incident)
+	record.Notify = decision.Notify
+	record.Reason = decision.Reason
+	record.Step = decision.Step
+	if !decision.Notify {
+		n.logger.Info(ctx, log.Trace(), log.Action("do_not_notify"), log.Reason(decision.Reason), log.NewValue("channelID", incident.ChannelId))
+		return n.record(ctx, record, nil)
+	}
 
-		if arg.statusFlag == incident.Status || arg.statusFlag == "all" {
-			notifyChannels(ctx, incident, msg)
+	msg := n.arg.msgFlag
+	if msg == "" {
+		var err error
+		msg, err = decision.Render(incident)
+		if err != nil {
+			return n.record(ctx, record, err)
 		}
 	}
 
+	if n.arg.roleFlag != "" {
+		msg = n.mentionRoleHolder(ctx, incident, n.arg.roleFlag, msg)
+	}
+
+	n.logger.Info(ctx, log.Trace(), log.Action("notify_job"), log.Reason(decision.Reason), log.NewValue("incident", incident))
+	err := reminder.Deliver(ctx, n.client, n.logger, n.repository, incident, decision, msg)
+	return n.record(ctx, record, err)
 }
 
-func notifyChannels(ctx context.Context, incident model.Incident, msg string) {
-	if reminder.CanSendNotify(ctx, client, logger, repository, incident) {
-		logger.Info(ctx, log.Trace(), log.Action("notify_job"), log.NewValue("incident", incident))
-		err := send(incident.ChannelId, msg)
-		if err != nil {
-			logger.Error(ctx, log.Trace(), log.NewValue("error", err))
-		}
+// mentionRoleHolder addresses the reminder to the user holding the role, or asks for someone to take the role
+func (n notifier) mentionRoleHolder(ctx context.Context, incident model.Incident, role string, msg string) string {
+	roles, err := n.repository.ListIncidentRoles(ctx, incident.Id)
+	if err != nil {
+		n.logger.Error(ctx, log.Trace(), log.NewValue("error", err), log.NewValue("channelID", incident.ChannelId))
+		return msg
+	}
+
+	holder := model.RoleHolder(roles, role)
+	if holder == "" {
+		return "Nobody is the " + model.RoleName(role) + " of this incident, assign it with `/hellper_role assign " + role + " @user`. " + msg
 	}
+	return "<@" + holder + "> " + msg
 }
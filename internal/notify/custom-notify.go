@@ -2,17 +2,9 @@ package notify
 
 import (
 	"context"
-	"hellper/internal/log"
 )
 
-func customNotify(ctx context.Context) {
-	logger.Info(ctx, log.Trace(), log.Action("running"))
-
-	err := send(arg.toFlag, arg.msgFlag)
-	if err != nil {
-		logger.Error(ctx, log.Trace(), log.NewValue("error", err))
-	}
-
-	logger.Info(ctx, log.Trace(), log.Action("done"))
-
+func (n notifier) customNotify(ctx context.Context) []Record {
+	err := n.send(n.arg.toFlag, n.arg.msgFlag)
+	return []Record{n.record(ctx, Record{Notify: true}, err)}
 }
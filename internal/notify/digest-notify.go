@@ -0,0 +1,17 @@
+package notify
+
+import (
+	"context"
+	"hellper/internal/digest"
+	"time"
+)
+
+var digestPeriods = map[string]time.Duration{
+	"daily":  digest.Daily,
+	"weekly": digest.Weekly,
+}
+
+func (n notifier) digestNotify(ctx context.Context) []Record {
+	err := digest.Send(ctx, n.client, n.logger, n.repository, n.arg.toFlag, digestPeriods[n.arg.periodFlag])
+	return []Record{n.record(ctx, Record{Notify: true}, err)}
+}
@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"context"
+	"hellper/internal/bot"
+	"hellper/internal/model"
+
+	"github.com/slack-go/slack"
+)
+
+// recordingClient keeps the messages posted by the notifications, on dry run they are not sent
+type recordingClient struct {
+	bot.Client
+	dryRun   bool
+	messages []Message
+}
+
+func newRecordingClient(client bot.Client, dryRun bool) *recordingClient {
+	return &recordingClient{Client: client, dryRun: dryRun}
+}
+
+func (c *recordingClient) PostMessage(channelID string, options ...slack.MsgOption) (string, string, error) {
+	message := Message{To: channelID, Text: messageText(channelID, options...)}
+	if c.dryRun {
+		c.messages = append(c.messages, message)
+		return "", "", nil
+	}
+
+	respChannel, timestamp, err := c.Client.PostMessage(channelID, options...)
+	message.Sent = err == nil
+	if err != nil {
+		message.Error = err.Error()
+	}
+	c.messages = append(c.messages, message)
+	return respChannel, timestamp, err
+}
+
+// flush returns the messages recorded since the last flush
+func (c *recordingClient) flush() []Message {
+	messages := c.messages
+	c.messages = nil
+	return messages
+}
+
+func messageText(channelID string, options ...slack.MsgOption) string {
+	_, values, err := slack.UnsafeApplyMsgOptions("", channelID, "", options...)
+	if err != nil {
+		return ""
+	}
+	return values.Get("text")
+}
+
+// dryRunRepository reads from the repository but does not record the reminders and their escalations nor clear the expired pauses
+type dryRunRepository struct {
+	model.Repository
+}
+
+func (dryRunRepository) InsertEscalation(context.Context, *model.Escalation) error {
+	return nil
+}
+
+func (dryRunRepository) InsertReminder(context.Context, *model.Reminder) error {
+	return nil
+}
+
+func (dryRunRepository) PauseNotifyIncident(context.Context, *model.Incident) error {
+	return nil
+}
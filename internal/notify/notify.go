@@ -4,83 +4,212 @@ import (
 	"context"
 	"errors"
 	"flag"
+	"fmt"
 	"hellper/internal"
-	"hellper/internal/config"
 	"hellper/internal/log"
 	"hellper/internal/model"
+	"hellper/internal/reminder"
+	"hellper/internal/sla"
+	"io"
+	"time"
 
 	"github.com/slack-go/slack"
 )
 
-var (
-	logger     = internal.NewLogger()
-	client     = internal.NewClient(logger)
-	repository = internal.NewRepository(logger)
-
-	arg opt
+// Exit codes of the CLI
+const (
+	ExitOK      = 0
+	ExitFailure = 1
+	ExitUsage   = 2
 )
 
+var statuses = map[string]bool{
+	"all":                true,
+	model.StatusOpen:     true,
+	model.StatusResolved: true,
+}
+
 type opt struct {
 	typeFlag   string
 	toFlag     string
 	msgFlag    string
 	statusFlag string
+	roleFlag   string
+	periodFlag string
+	dryRun     bool
+	output     string
 }
 
-func init() {
-	var typeFlag, toFlag, msgFlag, statusFlag string
-	flag.StringVar(&typeFlag, "type", "", "[channels|report|custom]")
-	flag.StringVar(&toFlag, "to", "", "[channel id|user id]")
-	flag.StringVar(&msgFlag, "msg", "", "A text message")
-	flag.StringVar(&statusFlag, "status", "", "[all|open|resolved]")
-	flag.Parse()
+type notifier struct {
+	logger     log.Logger
+	client     *recordingClient
+	repository model.Repository
+	policy     reminder.Policy
+	targets    sla.Targets
+	location   *time.Location
+	arg        opt
+}
 
-	arg = opt{typeFlag, toFlag, msgFlag, statusFlag}
+// Notify is a CLI responsible for sending messages, it writes what was sent to stdout and returns the exit code
+func Notify(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	arg, err := parseArgs(args, stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return ExitOK
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return ExitUsage
+	}
+
+	var (
+		logger     = internal.NewCLILogger()
+		client     = internal.NewClient(logger)
+		repository = internal.NewRepository(logger)
+	)
+	if arg.dryRun {
+		repository = dryRunRepository{repository}
+	}
+
+	n := notifier{
+		logger:     logger,
+		client:     newRecordingClient(client, arg.dryRun),
+		repository: repository,
+		policy:     internal.NewReminderPolicy(),
+		targets:    internal.NewSLATargets(),
+		location:   internal.NewLocation(),
+		arg:        arg,
+	}
+
+	return n.run(ctx, stdout)
 }
 
-// Notify is a CLI responsible for sending messages
-func Notify(ctx context.Context) {
-	logger.Info(ctx, log.Trace(), log.Action("running"))
+func parseArgs(args []string, stderr io.Writer) (opt, error) {
+	var arg opt
+
+	flags := flag.NewFlagSet("notify", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&arg.typeFlag, "type", "", "[channels|report|digest|postmortems|custom]")
+	flags.StringVar(&arg.toFlag, "to", "", "[channel id|user id]")
+	flags.StringVar(&arg.msgFlag, "msg", "", "A text message")
+	flags.StringVar(&arg.statusFlag, "status", "", "[all|open|resolved]")
+	flags.StringVar(&arg.roleFlag, "role", "", "Mention the holder of an incident role on --type=channels, e.g. comms_lead")
+	flags.StringVar(&arg.periodFlag, "period", "daily", "Period of the --type=digest summary [daily|weekly]")
+	flags.BoolVar(&arg.dryRun, "dry-run", false, "Show what would be sent without sending it")
+	flags.StringVar(&arg.output, "output", "text", "Format of the decisions written to stdout [text|json]")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return arg, err
+	}
+	if flags.NArg() > 0 {
+		return arg, errors.New("Unexpected argument " + flags.Arg(0))
+	}
+
+	return arg, arg.validate()
+}
+
+func (arg opt) validate() error {
+	if arg.output != "text" && arg.output != "json" {
+		return errors.New("Invalid output " + arg.output + ", use text or json")
+	}
+
+	if arg.statusFlag != "" && !statuses[arg.statusFlag] {
+		return errors.New("Invalid status " + arg.statusFlag + ", use all, open or resolved")
+	}
 
 	switch arg.typeFlag {
 	case "channels":
-		channelsNotify(ctx)
+		if arg.statusFlag == "" {
+			return errors.New("Must have a status")
+		}
+		if arg.toFlag != "" {
+			return errors.New("Forbidden to use the --to option with --type=channels")
+		}
+		if arg.roleFlag != "" && !model.IsIncidentRole(arg.roleFlag) {
+			return errors.New("Invalid role " + arg.roleFlag)
+		}
 	case "report":
-		reportNotify(ctx)
+		if arg.statusFlag == "" {
+			return errors.New("Must have a status")
+		}
+		if arg.toFlag == "" {
+			return errors.New("Must have a destination")
+		}
+		if arg.msgFlag != "" {
+			return errors.New("Forbidden to use the --msg option with --type=report")
+		}
+	case "digest":
+		if arg.toFlag == "" {
+			return errors.New("Must have a destination")
+		}
+		if arg.msgFlag != "" {
+			return errors.New("Forbidden to use the --msg option with --type=digest")
+		}
+		if _, ok := digestPeriods[arg.periodFlag]; !ok {
+			return errors.New("Invalid period " + arg.periodFlag + ", use daily or weekly")
+		}
+	case "postmortems":
+		if arg.toFlag != "" {
+			return errors.New("Forbidden to use the --to option with --type=postmortems")
+		}
+		if arg.msgFlag != "" {
+			return errors.New("Forbidden to use the --msg option with --type=postmortems")
+		}
 	case "custom":
-		customNotify(ctx)
+		if arg.toFlag == "" {
+			return errors.New("Must have a destination")
+		}
+		if arg.msgFlag == "" {
+			return errors.New("Must have a message")
+		}
 	default:
-		logger.Error(ctx, log.Trace(), log.NewValue("error", errors.New("Must have a type")))
-		return
+		return errors.New("Must have a type")
 	}
 
+	return nil
 }
 
-func send(to, msg string) error {
-
-	if to == "" {
-		return errors.New("Must have a destination")
-	}
+func (n notifier) run(ctx context.Context, stdout io.Writer) int {
+	n.logger.Info(ctx, log.Trace(), log.Action("running"), log.NewValue("type", n.arg.typeFlag), log.NewValue("dryRun", n.arg.dryRun))
 
-	if msg == "" {
-		return errors.New("Must have a message")
+	var records []Record
+	switch n.arg.typeFlag {
+	case "channels":
+		records = n.channelsNotify(ctx)
+	case "report":
+		records = n.reportNotify(ctx)
+	case "digest":
+		records = n.digestNotify(ctx)
+	case "postmortems":
+		records = n.postMortemsNotify(ctx)
+	case "custom":
+		records = n.customNotify(ctx)
 	}
 
-	_, _, err := client.PostMessage(to, slack.MsgOptionText(msg, false))
+	result := Result{DryRun: n.arg.dryRun, Type: n.arg.typeFlag, Records: records}
+	err := result.Write(stdout, n.arg.output)
 	if err != nil {
-		return err
+		n.logger.Error(ctx, log.Trace(), log.NewValue("error", err))
+		return ExitFailure
 	}
 
-	return nil
-
+	if result.Failed() {
+		return ExitFailure
+	}
+	return ExitOK
 }
 
-func statusNotify(incident model.Incident) string {
-	switch incident.Status {
-	case model.StatusOpen:
-		return config.Env.ReminderOpenNotifyMsg
-	case model.StatusResolved:
-		return config.Env.ReminderResolvedNotifyMsg
+// record collects the messages sent since the last record, with the error that stopped it
+func (n notifier) record(ctx context.Context, record Record, err error) Record {
+	record.Messages = n.client.flush()
+	if err != nil {
+		n.logger.Error(ctx, log.Trace(), log.NewValue("error", err), log.NewValue("channelID", record.ChannelID))
+		record.Error = err.Error()
 	}
-	return ""
+	return record
+}
+
+func (n notifier) send(to, msg string) error {
+	_, _, err := n.client.PostMessage(to, slack.MsgOptionText(msg, false))
+	return err
 }
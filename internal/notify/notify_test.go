@@ -0,0 +1,193 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hellper/internal/bot"
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/reminder"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testPolicy = `
+rules:
+  - status: open
+    interval: 15m
+    message: "Update {{.Channel}}"
+    stop_when: [snoozed]
+`
+
+func TestParseArgs(t *testing.T) {
+	table := []struct {
+		testName      string
+		args          []string
+		expectedError string
+	}{
+		{testName: "Channels", args: []string{"--type=channels", "--status=open", "--dry-run", "--output=json"}},
+		{testName: "Custom", args: []string{"--type=custom", "--to=C1", "--msg=hello"}},
+		{testName: "Missing type", args: []string{"--status=open"}, expectedError: "Must have a type"},
+		{testName: "Invalid status", args: []string{"--type=channels", "--status=closed"}, expectedError: "Invalid status closed, use all, open or resolved"},
+		{testName: "Invalid output", args: []string{"--type=channels", "--status=open", "--output=yaml"}, expectedError: "Invalid output yaml, use text or json"},
+		{testName: "Destination on channels", args: []string{"--type=channels", "--status=open", "--to=C1"}, expectedError: "Forbidden to use the --to option with --type=channels"},
+		{testName: "Post mortems", args: []string{"--type=postmortems", "--dry-run"}},
+		{testName: "Destination on post mortems", args: []string{"--type=postmortems", "--to=C1"}, expectedError: "Forbidden to use the --to option with --type=postmortems"},
+		{testName: "Invalid period", args: []string{"--type=digest", "--to=C1", "--period=monthly"}, expectedError: "Invalid period monthly, use daily or weekly"},
+		{testName: "Unknown flag", args: []string{"--type=custom", "--force"}, expectedError: "flag provided but not defined: -force"},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			_, err := parseArgs(f.args, &bytes.Buffer{})
+			if f.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, f.expectedError)
+		})
+	}
+}
+
+func TestNotifyUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	code := Notify(context.Background(), []string{"--type=report"}, &stdout, &stderr)
+
+	assert.Equal(t, ExitUsage, code)
+	assert.Empty(t, stdout.String())
+	assert.Contains(t, stderr.String(), "Must have a status")
+}
+
+type channelsFixture struct {
+	testName     string
+	dryRun       bool
+	postError    error
+	expectedCode int
+	expectedSent bool
+
+	ctx            context.Context
+	clientMock     *bot.ClientMock
+	repositoryMock *model.RepositoryMock
+	notifier       notifier
+}
+
+func (f *channelsFixture) setup(t *testing.T) {
+	var (
+		loggerMock     = log.NewLoggerMock()
+		clientMock     = bot.NewClientMock()
+		repositoryMock = model.NewRepositoryMock()
+		snoozedUntil   = time.Now().Add(time.Hour)
+	)
+
+	f.ctx = context.Background()
+
+	loggerMock.On("Info", f.ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+	loggerMock.On("Error", f.ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+	clientMock.On("PostMessage", "C1", mock.AnythingOfType("[]slack.MsgOption")).Return("", "", f.postError)
+	repositoryMock.On("ListActiveIncidents").Return([]model.Incident{
+		{Id: 1, ChannelId: "C1", Status: model.StatusOpen, SeverityLevel: 1},
+		{Id: 2, ChannelId: "C2", Status: model.StatusOpen, SeverityLevel: 2, SnoozedUntil: sql.NullTime{Time: snoozedUntil, Valid: true}},
+	}, nil)
+	repositoryMock.On("ListReminders", f.ctx, mock.AnythingOfType("int64")).Return(nil, nil)
+	repositoryMock.On("InsertReminder", f.ctx, mock.AnythingOfType("*model.Reminder")).Return(nil)
+
+	policy, err := reminder.ParsePolicy([]byte(testPolicy))
+	assert.NoError(t, err)
+
+	f.clientMock = clientMock
+	f.repositoryMock = repositoryMock
+	f.notifier = notifier{
+		logger:     loggerMock,
+		client:     newRecordingClient(clientMock, f.dryRun),
+		repository: repositoryMock,
+		policy:     policy,
+		arg:        opt{typeFlag: "channels", statusFlag: "all", dryRun: f.dryRun, output: "json"},
+	}
+}
+
+func TestChannelsNotify(t *testing.T) {
+	table := []channelsFixture{
+		{
+			testName:     "Dry run does not post",
+			dryRun:       true,
+			expectedCode: ExitOK,
+		},
+		{
+			testName:     "Posts the reminders",
+			expectedCode: ExitOK,
+			expectedSent: true,
+		},
+		{
+			testName:     "Failed post exits with failure",
+			postError:    errors.New("channel_not_found"),
+			expectedCode: ExitFailure,
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			f.setup(t)
+
+			var stdout bytes.Buffer
+			code := f.notifier.run(f.ctx, &stdout)
+			assert.Equal(t, f.expectedCode, code)
+
+			if f.dryRun {
+				f.clientMock.AssertNotCalled(t, "PostMessage", mock.Anything, mock.Anything)
+			} else {
+				f.clientMock.AssertNumberOfCalls(t, "PostMessage", 1)
+			}
+
+			var result Result
+			assert.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+			assert.Equal(t, f.dryRun, result.DryRun)
+			if assert.Len(t, result.Records, 2) {
+				notified, snoozed := result.Records[0], result.Records[1]
+
+				assert.True(t, notified.Notify)
+				assert.Equal(t, "the incident is open and no stop condition was met", notified.Reason)
+				assert.Equal(t, []Message{{To: "C1", Text: "Update <#C1>", Sent: f.expectedSent, Error: errorText(f.postError)}}, notified.Messages)
+
+				assert.False(t, snoozed.Notify)
+				assert.Equal(t, "C2", snoozed.ChannelID)
+				assert.Equal(t, "the reminders of this incident are snoozed", snoozed.Reason)
+				assert.Empty(t, snoozed.Messages)
+			}
+		})
+	}
+}
+
+func TestResultWriteText(t *testing.T) {
+	var (
+		stdout   bytes.Buffer
+		severity = int64(1)
+		result   = Result{
+			DryRun: true,
+			Type:   "channels",
+			Records: []Record{
+				{ChannelID: "C1", Status: "open", Severity: &severity, Notify: true, Reason: "no status update in the last 1h0m0s", Messages: []Message{{To: "C1", Text: "Update <#C1>"}}},
+				{ChannelID: "C2", Status: "open", Severity: &severity, Reason: "the reminders of this incident are snoozed"},
+			},
+		}
+	)
+
+	assert.NoError(t, result.Write(&stdout, "text"))
+	assert.Equal(t, "[dry-run] <#C1> open SEV1: notify (no status update in the last 1h0m0s)\n"+
+		"  -> C1: Update <#C1>\n"+
+		"[dry-run] <#C2> open SEV1: skip (the reminders of this incident are snoozed)\n", stdout.String())
+}
+
+func errorText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
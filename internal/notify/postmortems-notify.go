@@ -0,0 +1,44 @@
+package notify
+
+import (
+	"context"
+	"hellper/internal/config"
+	"hellper/internal/log"
+	"hellper/internal/postmortem"
+	"time"
+)
+
+func (n notifier) postMortemsNotify(ctx context.Context) []Record {
+	incidents, err := n.repository.ListPendingPostMortems(ctx)
+	if err != nil {
+		return []Record{n.record(ctx, Record{}, err)}
+	}
+
+	var (
+		now     = time.Now()
+		window  = time.Duration(config.Env.PostMortemRemindBeforeHours) * time.Hour
+		records []Record
+	)
+	for _, incident := range incidents {
+		severity := incident.SeverityLevel
+		record := Record{
+			IncidentID: incident.Id,
+			ChannelID:  incident.ChannelId,
+			Status:     incident.Status,
+			Severity:   &severity,
+		}
+
+		decision := postmortem.Decide(n.targets, incident, now, window)
+		record.Notify = decision.Remind
+		record.Reason = decision.Reason
+		if !decision.Remind {
+			n.logger.Info(ctx, log.Trace(), log.Action("do_not_notify"), log.Reason(decision.Reason), log.NewValue("channelID", incident.ChannelId))
+			records = append(records, n.record(ctx, record, nil))
+			continue
+		}
+
+		err := n.send(postmortem.Recipient(incident), postmortem.Message(incident, decision, n.location))
+		records = append(records, n.record(ctx, record, err))
+	}
+	return records
+}
@@ -0,0 +1,116 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Message is a message posted, or that would be posted on dry run, by the CLI
+type Message struct {
+	To    string `json:"to"`
+	Text  string `json:"text"`
+	Sent  bool   `json:"sent"`
+	Error string `json:"error,omitempty"`
+}
+
+// Record is the decision of the CLI for an incident, or for the whole run on the report, digest and custom types
+type Record struct {
+	IncidentID int64     `json:"incident_id,omitempty"`
+	ChannelID  string    `json:"channel_id,omitempty"`
+	Status     string    `json:"status,omitempty"`
+	Severity   *int64    `json:"severity,omitempty"`
+	Notify     bool      `json:"notify"`
+	Reason     string    `json:"reason,omitempty"`
+	Step       int       `json:"step,omitempty"`
+	Messages   []Message `json:"messages"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Result is what the CLI writes to stdout
+type Result struct {
+	DryRun  bool     `json:"dry_run"`
+	Type    string   `json:"type"`
+	Records []Record `json:"records"`
+}
+
+// Failed tells whether a record or a message failed
+func (r Result) Failed() bool {
+	for _, record := range r.Records {
+		if record.Error != "" {
+			return true
+		}
+		for _, message := range record.Messages {
+			if message.Error != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Write writes the result as json or as one line per record and message
+func (r Result) Write(w io.Writer, output string) error {
+	if output == "json" {
+		if r.Records == nil {
+			r.Records = []Record{}
+		}
+		return json.NewEncoder(w).Encode(r)
+	}
+
+	prefix := ""
+	if r.DryRun {
+		prefix = "[dry-run] "
+	}
+
+	for _, record := range r.Records {
+		_, err := fmt.Fprintln(w, prefix+record.summary(r.Type))
+		if err != nil {
+			return err
+		}
+
+		for _, message := range record.Messages {
+			_, err = fmt.Fprintln(w, "  "+message.summary())
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (record Record) summary(notifyType string) string {
+	text := notifyType
+	if record.ChannelID != "" {
+		text = "<#" + record.ChannelID + "> " + record.Status
+		if record.Severity != nil {
+			text += " SEV" + strconv.FormatInt(*record.Severity, 10)
+		}
+	}
+
+	if record.Notify {
+		text += ": notify"
+		if record.Step > 0 {
+			text += " step " + strconv.Itoa(record.Step)
+		}
+	} else {
+		text += ": skip"
+	}
+
+	if record.Reason != "" {
+		text += " (" + record.Reason + ")"
+	}
+	if record.Error != "" {
+		text += " error: " + record.Error
+	}
+	return text
+}
+
+func (message Message) summary() string {
+	text := "-> " + message.To + ": " + message.Text
+	if message.Error != "" {
+		text += " error: " + message.Error
+	}
+	return text
+}
@@ -0,0 +1,35 @@
+package pager
+
+import (
+	"context"
+	"sync"
+)
+
+// Fake keeps the events in memory, to try the paging flow without a pager
+type Fake struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+// NewFake creates an empty Fake
+func NewFake() *Fake {
+	return &Fake{}
+}
+
+func (f *Fake) Send(ctx context.Context, event Event) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.events = append(f.events, event)
+	if event.Action != ActionTrigger {
+		return "", nil
+	}
+	return "https://pager.example.com/events/" + event.DedupKey, nil
+}
+
+// Events returns the events sent
+func (f *Fake) Events() []Event {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Event(nil), f.events...)
+}
@@ -0,0 +1,98 @@
+// Package pager pages the on-call responders of the incidents through an Events API v2 compatible provider
+package pager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"hellper/internal/model"
+)
+
+// Actions of the pager events
+const (
+	ActionTrigger     = "trigger"
+	ActionAcknowledge = "acknowledge"
+	ActionResolve     = "resolve"
+)
+
+// ErrInvalidRoutes is returned when the routing keys can not be parsed
+var ErrInvalidRoutes = errors.New("invalid pager routing keys")
+
+// Event is sent to the provider, events with the same dedup key act on the same page
+type Event struct {
+	RoutingKey string
+	Action     string
+	DedupKey   string
+	Summary    string
+	Source     string
+	Severity   string
+	Link       string
+	Details    map[string]string
+}
+
+// Provider sends the events to the pager, it returns the link of the paged event when it has one
+type Provider interface {
+	Send(ctx context.Context, event Event) (string, error)
+}
+
+// Routes are the routing keys by product, severity (sev0) or product and severity (Checkout/sev0), * routes every incident
+type Routes map[string]string
+
+// ParseRoutes reads the routing keys in the format key=routing_key;key=routing_key, e.g. Checkout/sev0=R1;sev1=R2;*=R3
+func ParseRoutes(value string) (Routes, error) {
+	routes := Routes{}
+
+	for _, statement := range strings.Split(value, ";") {
+		statement = strings.TrimSpace(statement)
+		if statement == "" {
+			continue
+		}
+
+		parts := strings.SplitN(statement, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || key == "" {
+			return nil, fmt.Errorf("%w: %q has no key", ErrInvalidRoutes, statement)
+		}
+
+		routingKey := strings.TrimSpace(parts[1])
+		if routingKey == "" {
+			return nil, fmt.Errorf("%w: empty routing key of %s", ErrInvalidRoutes, key)
+		}
+		routes[key] = routingKey
+	}
+
+	return routes, nil
+}
+
+// RoutingKey picks the most specific routing key of the incident, an empty string when the incident is not paged
+func (r Routes) RoutingKey(inc model.Incident) string {
+	severity := "sev" + strconv.FormatInt(inc.SeverityLevel, 10)
+	for _, key := range []string{inc.Product + "/" + severity, inc.Product, severity, "*"} {
+		if routingKey, ok := r[key]; ok {
+			return routingKey
+		}
+	}
+	return ""
+}
+
+// Severity is the pager severity of an incident severity level
+func Severity(severityLevel int64) string {
+	switch severityLevel {
+	case 0, 1:
+		return "critical"
+	case 2:
+		return "error"
+	case 3:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// DedupKey identifies the page of the incident
+func DedupKey(inc model.Incident) string {
+	return strconv.FormatInt(inc.Id, 10)
+}
@@ -0,0 +1,99 @@
+package pager_test
+
+import (
+	"fmt"
+	"testing"
+
+	"hellper/internal/model"
+	"hellper/internal/pager"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRoutes(t *testing.T) {
+	table := []struct {
+		testName      string
+		value         string
+		expected      pager.Routes
+		expectedError string
+	}{
+		{
+			testName: "Routes by product and severity",
+			value:    "Checkout/sev0=R1; Checkout=R2;sev1=R3;*=R4",
+			expected: pager.Routes{"Checkout/sev0": "R1", "Checkout": "R2", "sev1": "R3", "*": "R4"},
+		},
+		{
+			testName: "Empty",
+			expected: pager.Routes{},
+		},
+		{
+			testName:      "Missing key",
+			value:         "R1",
+			expectedError: `invalid pager routing keys: "R1" has no key`,
+		},
+		{
+			testName:      "Empty routing key",
+			value:         "Checkout=",
+			expectedError: "invalid pager routing keys: empty routing key of Checkout",
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			routes, err := pager.ParseRoutes(f.value)
+			if f.expectedError != "" {
+				assert.EqualError(t, err, f.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, f.expected, routes)
+		})
+	}
+}
+
+func TestRoutingKey(t *testing.T) {
+	routes := pager.Routes{"Checkout/sev0": "R1", "Checkout": "R2", "sev1": "R3", "Search": "R5"}
+
+	table := []struct {
+		testName string
+		incident model.Incident
+		routes   pager.Routes
+		expected string
+	}{
+		{
+			testName: "Product and severity",
+			incident: model.Incident{Product: "Checkout", SeverityLevel: 0},
+			expected: "R1",
+		},
+		{
+			testName: "Product wins over severity",
+			incident: model.Incident{Product: "Checkout", SeverityLevel: 1},
+			expected: "R2",
+		},
+		{
+			testName: "Severity",
+			incident: model.Incident{Product: "Payments", SeverityLevel: 1},
+			expected: "R3",
+		},
+		{
+			testName: "Not paged",
+			incident: model.Incident{Product: "Payments", SeverityLevel: 3},
+		},
+		{
+			testName: "Fallback",
+			incident: model.Incident{Product: "Payments", SeverityLevel: 3},
+			routes:   pager.Routes{"*": "R4"},
+			expected: "R4",
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			r := routes
+			if f.routes != nil {
+				r = f.routes
+			}
+			assert.Equal(t, f.expected, r.RoutingKey(f.incident))
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package pager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// PagerDutyURL is the Events API v2 of PagerDuty
+const PagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+
+type pagerDuty struct {
+	url        string
+	eventURL   string
+	httpClient *http.Client
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+	Links       []pagerDutyLink   `json:"links,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+type pagerDutyLink struct {
+	Href string `json:"href"`
+	Text string `json:"text"`
+}
+
+// NewPagerDuty creates a Provider on an Events API v2 compatible url,
+// the link of a triggered event is the eventURL with {dedup_key} replaced, no link is kept when it is empty
+func NewPagerDuty(url string, eventURL string, timeout time.Duration) Provider {
+	return &pagerDuty{
+		url:        url,
+		eventURL:   eventURL,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *pagerDuty) Send(ctx context.Context, event Event) (string, error) {
+	body := pagerDutyEvent{
+		RoutingKey:  event.RoutingKey,
+		EventAction: event.Action,
+		DedupKey:    event.DedupKey,
+	}
+	// Only the trigger carries the payload, acknowledge and resolve act on the dedup key
+	if event.Action == ActionTrigger {
+		body.Payload = &pagerDutyPayload{
+			Summary:       event.Summary,
+			Source:        event.Source,
+			Severity:      event.Severity,
+			CustomDetails: event.Details,
+		}
+		if event.Link != "" {
+			body.Links = []pagerDutyLink{{Href: event.Link, Text: "Incident channel"}}
+		}
+	}
+
+	content, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(content))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	response, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(response)))
+	}
+
+	if event.Action != ActionTrigger || p.eventURL == "" {
+		return "", nil
+	}
+	return strings.ReplaceAll(p.eventURL, "{dedup_key}", event.DedupKey), nil
+}
@@ -0,0 +1,74 @@
+package pager_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"hellper/internal/pager"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPagerDuty(t *testing.T) {
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, _ := ioutil.ReadAll(r.Body)
+		body := map[string]interface{}{}
+		_ = json.Unmarshal(content, &body)
+		bodies = append(bodies, body)
+
+		if body["routing_key"] == "invalid" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"status":"invalid event","message":"Event object is invalid"}`))
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"status":"success","message":"Event processed","dedup_key":"42"}`))
+	}))
+	defer server.Close()
+
+	var (
+		ctx      = context.Background()
+		provider = pager.NewPagerDuty(server.URL, "https://acme.pagerduty.com/alerts?dedup_key={dedup_key}", time.Second)
+	)
+
+	link, err := provider.Send(ctx, pager.Event{
+		RoutingKey: "R1",
+		Action:     pager.ActionTrigger,
+		DedupKey:   "42",
+		Summary:    "[SEV1] Checkout errors",
+		Source:     "hellper",
+		Severity:   "critical",
+		Link:       "https://slack.com/app_redirect?channel=C1",
+		Details:    map[string]string{"product": "Checkout"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://acme.pagerduty.com/alerts?dedup_key=42", link)
+	assert.Equal(t, map[string]interface{}{
+		"routing_key":  "R1",
+		"event_action": "trigger",
+		"dedup_key":    "42",
+		"payload": map[string]interface{}{
+			"summary":        "[SEV1] Checkout errors",
+			"source":         "hellper",
+			"severity":       "critical",
+			"custom_details": map[string]interface{}{"product": "Checkout"},
+		},
+		"links": []interface{}{
+			map[string]interface{}{"href": "https://slack.com/app_redirect?channel=C1", "text": "Incident channel"},
+		},
+	}, bodies[0])
+
+	link, err = provider.Send(ctx, pager.Event{RoutingKey: "R1", Action: pager.ActionAcknowledge, DedupKey: "42"})
+	assert.NoError(t, err)
+	assert.Empty(t, link)
+	assert.Equal(t, map[string]interface{}{"routing_key": "R1", "event_action": "acknowledge", "dedup_key": "42"}, bodies[1])
+
+	_, err = provider.Send(ctx, pager.Event{RoutingKey: "invalid", Action: pager.ActionResolve, DedupKey: "42"})
+	assert.EqualError(t, err, `unexpected status 400: {"status":"invalid event","message":"Event object is invalid"}`)
+}
@@ -0,0 +1,177 @@
+package pager
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"hellper/internal/bot"
+	"hellper/internal/concurrence"
+	"hellper/internal/lifecycle"
+	"hellper/internal/log"
+	"hellper/internal/model"
+
+	"github.com/slack-go/slack"
+)
+
+// Service pages the responders of the opened incidents, acknowledges the page once the commander shows up and resolves it with the incident
+type Service struct {
+	logger     log.Logger
+	client     bot.Client
+	repository model.Repository
+	background *concurrence.Background
+	provider   Provider
+	routes     Routes
+
+	// acknowledged keeps the incidents whose page was acknowledged, the next messages of the commander do not send it again
+	acknowledged sync.Map
+}
+
+// NewService creates a Service sending to the provider with the routing keys of the routes, on the background
+func NewService(logger log.Logger, client bot.Client, repository model.Repository, background *concurrence.Background, provider Provider, routes Routes) *Service {
+	return &Service{
+		logger:     logger,
+		client:     client,
+		repository: repository,
+		background: background,
+		provider:   provider,
+		routes:     routes,
+	}
+}
+
+// Wants tells whether the event triggers or resolves the page
+func (s *Service) Wants(event string) bool {
+	return event == lifecycle.EventOpened || event == lifecycle.EventResolved || event == lifecycle.EventCanceled
+}
+
+// Dispatch triggers the page of an opened incident and resolves the page of a resolved or canceled one, in background
+func (s *Service) Dispatch(ctx context.Context, event string, incident model.Incident) {
+	s.background.Go(func() {
+		ctx := context.Background()
+
+		var err error
+		if event == lifecycle.EventOpened {
+			err = s.Trigger(ctx, incident)
+		} else {
+			err = s.Resolve(ctx, incident)
+		}
+		if err != nil {
+			s.logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("pager.Dispatch"),
+				log.Reason(err.Error()),
+				log.NewValue("event", event),
+				log.NewValue("channelID", incident.ChannelId),
+			)
+		}
+	})
+}
+
+// Trigger pages the responders of the routing key of the incident and keeps the link of the page on the incident
+func (s *Service) Trigger(ctx context.Context, incident model.Incident) error {
+	routingKey := s.routes.RoutingKey(incident)
+	if routingKey == "" {
+		s.logger.Info(
+			ctx,
+			log.Trace(),
+			log.NewValue("channelID", incident.ChannelId),
+			log.NewValue("product", incident.Product),
+			log.NewValue("severityLevel", incident.SeverityLevel),
+			log.NewValue("reason", "no routing key"),
+		)
+		return nil
+	}
+
+	link, err := s.provider.Send(ctx, Event{
+		RoutingKey: routingKey,
+		Action:     ActionTrigger,
+		DedupKey:   DedupKey(incident),
+		Summary:    "[SEV" + strconv.FormatInt(incident.SeverityLevel, 10) + "] " + incident.Title,
+		Source:     "hellper",
+		Severity:   Severity(incident.SeverityLevel),
+		Link:       "https://slack.com/app_redirect?channel=" + incident.ChannelId,
+		Details: map[string]string{
+			"product":     incident.Product,
+			"description": incident.DescriptionStarted,
+			"commander":   incident.CommanderEmail,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	text := ":rotating_light: The on-call responders were paged."
+	if link != "" {
+		text = ":rotating_light: The on-call responders were paged: " + link
+		err = s.repository.AddPagerEventUrl(ctx, incident.ChannelId, link)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, _, err = s.client.PostMessage(incident.ChannelId, slack.MsgOptionText(text, false))
+	if err != nil {
+		s.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("client.PostMessage"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", incident.ChannelId),
+		)
+	}
+	return nil
+}
+
+// Acknowledge acknowledges the page of the incident of the channel when the user is its commander, once per incident
+func (s *Service) Acknowledge(ctx context.Context, channelID string, userID string) error {
+	incident, err := s.repository.GetIncident(ctx, channelID)
+	if err != nil {
+		// Events of channels without incident are ignored
+		s.logger.Info(
+			ctx,
+			log.Trace(),
+			log.NewValue("channelID", channelID),
+			log.NewValue("reason", err.Error()),
+		)
+		return nil
+	}
+
+	if incident.Status != model.StatusOpen || incident.CommanderId == "" || incident.CommanderId != userID {
+		return nil
+	}
+
+	routingKey := s.routes.RoutingKey(incident)
+	if routingKey == "" {
+		return nil
+	}
+
+	if _, done := s.acknowledged.LoadOrStore(incident.Id, true); done {
+		return nil
+	}
+
+	_, err = s.provider.Send(ctx, Event{
+		RoutingKey: routingKey,
+		Action:     ActionAcknowledge,
+		DedupKey:   DedupKey(incident),
+	})
+	if err != nil {
+		s.acknowledged.Delete(incident.Id)
+	}
+	return err
+}
+
+// Resolve resolves the page of the incident
+func (s *Service) Resolve(ctx context.Context, incident model.Incident) error {
+	routingKey := s.routes.RoutingKey(incident)
+	if routingKey == "" {
+		return nil
+	}
+
+	_, err := s.provider.Send(ctx, Event{
+		RoutingKey: routingKey,
+		Action:     ActionResolve,
+		DedupKey:   DedupKey(incident),
+	})
+	return err
+}
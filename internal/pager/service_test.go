@@ -0,0 +1,145 @@
+package pager_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"hellper/internal/bot"
+	"hellper/internal/concurrence"
+	"hellper/internal/lifecycle"
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/pager"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDispatch(t *testing.T) {
+	var (
+		ctx            = context.Background()
+		loggerMock     = log.NewLoggerMock()
+		clientMock     = bot.NewClientMock()
+		repositoryMock = model.NewRepositoryMock()
+		fake           = pager.NewFake()
+		background     = &concurrence.Background{}
+		incident       = model.Incident{Id: 42, ChannelId: "C1", Title: "Checkout errors", Product: "Checkout", SeverityLevel: 1}
+	)
+
+	loggerMock.On("Info", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+	loggerMock.On("Error", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+	clientMock.On("PostMessage", "C1", mock.Anything).Return("", "", nil)
+	repositoryMock.On("AddPagerEventUrl", mock.Anything, "C1", "https://pager.example.com/events/42").Return(nil)
+
+	service := pager.NewService(loggerMock, clientMock, repositoryMock, background, fake, pager.Routes{"Checkout": "R1"})
+	assert.True(t, service.Wants(lifecycle.EventOpened))
+	assert.True(t, service.Wants(lifecycle.EventResolved))
+	assert.True(t, service.Wants(lifecycle.EventCanceled))
+	assert.False(t, service.Wants(lifecycle.EventClosed))
+
+	service.Dispatch(ctx, lifecycle.EventOpened, incident)
+	assert.NoError(t, background.Wait(ctx))
+	service.Dispatch(ctx, lifecycle.EventResolved, incident)
+	assert.NoError(t, background.Wait(ctx))
+
+	events := fake.Events()
+	assert.Len(t, events, 2)
+	assert.Equal(t, pager.Event{
+		RoutingKey: "R1",
+		Action:     pager.ActionTrigger,
+		DedupKey:   "42",
+		Summary:    "[SEV1] Checkout errors",
+		Source:     "hellper",
+		Severity:   "critical",
+		Link:       "https://slack.com/app_redirect?channel=C1",
+		Details:    map[string]string{"product": "Checkout", "description": "", "commander": ""},
+	}, events[0])
+	assert.Equal(t, pager.Event{RoutingKey: "R1", Action: pager.ActionResolve, DedupKey: "42"}, events[1])
+	repositoryMock.AssertCalled(t, "AddPagerEventUrl", mock.Anything, "C1", "https://pager.example.com/events/42")
+	clientMock.AssertCalled(t, "PostMessage", "C1", mock.Anything)
+	loggerMock.AssertNotCalled(t, "Error", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTriggerWithoutRoute(t *testing.T) {
+	var (
+		ctx            = context.Background()
+		loggerMock     = log.NewLoggerMock()
+		clientMock     = bot.NewClientMock()
+		repositoryMock = model.NewRepositoryMock()
+		fake           = pager.NewFake()
+	)
+
+	loggerMock.On("Info", ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+
+	service := pager.NewService(loggerMock, clientMock, repositoryMock, &concurrence.Background{}, fake, pager.Routes{"Checkout": "R1"})
+	err := service.Trigger(ctx, model.Incident{Id: 42, ChannelId: "C1", Product: "Search"})
+	assert.NoError(t, err)
+	assert.Empty(t, fake.Events())
+	clientMock.AssertNotCalled(t, "PostMessage", mock.Anything, mock.Anything)
+}
+
+func TestAcknowledge(t *testing.T) {
+	table := []struct {
+		testName          string
+		userID            string
+		incident          model.Incident
+		incidentError     error
+		expectAcknowledge bool
+	}{
+		{
+			testName:          "Commander acknowledges",
+			userID:            "U1",
+			incident:          model.Incident{Id: 42, ChannelId: "C1", Product: "Checkout", Status: model.StatusOpen, CommanderId: "U1"},
+			expectAcknowledge: true,
+		},
+		{
+			testName: "Other users do not acknowledge",
+			userID:   "U2",
+			incident: model.Incident{Id: 42, ChannelId: "C1", Product: "Checkout", Status: model.StatusOpen, CommanderId: "U1"},
+		},
+		{
+			testName: "Resolved incident",
+			userID:   "U1",
+			incident: model.Incident{Id: 42, ChannelId: "C1", Product: "Checkout", Status: model.StatusResolved, CommanderId: "U1"},
+		},
+		{
+			testName: "Incident not paged",
+			userID:   "U1",
+			incident: model.Incident{Id: 42, ChannelId: "C1", Product: "Search", Status: model.StatusOpen, CommanderId: "U1"},
+		},
+		{
+			testName:      "Channel without incident",
+			userID:        "U1",
+			incidentError: errors.New("Incident C1not found"),
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			var (
+				ctx            = context.Background()
+				loggerMock     = log.NewLoggerMock()
+				repositoryMock = model.NewRepositoryMock()
+				fake           = pager.NewFake()
+			)
+
+			loggerMock.On("Info", ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+			repositoryMock.On("GetIncident", "C1").Return(f.incident, f.incidentError)
+
+			service := pager.NewService(loggerMock, bot.NewClientMock(), repositoryMock, &concurrence.Background{}, fake, pager.Routes{"Checkout": "R1"})
+			// The commander posts twice, the page is acknowledged once
+			for attempt := 0; attempt < 2; attempt++ {
+				err := service.Acknowledge(ctx, "C1", f.userID)
+				assert.NoError(t, err)
+			}
+
+			if !f.expectAcknowledge {
+				assert.Empty(t, fake.Events())
+				return
+			}
+			assert.Equal(t, []pager.Event{{RoutingKey: "R1", Action: pager.ActionAcknowledge, DedupKey: "42"}}, fake.Events())
+		})
+	}
+}
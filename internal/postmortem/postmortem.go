@@ -0,0 +1,125 @@
+// Package postmortem follows up the post mortems of the resolved incidents until they are published
+package postmortem
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"hellper/internal/bot"
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/sla"
+
+	"github.com/slack-go/slack"
+)
+
+// Decision tells whether the commander is reminded of the post mortem, and why
+type Decision struct {
+	Remind bool
+	Reason string
+	DueAt  time.Time
+	// Left is the time until the due date, negative once overdue
+	Left time.Duration
+}
+
+// Decide reminds the post mortems that are not published when their due date is within the window, or past
+func Decide(targets sla.Targets, inc model.Incident, now time.Time, window time.Duration) Decision {
+	if inc.Status == model.StatusCancel || inc.PostMortemStatus == model.PostMortemPublished {
+		return Decision{Reason: "the post mortem is not pending"}
+	}
+
+	dueAt, ok := sla.DueAt(targets, inc, sla.MetricPostmortem)
+	if !ok {
+		return Decision{Reason: "the post mortem has no due date"}
+	}
+
+	decision := Decision{DueAt: dueAt, Left: dueAt.Sub(now)}
+	switch {
+	case decision.Left < 0:
+		decision.Remind = true
+		decision.Reason = "the post mortem is overdue"
+	case decision.Left <= window:
+		decision.Remind = true
+		decision.Reason = "the post mortem is due within the reminder window"
+	default:
+		decision.Reason = "the post mortem is not due yet"
+	}
+	return decision
+}
+
+// Recipient is the commander of the incident, or its channel when nobody is the commander
+func Recipient(inc model.Incident) string {
+	if inc.CommanderId != "" {
+		return inc.CommanderId
+	}
+	return inc.ChannelId
+}
+
+// Message asks the commander to move the post mortem forward
+func Message(inc model.Incident, decision Decision, loc *time.Location) string {
+	when := "due in " + sla.FormatDuration(decision.Left)
+	if decision.Left < 0 {
+		when = "overdue by " + sla.FormatDuration(-decision.Left)
+	}
+
+	msg := fmt.Sprintf(
+		":memo: The post mortem of <#%s> is *%s* and %s (due %s).",
+		inc.ChannelId,
+		model.PostMortemStatusName(inc.PostMortemStatus),
+		when,
+		decision.DueAt.In(loc).Format("Mon, 02 Jan 15:04 MST"),
+	)
+	if inc.PostMortemUrl != "" {
+		msg += " " + inc.PostMortemUrl
+	}
+	return msg + "\nMove it forward with `/hellper_postmortem draft`, `in_review` or `published` on the incident channel."
+}
+
+// RemindCommanders sends a direct message to the commanders of the post mortems due within the window, or past
+func RemindCommanders(ctx context.Context, client bot.Client, logger log.Logger, repository model.Repository, targets sla.Targets, window time.Duration, loc *time.Location) error {
+	incidents, err := repository.ListPendingPostMortems(ctx)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("repository.ListPendingPostMortems"),
+			log.Reason(err.Error()),
+		)
+		return err
+	}
+
+	var (
+		now     = time.Now()
+		lastErr error
+	)
+	for _, inc := range incidents {
+		decision := Decide(targets, inc, now, window)
+		if !decision.Remind {
+			logger.Info(
+				ctx,
+				log.Trace(),
+				log.Action("do_not_notify"),
+				log.Reason(decision.Reason),
+				log.NewValue("channelID", inc.ChannelId),
+			)
+			continue
+		}
+
+		to := Recipient(inc)
+		_, _, err := client.PostMessage(to, slack.MsgOptionText(Message(inc, decision, loc), false))
+		if err != nil {
+			logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("client.PostMessage"),
+				log.Reason(err.Error()),
+				log.NewValue("channelID", inc.ChannelId),
+				log.NewValue("to", to),
+			)
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
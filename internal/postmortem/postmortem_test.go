@@ -0,0 +1,109 @@
+package postmortem_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"hellper/internal/bot"
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/postmortem"
+	"hellper/internal/sla"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	now     = time.Date(2020, 10, 19, 12, 0, 0, 0, time.UTC)
+	targets = sla.Targets{
+		"sev0": {sla.MetricPostmortem: 48 * time.Hour},
+		"*":    {sla.MetricPostmortem: 120 * time.Hour},
+	}
+)
+
+func resolvedAgo(d time.Duration) *time.Time {
+	t := now.Add(-d)
+	return &t
+}
+
+func TestDecide(t *testing.T) {
+	table := []struct {
+		testName       string
+		incident       model.Incident
+		expectedRemind bool
+		expectedReason string
+	}{
+		{
+			testName:       "Overdue by severity",
+			incident:       model.Incident{Status: model.StatusResolved, SeverityLevel: 0, EndTimestamp: resolvedAgo(50 * time.Hour)},
+			expectedRemind: true,
+			expectedReason: "the post mortem is overdue",
+		},
+		{
+			testName:       "Due within the window",
+			incident:       model.Incident{Status: model.StatusClosed, SeverityLevel: 2, EndTimestamp: resolvedAgo(100 * time.Hour), PostMortemStatus: model.PostMortemInReview},
+			expectedRemind: true,
+			expectedReason: "the post mortem is due within the reminder window",
+		},
+		{
+			testName:       "Not due yet",
+			incident:       model.Incident{Status: model.StatusResolved, SeverityLevel: 2, EndTimestamp: resolvedAgo(50 * time.Hour)},
+			expectedReason: "the post mortem is not due yet",
+		},
+		{
+			testName:       "Published",
+			incident:       model.Incident{Status: model.StatusClosed, SeverityLevel: 0, EndTimestamp: resolvedAgo(50 * time.Hour), PostMortemStatus: model.PostMortemPublished},
+			expectedReason: "the post mortem is not pending",
+		},
+		{
+			testName:       "Not resolved",
+			incident:       model.Incident{Status: model.StatusOpen, SeverityLevel: 0},
+			expectedReason: "the post mortem has no due date",
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			decision := postmortem.Decide(targets, f.incident, now, 24*time.Hour)
+			assert.Equal(t, f.expectedRemind, decision.Remind)
+			assert.Equal(t, f.expectedReason, decision.Reason)
+		})
+	}
+}
+
+func TestMessage(t *testing.T) {
+	inc := model.Incident{ChannelId: "C1", Status: model.StatusResolved, SeverityLevel: 0, EndTimestamp: resolvedAgo(50 * time.Hour), PostMortemUrl: "https://docs.example"}
+	decision := postmortem.Decide(targets, inc, now, 24*time.Hour)
+
+	assert.Equal(t, ":memo: The post mortem of <#C1> is *Not started* and overdue by 2h (due Mon, 19 Oct 10:00 UTC). https://docs.example\n"+
+		"Move it forward with `/hellper_postmortem draft`, `in_review` or `published` on the incident channel.", postmortem.Message(inc, decision, time.UTC))
+}
+
+func TestRemindCommanders(t *testing.T) {
+	var (
+		ctx            = context.Background()
+		loggerMock     = log.NewLoggerMock()
+		clientMock     = bot.NewClientMock()
+		repositoryMock = model.NewRepositoryMock()
+		longAgo        = time.Now().Add(-30 * 24 * time.Hour)
+		recently       = time.Now().Add(-time.Hour)
+	)
+
+	loggerMock.On("Info", ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+	clientMock.On("PostMessage", mock.AnythingOfType("string"), mock.AnythingOfType("[]slack.MsgOption")).Return("", "", nil)
+	repositoryMock.On("ListPendingPostMortems", ctx).Return([]model.Incident{
+		{Id: 1, ChannelId: "C1", CommanderId: "U1", Status: model.StatusClosed, EndTimestamp: &longAgo},
+		{Id: 2, ChannelId: "C2", Status: model.StatusResolved, EndTimestamp: &longAgo},
+		{Id: 3, ChannelId: "C3", CommanderId: "U3", Status: model.StatusResolved, EndTimestamp: &recently},
+	}, nil)
+
+	err := postmortem.RemindCommanders(ctx, clientMock, loggerMock, repositoryMock, targets, 24*time.Hour, time.UTC)
+	assert.NoError(t, err)
+
+	clientMock.AssertNumberOfCalls(t, "PostMessage", 2)
+	clientMock.AssertCalled(t, "PostMessage", "U1", mock.Anything)
+	clientMock.AssertCalled(t, "PostMessage", "C2", mock.Anything)
+}
@@ -5,17 +5,37 @@ import (
 	"hellper/internal/bot"
 	"hellper/internal/log"
 	"hellper/internal/model"
+	"strconv"
+	"time"
 )
 
+// Decision tells whether the incident should be reminded now, and why
+type Decision struct {
+	Notify bool
+	Reason string
+	Rule   *Rule
+	// Step of the escalation chain, 0 is the reminder of the rule itself
+	Step int
+}
+
 type notifyRules struct {
-	snoozedUntil bool
-	lastPin      bool
-	slaClose     bool
-	status       string
+	snoozedUntil         bool
+	quietHours           bool
+	outsideBusinessHours bool
+	lastPin              bool
+	lastReminder         bool
+	slaClose             bool
+	rule                 *Rule
+	region               string
 }
 
-// CanSendNotify checks the notification rules
+// CanSendNotify checks the notification rules of the default policy
 func CanSendNotify(ctx context.Context, client bot.Client, logger log.Logger, repository model.Repository, incident model.Incident) bool {
+	return Evaluate(ctx, client, logger, repository, DefaultPolicy(), incident).Notify
+}
+
+// Evaluate checks the stop conditions of the policy rule matching the incident
+func Evaluate(ctx context.Context, client bot.Client, logger log.Logger, repository model.Repository, policy Policy, incident model.Incident) Decision {
 	logger.Info(
 		ctx,
 		log.Trace(),
@@ -24,47 +44,87 @@ func CanSendNotify(ctx context.Context, client bot.Client, logger log.Logger, re
 		log.NewValue("channelName", incident.ChannelName),
 	)
 
-	rules := notifyRules{
-		snoozedUntil: hasSnoozedUntil(ctx, logger, incident),
-		lastPin:      hasLastPin(ctx, client, logger, incident),
-		slaClose:     hasSLAClose(ctx, client, logger, incident),
-		status:       incident.Status,
+	rule := policy.Match(incident)
+	if rule == nil {
+		return Decision{
+			Reason: "no reminder rule for status " + incident.Status + " and severity SEV" + strconv.FormatInt(incident.SeverityLevel, 10),
+		}
+	}
+
+	rules := notifyRules{rule: rule}
+	if rule.stopsWhen(StopSnoozed) {
+		rules.snoozedUntil = hasSnoozedUntil(ctx, logger, incident)
+	}
+	if rule.stopsWhen(StopQuietHours) || rule.stopsWhen(StopOutsideBusinessHours) {
+		region := policy.Calendar.Region(incident.Product)
+		rules.quietHours = rule.stopsWhen(StopQuietHours) && isQuietTime(ctx, logger, region, incident, time.Now())
+		rules.outsideBusinessHours = rule.stopsWhen(StopOutsideBusinessHours) && isOutsideBusinessHours(ctx, logger, region, incident, time.Now())
+		if region != nil {
+			rules.region = region.Name()
+		}
+	}
+	if rule.stopsWhen(StopRecentUpdate) && !rule.escalates() {
+		rules.lastPin = hasLastPin(ctx, client, logger, incident, rule.Interval)
+	}
+	if rule.stopsWhen(StopWithinCloseSLA) {
+		rules.slaClose = hasSLAClose(ctx, client, logger, incident)
+	}
+	// the steps of an escalation chain are spaced by their own delays
+	if !rule.escalates() {
+		rules.lastReminder = hasLastReminder(ctx, logger, repository, incident, rule)
+	}
+
+	decision := rules.checkRules()
+	if !decision.Notify || !rule.escalates() {
+		return decision
 	}
+	return escalate(ctx, client, logger, repository, incident, rule)
+}
 
-	return rules.checkRules()
+// Targets returns the targets of the step of the decision
+func (d Decision) Targets() []Target {
+	if d.Step == 0 {
+		return d.Rule.ParsedTargets()
+	}
+	return parseTargets(d.Rule.Escalation[d.Step-1].Targets)
 }
 
-func (rules notifyRules) checkRules() bool {
-	switch rules.status {
-	case model.StatusOpen:
-		return rules.rulesInOpenStatus()
-	case model.StatusResolved:
-		return rules.rulesInResolvedStatus()
-	default:
-		return false
+// Render executes the message of the step of the decision, the steps without message use the one of the rule
+func (d Decision) Render(incident model.Incident) (string, error) {
+	if d.Step == 0 || d.Rule.Escalation[d.Step-1].Message == "" {
+		return render(d.Rule.Message, d.Rule.message, incident, d.Rule.stepAfter(d.Step))
 	}
+	step := d.Rule.Escalation[d.Step-1]
+	return render(step.Message, step.message, incident, step.After)
 }
 
-func (rules notifyRules) rulesInOpenStatus() bool {
+func (rules notifyRules) checkRules() Decision {
 	if rules.snoozedUntil {
-		return false
+		return Decision{Rule: rules.rule, Reason: "the reminders of this incident are snoozed"}
 	}
 
-	if rules.lastPin {
-		return false
+	if rules.quietHours {
+		return Decision{Rule: rules.rule, Reason: "it is quiet time on the " + rules.region + " work calendar"}
 	}
 
-	return true
-}
+	if rules.outsideBusinessHours {
+		return Decision{Rule: rules.rule, Reason: "it is outside the business hours of the " + rules.region + " work calendar"}
+	}
 
-func (rules notifyRules) rulesInResolvedStatus() bool {
-	if rules.snoozedUntil {
-		return false
+	if rules.lastPin {
+		return Decision{Rule: rules.rule, Reason: "the status was updated in the last " + rules.rule.Interval.String()}
 	}
 
 	if rules.slaClose {
-		return false
+		return Decision{Rule: rules.rule, Reason: "the incident is still within the SLA to close"}
 	}
 
-	return true
+	if rules.lastReminder {
+		return Decision{Rule: rules.rule, Reason: "the incident was reminded in the last " + rules.rule.Interval.String()}
+	}
+
+	if rules.rule.stopsWhen(StopRecentUpdate) {
+		return Decision{Notify: true, Rule: rules.rule, Reason: "no status update in the last " + rules.rule.Interval.String()}
+	}
+	return Decision{Notify: true, Rule: rules.rule, Reason: "the incident is " + rules.rule.Status + " and no stop condition was met"}
 }
@@ -0,0 +1,41 @@
+package reminder
+
+import (
+	"context"
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/workcalendar"
+	"time"
+)
+
+func isQuietTime(ctx context.Context, logger log.Logger, region *workcalendar.Region, incident model.Incident, now time.Time) bool {
+	if !region.IsQuietTime(now) {
+		return false
+	}
+
+	logger.Info(
+		ctx,
+		log.Trace(),
+		log.Action("do_not_notify"),
+		log.Reason("isQuietTime"),
+		log.NewValue("channelID", incident.ChannelId),
+		log.NewValue("region", region.Name()),
+	)
+	return true
+}
+
+func isOutsideBusinessHours(ctx context.Context, logger log.Logger, region *workcalendar.Region, incident model.Incident, now time.Time) bool {
+	if region.IsBusinessTime(now) {
+		return false
+	}
+
+	logger.Info(
+		ctx,
+		log.Trace(),
+		log.Action("do_not_notify"),
+		log.Reason("isOutsideBusinessHours"),
+		log.NewValue("channelID", incident.ChannelId),
+		log.NewValue("region", region.Name()),
+	)
+	return true
+}
@@ -0,0 +1,105 @@
+package reminder_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"hellper/internal/bot"
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/reminder"
+	"hellper/internal/workcalendar"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const calendarPolicy = `
+rules:
+  - status: open
+    severities: [0]
+    interval: 15m
+    message: "Update {{.Channel}}"
+  - status: open
+    severities: [2]
+    interval: 4h
+    message: "Update {{.Channel}}"
+    stop_when: [outside_business_hours]
+  - status: open
+    severities: [3]
+    interval: 24h
+    message: "Update {{.Channel}}"
+    stop_when: [quiet_hours]
+`
+
+// Every day is a day off in the off region, and no time is quiet in the always region
+const alwaysOffCalendar = `
+default_region: off
+products:
+  Checkout: always
+regions:
+  off:
+    timezone: UTC
+    weekends: [monday, tuesday, wednesday, thursday, friday, saturday, sunday]
+  always:
+    timezone: UTC
+    weekends: []
+`
+
+func TestEvaluateWorkCalendar(t *testing.T) {
+	table := []struct {
+		testName       string
+		incident       model.Incident
+		expectedNotify bool
+		expectedReason string
+	}{
+		{
+			testName:       "SEV0 ignores the calendar",
+			incident:       model.Incident{Status: model.StatusOpen, SeverityLevel: 0, Product: "Search"},
+			expectedNotify: true,
+			expectedReason: "the incident is open and no stop condition was met",
+		},
+		{
+			testName:       "SEV2 outside the business hours",
+			incident:       model.Incident{Status: model.StatusOpen, SeverityLevel: 2, Product: "Search"},
+			expectedReason: "it is outside the business hours of the off work calendar",
+		},
+		{
+			testName:       "SEV3 on quiet time",
+			incident:       model.Incident{Status: model.StatusOpen, SeverityLevel: 3, Product: "Search"},
+			expectedReason: "it is quiet time on the off work calendar",
+		},
+		{
+			testName:       "SEV3 on the region of the product",
+			incident:       model.Incident{Status: model.StatusOpen, SeverityLevel: 3, Product: "Checkout"},
+			expectedNotify: true,
+			expectedReason: "the incident is open and no stop condition was met",
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			var (
+				ctx            = context.Background()
+				loggerMock     = log.NewLoggerMock()
+				clientMock     = bot.NewClientMock()
+				repositoryMock = model.NewRepositoryMock()
+			)
+
+			loggerMock.On("Info", ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+			clientMock.On("ListPins", mock.AnythingOfType("string")).Return(lastPin(int((48*time.Hour).Seconds()), 0), nil, nil)
+			repositoryMock.On("ListReminders", ctx, f.incident.Id).Return(nil, nil)
+
+			policy, err := reminder.ParsePolicy([]byte(calendarPolicy))
+			assert.NoError(t, err)
+			policy.Calendar, err = workcalendar.Parse([]byte(alwaysOffCalendar))
+			assert.NoError(t, err)
+
+			decision := reminder.Evaluate(ctx, clientMock, loggerMock, repositoryMock, policy, f.incident)
+			assert.Equal(t, f.expectedNotify, decision.Notify)
+			assert.Equal(t, f.expectedReason, decision.Reason)
+		})
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"hellper/internal/bot"
-	"hellper/internal/config"
 	"hellper/internal/log"
 	"hellper/internal/model"
 	"strconv"
@@ -14,7 +13,29 @@ import (
 	"github.com/slack-go/slack"
 )
 
-func hasLastPin(ctx context.Context, client bot.Client, logger log.Logger, incident model.Incident) bool {
+func hasLastPin(ctx context.Context, client bot.Client, logger log.Logger, incident model.Incident, interval time.Duration) bool {
+	timeMessage, err := lastPinTime(ctx, client, logger, incident)
+	if err != nil {
+		return true
+	}
+
+	if timeMessage.After(time.Now().Add(-interval)) {
+		logger.Info(
+			ctx,
+			log.Trace(),
+			log.Action("do_not_notify"),
+			log.Reason("last_pin_time"),
+			log.NewValue("channelID", incident.ChannelId),
+			log.NewValue("channelName", incident.ChannelName),
+		)
+		return true
+	}
+
+	return false
+}
+
+// lastPinTime returns when the last message was pinned on the channel, the zero time when there is none
+func lastPinTime(ctx context.Context, client bot.Client, logger log.Logger, incident model.Incident) (time.Time, error) {
 	pin, err := bot.LastPin(client, incident.ChannelId)
 	if err != nil {
 		logger.Error(
@@ -25,47 +46,27 @@ func hasLastPin(ctx context.Context, client bot.Client, logger log.Logger, incid
 			log.NewValue("channelName", incident.ChannelName),
 			log.NewValue("error", err),
 		)
-		return true
+		return time.Time{}, err
 	}
 
-	if pin != (slack.Item{}) {
-		timeMessage, err := convertTimestamp(pin.Message.Msg.Timestamp)
-		if err != nil {
-			logger.Error(
-				ctx,
-				log.Trace(),
-				log.Action("convertTimestamp"),
-				log.NewValue("channelID", incident.ChannelId),
-				log.NewValue("channelName", incident.ChannelName),
-				log.NewValue("error", err),
-			)
-			return true
-		}
-
-		if timeMessage.After(time.Now().Add(-setRecurrence(incident))) {
-			logger.Info(
-				ctx,
-				log.Trace(),
-				log.Action("do_not_notify"),
-				log.Reason("last_pin_time"),
-				log.NewValue("channelID", incident.ChannelId),
-				log.NewValue("channelName", incident.ChannelName),
-			)
-			return true
-		}
+	if pin == (slack.Item{}) {
+		return time.Time{}, nil
 	}
 
-	return false
-}
-
-func setRecurrence(incident model.Incident) time.Duration {
-	switch incident.Status {
-	case model.StatusOpen:
-		return time.Duration(config.Env.ReminderOpenStatusSeconds) * time.Second
-	case model.StatusResolved:
-		return time.Duration(config.Env.ReminderResolvedStatusSeconds) * time.Second
+	timeMessage, err := convertTimestamp(pin.Message.Msg.Timestamp)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("convertTimestamp"),
+			log.NewValue("channelID", incident.ChannelId),
+			log.NewValue("channelName", incident.ChannelName),
+			log.NewValue("error", err),
+		)
+		return time.Time{}, err
 	}
-	return 0
+
+	return timeMessage, nil
 }
 
 func convertTimestamp(timestamp string) (time.Time, error) {
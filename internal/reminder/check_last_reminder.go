@@ -0,0 +1,41 @@
+package reminder
+
+import (
+	"context"
+	"time"
+
+	"hellper/internal/log"
+	"hellper/internal/model"
+)
+
+// hasLastReminder tells whether the rule already reminded the incident in the last interval of the rule
+func hasLastReminder(ctx context.Context, logger log.Logger, repository model.Repository, incident model.Incident, rule *Rule) bool {
+	reminders, err := repository.ListReminders(ctx, incident.Id)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("repository.ListReminders"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", incident.ChannelId),
+		)
+		return true
+	}
+
+	key := rule.key()
+	for _, reminder := range reminders {
+		if reminder.Rule == key && reminder.RemindedAt != nil && reminder.RemindedAt.After(time.Now().Add(-rule.Interval)) {
+			logger.Info(
+				ctx,
+				log.Trace(),
+				log.Action("do_not_notify"),
+				log.Reason("last_reminder_time"),
+				log.NewValue("channelID", incident.ChannelId),
+				log.NewValue("channelName", incident.ChannelName),
+			)
+			return true
+		}
+	}
+
+	return false
+}
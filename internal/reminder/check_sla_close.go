@@ -3,9 +3,9 @@ package reminder
 import (
 	"context"
 	"hellper/internal/bot"
-	"hellper/internal/config"
 	"hellper/internal/log"
 	"hellper/internal/model"
+	"hellper/internal/sla"
 	"time"
 )
 
@@ -14,17 +14,18 @@ func hasSLAClose(ctx context.Context, client bot.Client, logger log.Logger, inci
 		now := time.Now()
 		endTS := incident.EndTimestamp
 		diffHours := now.Sub(*endTS)
-		if int(diffHours.Hours()) <= config.Env.SLAHoursToClose {
+		slaToClose := sla.EnvTargets().Target(incident.SeverityLevel, sla.MetricClose)
+		if diffHours.Truncate(time.Hour) <= slaToClose {
 			logger.Info(
 				ctx,
 				log.Trace(),
 				log.Action("do_not_notify"),
-				log.Reason("SLAHoursToClose"),
+				log.Reason("SLAToClose"),
 				log.NewValue("channelID", incident.ChannelId),
 				log.NewValue("channelName", incident.ChannelName),
 				log.NewValue("incident.Status", incident.Status),
 				log.NewValue("incident.EndTimestamp", incident.EndTimestamp),
-				log.NewValue("SLAHoursToClose", config.Env.SLAHoursToClose),
+				log.NewValue("SLAToClose", slaToClose),
 				log.NewValue("diffHours", diffHours),
 			)
 			return true
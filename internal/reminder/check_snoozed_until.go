@@ -2,9 +2,13 @@ package reminder
 
 import (
 	"context"
+	"database/sql"
+	"hellper/internal/bot"
 	"hellper/internal/log"
 	"hellper/internal/model"
 	"time"
+
+	"github.com/slack-go/slack"
 )
 
 func hasSnoozedUntil(ctx context.Context, logger log.Logger, incident model.Incident) bool {
@@ -24,3 +28,62 @@ func hasSnoozedUntil(ctx context.Context, logger log.Logger, incident model.Inci
 
 	return false
 }
+
+// AnnounceSnoozeExpiry tells the incident channel its notifications pause expired and clears it, so it is announced once
+func AnnounceSnoozeExpiry(ctx context.Context, client bot.Client, logger log.Logger, repository model.Repository, incident model.Incident, now time.Time) bool {
+	if !incident.SnoozedUntil.Valid || incident.SnoozedUntil.Time.After(now) {
+		return false
+	}
+
+	snoozes, err := repository.ListSnoozes(ctx, incident.Id)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("repository.ListSnoozes"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", incident.ChannelId),
+		)
+	}
+
+	incident.SnoozedUntil = sql.NullTime{}
+	err = repository.PauseNotifyIncident(ctx, &incident)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("repository.PauseNotifyIncident"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", incident.ChannelId),
+		)
+		return false
+	}
+
+	_, _, err = client.PostMessage(incident.ChannelId, slack.MsgOptionText(snoozeExpiryMessage(snoozes), false))
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("client.PostMessage"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", incident.ChannelId),
+		)
+		return false
+	}
+
+	return true
+}
+
+func snoozeExpiryMessage(snoozes []model.Snooze) string {
+	text := ":alarm_clock: The pause of the Hellper notifications has expired, the reminders of this incident are back."
+	if len(snoozes) == 0 {
+		return text
+	}
+
+	last := snoozes[len(snoozes)-1]
+	text += " It was set by <@" + last.SnoozedBy + ">"
+	if last.Reason != "" {
+		text += " for the following reason:\n```\n" + last.Reason + "\n```"
+	}
+	return text
+}
@@ -0,0 +1,69 @@
+package reminder_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"hellper/internal/bot"
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/reminder"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAnnounceSnoozeExpiry(t *testing.T) {
+	now := time.Now()
+
+	table := []struct {
+		testName          string
+		snoozedUntil      sql.NullTime
+		expectedAnnounced bool
+	}{
+		{
+			testName:          "Expired pause",
+			snoozedUntil:      sql.NullTime{Time: now.Add(-time.Minute), Valid: true},
+			expectedAnnounced: true,
+		},
+		{
+			testName:     "Running pause",
+			snoozedUntil: sql.NullTime{Time: now.Add(time.Hour), Valid: true},
+		},
+		{
+			testName: "Never paused",
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			var (
+				ctx            = context.Background()
+				loggerMock     = log.NewLoggerMock()
+				clientMock     = bot.NewClientMock()
+				repositoryMock = model.NewRepositoryMock()
+			)
+
+			clientMock.On("PostMessage", "C1", mock.AnythingOfType("[]slack.MsgOption")).Return("", "", nil)
+			repositoryMock.On("ListSnoozes", ctx, int64(42)).Return([]model.Snooze{{SnoozedBy: "U1", Reason: "Waiting for the vendor"}}, nil)
+			repositoryMock.On("PauseNotifyIncident", ctx, mock.AnythingOfType("*model.Incident")).Return(nil)
+
+			incident := model.Incident{Id: 42, ChannelId: "C1", SnoozedUntil: f.snoozedUntil}
+			announced := reminder.AnnounceSnoozeExpiry(ctx, clientMock, loggerMock, repositoryMock, incident, now)
+			assert.Equal(t, f.expectedAnnounced, announced)
+
+			if !f.expectedAnnounced {
+				repositoryMock.AssertNotCalled(t, "PauseNotifyIncident", mock.Anything, mock.Anything)
+				clientMock.AssertNotCalled(t, "PostMessage", mock.Anything, mock.Anything)
+				return
+			}
+
+			cleared := repositoryMock.Calls[1].Arguments.Get(1).(*model.Incident)
+			assert.False(t, cleared.SnoozedUntil.Valid)
+			clientMock.AssertCalled(t, "PostMessage", "C1", mock.Anything)
+		})
+	}
+}
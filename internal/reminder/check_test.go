@@ -43,6 +43,7 @@ func (f *checkReminderFixture) setup(t *testing.T) {
 
 	loggerMock.On("Info", f.ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
 	clientMock.On("ListPins", mock.AnythingOfType("string")).Return(f.lastPin, nil, nil)
+	repositoryMock.On("ListReminders", f.ctx, mock.AnythingOfType("int64")).Return(nil, nil)
 
 	f.mockLogger = loggerMock
 	f.mockClient = clientMock
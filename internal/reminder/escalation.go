@@ -0,0 +1,86 @@
+package reminder
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"hellper/internal/bot"
+	"hellper/internal/log"
+	"hellper/internal/model"
+)
+
+// escalate picks the next step of the escalation chain of the rule, the chain restarts after a status update or an acknowledgement
+func escalate(ctx context.Context, client bot.Client, logger log.Logger, repository model.Repository, incident model.Incident, rule *Rule) Decision {
+	lastPin, err := lastPinTime(ctx, client, logger, incident)
+	if err != nil {
+		return Decision{Rule: rule, Reason: "the pinned messages could not be read"}
+	}
+
+	escalations, err := repository.ListEscalations(ctx, incident.Id)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("repository.ListEscalations"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", incident.ChannelId),
+		)
+		return Decision{Rule: rule, Reason: "the escalations could not be read"}
+	}
+
+	quietSince := lastPin
+	if incident.StartTimestamp != nil && incident.StartTimestamp.After(quietSince) {
+		quietSince = *incident.StartTimestamp
+	}
+	for _, escalation := range escalations {
+		if escalation.AcknowledgedAt != nil && escalation.AcknowledgedAt.After(quietSince) {
+			quietSince = *escalation.AcknowledgedAt
+		}
+	}
+
+	next := nextStep(escalations, lastPin)
+	if next > len(rule.Escalation) {
+		return Decision{
+			Rule:   rule,
+			Reason: "the escalation chain ended at step " + strconv.Itoa(len(rule.Escalation)) + ", waiting for an acknowledgement or a status update",
+		}
+	}
+
+	quiet := time.Since(quietSince).Round(time.Minute)
+	after := rule.stepAfter(next)
+	if time.Since(quietSince) < after {
+		return Decision{
+			Rule:   rule,
+			Reason: "escalation step " + strconv.Itoa(next) + " is due after " + after.String() + " without update, the last one was " + quiet.String() + " ago",
+		}
+	}
+
+	return Decision{
+		Notify: true,
+		Rule:   rule,
+		Step:   next,
+		Reason: "no status update nor acknowledgement for " + quiet.String() + ", escalation step " + strconv.Itoa(next),
+	}
+}
+
+// nextStep follows the steps sent after the last status update and not acknowledged yet
+func nextStep(escalations []model.Escalation, lastPin time.Time) int {
+	next := 0
+	for _, escalation := range escalations {
+		if escalation.AcknowledgedAt != nil || escalation.EscalatedAt == nil || !escalation.EscalatedAt.After(lastPin) {
+			continue
+		}
+		if escalation.Step >= next {
+			next = escalation.Step + 1
+		}
+	}
+	return next
+}
+
+func (r *Rule) stepAfter(step int) time.Duration {
+	if step == 0 {
+		return r.Interval
+	}
+	return r.Escalation[step-1].After
+}
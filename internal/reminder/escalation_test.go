@@ -0,0 +1,155 @@
+package reminder_test
+
+import (
+	"context"
+	"fmt"
+	"hellper/internal/bot"
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/reminder"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const escalationPolicy = `
+rules:
+  - status: open
+    interval: 15m
+    message: "Update {{.Channel}}"
+    stop_when: [snoozed]
+    escalation:
+      - after: 30m
+        message: "{{.Commander}}, nobody updated {{.Channel}} for {{.Interval}}"
+        targets: [commander]
+      - after: 1h
+        targets: [usergroup:S0SUPPORT]
+`
+
+type escalationFixture struct {
+	testName       string
+	pinAgo         time.Duration
+	startedAgo     time.Duration
+	escalations    []model.Escalation
+	expectedNotify bool
+	expectedStep   int
+	expectedReason string
+
+	ctx            context.Context
+	mockLogger     log.Logger
+	mockClient     bot.Client
+	mockRepository *model.RepositoryMock
+	incident       model.Incident
+	policy         reminder.Policy
+}
+
+func (f *escalationFixture) setup(t *testing.T) {
+	var (
+		loggerMock     = log.NewLoggerMock()
+		clientMock     = bot.NewClientMock()
+		repositoryMock = model.NewRepositoryMock()
+		err            error
+	)
+
+	f.ctx = context.Background()
+
+	loggerMock.On("Info", f.ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+	if f.pinAgo > 0 {
+		clientMock.On("ListPins", "C1").Return(lastPin(int(f.pinAgo.Seconds()), 0), nil, nil)
+	} else {
+		clientMock.On("ListPins", "C1").Return(nil, nil, nil)
+	}
+	repositoryMock.On("ListEscalations", f.ctx, int64(42)).Return(f.escalations, nil)
+
+	started := time.Now().Add(-f.startedAgo)
+	f.incident = model.Incident{Id: 42, ChannelId: "C1", Status: model.StatusOpen, StartTimestamp: &started}
+
+	f.policy, err = reminder.ParsePolicy([]byte(escalationPolicy))
+	assert.NoError(t, err)
+
+	f.mockLogger = loggerMock
+	f.mockClient = clientMock
+	f.mockRepository = repositoryMock
+}
+
+func ago(d time.Duration) *time.Time {
+	t := time.Now().Add(-d)
+	return &t
+}
+
+func TestEscalation(t *testing.T) {
+	table := []escalationFixture{
+		{
+			testName:       "Quiet for less than the interval",
+			startedAgo:     10 * time.Minute,
+			expectedReason: "escalation step 0 is due after 15m0s without update, the last one was 10m0s ago",
+		},
+		{
+			testName:       "Reminds the channel first",
+			startedAgo:     20 * time.Minute,
+			expectedNotify: true,
+			expectedStep:   0,
+			expectedReason: "no status update nor acknowledgement for 20m0s, escalation step 0",
+		},
+		{
+			testName:       "Escalates to the commander",
+			startedAgo:     40 * time.Minute,
+			escalations:    []model.Escalation{{Step: 0, EscalatedAt: ago(25 * time.Minute)}},
+			expectedNotify: true,
+			expectedStep:   1,
+			expectedReason: "no status update nor acknowledgement for 40m0s, escalation step 1",
+		},
+		{
+			testName:   "Waits for the support group step",
+			startedAgo: 45 * time.Minute,
+			escalations: []model.Escalation{
+				{Step: 0, EscalatedAt: ago(30 * time.Minute)},
+				{Step: 1, EscalatedAt: ago(15 * time.Minute)},
+			},
+			expectedReason: "escalation step 2 is due after 1h0m0s without update, the last one was 45m0s ago",
+		},
+		{
+			testName:   "Chain exhausted",
+			startedAgo: 3 * time.Hour,
+			escalations: []model.Escalation{
+				{Step: 0, EscalatedAt: ago(150 * time.Minute)},
+				{Step: 1, EscalatedAt: ago(140 * time.Minute)},
+				{Step: 2, EscalatedAt: ago(110 * time.Minute)},
+			},
+			expectedReason: "the escalation chain ended at step 2, waiting for an acknowledgement or a status update",
+		},
+		{
+			testName:   "Acknowledgement restarts the chain",
+			startedAgo: 3 * time.Hour,
+			escalations: []model.Escalation{
+				{Step: 0, EscalatedAt: ago(150 * time.Minute), AcknowledgedAt: ago(20 * time.Minute)},
+				{Step: 1, EscalatedAt: ago(140 * time.Minute), AcknowledgedAt: ago(20 * time.Minute)},
+			},
+			expectedNotify: true,
+			expectedStep:   0,
+			expectedReason: "no status update nor acknowledgement for 20m0s, escalation step 0",
+		},
+		{
+			testName:   "Status update restarts the chain",
+			startedAgo: 3 * time.Hour,
+			pinAgo:     10 * time.Minute,
+			escalations: []model.Escalation{
+				{Step: 0, EscalatedAt: ago(150 * time.Minute)},
+				{Step: 1, EscalatedAt: ago(140 * time.Minute)},
+			},
+			expectedReason: "escalation step 0 is due after 15m0s without update, the last one was 10m0s ago",
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			f.setup(t)
+			decision := reminder.Evaluate(f.ctx, f.mockClient, f.mockLogger, f.mockRepository, f.policy, f.incident)
+			assert.Equal(t, f.expectedNotify, decision.Notify)
+			assert.Equal(t, f.expectedStep, decision.Step)
+			assert.Equal(t, f.expectedReason, decision.Reason)
+		})
+	}
+}
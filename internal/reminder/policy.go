@@ -0,0 +1,309 @@
+package reminder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"hellper/internal/config"
+	"hellper/internal/model"
+	"hellper/internal/workcalendar"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Stop conditions of a reminder rule
+const (
+	StopSnoozed        = "snoozed"
+	StopRecentUpdate   = "recent_update"
+	StopWithinCloseSLA = "within_close_sla"
+	// StopQuietHours and StopOutsideBusinessHours follow the work calendar of the incident product
+	StopQuietHours           = "quiet_hours"
+	StopOutsideBusinessHours = "outside_business_hours"
+)
+
+// Targets of a reminder rule, roles, user groups and other channels are written as role:<role>, usergroup:<id> and channel:<id>
+const (
+	TargetChannel   = "channel"
+	TargetCommander = "commander"
+	TargetRole      = "role"
+	TargetUserGroup = "usergroup"
+)
+
+// ErrInvalidPolicy is returned when a reminder policy can not be parsed
+var ErrInvalidPolicy = errors.New("invalid reminder policy")
+
+// Policy lists the reminder rules, the first rule matching the status and the severity of an incident is used
+type Policy struct {
+	Rules []Rule `yaml:"rules"`
+	// Calendar tells the quiet and business hours of the rules stopping on them
+	Calendar workcalendar.Calendar `yaml:"-"`
+}
+
+// Rule says how often and to whom the incidents of a status and severity are reminded
+type Rule struct {
+	Status     string        `yaml:"status"`
+	Severities []int64       `yaml:"severities"`
+	Interval   time.Duration `yaml:"interval"`
+	Message    string        `yaml:"message"`
+	Targets    []string      `yaml:"targets"`
+	StopWhen   []string      `yaml:"stop_when"`
+	Escalation []Step        `yaml:"escalation"`
+
+	message *template.Template
+}
+
+// Step is a step of the escalation chain of a rule, sent when the incident has no update nor acknowledgement for After
+type Step struct {
+	After   time.Duration `yaml:"after"`
+	Message string        `yaml:"message"`
+	Targets []string      `yaml:"targets"`
+
+	message *template.Template
+}
+
+// Target is a parsed reminder target
+type Target struct {
+	Kind string
+	ID   string
+}
+
+type messageData struct {
+	Title     string
+	Status    string
+	Severity  string
+	Product   string
+	Channel   string
+	Commander string
+	Interval  time.Duration
+}
+
+// DefaultPolicy reproduces the reminders configured by the HELLPER_REMINDER_* variables
+func DefaultPolicy() Policy {
+	return Policy{
+		Rules: []Rule{
+			{
+				Status:   model.StatusOpen,
+				Interval: time.Duration(config.Env.ReminderOpenStatusSeconds) * time.Second,
+				Message:  config.Env.ReminderOpenNotifyMsg,
+				Targets:  []string{TargetChannel},
+				StopWhen: []string{StopSnoozed, StopRecentUpdate},
+			},
+			{
+				Status:   model.StatusResolved,
+				Interval: time.Duration(config.Env.ReminderResolvedStatusSeconds) * time.Second,
+				Message:  config.Env.ReminderResolvedNotifyMsg,
+				Targets:  []string{TargetChannel},
+				StopWhen: []string{StopSnoozed, StopWithinCloseSLA},
+			},
+		},
+	}
+}
+
+// LoadPolicy reads a YAML reminder policy file
+func LoadPolicy(path string) (Policy, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Policy{}, err
+	}
+	return ParsePolicy(content)
+}
+
+// ParsePolicy parses and validates a YAML reminder policy
+func ParsePolicy(content []byte) (Policy, error) {
+	var policy Policy
+	err := yaml.Unmarshal(content, &policy)
+	if err != nil {
+		return Policy{}, fmt.Errorf("%w: %s", ErrInvalidPolicy, err.Error())
+	}
+
+	for i := range policy.Rules {
+		err = policy.Rules[i].validate()
+		if err != nil {
+			return Policy{}, fmt.Errorf("%w: rule %d: %s", ErrInvalidPolicy, i+1, err.Error())
+		}
+	}
+
+	return policy, nil
+}
+
+// Match returns the first rule of the status and severity of the incident, or nil when the incident is not reminded
+func (p Policy) Match(incident model.Incident) *Rule {
+	for i, rule := range p.Rules {
+		if rule.matches(incident) {
+			return &p.Rules[i]
+		}
+	}
+	return nil
+}
+
+// Render executes the message template of the rule for the incident, messages of the default policy are sent as they are
+func (r *Rule) Render(incident model.Incident) (string, error) {
+	return render(r.Message, r.message, incident, r.Interval)
+}
+
+// ParsedTargets returns the targets of the rule, the incident channel when none was set
+func (r *Rule) ParsedTargets() []Target {
+	return parseTargets(r.Targets)
+}
+
+func render(text string, message *template.Template, incident model.Incident, interval time.Duration) (string, error) {
+	if message == nil {
+		return text, nil
+	}
+
+	var msg bytes.Buffer
+	err := message.Execute(&msg, messageData{
+		Title:     incident.Title,
+		Status:    incident.Status,
+		Severity:  "SEV" + strconv.FormatInt(incident.SeverityLevel, 10),
+		Product:   incident.Product,
+		Channel:   "<#" + incident.ChannelId + ">",
+		Commander: "<@" + incident.CommanderId + ">",
+		Interval:  interval,
+	})
+	if err != nil {
+		return "", err
+	}
+	return msg.String(), nil
+}
+
+func parseTargets(raw []string) []Target {
+	if len(raw) == 0 {
+		return []Target{{Kind: TargetChannel}}
+	}
+
+	targets := make([]Target, 0, len(raw))
+	for _, target := range raw {
+		parts := strings.SplitN(target, ":", 2)
+		if len(parts) == 2 {
+			targets = append(targets, Target{Kind: parts[0], ID: parts[1]})
+		} else {
+			targets = append(targets, Target{Kind: parts[0]})
+		}
+	}
+	return targets
+}
+
+func (r *Rule) stopsWhen(condition string) bool {
+	for _, stop := range r.StopWhen {
+		if stop == condition {
+			return true
+		}
+	}
+	return false
+}
+
+// key identifies the rule on the reminders it sent, by the status and the severities it matches
+func (r *Rule) key() string {
+	if len(r.Severities) == 0 {
+		return r.Status
+	}
+	severities := make([]string, 0, len(r.Severities))
+	for _, severity := range r.Severities {
+		severities = append(severities, "SEV"+strconv.FormatInt(severity, 10))
+	}
+	return r.Status + " " + strings.Join(severities, ",")
+}
+
+func (r *Rule) escalates() bool {
+	return len(r.Escalation) > 0
+}
+
+func (r Rule) matches(incident model.Incident) bool {
+	if r.Status != incident.Status {
+		return false
+	}
+	if len(r.Severities) == 0 {
+		return true
+	}
+	for _, severity := range r.Severities {
+		if severity == incident.SeverityLevel {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) validate() error {
+	if r.Status != model.StatusOpen && r.Status != model.StatusResolved {
+		return errors.New("status must be open or resolved, got " + strconv.Quote(r.Status))
+	}
+	if r.Interval <= 0 {
+		return errors.New("interval must be a positive duration, e.g. 15m")
+	}
+	if r.Message == "" {
+		return errors.New("message is required")
+	}
+
+	message, err := template.New("message").Parse(r.Message)
+	if err != nil {
+		return err
+	}
+	r.message = message
+
+	err = validateTargets(r.Targets)
+	if err != nil {
+		return err
+	}
+
+	for _, stop := range r.StopWhen {
+		switch stop {
+		case StopSnoozed, StopRecentUpdate, StopWithinCloseSLA, StopQuietHours, StopOutsideBusinessHours:
+		default:
+			return errors.New("unknown stop condition " + strconv.Quote(stop))
+		}
+	}
+
+	// Each step waits longer than the previous one, the first reminder of the chain is the rule itself
+	after := r.Interval
+	for i := range r.Escalation {
+		step := &r.Escalation[i]
+		if step.After <= after {
+			return fmt.Errorf("escalation step %d must come after %s", i+1, after)
+		}
+		after = step.After
+
+		if step.Message != "" {
+			step.message, err = template.New("message").Parse(step.Message)
+			if err != nil {
+				return fmt.Errorf("escalation step %d: %s", i+1, err.Error())
+			}
+		}
+
+		err = validateTargets(step.Targets)
+		if err != nil {
+			return fmt.Errorf("escalation step %d: %s", i+1, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func validateTargets(targets []string) error {
+	for _, target := range parseTargets(targets) {
+		switch target.Kind {
+		case TargetChannel:
+		case TargetCommander:
+			if target.ID != "" {
+				return errors.New("target " + target.Kind + " takes no value")
+			}
+		case TargetRole:
+			if !model.IsIncidentRole(target.ID) {
+				return errors.New("unknown role " + strconv.Quote(target.ID))
+			}
+		case TargetUserGroup:
+			if target.ID == "" {
+				return errors.New("usergroup target needs a user group ID, e.g. usergroup:S0123ABC")
+			}
+		default:
+			return errors.New("unknown target " + strconv.Quote(target.Kind))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package reminder_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hellper/internal/bot"
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/reminder"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const severityPolicy = `
+rules:
+  - status: open
+    severities: [0]
+    interval: 15m
+    message: "{{.Severity}} {{.Title}}: update {{.Channel}} every {{.Interval}}"
+    targets: [channel, commander, role:comms_lead]
+    stop_when: [snoozed, recent_update]
+  - status: open
+    severities: [3]
+    interval: 24h
+    message: "Daily update of {{.Title}}"
+    stop_when: [recent_update]
+  - status: resolved
+    interval: 24h
+    message: "Close {{.Title}}"
+    targets: [usergroup:S0123ABC]
+    stop_when: [within_close_sla]
+`
+
+func TestParsePolicy(t *testing.T) {
+	table := []struct {
+		testName     string
+		content      string
+		expectError  bool
+		errorMessage string
+	}{
+		{testName: "Severity policy", content: severityPolicy},
+		{testName: "Empty policy", content: ""},
+		{
+			testName:     "Invalid YAML",
+			content:      "rules: [",
+			expectError:  true,
+			errorMessage: "invalid reminder policy: yaml: line 1: did not find expected node content",
+		},
+		{
+			testName:     "Closed status",
+			content:      "rules:\n  - status: closed\n    interval: 1h\n    message: hi",
+			expectError:  true,
+			errorMessage: `invalid reminder policy: rule 1: status must be open or resolved, got "closed"`,
+		},
+		{
+			testName:     "Without interval",
+			content:      "rules:\n  - status: open\n    message: hi",
+			expectError:  true,
+			errorMessage: "invalid reminder policy: rule 1: interval must be a positive duration, e.g. 15m",
+		},
+		{
+			testName:     "Without message",
+			content:      "rules:\n  - status: open\n    interval: 1h",
+			expectError:  true,
+			errorMessage: "invalid reminder policy: rule 1: message is required",
+		},
+		{
+			testName:     "Unknown role",
+			content:      "rules:\n  - status: open\n    interval: 1h\n    message: hi\n    targets: [role:boss]",
+			expectError:  true,
+			errorMessage: `invalid reminder policy: rule 1: unknown role "boss"`,
+		},
+		{
+			testName:     "Unknown target",
+			content:      "rules:\n  - status: open\n    interval: 1h\n    message: hi\n    targets: [everyone]",
+			expectError:  true,
+			errorMessage: `invalid reminder policy: rule 1: unknown target "everyone"`,
+		},
+		{
+			testName:     "Escalation before the interval",
+			content:      "rules:\n  - status: open\n    interval: 1h\n    message: hi\n    escalation:\n      - after: 30m",
+			expectError:  true,
+			errorMessage: "invalid reminder policy: rule 1: escalation step 1 must come after 1h0m0s",
+		},
+		{
+			testName:     "Escalation to an unknown target",
+			content:      "rules:\n  - status: open\n    interval: 1h\n    message: hi\n    escalation:\n      - after: 2h\n        targets: [pager]",
+			expectError:  true,
+			errorMessage: `invalid reminder policy: rule 1: escalation step 1: unknown target "pager"`,
+		},
+		{
+			testName:     "Unknown stop condition",
+			content:      "rules:\n  - status: open\n    interval: 1h\n    message: hi\n    stop_when: [weekend]",
+			expectError:  true,
+			errorMessage: `invalid reminder policy: rule 1: unknown stop condition "weekend"`,
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			_, err := reminder.ParsePolicy([]byte(f.content))
+			if f.expectError {
+				assert.EqualError(t, err, f.errorMessage)
+				assert.True(t, errors.Is(err, reminder.ErrInvalidPolicy))
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestLoadExamplePolicy(t *testing.T) {
+	policy, err := reminder.LoadPolicy("../../reminder-policy.example.yaml")
+	assert.NoError(t, err)
+
+	sev0 := policy.Match(model.Incident{Status: model.StatusOpen, SeverityLevel: 0})
+	sev3 := policy.Match(model.Incident{Status: model.StatusOpen, SeverityLevel: 3})
+	if assert.NotNil(t, sev0) && assert.NotNil(t, sev3) {
+		assert.Equal(t, 15*time.Minute, sev0.Interval)
+		assert.Equal(t, 24*time.Hour, sev3.Interval)
+	}
+}
+
+func TestRuleRender(t *testing.T) {
+	policy, err := reminder.ParsePolicy([]byte(severityPolicy))
+	assert.NoError(t, err)
+
+	rule := policy.Match(model.Incident{Status: model.StatusOpen, SeverityLevel: 0})
+	msg, err := rule.Render(model.Incident{Title: "Checkout down", ChannelId: "C1", SeverityLevel: 0})
+	assert.NoError(t, err)
+	assert.Equal(t, "SEV0 Checkout down: update <#C1> every 15m0s", msg)
+
+	assert.Equal(t, []reminder.Target{
+		{Kind: reminder.TargetChannel},
+		{Kind: reminder.TargetCommander},
+		{Kind: reminder.TargetRole, ID: model.RoleCommsLead},
+	}, rule.ParsedTargets())
+}
+
+type evaluateFixture struct {
+	testName       string
+	incident       model.Incident
+	pinAgo         time.Duration
+	expectedNotify bool
+	expectedReason string
+
+	ctx        context.Context
+	mockLogger log.Logger
+	mockClient bot.Client
+	policy     reminder.Policy
+}
+
+func (f *evaluateFixture) setup(t *testing.T) {
+	var (
+		loggerMock = log.NewLoggerMock()
+		clientMock = bot.NewClientMock()
+		err        error
+	)
+
+	f.ctx = context.Background()
+
+	loggerMock.On("Info", f.ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+	clientMock.On("ListPins", mock.AnythingOfType("string")).Return(lastPin(int(f.pinAgo.Seconds()), 0), nil, nil)
+
+	f.policy, err = reminder.ParsePolicy([]byte(severityPolicy))
+	assert.NoError(t, err)
+
+	f.mockLogger = loggerMock
+	f.mockClient = clientMock
+}
+
+func TestEvaluate(t *testing.T) {
+	table := []evaluateFixture{
+		{
+			testName:       "SEV0 without update for 20 minutes",
+			incident:       model.Incident{Status: model.StatusOpen, SeverityLevel: 0},
+			pinAgo:         20 * time.Minute,
+			expectedNotify: true,
+			expectedReason: "no status update in the last 15m0s",
+		},
+		{
+			testName:       "SEV0 updated 10 minutes ago",
+			incident:       model.Incident{Status: model.StatusOpen, SeverityLevel: 0},
+			pinAgo:         10 * time.Minute,
+			expectedReason: "the status was updated in the last 15m0s",
+		},
+		{
+			testName:       "SEV3 updated 2 hours ago",
+			incident:       model.Incident{Status: model.StatusOpen, SeverityLevel: 3},
+			pinAgo:         2 * time.Hour,
+			expectedReason: "the status was updated in the last 24h0m0s",
+		},
+		{
+			testName:       "SEV3 without update for 2 days",
+			incident:       model.Incident{Status: model.StatusOpen, SeverityLevel: 3},
+			pinAgo:         48 * time.Hour,
+			expectedNotify: true,
+			expectedReason: "no status update in the last 24h0m0s",
+		},
+		{
+			testName: "SEV0 snoozed",
+			incident: model.Incident{
+				Status:        model.StatusOpen,
+				SeverityLevel: 0,
+				SnoozedUntil:  sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true},
+			},
+			pinAgo:         20 * time.Minute,
+			expectedReason: "the reminders of this incident are snoozed",
+		},
+		{
+			testName:       "SEV3 ignores the snooze",
+			incident:       model.Incident{Status: model.StatusOpen, SeverityLevel: 3, SnoozedUntil: sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true}},
+			pinAgo:         48 * time.Hour,
+			expectedNotify: true,
+			expectedReason: "no status update in the last 24h0m0s",
+		},
+		{
+			testName:       "Severity without rule",
+			incident:       model.Incident{Status: model.StatusOpen, SeverityLevel: 2},
+			expectedReason: "no reminder rule for status open and severity SEV2",
+		},
+		{
+			testName:       "Resolved within the SLA to close",
+			incident:       model.Incident{Status: model.StatusResolved, EndTimestamp: &[]time.Time{time.Now().AddDate(0, 0, -1)}[0]},
+			expectedReason: "the incident is still within the SLA to close",
+		},
+		{
+			testName:       "Resolved after the SLA to close",
+			incident:       model.Incident{Status: model.StatusResolved, EndTimestamp: &[]time.Time{time.Now().AddDate(0, 0, -8)}[0]},
+			expectedNotify: true,
+			expectedReason: "the incident is resolved and no stop condition was met",
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			f.setup(t)
+			repositoryMock := model.NewRepositoryMock()
+			repositoryMock.On("ListReminders", f.ctx, f.incident.Id).Return(nil, nil)
+
+			decision := reminder.Evaluate(f.ctx, f.mockClient, f.mockLogger, repositoryMock, f.policy, f.incident)
+			assert.Equal(t, f.expectedNotify, decision.Notify)
+			assert.Equal(t, f.expectedReason, decision.Reason)
+		})
+	}
+}
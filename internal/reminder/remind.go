@@ -0,0 +1,102 @@
+package reminder
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"hellper/internal/bot"
+	"hellper/internal/log"
+	"hellper/internal/model"
+
+	"github.com/slack-go/slack"
+)
+
+// RemindIncidents sends the reminders due by the policy to the active incidents of the status, or of any status with "all"
+func RemindIncidents(ctx context.Context, client bot.Client, logger log.Logger, repository model.Repository, policy Policy, status string) error {
+	incidents, err := repository.ListActiveIncidents(ctx)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("repository.ListActiveIncidents"),
+			log.Reason(err.Error()),
+		)
+		return err
+	}
+
+	for _, incident := range incidents {
+		if status != "all" && status != incident.Status {
+			continue
+		}
+
+		AnnounceSnoozeExpiry(ctx, client, logger, repository, incident, time.Now())
+
+		decision := Evaluate(ctx, client, logger, repository, policy, incident)
+		if !decision.Notify {
+			logger.Info(ctx, log.Trace(), log.Action("do_not_notify"), log.Reason(decision.Reason), log.NewValue("channelID", incident.ChannelId))
+			continue
+		}
+
+		msg, err := decision.Render(incident)
+		if err != nil {
+			logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("rule.Render"),
+				log.Reason(err.Error()),
+				log.NewValue("channelID", incident.ChannelId),
+			)
+			continue
+		}
+
+		logger.Info(ctx, log.Trace(), log.Action("notify_job"), log.Reason(decision.Reason), log.NewValue("channelID", incident.ChannelId))
+		Deliver(ctx, client, logger, repository, incident, decision, msg)
+	}
+
+	return nil
+}
+
+// SendReport posts the report of the active incidents of the status to the channel
+func SendReport(ctx context.Context, client bot.Client, logger log.Logger, repository model.Repository, channelID string, status string) error {
+	incidents, err := repository.ListActiveIncidents(ctx)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("repository.ListActiveIncidents"),
+			log.Reason(err.Error()),
+		)
+		return err
+	}
+
+	_, _, err = client.PostMessage(channelID, slack.MsgOptionText(Report(incidents, status), false))
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("client.PostMessage"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", channelID),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// Report lists the active incidents of the status, or of any status with "all"
+func Report(incidents []model.Incident, status string) string {
+	var report strings.Builder
+	report.WriteString(":mega: *Incident Reporting:*\n")
+
+	for _, incident := range incidents {
+		if status == incident.Status || status == "all" {
+			report.WriteString("*<#" + incident.ChannelId + ">* - ")
+			report.WriteString("Status: `" + incident.Status + "` - ")
+			report.WriteString("Commander: <@" + incident.CommanderId + ">\n")
+		}
+	}
+
+	return report.String()
+}
@@ -0,0 +1,78 @@
+package reminder_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hellper/internal/bot"
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/reminder"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const intervalPolicy = `
+rules:
+  - status: open
+    severities: [1, 2]
+    interval: 1h
+    message: "Update {{.Channel}}"
+`
+
+// reminderStore keeps the reminders recorded, as the database does
+type reminderStore struct {
+	*model.RepositoryMock
+	reminders []model.Reminder
+}
+
+func (s *reminderStore) InsertReminder(ctx context.Context, sent *model.Reminder) error {
+	now := time.Now()
+	sent.RemindedAt = &now
+	s.reminders = append(s.reminders, *sent)
+	return nil
+}
+
+func (s *reminderStore) ListReminders(ctx context.Context, incidentID int64) ([]model.Reminder, error) {
+	return s.reminders, nil
+}
+
+func TestRemindIncidentsInterval(t *testing.T) {
+	var (
+		ctx            = context.Background()
+		loggerMock     = log.NewLoggerMock()
+		clientMock     = bot.NewClientMock()
+		repositoryMock = model.NewRepositoryMock()
+		store          = &reminderStore{RepositoryMock: repositoryMock}
+		incident       = model.Incident{Id: 42, ChannelId: "C1", Status: model.StatusOpen, SeverityLevel: 1}
+	)
+
+	policy, err := reminder.ParsePolicy([]byte(intervalPolicy))
+	assert.NoError(t, err)
+
+	loggerMock.On("Info", ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+	clientMock.On("PostMessage", "C1", mock.AnythingOfType("[]slack.MsgOption")).Return("", "", nil)
+	repositoryMock.On("ListActiveIncidents").Return([]model.Incident{incident}, nil)
+
+	// the second tick runs inside the interval of the reminder sent by the first one
+	for tick := 0; tick < 2; tick++ {
+		err = reminder.RemindIncidents(ctx, clientMock, loggerMock, store, policy, "all")
+		assert.NoError(t, err)
+	}
+
+	clientMock.AssertNumberOfCalls(t, "PostMessage", 1)
+	if assert.Len(t, store.reminders, 1) {
+		assert.Equal(t, int64(42), store.reminders[0].IncidentId)
+		assert.Equal(t, "open SEV1,SEV2", store.reminders[0].Rule)
+	}
+
+	// a reminder older than the interval lets the rule remind again
+	hourAgo := time.Now().Add(-61 * time.Minute)
+	store.reminders[0].RemindedAt = &hourAgo
+
+	err = reminder.RemindIncidents(ctx, clientMock, loggerMock, store, policy, "all")
+	assert.NoError(t, err)
+	clientMock.AssertNumberOfCalls(t, "PostMessage", 2)
+}
@@ -0,0 +1,128 @@
+package reminder
+
+import (
+	"context"
+	"hellper/internal/bot"
+	"hellper/internal/log"
+	"hellper/internal/model"
+
+	"github.com/slack-go/slack"
+)
+
+// AcknowledgeCallbackID is the callback of the button that acknowledges an escalation
+const AcknowledgeCallbackID = "inc-ack"
+
+// Deliver sends the reminder of the decision to its targets and records it, so the rule waits for its interval before the next one.
+// The steps of an escalation chain are recorded as escalations and carry an acknowledge button
+func Deliver(ctx context.Context, client bot.Client, logger log.Logger, repository model.Repository, incident model.Incident, decision Decision, msg string) error {
+	if !decision.Rule.escalates() {
+		sendErr := Send(ctx, client, logger, repository, incident, decision.Targets(), msg)
+
+		// The reminder is recorded even when a target failed, so it is not sent again before the interval
+		err := repository.InsertReminder(ctx, &model.Reminder{IncidentId: incident.Id, Rule: decision.Rule.key()})
+		if err != nil {
+			logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("repository.InsertReminder"),
+				log.Reason(err.Error()),
+				log.NewValue("channelID", incident.ChannelId),
+			)
+			return err
+		}
+		return sendErr
+	}
+
+	acknowledge := slack.MsgOptionAttachments(slack.Attachment{
+		CallbackID: AcknowledgeCallbackID,
+		Fallback:   "Acknowledge",
+		Actions: []slack.AttachmentAction{
+			{
+				Name:  "acknowledge",
+				Text:  "Acknowledge",
+				Type:  "button",
+				Style: "primary",
+				Value: incident.ChannelId,
+			},
+		},
+	})
+	sendErr := Send(ctx, client, logger, repository, incident, decision.Targets(), msg, acknowledge)
+
+	// The step is recorded even when a target failed, so it is not sent again on the next run
+	err := repository.InsertEscalation(ctx, &model.Escalation{IncidentId: incident.Id, Step: decision.Step})
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("repository.InsertEscalation"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", incident.ChannelId),
+			log.NewValue("step", decision.Step),
+		)
+		return err
+	}
+
+	return sendErr
+}
+
+// Send delivers the reminder message to every target
+func Send(ctx context.Context, client bot.Client, logger log.Logger, repository model.Repository, incident model.Incident, targets []Target, msg string, options ...slack.MsgOption) error {
+	var lastErr error
+	for _, target := range targets {
+		to, text := targetMessage(ctx, logger, repository, incident, target, msg)
+		if to == "" {
+			continue
+		}
+
+		_, _, err := client.PostMessage(to, append([]slack.MsgOption{slack.MsgOptionText(text, false)}, options...)...)
+		if err != nil {
+			logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("client.PostMessage"),
+				log.Reason(err.Error()),
+				log.NewValue("channelID", incident.ChannelId),
+				log.NewValue("target", target),
+			)
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+func targetMessage(ctx context.Context, logger log.Logger, repository model.Repository, incident model.Incident, target Target, msg string) (string, string) {
+	switch target.Kind {
+	case TargetCommander:
+		if incident.CommanderId == "" {
+			return incident.ChannelId, "Nobody is the Commander of this incident. " + msg
+		}
+		return incident.CommanderId, "<#" + incident.ChannelId + "> " + msg
+	case TargetRole:
+		roles, err := repository.ListIncidentRoles(ctx, incident.Id)
+		if err != nil {
+			logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("repository.ListIncidentRoles"),
+				log.Reason(err.Error()),
+				log.NewValue("channelID", incident.ChannelId),
+			)
+			return "", ""
+		}
+
+		holder := model.RoleHolder(roles, target.ID)
+		if holder == "" {
+			return incident.ChannelId, "Nobody is the " + model.RoleName(target.ID) + " of this incident, assign it with `/hellper_role assign " + target.ID + " @user`. " + msg
+		}
+		return holder, "<#" + incident.ChannelId + "> " + msg
+	case TargetUserGroup:
+		return incident.ChannelId, "<!subteam^" + target.ID + "> " + msg
+	case TargetChannel:
+		if target.ID != "" && target.ID != incident.ChannelId {
+			return target.ID, "<#" + incident.ChannelId + "> " + msg
+		}
+		return incident.ChannelId, msg
+	default:
+		return incident.ChannelId, msg
+	}
+}
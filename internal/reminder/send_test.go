@@ -0,0 +1,64 @@
+package reminder_test
+
+import (
+	"context"
+	"hellper/internal/bot"
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/reminder"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSend(t *testing.T) {
+	var (
+		ctx            = context.Background()
+		loggerMock     = log.NewLoggerMock()
+		clientMock     = bot.NewClientMock()
+		repositoryMock = model.NewRepositoryMock()
+		incident       = model.Incident{Id: 42, ChannelId: "C1", CommanderId: "U1"}
+		rule           = &reminder.Rule{Targets: []string{"channel", "commander", "role:comms_lead", "role:scribe", "usergroup:S1", "channel:C9"}}
+	)
+
+	clientMock.On("PostMessage", mock.AnythingOfType("string"), mock.AnythingOfType("[]slack.MsgOption")).Return("", "", nil)
+	repositoryMock.On("ListIncidentRoles", ctx, int64(42)).Return([]model.IncidentRole{{Role: model.RoleCommsLead, UserId: "U2"}}, nil)
+
+	err := reminder.Send(ctx, clientMock, loggerMock, repositoryMock, incident, rule.ParsedTargets(), "Update the status")
+	assert.NoError(t, err)
+
+	clientMock.AssertNumberOfCalls(t, "PostMessage", 6)
+	clientMock.AssertCalled(t, "PostMessage", "U1", mock.Anything)
+	clientMock.AssertCalled(t, "PostMessage", "U2", mock.Anything)
+	clientMock.AssertCalled(t, "PostMessage", "C1", mock.Anything)
+	clientMock.AssertCalled(t, "PostMessage", "C9", mock.Anything)
+}
+
+func TestDeliverEscalation(t *testing.T) {
+	var (
+		ctx            = context.Background()
+		loggerMock     = log.NewLoggerMock()
+		clientMock     = bot.NewClientMock()
+		repositoryMock = model.NewRepositoryMock()
+		incident       = model.Incident{Id: 42, ChannelId: "C1", CommanderId: "U1"}
+	)
+
+	policy, err := reminder.ParsePolicy([]byte(escalationPolicy))
+	assert.NoError(t, err)
+
+	clientMock.On("PostMessage", mock.AnythingOfType("string"), mock.AnythingOfType("[]slack.MsgOption")).Return("", "", nil)
+	repositoryMock.On("InsertEscalation", ctx, mock.AnythingOfType("*model.Escalation")).Return(nil)
+
+	decision := reminder.Decision{Notify: true, Rule: &policy.Rules[0], Step: 1}
+	msg, err := decision.Render(incident)
+	assert.NoError(t, err)
+	assert.Equal(t, "<@U1>, nobody updated <#C1> for 30m0s", msg)
+
+	err = reminder.Deliver(ctx, clientMock, loggerMock, repositoryMock, incident, decision, msg)
+	assert.NoError(t, err)
+
+	clientMock.AssertNumberOfCalls(t, "PostMessage", 1)
+	clientMock.AssertCalled(t, "PostMessage", "U1", mock.Anything)
+	repositoryMock.AssertCalled(t, "InsertEscalation", ctx, &model.Escalation{IncidentId: 42, Step: 1})
+}
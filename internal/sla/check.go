@@ -0,0 +1,72 @@
+package sla
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"hellper/internal/bot"
+	"hellper/internal/log"
+	"hellper/internal/model"
+
+	"github.com/slack-go/slack"
+)
+
+// CheckIncidents records the breaches of the active incidents, alerting the metrics still running
+// on the incident channel and on the alert channel, when there is one
+func CheckIncidents(ctx context.Context, client bot.Client, logger log.Logger, repository model.Repository, targets Targets, alertChannelID string) {
+	incidents, err := repository.ListActiveIncidents(ctx)
+	if err != nil {
+		logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("repository.ListActiveIncidents"),
+			log.Reason(err.Error()),
+		)
+		return
+	}
+
+	var (
+		recorder = NewRecorder(logger, repository, targets)
+		now      = time.Now()
+	)
+	for _, incident := range incidents {
+		for _, breach := range recorder.Record(ctx, incident, now) {
+			if breach.Done {
+				continue
+			}
+
+			msg := AlertMessage(incident, breach)
+			for _, channelID := range []string{incident.ChannelId, alertChannelID} {
+				if channelID == "" {
+					continue
+				}
+
+				_, _, err := client.PostMessage(channelID, slack.MsgOptionText(msg, false))
+				if err != nil {
+					logger.Error(
+						ctx,
+						log.Trace(),
+						log.Action("client.PostMessage"),
+						log.Reason(err.Error()),
+						log.NewValue("channelID", channelID),
+						log.NewValue("incidentChannelID", incident.ChannelId),
+						log.NewValue("metric", breach.Metric),
+					)
+				}
+			}
+		}
+	}
+}
+
+// AlertMessage tells the incident breached the SLA target of the metric
+func AlertMessage(incident model.Incident, breach Status) string {
+	return fmt.Sprintf(
+		":rotating_light: SLA breached on <#%s> (SEV%d): *%s* target of %s missed, running for %s",
+		incident.ChannelId,
+		incident.SeverityLevel,
+		breach.Metric,
+		FormatDuration(breach.Target),
+		FormatDuration(breach.Elapsed),
+	)
+}
@@ -0,0 +1,74 @@
+package sla
+
+import (
+	"context"
+	"time"
+
+	"hellper/internal/lifecycle"
+	"hellper/internal/log"
+	"hellper/internal/model"
+)
+
+// Recorder stores the SLA breaches of the incidents, it listens to the lifecycle events so the
+// metrics that finish late between two checks are still recorded
+type Recorder struct {
+	logger     log.Logger
+	repository model.Repository
+	targets    Targets
+	now        func() time.Time
+}
+
+// NewRecorder creates a Recorder of the targets
+func NewRecorder(logger log.Logger, repository model.Repository, targets Targets) *Recorder {
+	return &Recorder{
+		logger:     logger,
+		repository: repository,
+		targets:    targets,
+		now:        time.Now,
+	}
+}
+
+// Wants tells whether the event may stop the clock of a metric
+func (r *Recorder) Wants(event string) bool {
+	switch event {
+	case lifecycle.EventUpdated, lifecycle.EventResolved, lifecycle.EventClosed:
+		return true
+	}
+	return false
+}
+
+// Dispatch records the breaches of the changed incident
+func (r *Recorder) Dispatch(ctx context.Context, event string, incident model.Incident) {
+	r.Record(ctx, incident, r.now())
+}
+
+// Record stores every breached metric of the incident, returning the ones breached for the first time
+func (r *Recorder) Record(ctx context.Context, incident model.Incident, now time.Time) []Status {
+	var breaches []Status
+	for _, status := range Evaluate(r.targets, incident, now) {
+		if !status.Breached() {
+			continue
+		}
+
+		inserted, err := r.repository.InsertSLABreach(ctx, &model.SLABreach{
+			IncidentId: incident.Id,
+			Metric:     status.Metric,
+			Target:     status.Target,
+		})
+		if err != nil {
+			r.logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("repository.InsertSLABreach"),
+				log.Reason(err.Error()),
+				log.NewValue("channelID", incident.ChannelId),
+				log.NewValue("metric", status.Metric),
+			)
+			continue
+		}
+		if inserted {
+			breaches = append(breaches, status)
+		}
+	}
+	return breaches
+}
@@ -0,0 +1,194 @@
+package sla_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"hellper/internal/bot"
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/sla"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParseTargets(t *testing.T) {
+	table := []struct {
+		testName      string
+		value         string
+		expected      sla.Targets
+		expectedError string
+	}{
+		{
+			testName: "Targets by severity",
+			value:    "sev0=acknowledge:15m,resolve:4h; *=postmortem:72h",
+			expected: sla.Targets{
+				"sev0": {sla.MetricAcknowledge: 15 * time.Minute, sla.MetricResolve: 4 * time.Hour},
+				"*":    {sla.MetricPostmortem: 72 * time.Hour, sla.MetricClose: 168 * time.Hour},
+			},
+		},
+		{
+			testName: "Close target overrides the default",
+			value:    "*=close:48h",
+			expected: sla.Targets{"*": {sla.MetricPostmortem: 168 * time.Hour, sla.MetricClose: 48 * time.Hour}},
+		},
+		{
+			testName: "Empty",
+			expected: sla.Targets{"*": {sla.MetricPostmortem: 168 * time.Hour, sla.MetricClose: 168 * time.Hour}},
+		},
+		{
+			testName:      "Unknown severity",
+			value:         "critical=resolve:4h",
+			expectedError: `invalid SLA targets: unknown severity "critical"`,
+		},
+		{
+			testName:      "Unknown metric",
+			value:         "sev1=mitigate:4h",
+			expectedError: `invalid SLA targets: unknown metric "mitigate"`,
+		},
+		{
+			testName:      "Invalid duration",
+			value:         "sev1=resolve:4 hours",
+			expectedError: `invalid SLA targets: invalid duration "4 hours" of resolve`,
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			targets, err := sla.ParseTargets(f.value, 168*time.Hour)
+			if f.expectedError != "" {
+				assert.EqualError(t, err, f.expectedError)
+				assert.True(t, errors.Is(err, sla.ErrInvalidTargets))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, f.expected, targets)
+		})
+	}
+}
+
+var (
+	started = time.Date(2020, 10, 19, 12, 0, 0, 0, time.UTC)
+	targets = sla.Targets{
+		"sev1": {sla.MetricAcknowledge: 15 * time.Minute, sla.MetricResolve: 4 * time.Hour},
+		"*":    {sla.MetricPostmortem: 72 * time.Hour, sla.MetricClose: 168 * time.Hour},
+	}
+)
+
+func at(d time.Duration) *time.Time {
+	t := started.Add(d)
+	return &t
+}
+
+func TestEvaluate(t *testing.T) {
+	table := []struct {
+		testName string
+		incident model.Incident
+		now      time.Time
+		expected []string
+	}{
+		{
+			testName: "Open incident not identified",
+			incident: model.Incident{Status: model.StatusOpen, SeverityLevel: 1, StartTimestamp: at(0)},
+			now:      *at(20 * time.Minute),
+			expected: []string{"acknowledge: breached by 5m, target 15m", "resolve: 3h40m left of 4h"},
+		},
+		{
+			testName: "Resolved incident",
+			incident: model.Incident{Status: model.StatusResolved, SeverityLevel: 1, StartTimestamp: at(0), IdentificationTimestamp: at(10 * time.Minute), EndTimestamp: at(5 * time.Hour)},
+			now:      *at(29 * time.Hour),
+			expected: []string{"acknowledge: met in 10m of 15m", "resolve: missed, took 5h of 4h", "postmortem: 2d left of 3d", "close: 6d left of 7d"},
+		},
+		{
+			testName: "Severity without its own targets",
+			incident: model.Incident{Status: model.StatusOpen, SeverityLevel: 3, StartTimestamp: at(0)},
+			now:      *at(time.Hour),
+		},
+		{
+			testName: "Canceled incident",
+			incident: model.Incident{Status: model.StatusCancel, SeverityLevel: 1, StartTimestamp: at(0)},
+			now:      *at(time.Hour),
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			var texts []string
+			for _, status := range sla.Evaluate(targets, f.incident, f.now) {
+				texts = append(texts, status.Text())
+			}
+			assert.Equal(t, f.expected, texts)
+		})
+	}
+}
+
+type checkFixture struct {
+	testName       string
+	alertChannelID string
+	inserted       bool
+	expectedPosts  []string
+
+	ctx            context.Context
+	clientMock     *bot.ClientMock
+	repositoryMock *model.RepositoryMock
+	loggerMock     *log.LoggerMock
+}
+
+func (f *checkFixture) setup(t *testing.T) {
+	f.ctx = context.Background()
+	f.loggerMock = log.NewLoggerMock()
+	f.clientMock = bot.NewClientMock()
+	f.repositoryMock = model.NewRepositoryMock()
+
+	now := time.Now()
+	start := now.Add(-time.Hour)
+	identified := now.Add(-50 * time.Minute)
+
+	f.loggerMock.On("Error", f.ctx, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+	f.clientMock.On("PostMessage", mock.AnythingOfType("string"), mock.AnythingOfType("[]slack.MsgOption")).Return("", "", nil)
+	f.repositoryMock.On("ListActiveIncidents").Return([]model.Incident{
+		{Id: 1, ChannelId: "C1", Status: model.StatusOpen, SeverityLevel: 1, StartTimestamp: &start, IdentificationTimestamp: &identified},
+	}, nil)
+	f.repositoryMock.On("InsertSLABreach", f.ctx, mock.AnythingOfType("*model.SLABreach")).Return(f.inserted, nil)
+}
+
+func TestCheckIncidents(t *testing.T) {
+	table := []checkFixture{
+		{
+			testName:       "New breach is alerted on both channels",
+			alertChannelID: "CSLA",
+			inserted:       true,
+			expectedPosts:  []string{"C1", "CSLA"},
+		},
+		{
+			testName:      "New breach without alert channel",
+			inserted:      true,
+			expectedPosts: []string{"C1"},
+		},
+		{
+			testName:       "Breach already recorded is not alerted again",
+			alertChannelID: "CSLA",
+		},
+	}
+
+	for index := range table {
+		f := &table[index]
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			f.setup(t)
+
+			shortTargets := sla.Targets{"sev1": {sla.MetricAcknowledge: 5 * time.Minute, sla.MetricResolve: 30 * time.Minute}}
+			sla.CheckIncidents(f.ctx, f.clientMock, f.loggerMock, f.repositoryMock, shortTargets, f.alertChannelID)
+
+			// The acknowledge finished late, so it is recorded but only the running resolve is alerted
+			f.repositoryMock.AssertNumberOfCalls(t, "InsertSLABreach", 2)
+			f.clientMock.AssertNumberOfCalls(t, "PostMessage", len(f.expectedPosts))
+			for _, channelID := range f.expectedPosts {
+				f.clientMock.AssertCalled(t, "PostMessage", channelID, mock.AnythingOfType("[]slack.MsgOption"))
+			}
+		})
+	}
+}
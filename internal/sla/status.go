@@ -0,0 +1,131 @@
+package sla
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"hellper/internal/model"
+)
+
+// Status is the progress of an incident on the target of a metric
+type Status struct {
+	Metric  string
+	Target  time.Duration
+	Elapsed time.Duration
+	// Done tells the clock of the metric stopped, e.g. the incident was identified
+	Done bool
+}
+
+// Breached tells whether the metric took longer than the target
+func (s Status) Breached() bool {
+	return s.Elapsed > s.Target
+}
+
+// Remaining is the time left to meet the target, negative once breached
+func (s Status) Remaining() time.Duration {
+	return s.Target - s.Elapsed
+}
+
+// Text describes the status, e.g. "resolve: 1h20m left of 4h"
+func (s Status) Text() string {
+	switch {
+	case s.Done && s.Breached():
+		return s.Metric + ": missed, took " + FormatDuration(s.Elapsed) + " of " + FormatDuration(s.Target)
+	case s.Done:
+		return s.Metric + ": met in " + FormatDuration(s.Elapsed) + " of " + FormatDuration(s.Target)
+	case s.Breached():
+		return s.Metric + ": breached by " + FormatDuration(-s.Remaining()) + ", target " + FormatDuration(s.Target)
+	default:
+		return s.Metric + ": " + FormatDuration(s.Remaining()) + " left of " + FormatDuration(s.Target)
+	}
+}
+
+// Evaluate tells the progress of the incident on each metric with a target whose clock started
+func Evaluate(targets Targets, inc model.Incident, now time.Time) []Status {
+	if inc.Status == model.StatusCancel {
+		return nil
+	}
+
+	var statuses []Status
+	for _, metric := range Metrics {
+		target := targets.Target(inc.SeverityLevel, metric)
+		if target <= 0 {
+			continue
+		}
+
+		start, stop := clock(inc, metric)
+		if start == nil || start.IsZero() {
+			continue
+		}
+
+		status := Status{Metric: metric, Target: target}
+		if stop != nil && !stop.IsZero() {
+			status.Done = true
+			status.Elapsed = stop.Sub(*start)
+		} else {
+			status.Elapsed = now.Sub(*start)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// DueAt is when the clock of the metric runs out, false when the metric has no target or its clock did not start
+func DueAt(targets Targets, inc model.Incident, metric string) (time.Time, bool) {
+	target := targets.Target(inc.SeverityLevel, metric)
+	start, _ := clock(inc, metric)
+	if target <= 0 || start == nil || start.IsZero() {
+		return time.Time{}, false
+	}
+	return start.Add(target), true
+}
+
+// Summary describes the metrics still running, or the breached ones, on a single line
+func Summary(statuses []Status) string {
+	var texts []string
+	for _, status := range statuses {
+		if !status.Done || status.Breached() {
+			texts = append(texts, status.Text())
+		}
+	}
+	return strings.Join(texts, " - ")
+}
+
+func clock(inc model.Incident, metric string) (*time.Time, *time.Time) {
+	switch metric {
+	case MetricAcknowledge:
+		return inc.StartTimestamp, inc.IdentificationTimestamp
+	case MetricResolve:
+		return inc.StartTimestamp, inc.EndTimestamp
+	case MetricPostmortem:
+		return inc.EndTimestamp, inc.PostMortemPublishedAt
+	case MetricClose:
+		return inc.EndTimestamp, inc.ClosedAt
+	}
+	return nil, nil
+}
+
+// FormatDuration shows days, hours and minutes, e.g. 2d3h or 45m
+func FormatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return "<1m"
+	}
+
+	var (
+		days    = int(d / (24 * time.Hour))
+		hours   = int(d % (24 * time.Hour) / time.Hour)
+		minutes = int(d % time.Hour / time.Minute)
+		text    strings.Builder
+	)
+	if days > 0 {
+		text.WriteString(strconv.Itoa(days) + "d")
+	}
+	if hours > 0 {
+		text.WriteString(strconv.Itoa(hours) + "h")
+	}
+	if minutes > 0 && days == 0 {
+		text.WriteString(strconv.Itoa(minutes) + "m")
+	}
+	return text.String()
+}
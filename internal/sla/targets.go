@@ -0,0 +1,134 @@
+// Package sla tracks the service level targets of the incidents by severity
+package sla
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"hellper/internal/config"
+)
+
+// Metrics tracked for each incident
+const (
+	// MetricAcknowledge runs from the start to the identification of the incident
+	MetricAcknowledge = "acknowledge"
+	// MetricResolve runs from the start to the resolution of the incident
+	MetricResolve = "resolve"
+	// MetricPostmortem runs from the resolution to the publication of the post mortem
+	MetricPostmortem = "postmortem"
+	// MetricClose runs from the resolution to the close of the incident
+	MetricClose = "close"
+)
+
+// Metrics lists the metrics in the order of the incident lifecycle
+var Metrics = []string{MetricAcknowledge, MetricResolve, MetricPostmortem, MetricClose}
+
+// AllSeverities is the key of the targets of the severities without their own
+const AllSeverities = "*"
+
+// ErrInvalidTargets is returned when the targets can not be parsed
+var ErrInvalidTargets = errors.New("invalid SLA targets")
+
+// Targets are the durations of each metric by severity, e.g. targets["sev1"]["resolve"]
+type Targets map[string]map[string]time.Duration
+
+// ParseTargets reads the targets in the format severity=metric:duration,metric:duration;severity=metric:duration,
+// the severity * applies to the severities without the metric, and defaultTarget is the post mortem and close target of every severity without one, e.g.
+// sev0=acknowledge:15m,resolve:4h,postmortem:72h;sev1=acknowledge:30m,resolve:8h;*=postmortem:120h,close:168h
+func ParseTargets(value string, defaultTarget time.Duration) (Targets, error) {
+	targets := Targets{}
+
+	for _, statement := range strings.Split(value, ";") {
+		statement = strings.TrimSpace(statement)
+		if statement == "" {
+			continue
+		}
+
+		parts := strings.SplitN(statement, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%w: %q has no targets", ErrInvalidTargets, statement)
+		}
+
+		severity := strings.ToLower(strings.TrimSpace(parts[0]))
+		if severity != AllSeverities && !isSeverity(severity) {
+			return nil, fmt.Errorf("%w: unknown severity %q", ErrInvalidTargets, severity)
+		}
+
+		for _, target := range strings.Split(parts[1], ",") {
+			metric, duration, err := parseTarget(strings.TrimSpace(target))
+			if err != nil {
+				return nil, err
+			}
+			if targets[severity] == nil {
+				targets[severity] = map[string]time.Duration{}
+			}
+			targets[severity][metric] = duration
+		}
+	}
+
+	for _, metric := range []string{MetricPostmortem, MetricClose} {
+		if _, ok := targets[AllSeverities][metric]; ok || defaultTarget <= 0 {
+			continue
+		}
+		if targets[AllSeverities] == nil {
+			targets[AllSeverities] = map[string]time.Duration{}
+		}
+		targets[AllSeverities][metric] = defaultTarget
+	}
+
+	return targets, nil
+}
+
+// EnvTargets reads the targets of the environment, they are validated when the application starts
+func EnvTargets() Targets {
+	defaultTarget := time.Duration(config.Env.SLAHoursToClose) * time.Hour
+	targets, err := ParseTargets(config.Env.SLATargets, defaultTarget)
+	if err != nil {
+		return Targets{AllSeverities: {MetricPostmortem: defaultTarget, MetricClose: defaultTarget}}
+	}
+	return targets
+}
+
+// Target is the target of the metric for the severity, zero when it has no target
+func (t Targets) Target(severityLevel int64, metric string) time.Duration {
+	if target, ok := t["sev"+strconv.FormatInt(severityLevel, 10)][metric]; ok {
+		return target
+	}
+	return t[AllSeverities][metric]
+}
+
+func parseTarget(value string) (string, time.Duration, error) {
+	parts := strings.SplitN(value, ":", 2)
+	if len(parts) != 2 {
+		return "", 0, fmt.Errorf("%w: %q has no duration", ErrInvalidTargets, value)
+	}
+
+	metric := strings.TrimSpace(parts[0])
+	if !isMetric(metric) {
+		return "", 0, fmt.Errorf("%w: unknown metric %q", ErrInvalidTargets, metric)
+	}
+
+	duration, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+	if err != nil || duration <= 0 {
+		return "", 0, fmt.Errorf("%w: invalid duration %q of %s", ErrInvalidTargets, parts[1], metric)
+	}
+
+	return metric, duration, nil
+}
+
+func isMetric(metric string) bool {
+	for _, m := range Metrics {
+		if m == metric {
+			return true
+		}
+	}
+	return false
+}
+
+func isSeverity(severity string) bool {
+	level, err := strconv.Atoi(strings.TrimPrefix(severity, "sev"))
+	return strings.HasPrefix(severity, "sev") && err == nil && level >= 0
+}
@@ -0,0 +1,133 @@
+// Package snooze parses the durations and the per-severity limits of the notification pauses
+package snooze
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"hellper/internal/model"
+)
+
+// AllIncidents is the key of the limit applying to every incident
+const AllIncidents = "*"
+
+var (
+	// ErrInvalidDuration is returned when the duration of a snooze can not be parsed
+	ErrInvalidDuration = errors.New("invalid snooze duration")
+	// ErrInvalidLimits is returned when the limits can not be parsed
+	ErrInvalidLimits = errors.New("invalid snooze limits")
+)
+
+// Limits are the longest snoozes by incident status, by severity or for all incidents, e.g. limits["sev0"]
+type Limits map[string]time.Duration
+
+// ParseDuration reads a duration such as 90m, 4h or 1h30m, and the days notation such as 2d
+func ParseDuration(text string) (time.Duration, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+
+	var (
+		duration time.Duration
+		err      error
+	)
+	if strings.HasSuffix(text, "d") {
+		var days int
+		days, err = strconv.Atoi(strings.TrimSuffix(text, "d"))
+		duration = time.Duration(days) * 24 * time.Hour
+	} else {
+		duration, err = time.ParseDuration(text)
+	}
+
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("%w: %q, use a duration such as 30m, 4h or 2d", ErrInvalidDuration, text)
+	}
+	return duration, nil
+}
+
+// ParseLimits reads the limits in the format key=duration;key=duration, the keys are an incident status,
+// a severity or * for all incidents, e.g. open=1d;resolved=3d;sev0=4h
+func ParseLimits(value string) (Limits, error) {
+	limits := Limits{}
+
+	for _, statement := range strings.Split(value, ";") {
+		statement = strings.TrimSpace(statement)
+		if statement == "" {
+			continue
+		}
+
+		parts := strings.SplitN(statement, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%w: %q has no duration", ErrInvalidLimits, statement)
+		}
+
+		key := strings.ToLower(strings.TrimSpace(parts[0]))
+		if !isKey(key) {
+			return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidLimits, key)
+		}
+
+		duration, err := ParseDuration(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid duration %q of %s", ErrInvalidLimits, strings.TrimSpace(parts[1]), key)
+		}
+		limits[key] = duration
+	}
+
+	return limits, nil
+}
+
+// Limit is the longest snooze of the incident, the shortest of the limits of its status, of its severity and of
+// all incidents, false when none applies
+func (l Limits) Limit(inc model.Incident) (time.Duration, bool) {
+	var (
+		limit time.Duration
+		found bool
+	)
+	for _, key := range []string{inc.Status, "sev" + strconv.FormatInt(inc.SeverityLevel, 10), AllIncidents} {
+		duration, ok := l[key]
+		if !ok {
+			continue
+		}
+		if !found || duration < limit {
+			limit = duration
+			found = true
+		}
+	}
+	return limit, found
+}
+
+// Check returns an error when the duration exceeds the limit of the incident
+func (l Limits) Check(inc model.Incident, duration time.Duration) error {
+	limit, ok := l.Limit(inc)
+	if ok && duration > limit {
+		return fmt.Errorf("%s incidents of SEV%d can be paused for at most %s", inc.Status, inc.SeverityLevel, FormatDuration(limit))
+	}
+	return nil
+}
+
+// FormatDuration writes the duration in days and hours when it is long enough, e.g. 2d, 1d12h or 90m
+func FormatDuration(duration time.Duration) string {
+	days := duration / (24 * time.Hour)
+	rest := duration - days*24*time.Hour
+
+	switch {
+	case days > 0 && rest == 0:
+		return fmt.Sprintf("%dd", days)
+	case days > 0 && rest%time.Hour == 0:
+		return fmt.Sprintf("%dd%dh", days, rest/time.Hour)
+	case duration%time.Hour == 0:
+		return fmt.Sprintf("%dh", duration/time.Hour)
+	case duration%time.Minute == 0:
+		return fmt.Sprintf("%dm", duration/time.Minute)
+	}
+	return duration.String()
+}
+
+func isKey(key string) bool {
+	if key == AllIncidents || key == model.StatusOpen || key == model.StatusResolved {
+		return true
+	}
+	level, err := strconv.Atoi(strings.TrimPrefix(key, "sev"))
+	return strings.HasPrefix(key, "sev") && err == nil && level >= 0
+}
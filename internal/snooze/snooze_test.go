@@ -0,0 +1,141 @@
+package snooze_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"hellper/internal/model"
+	"hellper/internal/snooze"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDuration(t *testing.T) {
+	table := []struct {
+		testName      string
+		text          string
+		expected      time.Duration
+		expectedError string
+	}{
+		{testName: "Minutes", text: "90m", expected: 90 * time.Minute},
+		{testName: "Hours and minutes", text: " 1h30m ", expected: 90 * time.Minute},
+		{testName: "Days", text: "2d", expected: 48 * time.Hour},
+		{testName: "Missing unit", text: "2", expectedError: `invalid snooze duration: "2", use a duration such as 30m, 4h or 2d`},
+		{testName: "Negative", text: "-1h", expectedError: `invalid snooze duration: "-1h", use a duration such as 30m, 4h or 2d`},
+		{testName: "Zero days", text: "0d", expectedError: `invalid snooze duration: "0d", use a duration such as 30m, 4h or 2d`},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			duration, err := snooze.ParseDuration(f.text)
+			if f.expectedError != "" {
+				assert.EqualError(t, err, f.expectedError)
+				assert.True(t, errors.Is(err, snooze.ErrInvalidDuration))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, f.expected, duration)
+		})
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	table := []struct {
+		testName      string
+		value         string
+		expected      snooze.Limits
+		expectedError string
+	}{
+		{
+			testName: "Limits by status and severity",
+			value:    "open=1d; resolved=3d;SEV0=4h;*=7d",
+			expected: snooze.Limits{"open": 24 * time.Hour, "resolved": 72 * time.Hour, "sev0": 4 * time.Hour, "*": 168 * time.Hour},
+		},
+		{
+			testName: "Empty",
+			expected: snooze.Limits{},
+		},
+		{
+			testName:      "Missing duration",
+			value:         "open",
+			expectedError: `invalid snooze limits: "open" has no duration`,
+		},
+		{
+			testName:      "Unknown key",
+			value:         "closed=1d",
+			expectedError: `invalid snooze limits: unknown key "closed"`,
+		},
+		{
+			testName:      "Invalid duration",
+			value:         "sev1=forever",
+			expectedError: `invalid snooze limits: invalid duration "forever" of sev1`,
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			limits, err := snooze.ParseLimits(f.value)
+			if f.expectedError != "" {
+				assert.EqualError(t, err, f.expectedError)
+				assert.True(t, errors.Is(err, snooze.ErrInvalidLimits))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, f.expected, limits)
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	limits := snooze.Limits{"open": 24 * time.Hour, "resolved": 72 * time.Hour, "sev0": 4 * time.Hour}
+
+	table := []struct {
+		testName      string
+		incident      model.Incident
+		duration      time.Duration
+		expectedError string
+	}{
+		{
+			testName: "Within the status limit",
+			incident: model.Incident{Status: model.StatusResolved, SeverityLevel: 2},
+			duration: 48 * time.Hour,
+		},
+		{
+			testName:      "Over the status limit",
+			incident:      model.Incident{Status: model.StatusOpen, SeverityLevel: 2},
+			duration:      36 * time.Hour,
+			expectedError: "open incidents of SEV2 can be paused for at most 1d",
+		},
+		{
+			testName:      "The severity limit is shorter",
+			incident:      model.Incident{Status: model.StatusResolved, SeverityLevel: 0},
+			duration:      5 * time.Hour,
+			expectedError: "resolved incidents of SEV0 can be paused for at most 4h",
+		},
+		{
+			testName: "No limit applies",
+			incident: model.Incident{Status: "unknown", SeverityLevel: 3},
+			duration: 720 * time.Hour,
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			err := limits.Check(f.incident, f.duration)
+			if f.expectedError != "" {
+				assert.EqualError(t, err, f.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "2d", snooze.FormatDuration(48*time.Hour))
+	assert.Equal(t, "1d12h", snooze.FormatDuration(36*time.Hour))
+	assert.Equal(t, "4h", snooze.FormatDuration(4*time.Hour))
+	assert.Equal(t, "90m", snooze.FormatDuration(90*time.Minute))
+}
@@ -0,0 +1,46 @@
+package statuspage
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+)
+
+// Fake keeps the public incidents in memory, to try the status page flow without a status page
+type Fake struct {
+	mu        sync.Mutex
+	incidents map[string][]Update
+}
+
+// NewFake creates an empty Fake
+func NewFake() *Fake {
+	return &Fake{incidents: map[string][]Update{}}
+}
+
+func (f *Fake) CreateIncident(ctx context.Context, update Update) (PageIncident, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := strconv.Itoa(len(f.incidents) + 1)
+	f.incidents[id] = []Update{update}
+	return PageIncident{ID: id, URL: "https://status.example.com/incidents/" + id}, nil
+}
+
+func (f *Fake) UpdateIncident(ctx context.Context, id string, update Update) (PageIncident, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.incidents[id]; !ok {
+		return PageIncident{}, errors.New("public incident " + id + " not found")
+	}
+	f.incidents[id] = append(f.incidents[id], update)
+	return PageIncident{ID: id, URL: "https://status.example.com/incidents/" + id}, nil
+}
+
+// Updates returns the updates published on the public incident
+func (f *Fake) Updates(id string) []Update {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Update(nil), f.incidents[id]...)
+}
@@ -0,0 +1,378 @@
+package statuspage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"hellper/internal/bot"
+	"hellper/internal/concurrence"
+	"hellper/internal/lifecycle"
+	"hellper/internal/log"
+	"hellper/internal/model"
+
+	"github.com/slack-go/slack"
+)
+
+// ReviewCallbackID is the callback of the buttons that publish or discard a draft
+const ReviewCallbackID = "statuspage-review"
+
+// Actions of the review buttons
+const (
+	ActionPublish = "publish"
+	ActionDiscard = "discard"
+)
+
+// Service drafts the status page updates of the incidents and publishes them once the comms lead approves them
+type Service struct {
+	logger     log.Logger
+	client     bot.Client
+	repository model.Repository
+	background *concurrence.Background
+	provider   Provider
+	components Components
+}
+
+// NewService creates a Service publishing on the provider, the lifecycle events are handled on the background
+func NewService(logger log.Logger, client bot.Client, repository model.Repository, background *concurrence.Background, provider Provider, components Components) *Service {
+	return &Service{
+		logger:     logger,
+		client:     client,
+		repository: repository,
+		background: background,
+		provider:   provider,
+		components: components,
+	}
+}
+
+// Wants tells whether the event changes the public incident
+func (s *Service) Wants(event string) bool {
+	return event == lifecycle.EventOpened || event == lifecycle.EventUpdated || event == lifecycle.EventResolved
+}
+
+// Dispatch drafts the public incident of an opened incident and an update of an updated one, and resolves the public
+// incident of a resolved one, in background
+func (s *Service) Dispatch(ctx context.Context, event string, incident model.Incident) {
+	s.background.Go(func() {
+		ctx := context.Background()
+
+		var err error
+		switch event {
+		case lifecycle.EventOpened:
+			body := incident.DescriptionStarted
+			if body == "" {
+				body = incident.Title
+			}
+			_, err = s.Draft(ctx, incident, "", StatusInvestigating, body)
+		case lifecycle.EventUpdated:
+			err = s.draftUpdated(ctx, incident)
+		case lifecycle.EventResolved:
+			err = s.Resolve(ctx, incident)
+		}
+		if err != nil {
+			s.logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("statuspage.Dispatch"),
+				log.Reason(err.Error()),
+				log.NewValue("event", event),
+				log.NewValue("channelID", incident.ChannelId),
+			)
+		}
+	})
+}
+
+// Draft saves a pending update and asks the comms lead to publish or discard it on the incident channel
+func (s *Service) Draft(ctx context.Context, incident model.Incident, userID string, status string, body string) (model.StatusPageUpdate, error) {
+	if !IsStatus(status) {
+		return model.StatusPageUpdate{}, fmt.Errorf("invalid status %q, use one of %s", status, strings.Join(Statuses, ", "))
+	}
+	if strings.TrimSpace(body) == "" {
+		return model.StatusPageUpdate{}, errors.New("the update has no message")
+	}
+
+	update := model.StatusPageUpdate{
+		IncidentId: incident.Id,
+		Status:     status,
+		Body:       body,
+		State:      model.StatusPageUpdatePending,
+		DraftedBy:  userID,
+	}
+	err := s.repository.InsertStatusPageUpdate(ctx, &update)
+	if err != nil {
+		return model.StatusPageUpdate{}, err
+	}
+
+	reviewer := "Nobody is the " + model.RoleName(model.RoleCommsLead) + " of this incident, assign it with `/hellper_role assign " + model.RoleCommsLead + " @user` to publish it."
+	roles, err := s.repository.ListIncidentRoles(ctx, incident.Id)
+	if err != nil {
+		s.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("repository.ListIncidentRoles"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", incident.ChannelId),
+		)
+	} else if holder := model.RoleHolder(roles, model.RoleCommsLead); holder != "" {
+		reviewer = "<@" + holder + ">, publish it on the status page?"
+	}
+
+	author := "Hellper"
+	if userID != "" {
+		author = "<@" + userID + ">"
+	}
+
+	value := strconv.FormatInt(update.Id, 10)
+	_, _, err = s.client.PostMessage(
+		incident.ChannelId,
+		slack.MsgOptionText(":memo: "+author+" drafted a status page update *"+status+"*:\n>"+strings.ReplaceAll(body, "\n", "\n>")+"\n"+reviewer, false),
+		slack.MsgOptionAttachments(slack.Attachment{
+			CallbackID: ReviewCallbackID,
+			Fallback:   "Publish or discard the status page update",
+			Actions: []slack.AttachmentAction{
+				{Name: ActionPublish, Text: "Publish", Type: "button", Style: "primary", Value: value},
+				{Name: ActionDiscard, Text: "Discard", Type: "button", Style: "danger", Value: value},
+			},
+		}),
+	)
+	if err != nil {
+		s.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("client.PostMessage"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", incident.ChannelId),
+		)
+		return update, err
+	}
+
+	return update, nil
+}
+
+// Review publishes or discards a pending update, only the comms lead of the incident may review it
+func (s *Service) Review(ctx context.Context, channelID string, userID string, updateID int64, publish bool) error {
+	incident, err := s.repository.GetIncident(ctx, channelID)
+	if err != nil {
+		return err
+	}
+
+	roles, err := s.repository.ListIncidentRoles(ctx, incident.Id)
+	if err != nil {
+		return err
+	}
+	holder := model.RoleHolder(roles, model.RoleCommsLead)
+	if holder != userID {
+		text := "Only the " + model.RoleName(model.RoleCommsLead) + " of this incident can review status page updates."
+		if holder == "" {
+			text = "Nobody is the " + model.RoleName(model.RoleCommsLead) + " of this incident, assign it with `/hellper_role assign " + model.RoleCommsLead + " @user`."
+		}
+		return s.ephemeral(ctx, channelID, userID, text)
+	}
+
+	update, err := s.repository.GetStatusPageUpdate(ctx, updateID)
+	if err != nil {
+		return err
+	}
+	if update.IncidentId != incident.Id {
+		return errors.New("status page update " + strconv.FormatInt(updateID, 10) + " is not from this incident")
+	}
+	if update.State != model.StatusPageUpdatePending {
+		return s.ephemeral(ctx, channelID, userID, "This status page update was already "+update.State+".")
+	}
+
+	update.ReviewedBy = userID
+	if !publish {
+		update.State = model.StatusPageUpdateDiscarded
+		err = s.repository.ReviewStatusPageUpdate(ctx, &update)
+		if errors.Is(err, model.ErrStatusPageUpdateReviewed) {
+			return s.ephemeral(ctx, channelID, userID, "This status page update was already reviewed.")
+		}
+		if err != nil {
+			return err
+		}
+		return s.post(ctx, channelID, ":wastebasket: <@"+userID+"> discarded the status page update *"+update.Status+"*.")
+	}
+
+	page, resolved, err := s.pageIncident(ctx, incident.Id)
+	if err != nil {
+		return err
+	}
+	if resolved {
+		return s.ephemeral(ctx, channelID, userID, "The public incident is already resolved, the update can not be published.")
+	}
+
+	// The draft is claimed before the status page is called, a second click on Publish finds it already claimed
+	update.State = model.StatusPageUpdatePublishing
+	err = s.repository.ReviewStatusPageUpdate(ctx, &update)
+	if errors.Is(err, model.ErrStatusPageUpdateReviewed) {
+		return s.ephemeral(ctx, channelID, userID, "This status page update was already reviewed.")
+	}
+	if err != nil {
+		return err
+	}
+
+	published, err := s.publish(ctx, incident, page, update.Status, update.Body)
+	if err != nil {
+		update.State = model.StatusPageUpdateFailed
+		update.Error = err.Error()
+		reviewErr := s.repository.ReviewStatusPageUpdate(ctx, &update)
+		if reviewErr != nil {
+			return reviewErr
+		}
+		return s.post(ctx, channelID, ":warning: The status page update *"+update.Status+"* could not be published: "+err.Error())
+	}
+
+	update.State = model.StatusPageUpdatePublished
+	update.PageIncidentId = published.ID
+	update.PageURL = published.URL
+	err = s.repository.ReviewStatusPageUpdate(ctx, &update)
+	if err != nil {
+		return err
+	}
+	return s.post(ctx, channelID, ":loudspeaker: <@"+userID+"> published the status page update *"+update.Status+"*: "+published.URL)
+}
+
+// draftUpdated drafts an update of the public incident of an open incident whose dates changed, identified once the
+// incident has an identification date
+func (s *Service) draftUpdated(ctx context.Context, incident model.Incident) error {
+	if incident.Status != model.StatusOpen {
+		return nil
+	}
+
+	status, body := StatusInvestigating, "We are still investigating the issue."
+	if incident.IdentificationTimestamp != nil && !incident.IdentificationTimestamp.IsZero() {
+		status, body = StatusIdentified, "The issue has been identified and a fix is being worked on."
+	}
+	_, err := s.Draft(ctx, incident, "", status, body)
+	return err
+}
+
+// Resolve resolves the public incident of the incident, when there is one
+func (s *Service) Resolve(ctx context.Context, incident model.Incident) error {
+	page, resolved, err := s.pageIncident(ctx, incident.Id)
+	if err != nil {
+		return err
+	}
+	if page.ID == "" || resolved {
+		return nil
+	}
+
+	body := incident.DescriptionResolved
+	if body == "" {
+		body = "This incident has been resolved."
+	}
+
+	update := model.StatusPageUpdate{
+		IncidentId: incident.Id,
+		Status:     StatusResolved,
+		Body:       body,
+		State:      model.StatusPageUpdatePublished,
+	}
+	published, err := s.publish(ctx, incident, page, StatusResolved, body)
+	if err != nil {
+		update.State = model.StatusPageUpdateFailed
+		update.Error = err.Error()
+	} else {
+		update.PageIncidentId = published.ID
+		update.PageURL = published.URL
+	}
+
+	insertErr := s.repository.InsertStatusPageUpdate(ctx, &update)
+	if err != nil {
+		return err
+	}
+	return insertErr
+}
+
+// Summary describes the public incident and its pending drafts
+func (s *Service) Summary(ctx context.Context, incident model.Incident) (string, error) {
+	updates, err := s.repository.ListStatusPageUpdates(ctx, incident.Id)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		page    string
+		pending []string
+	)
+	for _, update := range updates {
+		switch update.State {
+		case model.StatusPageUpdatePublished:
+			page = "The public incident is *" + update.Status + "*: " + update.PageURL
+		case model.StatusPageUpdatePending:
+			pending = append(pending, "• *"+update.Status+"* "+update.Body)
+		}
+	}
+	if page == "" {
+		page = "Nothing was published on the status page yet."
+	}
+	if len(pending) == 0 {
+		return page, nil
+	}
+	return page + "\nDrafts waiting for the " + model.RoleName(model.RoleCommsLead) + ":\n" + strings.Join(pending, "\n"), nil
+}
+
+// pageIncident finds the public incident from the published updates
+func (s *Service) pageIncident(ctx context.Context, incidentID int64) (PageIncident, bool, error) {
+	updates, err := s.repository.ListStatusPageUpdates(ctx, incidentID)
+	if err != nil {
+		return PageIncident{}, false, err
+	}
+
+	var (
+		page     PageIncident
+		resolved bool
+	)
+	for _, update := range updates {
+		if update.State != model.StatusPageUpdatePublished || update.PageIncidentId == "" {
+			continue
+		}
+		page = PageIncident{ID: update.PageIncidentId, URL: update.PageURL}
+		resolved = update.Status == StatusResolved
+	}
+	return page, resolved, nil
+}
+
+// publish creates the public incident, or updates it when it was already created
+func (s *Service) publish(ctx context.Context, incident model.Incident, page PageIncident, status string, body string) (PageIncident, error) {
+	update := Update{
+		Name:       incident.Title,
+		Status:     status,
+		Body:       body,
+		Components: s.components.Statuses(incident, status),
+	}
+	if page.ID == "" {
+		return s.provider.CreateIncident(ctx, update)
+	}
+	return s.provider.UpdateIncident(ctx, page.ID, update)
+}
+
+func (s *Service) post(ctx context.Context, channelID string, text string) error {
+	_, _, err := s.client.PostMessage(channelID, slack.MsgOptionText(text, false))
+	if err != nil {
+		s.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("client.PostMessage"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", channelID),
+		)
+	}
+	return err
+}
+
+func (s *Service) ephemeral(ctx context.Context, channelID string, userID string, text string) error {
+	_, err := s.client.PostEphemeralContext(ctx, channelID, userID, slack.MsgOptionText(text, false))
+	if err != nil {
+		s.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("client.PostEphemeralContext"),
+			log.Reason(err.Error()),
+			log.NewValue("channelID", channelID),
+		)
+	}
+	return err
+}
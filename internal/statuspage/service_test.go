@@ -0,0 +1,257 @@
+package statuspage_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"hellper/internal/bot"
+	"hellper/internal/concurrence"
+	"hellper/internal/lifecycle"
+	"hellper/internal/log"
+	"hellper/internal/model"
+	"hellper/internal/statuspage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type failingProvider struct{}
+
+func (failingProvider) CreateIncident(ctx context.Context, update statuspage.Update) (statuspage.PageIncident, error) {
+	return statuspage.PageIncident{}, errors.New("unauthorized")
+}
+
+func (failingProvider) UpdateIncident(ctx context.Context, id string, update statuspage.Update) (statuspage.PageIncident, error) {
+	return statuspage.PageIncident{}, errors.New("unauthorized")
+}
+
+func TestReview(t *testing.T) {
+	published := model.StatusPageUpdate{Id: 1, IncidentId: 7, Status: statuspage.StatusInvestigating, State: model.StatusPageUpdatePublished, PageIncidentId: "1", PageURL: "https://status.example.com/incidents/1"}
+
+	table := []struct {
+		testName      string
+		userID        string
+		publish       bool
+		provider      statuspage.Provider
+		pageIncident  bool
+		roles         []model.IncidentRole
+		draft         model.StatusPageUpdate
+		previous      []model.StatusPageUpdate
+		claimed       bool
+		expectedState string
+		expectedPage  string
+		ephemeral     bool
+	}{
+		{
+			testName:      "Comms lead publishes the first update",
+			userID:        "U1",
+			publish:       true,
+			expectedState: model.StatusPageUpdatePublished,
+			expectedPage:  "1",
+		},
+		{
+			testName:      "Comms lead publishes a follow-up update",
+			userID:        "U1",
+			publish:       true,
+			pageIncident:  true,
+			previous:      []model.StatusPageUpdate{published},
+			expectedState: model.StatusPageUpdatePublished,
+			expectedPage:  "1",
+		},
+		{
+			testName:      "Comms lead discards the draft",
+			userID:        "U1",
+			expectedState: model.StatusPageUpdateDiscarded,
+		},
+		{
+			testName:      "Provider failure",
+			userID:        "U1",
+			publish:       true,
+			provider:      failingProvider{},
+			expectedState: model.StatusPageUpdateFailed,
+		},
+		{
+			testName:  "Only the comms lead reviews",
+			userID:    "U2",
+			publish:   true,
+			ephemeral: true,
+		},
+		{
+			testName:  "Without comms lead",
+			userID:    "U1",
+			publish:   true,
+			roles:     []model.IncidentRole{},
+			ephemeral: true,
+		},
+		{
+			testName:  "Already reviewed",
+			userID:    "U1",
+			publish:   true,
+			draft:     model.StatusPageUpdate{Id: 2, IncidentId: 7, Status: statuspage.StatusMonitoring, State: model.StatusPageUpdateDiscarded},
+			ephemeral: true,
+		},
+		{
+			testName:  "Draft claimed by another click",
+			userID:    "U1",
+			publish:   true,
+			claimed:   true,
+			ephemeral: true,
+		},
+		{
+			testName: "Public incident already resolved",
+			userID:   "U1",
+			publish:  true,
+			previous: []model.StatusPageUpdate{
+				published,
+				{Id: 3, IncidentId: 7, Status: statuspage.StatusResolved, State: model.StatusPageUpdatePublished, PageIncidentId: "1"},
+			},
+			ephemeral: true,
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			var (
+				ctx            = context.Background()
+				loggerMock     = log.NewLoggerMock()
+				clientMock     = bot.NewClientMock()
+				repositoryMock = model.NewRepositoryMock()
+				fake           = statuspage.NewFake()
+				provider       = f.provider
+				roles          = f.roles
+				draft          = f.draft
+				reviewErr      error
+				states         []string
+			)
+			if provider == nil {
+				provider = fake
+			}
+			if roles == nil {
+				roles = []model.IncidentRole{{Role: model.RoleCommsLead, UserId: "U1"}}
+			}
+			if draft.Id == 0 {
+				draft = model.StatusPageUpdate{Id: 2, IncidentId: 7, Status: statuspage.StatusMonitoring, Body: "A fix is deployed", State: model.StatusPageUpdatePending}
+			}
+			if f.pageIncident {
+				_, err := fake.CreateIncident(ctx, statuspage.Update{Name: "Checkout errors", Status: statuspage.StatusInvestigating})
+				assert.NoError(t, err)
+			}
+
+			loggerMock.On("Error", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+			clientMock.On("PostMessage", "C1", mock.Anything).Return("", "", nil)
+			clientMock.On("PostEphemeralContext", ctx, "C1", f.userID, mock.Anything).Return("", nil)
+			repositoryMock.On("GetIncident", "C1").Return(model.Incident{Id: 7, ChannelId: "C1", Title: "Checkout errors", Product: "Checkout"}, nil)
+			repositoryMock.On("ListIncidentRoles", ctx, int64(7)).Return(roles, nil)
+			repositoryMock.On("GetStatusPageUpdate", ctx, int64(2)).Return(draft, nil)
+			repositoryMock.On("ListStatusPageUpdates", ctx, int64(7)).Return(f.previous, nil)
+			if f.claimed {
+				reviewErr = model.ErrStatusPageUpdateReviewed
+			}
+			repositoryMock.On("ReviewStatusPageUpdate", ctx, mock.AnythingOfType("*model.StatusPageUpdate")).Return(reviewErr).Run(func(args mock.Arguments) {
+				states = append(states, args.Get(1).(*model.StatusPageUpdate).State)
+			})
+
+			service := statuspage.NewService(loggerMock, clientMock, repositoryMock, &concurrence.Background{}, provider, statuspage.Components{"Checkout": {"cmp1"}})
+			err := service.Review(ctx, "C1", f.userID, 2, f.publish)
+			assert.NoError(t, err)
+
+			if f.ephemeral {
+				clientMock.AssertCalled(t, "PostEphemeralContext", ctx, "C1", f.userID, mock.Anything)
+				if f.claimed {
+					assert.Equal(t, []string{model.StatusPageUpdatePublishing}, states)
+					assert.Empty(t, fake.Updates("1"), "the claimed draft is published once")
+				} else {
+					assert.Empty(t, states)
+				}
+				return
+			}
+
+			if f.publish {
+				assert.Equal(t, []string{model.StatusPageUpdatePublishing, f.expectedState}, states)
+			} else {
+				assert.Equal(t, []string{model.StatusPageUpdateDiscarded}, states)
+			}
+
+			clientMock.AssertCalled(t, "PostMessage", "C1", mock.Anything)
+			reviewed := repositoryMock.Calls[len(repositoryMock.Calls)-1].Arguments.Get(1).(*model.StatusPageUpdate)
+			assert.Equal(t, f.expectedState, reviewed.State)
+			assert.Equal(t, "U1", reviewed.ReviewedBy)
+			assert.Equal(t, f.expectedPage, reviewed.PageIncidentId)
+			if f.expectedState == model.StatusPageUpdateFailed {
+				assert.Equal(t, "unauthorized", reviewed.Error)
+			}
+			if f.expectedPage != "" {
+				updates := fake.Updates(f.expectedPage)
+				last := updates[len(updates)-1]
+				assert.Equal(t, statuspage.StatusMonitoring, last.Status)
+				assert.Equal(t, map[string]string{"cmp1": statuspage.ComponentMajorOutage}, last.Components)
+			}
+		})
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	var (
+		ctx            = context.Background()
+		loggerMock     = log.NewLoggerMock()
+		clientMock     = bot.NewClientMock()
+		repositoryMock = model.NewRepositoryMock()
+		fake           = statuspage.NewFake()
+		incident       = model.Incident{Id: 7, ChannelId: "C1", Title: "Checkout errors", Product: "Checkout", SeverityLevel: 1, DescriptionResolved: "Payments are back"}
+	)
+
+	page, err := fake.CreateIncident(ctx, statuspage.Update{Name: incident.Title, Status: statuspage.StatusInvestigating})
+	assert.NoError(t, err)
+
+	loggerMock.On("Error", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+	clientMock.On("PostMessage", "C1", mock.Anything).Return("", "", nil)
+	repositoryMock.On("InsertStatusPageUpdate", mock.Anything, mock.AnythingOfType("*model.StatusPageUpdate")).Return(nil)
+	repositoryMock.On("ListIncidentRoles", mock.Anything, int64(7)).Return([]model.IncidentRole{}, nil)
+	repositoryMock.On("ListStatusPageUpdates", mock.Anything, int64(7)).Return([]model.StatusPageUpdate{
+		{IncidentId: 7, Status: statuspage.StatusInvestigating, State: model.StatusPageUpdatePublished, PageIncidentId: page.ID, PageURL: page.URL},
+	}, nil)
+
+	background := &concurrence.Background{}
+	service := statuspage.NewService(loggerMock, clientMock, repositoryMock, background, fake, statuspage.Components{"Checkout": {"cmp1"}})
+	assert.True(t, service.Wants(lifecycle.EventOpened))
+	assert.True(t, service.Wants(lifecycle.EventUpdated))
+	assert.True(t, service.Wants(lifecycle.EventResolved))
+	assert.False(t, service.Wants(lifecycle.EventClosed))
+
+	service.Dispatch(ctx, lifecycle.EventOpened, incident)
+	assert.NoError(t, background.Wait(ctx))
+
+	drafted := repositoryMock.Calls[0].Arguments.Get(1).(*model.StatusPageUpdate)
+	assert.Equal(t, model.StatusPageUpdatePending, drafted.State)
+	assert.Equal(t, statuspage.StatusInvestigating, drafted.Status)
+	assert.Equal(t, "Checkout errors", drafted.Body)
+	assert.Len(t, fake.Updates(page.ID), 1, "drafts wait for the comms lead")
+
+	identified := time.Date(2020, time.March, 19, 12, 0, 0, 0, time.UTC)
+	updated := incident
+	updated.Status = model.StatusOpen
+	updated.IdentificationTimestamp = &identified
+	service.Dispatch(ctx, lifecycle.EventUpdated, updated)
+	assert.NoError(t, background.Wait(ctx))
+
+	drafted = repositoryMock.Calls[len(repositoryMock.Calls)-2].Arguments.Get(1).(*model.StatusPageUpdate)
+	assert.Equal(t, model.StatusPageUpdatePending, drafted.State)
+	assert.Equal(t, statuspage.StatusIdentified, drafted.Status)
+	assert.Len(t, fake.Updates(page.ID), 1, "drafts wait for the comms lead")
+
+	service.Dispatch(ctx, lifecycle.EventResolved, incident)
+	assert.NoError(t, background.Wait(ctx))
+
+	updates := fake.Updates(page.ID)
+	assert.Len(t, updates, 2)
+	assert.Equal(t, statuspage.Update{
+		Name:       "Checkout errors",
+		Status:     statuspage.StatusResolved,
+		Body:       "Payments are back",
+		Components: map[string]string{"cmp1": statuspage.ComponentOperational},
+	}, updates[1])
+	loggerMock.AssertNotCalled(t, "Error", mock.Anything, mock.Anything, mock.Anything)
+}
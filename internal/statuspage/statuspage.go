@@ -0,0 +1,125 @@
+// Package statuspage publishes the public incident of each incident on a status page
+package statuspage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"hellper/internal/model"
+)
+
+// Statuses of a public incident
+const (
+	StatusInvestigating = "investigating"
+	StatusIdentified    = "identified"
+	StatusMonitoring    = "monitoring"
+	StatusResolved      = "resolved"
+)
+
+// Statuses lists the statuses of the drafts, the public incident is resolved with the incident
+var Statuses = []string{StatusInvestigating, StatusIdentified, StatusMonitoring}
+
+// Statuses of the components affected by a public incident
+const (
+	ComponentOperational   = "operational"
+	ComponentDegraded      = "degraded_performance"
+	ComponentPartialOutage = "partial_outage"
+	ComponentMajorOutage   = "major_outage"
+)
+
+// ErrInvalidComponents is returned when the components can not be parsed
+var ErrInvalidComponents = errors.New("invalid status page components")
+
+// Components are the ids of the status page components affected by the incidents of each product
+type Components map[string][]string
+
+// Update is what is published on the public incident
+type Update struct {
+	Name       string
+	Status     string
+	Body       string
+	Components map[string]string
+}
+
+// PageIncident is the public incident on the status page
+type PageIncident struct {
+	ID  string
+	URL string
+}
+
+// Provider creates and updates the public incidents of a status page
+type Provider interface {
+	CreateIncident(ctx context.Context, update Update) (PageIncident, error)
+	UpdateIncident(ctx context.Context, id string, update Update) (PageIncident, error)
+}
+
+// IsStatus tells whether the status can be drafted
+func IsStatus(status string) bool {
+	for _, s := range Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseComponents reads the components in the format product=id,id;product=id, e.g. Checkout=cmp1,cmp2;Search=cmp3
+func ParseComponents(value string) (Components, error) {
+	components := Components{}
+
+	for _, statement := range strings.Split(value, ";") {
+		statement = strings.TrimSpace(statement)
+		if statement == "" {
+			continue
+		}
+
+		parts := strings.SplitN(statement, "=", 2)
+		product := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || product == "" {
+			return nil, fmt.Errorf("%w: %q has no product", ErrInvalidComponents, statement)
+		}
+
+		for _, id := range strings.Split(parts[1], ",") {
+			id = strings.TrimSpace(id)
+			if id == "" {
+				return nil, fmt.Errorf("%w: empty component of %s", ErrInvalidComponents, product)
+			}
+			components[product] = append(components[product], id)
+		}
+	}
+
+	return components, nil
+}
+
+// Statuses are the statuses of the components of the incident product, operational once the public incident is resolved
+func (c Components) Statuses(inc model.Incident, status string) map[string]string {
+	ids := c[inc.Product]
+	if len(ids) == 0 {
+		return nil
+	}
+
+	componentStatus := ComponentStatus(inc.SeverityLevel)
+	if status == StatusResolved {
+		componentStatus = ComponentOperational
+	}
+
+	statuses := make(map[string]string, len(ids))
+	for _, id := range ids {
+		statuses[id] = componentStatus
+	}
+	return statuses
+}
+
+// ComponentStatus is the status of the affected components while an incident of the severity is running
+func ComponentStatus(severityLevel int64) string {
+	switch severityLevel {
+	case 0:
+		return ComponentMajorOutage
+	case 1:
+		return ComponentPartialOutage
+	default:
+		return ComponentDegraded
+	}
+}
@@ -0,0 +1,99 @@
+package statuspage_test
+
+import (
+	"fmt"
+	"testing"
+
+	"hellper/internal/model"
+	"hellper/internal/statuspage"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseComponents(t *testing.T) {
+	table := []struct {
+		testName      string
+		value         string
+		expected      statuspage.Components
+		expectedError string
+	}{
+		{
+			testName: "Components by product",
+			value:    "Checkout=cmp1, cmp2;Search=cmp3",
+			expected: statuspage.Components{"Checkout": {"cmp1", "cmp2"}, "Search": {"cmp3"}},
+		},
+		{
+			testName: "Empty",
+			expected: statuspage.Components{},
+		},
+		{
+			testName:      "Missing product",
+			value:         "cmp1",
+			expectedError: `invalid status page components: "cmp1" has no product`,
+		},
+		{
+			testName:      "Empty component",
+			value:         "Checkout=cmp1,",
+			expectedError: "invalid status page components: empty component of Checkout",
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			components, err := statuspage.ParseComponents(f.value)
+			if f.expectedError != "" {
+				assert.EqualError(t, err, f.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, f.expected, components)
+		})
+	}
+}
+
+func TestComponentStatuses(t *testing.T) {
+	components := statuspage.Components{"Checkout": {"cmp1", "cmp2"}}
+
+	table := []struct {
+		testName string
+		incident model.Incident
+		status   string
+		expected map[string]string
+	}{
+		{
+			testName: "SEV0 is a major outage",
+			incident: model.Incident{Product: "Checkout", SeverityLevel: 0},
+			status:   statuspage.StatusInvestigating,
+			expected: map[string]string{"cmp1": statuspage.ComponentMajorOutage, "cmp2": statuspage.ComponentMajorOutage},
+		},
+		{
+			testName: "SEV1 is a partial outage",
+			incident: model.Incident{Product: "Checkout", SeverityLevel: 1},
+			status:   statuspage.StatusIdentified,
+			expected: map[string]string{"cmp1": statuspage.ComponentPartialOutage, "cmp2": statuspage.ComponentPartialOutage},
+		},
+		{
+			testName: "Lower severities degrade the performance",
+			incident: model.Incident{Product: "Checkout", SeverityLevel: 3},
+			status:   statuspage.StatusMonitoring,
+			expected: map[string]string{"cmp1": statuspage.ComponentDegraded, "cmp2": statuspage.ComponentDegraded},
+		},
+		{
+			testName: "Resolved is operational",
+			incident: model.Incident{Product: "Checkout", SeverityLevel: 0},
+			status:   statuspage.StatusResolved,
+			expected: map[string]string{"cmp1": statuspage.ComponentOperational, "cmp2": statuspage.ComponentOperational},
+		},
+		{
+			testName: "Product without components",
+			incident: model.Incident{Product: "Search"},
+			status:   statuspage.StatusInvestigating,
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			assert.Equal(t, f.expected, components.Statuses(f.incident, f.status))
+		})
+	}
+}
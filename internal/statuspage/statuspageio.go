@@ -0,0 +1,100 @@
+package statuspage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// StatuspageIOURL is the API of Statuspage.io
+const StatuspageIOURL = "https://api.statuspage.io/v1"
+
+type statuspageIO struct {
+	baseURL    string
+	pageID     string
+	apiKey     string
+	httpClient *http.Client
+}
+
+type statuspageIOIncident struct {
+	Name         string            `json:"name,omitempty"`
+	Status       string            `json:"status"`
+	Body         string            `json:"body"`
+	ComponentIDs []string          `json:"component_ids,omitempty"`
+	Components   map[string]string `json:"components,omitempty"`
+}
+
+type statuspageIOResponse struct {
+	ID        string `json:"id"`
+	Shortlink string `json:"shortlink"`
+}
+
+// NewStatuspageIO creates a Provider on the page of a Statuspage.io compatible API
+func NewStatuspageIO(baseURL string, pageID string, apiKey string, timeout time.Duration) Provider {
+	return &statuspageIO{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		pageID:     pageID,
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *statuspageIO) CreateIncident(ctx context.Context, update Update) (PageIncident, error) {
+	return s.send(ctx, http.MethodPost, s.baseURL+"/pages/"+s.pageID+"/incidents", update)
+}
+
+func (s *statuspageIO) UpdateIncident(ctx context.Context, id string, update Update) (PageIncident, error) {
+	// The name of the public incident is only set on creation
+	update.Name = ""
+	return s.send(ctx, http.MethodPatch, s.baseURL+"/pages/"+s.pageID+"/incidents/"+id, update)
+}
+
+func (s *statuspageIO) send(ctx context.Context, method string, url string, update Update) (PageIncident, error) {
+	incident := statuspageIOIncident{
+		Name:       update.Name,
+		Status:     update.Status,
+		Body:       update.Body,
+		Components: update.Components,
+	}
+	for id := range update.Components {
+		incident.ComponentIDs = append(incident.ComponentIDs, id)
+	}
+
+	body, err := json.Marshal(map[string]statuspageIOIncident{"incident": incident})
+	if err != nil {
+		return PageIncident{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return PageIncident{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "OAuth "+s.apiKey)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return PageIncident{}, err
+	}
+	defer resp.Body.Close()
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return PageIncident{}, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return PageIncident{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(content)))
+	}
+
+	var response statuspageIOResponse
+	err = json.Unmarshal(content, &response)
+	if err != nil {
+		return PageIncident{}, err
+	}
+	return PageIncident{ID: response.ID, URL: response.Shortlink}, nil
+}
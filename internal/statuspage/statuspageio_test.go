@@ -0,0 +1,69 @@
+package statuspage_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"hellper/internal/statuspage"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatuspageIO(t *testing.T) {
+	var (
+		requests []*http.Request
+		bodies   []map[string]map[string]interface{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, _ := ioutil.ReadAll(r.Body)
+		body := map[string]map[string]interface{}{}
+		_ = json.Unmarshal(content, &body)
+
+		requests = append(requests, r)
+		bodies = append(bodies, body)
+		if r.URL.Path == "/pages/page1/incidents/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"not found"}`))
+			return
+		}
+		w.Write([]byte(`{"id":"inc1","shortlink":"https://stspg.io/abc"}`))
+	}))
+	defer server.Close()
+
+	var (
+		ctx      = context.Background()
+		provider = statuspage.NewStatuspageIO(server.URL+"/", "page1", "key1", time.Second)
+		update   = statuspage.Update{
+			Name:       "Checkout errors",
+			Status:     statuspage.StatusInvestigating,
+			Body:       "Payments are failing",
+			Components: map[string]string{"cmp1": statuspage.ComponentMajorOutage},
+		}
+	)
+
+	page, err := provider.CreateIncident(ctx, update)
+	assert.NoError(t, err)
+	assert.Equal(t, statuspage.PageIncident{ID: "inc1", URL: "https://stspg.io/abc"}, page)
+	assert.Equal(t, http.MethodPost, requests[0].Method)
+	assert.Equal(t, "/pages/page1/incidents", requests[0].URL.Path)
+	assert.Equal(t, "OAuth key1", requests[0].Header.Get("Authorization"))
+	assert.Equal(t, "Checkout errors", bodies[0]["incident"]["name"])
+	assert.Equal(t, []interface{}{"cmp1"}, bodies[0]["incident"]["component_ids"])
+	assert.Equal(t, map[string]interface{}{"cmp1": "major_outage"}, bodies[0]["incident"]["components"])
+
+	update.Status = statuspage.StatusResolved
+	_, err = provider.UpdateIncident(ctx, "inc1", update)
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodPatch, requests[1].Method)
+	assert.Equal(t, "/pages/page1/incidents/inc1", requests[1].URL.Path)
+	assert.Equal(t, "resolved", bodies[1]["incident"]["status"])
+	assert.NotContains(t, bodies[1]["incident"], "name")
+
+	_, err = provider.UpdateIncident(ctx, "missing", update)
+	assert.EqualError(t, err, `unexpected status 404: {"error":"not found"}`)
+}
@@ -0,0 +1,33 @@
+package warroom
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"hellper/internal/model"
+)
+
+type matrix struct {
+	host     string
+	roomID   string
+	roomName string
+}
+
+// NewMatrix creates a Provider of Matrix rooms on the host, one room per incident channel.
+// With a room id every incident shares that room, named roomName, as the staging environment does
+func NewMatrix(host string, roomID string, roomName string) Provider {
+	return &matrix{
+		host:     strings.TrimSuffix(host, "/"),
+		roomID:   roomID,
+		roomName: roomName,
+	}
+}
+
+func (m *matrix) CreateRoom(ctx context.Context, inc model.Incident) (string, error) {
+	roomID, roomName := inc.ChannelName, inc.ChannelName
+	if m.roomID != "" {
+		roomID, roomName = m.roomID, m.roomName
+	}
+	return m.host + "/new?roomId=" + url.QueryEscape(roomID) + "&roomName=" + url.QueryEscape(roomName), nil
+}
@@ -0,0 +1,40 @@
+package warroom
+
+import (
+	"context"
+	"time"
+
+	"hellper/internal/calendar"
+	"hellper/internal/model"
+)
+
+type googleMeet struct {
+	calendar calendar.Calendar
+	duration time.Duration
+}
+
+// NewGoogleMeet creates a Provider of Google Meet rooms, booked on the calendar as a meeting of the commander
+// starting now and lasting the duration
+func NewGoogleMeet(calendar calendar.Calendar, duration time.Duration) Provider {
+	return &googleMeet{
+		calendar: calendar,
+		duration: duration,
+	}
+}
+
+func (g *googleMeet) CreateRoom(ctx context.Context, inc model.Incident) (string, error) {
+	var (
+		start   = time.Now().UTC()
+		end     = start.Add(g.duration)
+		summary = "[War Room] " + inc.Title
+	)
+
+	event, err := g.calendar.CreateCalendarEvent(ctx, start.Format(time.RFC3339), end.Format(time.RFC3339), summary, inc.CommanderEmail, nil)
+	if err != nil {
+		return "", err
+	}
+	if event == nil || event.ConferenceURL == "" {
+		return "", ErrNoConference
+	}
+	return event.ConferenceURL, nil
+}
@@ -0,0 +1,27 @@
+package warroom
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"hellper/internal/model"
+)
+
+type staticURL struct {
+	template string
+}
+
+// NewTemplate creates a Provider of static URLs, the {channel_name} and {channel_id} placeholders of the template are
+// replaced by those of the incident
+func NewTemplate(urlTemplate string) Provider {
+	return &staticURL{template: urlTemplate}
+}
+
+func (s *staticURL) CreateRoom(ctx context.Context, inc model.Incident) (string, error) {
+	replacer := strings.NewReplacer(
+		"{channel_name}", url.PathEscape(inc.ChannelName),
+		"{channel_id}", url.PathEscape(inc.ChannelId),
+	)
+	return replacer.Replace(s.template), nil
+}
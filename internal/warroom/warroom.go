@@ -0,0 +1,17 @@
+// Package warroom creates the rooms the responders of an incident meet in
+package warroom
+
+import (
+	"context"
+	"errors"
+
+	"hellper/internal/model"
+)
+
+// ErrNoConference is returned when the meeting of the war room has no conference link
+var ErrNoConference = errors.New("the war room meeting has no conference link")
+
+// Provider creates the war room of an incident, returning its URL
+type Provider interface {
+	CreateRoom(ctx context.Context, inc model.Incident) (string, error)
+}
@@ -0,0 +1,117 @@
+package warroom_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"hellper/internal/calendar"
+	"hellper/internal/model"
+	"hellper/internal/warroom"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateRoom(t *testing.T) {
+	inc := model.Incident{Title: "Checkout errors", ChannelName: "inc-checkout", ChannelId: "C1", CommanderEmail: "commander@example.com"}
+
+	table := []struct {
+		testName string
+		provider warroom.Provider
+		expected string
+	}{
+		{
+			testName: "Matrix room of the channel",
+			provider: warroom.NewMatrix("https://matrix.example.com/", "", ""),
+			expected: "https://matrix.example.com/new?roomId=inc-checkout&roomName=inc-checkout",
+		},
+		{
+			testName: "Shared Matrix room",
+			provider: warroom.NewMatrix("https://matrix.example.com", "dc82e346", "hellper-staging"),
+			expected: "https://matrix.example.com/new?roomId=dc82e346&roomName=hellper-staging",
+		},
+		{
+			testName: "Template",
+			provider: warroom.NewTemplate("https://zoom.example.com/my/{channel_name}?channel={channel_id}"),
+			expected: "https://zoom.example.com/my/inc-checkout?channel=C1",
+		},
+		{
+			testName: "Template without placeholders",
+			provider: warroom.NewTemplate("https://meet.example.com/incidents"),
+			expected: "https://meet.example.com/incidents",
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			url, err := f.provider.CreateRoom(context.Background(), inc)
+			assert.NoError(t, err)
+			assert.Equal(t, f.expected, url)
+		})
+	}
+}
+
+func TestGoogleMeet(t *testing.T) {
+	inc := model.Incident{Title: "Checkout errors", ChannelName: "inc-checkout", CommanderEmail: "commander@example.com"}
+
+	table := []struct {
+		testName      string
+		event         *model.Event
+		eventError    error
+		expected      string
+		expectedError string
+	}{
+		{
+			testName: "Conference link of the meeting",
+			event:    &model.Event{EventURL: "https://calendar.example.com/event", ConferenceURL: "https://meet.google.com/abc-defg-hij"},
+			expected: "https://meet.google.com/abc-defg-hij",
+		},
+		{
+			testName:      "Meeting without conference",
+			event:         &model.Event{EventURL: "https://calendar.example.com/event"},
+			expectedError: warroom.ErrNoConference.Error(),
+		},
+		{
+			testName:      "Calendar scheduling nothing",
+			expectedError: warroom.ErrNoConference.Error(),
+		},
+		{
+			testName:      "Calendar failure",
+			eventError:    errors.New("calendar unavailable"),
+			expectedError: "calendar unavailable",
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			var (
+				ctx          = context.Background()
+				calendarMock = calendar.NewCalendarMock()
+			)
+			calendarMock.On(
+				"CreateCalendarEvent",
+				ctx,
+				mock.AnythingOfType("string"),
+				mock.AnythingOfType("string"),
+				"[War Room] Checkout errors",
+				"commander@example.com",
+				mock.Anything,
+			).Return(f.event, f.eventError)
+
+			url, err := warroom.NewGoogleMeet(calendarMock, time.Hour).CreateRoom(ctx, inc)
+			if f.expectedError != "" {
+				assert.EqualError(t, err, f.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, f.expected, url)
+
+			start, _ := time.Parse(time.RFC3339, calendarMock.Calls[0].Arguments.String(1))
+			end, _ := time.Parse(time.RFC3339, calendarMock.Calls[0].Arguments.String(2))
+			assert.Equal(t, time.Hour, end.Sub(start))
+		})
+	}
+}
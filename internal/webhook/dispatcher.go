@@ -0,0 +1,169 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"hellper/internal/concurrence"
+	"hellper/internal/log"
+	"hellper/internal/model"
+)
+
+// Dispatcher posts the incident events to the subscriptions, retrying the failed deliveries in background
+type Dispatcher struct {
+	logger        log.Logger
+	repository    model.Repository
+	background    *concurrence.Background
+	httpClient    *http.Client
+	subscriptions []Subscription
+	maxAttempts   int
+	backoff       time.Duration
+	sleep         func(time.Duration)
+	now           func() time.Time
+}
+
+// NewDispatcher creates a Dispatcher delivering on the background, every attempt is recorded on the delivery log of the repository
+func NewDispatcher(logger log.Logger, repository model.Repository, background *concurrence.Background, subscriptions []Subscription, maxAttempts int, timeout time.Duration) *Dispatcher {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &Dispatcher{
+		logger:        logger,
+		repository:    repository,
+		background:    background,
+		httpClient:    &http.Client{Timeout: timeout},
+		subscriptions: subscriptions,
+		maxAttempts:   maxAttempts,
+		backoff:       time.Second,
+		sleep:         time.Sleep,
+		now:           time.Now,
+	}
+}
+
+// Wants tells whether a subscription receives the event
+func (d *Dispatcher) Wants(event string) bool {
+	for _, subscription := range d.subscriptions {
+		if subscription.wants(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// Dispatch sends the event to the subscriptions in background, the caller does not wait for the deliveries
+func (d *Dispatcher) Dispatch(ctx context.Context, event string, incident model.Incident) {
+	payload := NewPayload(event, incident, d.now())
+	body, err := json.Marshal(payload)
+	if err != nil {
+		d.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("json.Marshal"),
+			log.Reason(err.Error()),
+			log.NewValue("event", event),
+			log.NewValue("channelID", incident.ChannelId),
+		)
+		return
+	}
+
+	for _, subscription := range d.subscriptions {
+		if !subscription.wants(event) {
+			continue
+		}
+
+		subscription := subscription
+		d.background.Go(func() {
+			d.deliver(context.Background(), subscription, payload, body)
+		})
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, subscription Subscription, payload Payload, body []byte) {
+	backoff := d.backoff
+	for attempt := 1; attempt <= d.maxAttempts; attempt++ {
+		statusCode, err := d.post(ctx, subscription, payload, body)
+
+		delivery := &model.WebhookDelivery{
+			EventId:      payload.ID,
+			Event:        payload.Event,
+			IncidentId:   payload.Incident.ID,
+			Subscription: subscription.Name,
+			URL:          subscription.URL,
+			Attempt:      attempt,
+			StatusCode:   statusCode,
+			Delivered:    err == nil,
+		}
+		if err != nil {
+			delivery.Error = err.Error()
+		}
+
+		insertErr := d.repository.InsertWebhookDelivery(ctx, delivery)
+		if insertErr != nil {
+			d.logger.Error(
+				ctx,
+				log.Trace(),
+				log.Action("repository.InsertWebhookDelivery"),
+				log.Reason(insertErr.Error()),
+				log.NewValue("eventID", payload.ID),
+				log.NewValue("subscription", subscription.Name),
+			)
+		}
+
+		if err == nil {
+			return
+		}
+
+		d.logger.Error(
+			ctx,
+			log.Trace(),
+			log.Action("webhook.post"),
+			log.Reason(err.Error()),
+			log.NewValue("eventID", payload.ID),
+			log.NewValue("event", payload.Event),
+			log.NewValue("subscription", subscription.Name),
+			log.NewValue("attempt", attempt),
+		)
+		if !retryable(statusCode) || attempt == d.maxAttempts {
+			return
+		}
+
+		d.sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (d *Dispatcher) post(ctx context.Context, subscription Subscription, payload Payload, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "hellper-webhook/"+PayloadVersion)
+	req.Header.Set("X-Hellper-Event", payload.Event)
+	req.Header.Set("X-Hellper-Delivery", payload.ID)
+	// Every attempt is signed again, so a retry doesn't carry the timestamp of the first one
+	timestamp := d.now().Unix()
+	req.Header.Set("X-Hellper-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Hellper-Signature", "sha256="+Sign(subscription.Secret, timestamp, body))
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, errors.New("unexpected status " + strconv.Itoa(resp.StatusCode))
+	}
+	return resp.StatusCode, nil
+}
+
+// retryable tells whether a failed delivery may succeed later, network errors have no status code
+func retryable(statusCode int) bool {
+	return statusCode == 0 || statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
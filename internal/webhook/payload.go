@@ -0,0 +1,112 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"hellper/internal/model"
+)
+
+// PayloadVersion is bumped whenever a field of the payload changes or is removed
+const PayloadVersion = "1"
+
+// Payload is the body posted to the subscriptions
+type Payload struct {
+	Version    string    `json:"version"`
+	ID         string    `json:"id"`
+	Event      string    `json:"event"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Incident   Incident  `json:"incident"`
+}
+
+// Incident is the incident as sent on the payload, decoupled from the database model
+type Incident struct {
+	ID                   int64      `json:"id"`
+	Title                string     `json:"title"`
+	Status               string     `json:"status"`
+	Product              string     `json:"product"`
+	SeverityLevel        int64      `json:"severity_level"`
+	ChannelID            string     `json:"channel_id"`
+	ChannelName          string     `json:"channel_name"`
+	CommanderID          string     `json:"commander_id"`
+	CommanderEmail       string     `json:"commander_email"`
+	AuthorID             string     `json:"author_id"`
+	DescriptionStarted   string     `json:"description_started"`
+	DescriptionResolved  string     `json:"description_resolved"`
+	DescriptionCancelled string     `json:"description_cancelled"`
+	Team                 string     `json:"team"`
+	Responsibility       string     `json:"responsibility"`
+	Functionality        string     `json:"functionality"`
+	RootCause            string     `json:"root_cause"`
+	CustomerImpact       *int64     `json:"customer_impact"`
+	StatusPageURL        string     `json:"status_page_url"`
+	PostMortemURL        string     `json:"post_mortem_url"`
+	StartedAt            *time.Time `json:"started_at"`
+	IdentifiedAt         *time.Time `json:"identified_at"`
+	ResolvedAt           *time.Time `json:"resolved_at"`
+	ClosedAt             *time.Time `json:"closed_at"`
+	SnoozedUntil         *time.Time `json:"snoozed_until"`
+}
+
+// NewPayload builds the payload of the event
+func NewPayload(event string, inc model.Incident, now time.Time) Payload {
+	payload := Payload{
+		Version:    PayloadVersion,
+		ID:         newEventID(),
+		Event:      event,
+		OccurredAt: now.UTC(),
+		Incident: Incident{
+			ID:                   inc.Id,
+			Title:                inc.Title,
+			Status:               inc.Status,
+			Product:              inc.Product,
+			SeverityLevel:        inc.SeverityLevel,
+			ChannelID:            inc.ChannelId,
+			ChannelName:          inc.ChannelName,
+			CommanderID:          inc.CommanderId,
+			CommanderEmail:       inc.CommanderEmail,
+			AuthorID:             inc.IncidentAuthor,
+			DescriptionStarted:   inc.DescriptionStarted,
+			DescriptionResolved:  inc.DescriptionResolved,
+			DescriptionCancelled: inc.DescriptionCancelled,
+			Team:                 inc.Team,
+			Responsibility:       inc.Responsibility,
+			Functionality:        inc.Functionality,
+			RootCause:            inc.RootCause,
+			StatusPageURL:        inc.StatusPageUrl,
+			PostMortemURL:        inc.PostMortemUrl,
+			StartedAt:            inc.StartTimestamp,
+			IdentifiedAt:         inc.IdentificationTimestamp,
+			ResolvedAt:           inc.EndTimestamp,
+			ClosedAt:             inc.ClosedAt,
+		},
+	}
+
+	if inc.CustomerImpact.Valid {
+		payload.Incident.CustomerImpact = &inc.CustomerImpact.Int64
+	}
+	if inc.SnoozedUntil.Valid {
+		payload.Incident.SnoozedUntil = &inc.SnoozedUntil.Time
+	}
+
+	return payload
+}
+
+// Sign is the hex HMAC-SHA256 of "t=<timestamp>.<body>" with the subscription secret, sent as "sha256=<signature>".
+// The unix timestamp goes on its own header, so the receivers can reject the replays of old deliveries
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("t=" + strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newEventID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
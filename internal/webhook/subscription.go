@@ -0,0 +1,105 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+
+	"hellper/internal/lifecycle"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidSubscription is returned when the webhooks file has an invalid subscription
+var ErrInvalidSubscription = errors.New("invalid webhook subscription")
+
+// Subscription is an endpoint receiving some of the incident events
+type Subscription struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`
+	// Secret signs the payloads, SecretEnv reads it from an environment variable instead of the file
+	Secret    string   `yaml:"secret"`
+	SecretEnv string   `yaml:"secret_env"`
+	Events    []string `yaml:"events"`
+}
+
+type subscriptionsFile struct {
+	Subscriptions []Subscription `yaml:"subscriptions"`
+}
+
+// LoadSubscriptions reads a YAML webhooks file
+func LoadSubscriptions(path string) ([]Subscription, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSubscriptions(content)
+}
+
+// ParseSubscriptions parses and validates the webhook subscriptions, resolving the secrets read from the environment
+func ParseSubscriptions(content []byte) ([]Subscription, error) {
+	var file subscriptionsFile
+	err := yaml.Unmarshal(content, &file)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	for index := range file.Subscriptions {
+		subscription := &file.Subscriptions[index]
+		if subscription.SecretEnv != "" {
+			subscription.Secret = os.Getenv(subscription.SecretEnv)
+		}
+
+		err = subscription.validate()
+		if err != nil {
+			return nil, fmt.Errorf("%w: subscription %d: %s", ErrInvalidSubscription, index, err.Error())
+		}
+		if names[subscription.Name] {
+			return nil, fmt.Errorf("%w: subscription %d: duplicated name %s", ErrInvalidSubscription, index, subscription.Name)
+		}
+		names[subscription.Name] = true
+	}
+
+	return file.Subscriptions, nil
+}
+
+func (s Subscription) validate() error {
+	if s.Name == "" {
+		return errors.New("missing name")
+	}
+
+	endpoint, err := url.Parse(s.URL)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return errors.New("invalid url " + s.URL)
+	}
+
+	if s.Secret == "" {
+		if s.SecretEnv != "" {
+			return errors.New("empty secret on " + s.SecretEnv)
+		}
+		return errors.New("missing secret")
+	}
+
+	if len(s.Events) == 0 {
+		return errors.New("missing events")
+	}
+	for _, event := range s.Events {
+		if !lifecycle.IsEvent(event) {
+			return errors.New("invalid event " + event)
+		}
+	}
+
+	return nil
+}
+
+func (s Subscription) wants(event string) bool {
+	for _, e := range s.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,187 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"hellper/internal/concurrence"
+	"hellper/internal/lifecycle"
+	"hellper/internal/log"
+	"hellper/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParseSubscriptions(t *testing.T) {
+	table := []struct {
+		testName      string
+		content       string
+		expectedError string
+	}{
+		{
+			testName: "Valid subscription",
+			content:  "subscriptions:\n  - {name: a, url: 'https://example.com', secret: s, events: [opened, closed]}",
+		},
+		{
+			testName:      "Missing secret",
+			content:       "subscriptions:\n  - {name: a, url: 'https://example.com', events: [opened]}",
+			expectedError: "invalid webhook subscription: subscription 0: missing secret",
+		},
+		{
+			testName:      "Empty secret env",
+			content:       "subscriptions:\n  - {name: a, url: 'https://example.com', secret_env: HELLPER_TEST_UNSET_SECRET, events: [opened]}",
+			expectedError: "invalid webhook subscription: subscription 0: empty secret on HELLPER_TEST_UNSET_SECRET",
+		},
+		{
+			testName:      "Invalid url",
+			content:       "subscriptions:\n  - {name: a, url: 'ftp://example.com', secret: s, events: [opened]}",
+			expectedError: "invalid webhook subscription: subscription 0: invalid url ftp://example.com",
+		},
+		{
+			testName:      "Invalid event",
+			content:       "subscriptions:\n  - {name: a, url: 'https://example.com', secret: s, events: [paused]}",
+			expectedError: "invalid webhook subscription: subscription 0: invalid event paused",
+		},
+		{
+			testName: "Duplicated name",
+			content: "subscriptions:\n" +
+				"  - {name: a, url: 'https://example.com', secret: s, events: [opened]}\n" +
+				"  - {name: a, url: 'https://example.org', secret: s, events: [closed]}",
+			expectedError: "invalid webhook subscription: subscription 1: duplicated name a",
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			_, err := ParseSubscriptions([]byte(f.content))
+			if f.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, f.expectedError)
+			assert.True(t, errors.Is(err, ErrInvalidSubscription))
+		})
+	}
+}
+
+func TestLoadExampleSubscriptions(t *testing.T) {
+	_, err := LoadSubscriptions("../../webhooks.example.yaml")
+	assert.True(t, errors.Is(err, ErrInvalidSubscription), "the example reads the secrets from unset variables")
+}
+
+type deliveryFixture struct {
+	testName          string
+	statuses          []int
+	expectedAttempts  int
+	expectedDelivered bool
+
+	server         *httptest.Server
+	repositoryMock *model.RepositoryMock
+	dispatcher     *Dispatcher
+	background     *concurrence.Background
+
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (f *deliveryFixture) setup(t *testing.T) {
+	var (
+		loggerMock     = log.NewLoggerMock()
+		repositoryMock = model.NewRepositoryMock()
+	)
+
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.requests = append(f.requests, r)
+		f.bodies = append(f.bodies, body)
+		w.WriteHeader(f.statuses[len(f.requests)-1])
+	}))
+
+	loggerMock.On("Error", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]log.Value")).Return()
+	repositoryMock.On("InsertWebhookDelivery", mock.Anything, mock.AnythingOfType("*model.WebhookDelivery")).Return(nil)
+
+	f.repositoryMock = repositoryMock
+	f.background = &concurrence.Background{}
+	f.dispatcher = NewDispatcher(loggerMock, repositoryMock, f.background, []Subscription{
+		{Name: "tooling", URL: f.server.URL, Secret: "s3cr3t", Events: []string{lifecycle.EventResolved}},
+		{Name: "analytics", URL: f.server.URL, Secret: "other", Events: []string{lifecycle.EventClosed}},
+	}, 3, time.Second)
+	f.dispatcher.sleep = func(time.Duration) {}
+	f.dispatcher.now = func() time.Time { return time.Unix(1600000000, 0) }
+}
+
+func TestDispatch(t *testing.T) {
+	table := []deliveryFixture{
+		{
+			testName:          "Delivered on the first attempt",
+			statuses:          []int{http.StatusOK},
+			expectedAttempts:  1,
+			expectedDelivered: true,
+		},
+		{
+			testName:          "Retries server errors",
+			statuses:          []int{http.StatusBadGateway, http.StatusTooManyRequests, http.StatusNoContent},
+			expectedAttempts:  3,
+			expectedDelivered: true,
+		},
+		{
+			testName:         "Gives up after the last attempt",
+			statuses:         []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError},
+			expectedAttempts: 3,
+		},
+		{
+			testName:         "Does not retry client errors",
+			statuses:         []int{http.StatusBadRequest},
+			expectedAttempts: 1,
+		},
+	}
+
+	for index := range table {
+		f := &table[index]
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			f.setup(t)
+			defer f.server.Close()
+
+			f.dispatcher.Dispatch(context.Background(), lifecycle.EventResolved, model.Incident{Id: 42, ChannelId: "C1", Status: model.StatusResolved})
+			assert.NoError(t, f.background.Wait(context.Background()))
+
+			assert.Len(t, f.requests, f.expectedAttempts)
+			f.repositoryMock.AssertNumberOfCalls(t, "InsertWebhookDelivery", f.expectedAttempts)
+
+			last := f.repositoryMock.Calls[len(f.repositoryMock.Calls)-1].Arguments.Get(1).(*model.WebhookDelivery)
+			assert.Equal(t, f.expectedDelivered, last.Delivered)
+			assert.Equal(t, f.expectedAttempts, last.Attempt)
+			assert.Equal(t, "tooling", last.Subscription)
+
+			request, body := f.requests[0], f.bodies[0]
+			assert.Equal(t, "resolved", request.Header.Get("X-Hellper-Event"))
+			assert.Equal(t, "1600000000", request.Header.Get("X-Hellper-Timestamp"))
+			mac := hmac.New(sha256.New, []byte("s3cr3t"))
+			mac.Write(append([]byte("t=1600000000."), body...))
+			assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), request.Header.Get("X-Hellper-Signature"))
+
+			var payload Payload
+			assert.NoError(t, json.Unmarshal(body, &payload))
+			assert.Equal(t, PayloadVersion, payload.Version)
+			assert.Equal(t, request.Header.Get("X-Hellper-Delivery"), payload.ID)
+			assert.Equal(t, int64(42), payload.Incident.ID)
+			assert.Equal(t, "C1", payload.Incident.ChannelID)
+		})
+	}
+}
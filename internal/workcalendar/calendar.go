@@ -0,0 +1,268 @@
+// Package workcalendar tells the business hours, quiet hours, weekends and holidays of each region
+package workcalendar
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultRegion is the name of the region built from the timezone when there is no calendar file
+const DefaultRegion = "default"
+
+// ErrInvalidCalendar is returned when a work calendar can not be parsed
+var ErrInvalidCalendar = errors.New("invalid work calendar")
+
+// Calendar lists the regions, the incidents of a product follow the region of the product, or the default region
+type Calendar struct {
+	DefaultRegion string             `yaml:"default_region"`
+	Products      map[string]string  `yaml:"products"`
+	Regions       map[string]*Region `yaml:"regions"`
+}
+
+// Region is the working calendar of a region, business hours default to 09:00-18:00 from Monday to Friday
+// and the post mortem meetings to 15:00
+type Region struct {
+	Timezone      string   `yaml:"timezone"`
+	BusinessHours Hours    `yaml:"business_hours"`
+	QuietHours    Hours    `yaml:"quiet_hours"`
+	MeetingTime   string   `yaml:"meeting_time"`
+	Weekends      []string `yaml:"weekends"`
+	Holidays      []string `yaml:"holidays"`
+
+	name     string
+	location *time.Location
+	meeting  clock
+	weekends map[time.Weekday]bool
+	holidays map[string]bool
+}
+
+// Hours is a daily range of local time such as 22:00 to 08:00, it wraps midnight when the end comes before the start
+type Hours struct {
+	Start string `yaml:"start"`
+	End   string `yaml:"end"`
+
+	start clock
+	end   clock
+}
+
+// clock is a local time of the day in minutes since midnight
+type clock int
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// Default is the calendar of a single region on the timezone, without holidays nor quiet hours
+func Default(timezone string) (Calendar, error) {
+	calendar := Calendar{
+		DefaultRegion: DefaultRegion,
+		Regions:       map[string]*Region{DefaultRegion: {Timezone: timezone}},
+	}
+	return calendar, calendar.validate()
+}
+
+// Load reads a YAML work calendar file
+func Load(path string) (Calendar, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Calendar{}, err
+	}
+	return Parse(content)
+}
+
+// Parse parses and validates a YAML work calendar
+func Parse(content []byte) (Calendar, error) {
+	var calendar Calendar
+	err := yaml.Unmarshal(content, &calendar)
+	if err != nil {
+		return Calendar{}, fmt.Errorf("%w: %s", ErrInvalidCalendar, err.Error())
+	}
+	return calendar, calendar.validate()
+}
+
+// Region returns the region of the product, or the default region, nil on an empty calendar
+func (c Calendar) Region(product string) *Region {
+	if name, ok := c.Products[product]; ok {
+		return c.Regions[name]
+	}
+	return c.Regions[c.DefaultRegion]
+}
+
+// Name is the name of the region on the calendar
+func (r *Region) Name() string {
+	return r.name
+}
+
+// IsDayOff tells whether the local day of t is a weekend or a holiday of the region
+func (r *Region) IsDayOff(t time.Time) bool {
+	if r == nil {
+		return false
+	}
+	local := t.In(r.location)
+	return r.weekends[local.Weekday()] || r.holidays[local.Format("2006-01-02")] || r.holidays[local.Format("01-02")]
+}
+
+// IsBusinessTime tells whether t is within the business hours of a working day, always true without a region
+func (r *Region) IsBusinessTime(t time.Time) bool {
+	if r == nil {
+		return true
+	}
+	return !r.IsDayOff(t) && r.BusinessHours.contains(t.In(r.location))
+}
+
+// IsQuietTime tells whether t is within the quiet hours or on a day off, always false without a region
+func (r *Region) IsQuietTime(t time.Time) bool {
+	if r == nil {
+		return false
+	}
+	return r.IsDayOff(t) || (r.QuietHours.Start != "" && r.QuietHours.contains(t.In(r.location)))
+}
+
+// MeetingSlot is the meeting time of the first working day at least gapDays after t
+func (r *Region) MeetingSlot(t time.Time, gapDays int) time.Time {
+	local := t.In(r.location)
+	day := time.Date(local.Year(), local.Month(), local.Day()+gapDays, int(r.meeting)/60, int(r.meeting)%60, 0, 0, r.location)
+	for i := 0; i < 366 && r.IsDayOff(day); i++ {
+		day = time.Date(day.Year(), day.Month(), day.Day()+1, day.Hour(), day.Minute(), 0, 0, r.location)
+	}
+	return day
+}
+
+func (c *Calendar) validate() error {
+	if len(c.Regions) == 0 {
+		return fmt.Errorf("%w: no region", ErrInvalidCalendar)
+	}
+	if len(c.Regions) == 1 && c.DefaultRegion == "" {
+		for name := range c.Regions {
+			c.DefaultRegion = name
+		}
+	}
+	if c.Regions[c.DefaultRegion] == nil {
+		return fmt.Errorf("%w: unknown default region %q", ErrInvalidCalendar, c.DefaultRegion)
+	}
+
+	for product, name := range c.Products {
+		if c.Regions[name] == nil {
+			return fmt.Errorf("%w: unknown region %q of %s", ErrInvalidCalendar, name, product)
+		}
+	}
+
+	for name, region := range c.Regions {
+		if region == nil {
+			return fmt.Errorf("%w: region %s is empty", ErrInvalidCalendar, name)
+		}
+		region.name = name
+		err := region.validate()
+		if err != nil {
+			return fmt.Errorf("%w: region %s: %s", ErrInvalidCalendar, name, err.Error())
+		}
+	}
+	return nil
+}
+
+func (r *Region) validate() error {
+	var err error
+	r.location, err = time.LoadLocation(r.Timezone)
+	if err != nil || r.Timezone == "" {
+		return fmt.Errorf("invalid timezone %q", r.Timezone)
+	}
+
+	if r.BusinessHours == (Hours{}) {
+		r.BusinessHours = Hours{Start: "09:00", End: "18:00"}
+	}
+	err = r.BusinessHours.parse()
+	if err != nil {
+		return fmt.Errorf("business hours: %s", err.Error())
+	}
+	if r.BusinessHours.end <= r.BusinessHours.start {
+		return errors.New("business hours must end after they start")
+	}
+
+	if r.QuietHours != (Hours{}) {
+		err = r.QuietHours.parse()
+		if err != nil {
+			return fmt.Errorf("quiet hours: %s", err.Error())
+		}
+	}
+
+	if r.MeetingTime == "" {
+		r.MeetingTime = "15:00"
+	}
+	r.meeting, err = parseClock(r.MeetingTime)
+	if err != nil {
+		return fmt.Errorf("meeting time: %s", err.Error())
+	}
+	if !r.BusinessHours.contains(time.Date(2000, 1, 1, int(r.meeting)/60, int(r.meeting)%60, 0, 0, time.UTC)) {
+		return fmt.Errorf("meeting time %s is outside the business hours", r.MeetingTime)
+	}
+
+	if r.Weekends == nil {
+		r.Weekends = []string{"saturday", "sunday"}
+	}
+	r.weekends = map[time.Weekday]bool{}
+	for _, day := range r.Weekends {
+		weekday, ok := weekdays[strings.ToLower(day)]
+		if !ok {
+			return fmt.Errorf("unknown weekday %q", day)
+		}
+		r.weekends[weekday] = true
+	}
+
+	r.holidays = map[string]bool{}
+	for _, holiday := range r.Holidays {
+		_, err = time.Parse("2006-01-02", holiday)
+		if err != nil {
+			_, err = time.Parse("01-02", holiday)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid holiday %q, use 2006-01-02 or 01-02 for every year", holiday)
+		}
+		r.holidays[holiday] = true
+	}
+
+	return nil
+}
+
+func (h *Hours) parse() error {
+	var err error
+	h.start, err = parseClock(h.Start)
+	if err != nil {
+		return err
+	}
+	h.end, err = parseClock(h.End)
+	if err != nil {
+		return err
+	}
+	if h.start == h.end {
+		return errors.New("the hours start and end at the same time")
+	}
+	return nil
+}
+
+// contains tells whether the local time of t is within the hours
+func (h Hours) contains(t time.Time) bool {
+	now := clock(t.Hour()*60 + t.Minute())
+	if h.start < h.end {
+		return now >= h.start && now < h.end
+	}
+	return now >= h.start || now < h.end
+}
+
+func parseClock(value string) (clock, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, use 15:04", value)
+	}
+	return clock(t.Hour()*60 + t.Minute()), nil
+}
@@ -0,0 +1,230 @@
+package workcalendar_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"hellper/internal/workcalendar"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testCalendar = `
+default_region: br
+products:
+  Checkout: us
+regions:
+  br:
+    timezone: America/Sao_Paulo
+    quiet_hours: {start: "22:00", end: "08:00"}
+    holidays: ["2026-11-20", "12-25"]
+  us:
+    timezone: America/New_York
+    business_hours: {start: "08:00", end: "16:00"}
+    meeting_time: "10:00"
+    weekends: [friday, saturday, sunday]
+`
+
+func TestParse(t *testing.T) {
+	table := []struct {
+		testName      string
+		content       string
+		expectedError string
+	}{
+		{
+			testName: "Valid calendar",
+			content:  testCalendar,
+		},
+		{
+			testName: "Single region is the default one",
+			content:  "regions:\n  br: {timezone: America/Sao_Paulo}",
+		},
+		{
+			testName:      "No region",
+			content:       "default_region: br",
+			expectedError: "invalid work calendar: no region",
+		},
+		{
+			testName:      "Unknown default region",
+			content:       "default_region: us\nregions:\n  br: {timezone: America/Sao_Paulo}",
+			expectedError: `invalid work calendar: unknown default region "us"`,
+		},
+		{
+			testName:      "Unknown product region",
+			content:       "products: {Checkout: us}\nregions:\n  br: {timezone: America/Sao_Paulo}",
+			expectedError: `invalid work calendar: unknown region "us" of Checkout`,
+		},
+		{
+			testName:      "Invalid timezone",
+			content:       "regions:\n  br: {timezone: Mars/Olympus}",
+			expectedError: `invalid work calendar: region br: invalid timezone "Mars/Olympus"`,
+		},
+		{
+			testName:      "Business hours wrapping midnight",
+			content:       "regions:\n  br: {timezone: UTC, business_hours: {start: '22:00', end: '06:00'}}",
+			expectedError: "invalid work calendar: region br: business hours must end after they start",
+		},
+		{
+			testName:      "Meeting outside the business hours",
+			content:       "regions:\n  br: {timezone: UTC, meeting_time: '19:00'}",
+			expectedError: "invalid work calendar: region br: meeting time 19:00 is outside the business hours",
+		},
+		{
+			testName:      "Invalid holiday",
+			content:       "regions:\n  br: {timezone: UTC, holidays: [christmas]}",
+			expectedError: `invalid work calendar: region br: invalid holiday "christmas", use 2006-01-02 or 01-02 for every year`,
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			_, err := workcalendar.Parse([]byte(f.content))
+			if f.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, f.expectedError)
+			assert.True(t, errors.Is(err, workcalendar.ErrInvalidCalendar))
+		})
+	}
+}
+
+func TestLoadExampleCalendar(t *testing.T) {
+	_, err := workcalendar.Load("../../work-calendar.example.yaml")
+	assert.NoError(t, err)
+}
+
+func TestRegion(t *testing.T) {
+	calendar, err := workcalendar.Parse([]byte(testCalendar))
+	assert.NoError(t, err)
+
+	saoPaulo, _ := time.LoadLocation("America/Sao_Paulo")
+	newYork, _ := time.LoadLocation("America/New_York")
+
+	table := []struct {
+		testName         string
+		product          string
+		at               time.Time
+		expectedRegion   string
+		expectedBusiness bool
+		expectedQuiet    bool
+	}{
+		{
+			testName:         "Working day afternoon",
+			product:          "Search",
+			at:               time.Date(2026, 10, 19, 14, 0, 0, 0, saoPaulo),
+			expectedRegion:   "br",
+			expectedBusiness: true,
+		},
+		{
+			testName:       "After the business hours",
+			product:        "Search",
+			at:             time.Date(2026, 10, 19, 19, 0, 0, 0, saoPaulo),
+			expectedRegion: "br",
+		},
+		{
+			testName:       "Quiet hours wrap midnight",
+			product:        "Search",
+			at:             time.Date(2026, 10, 20, 3, 0, 0, 0, saoPaulo),
+			expectedRegion: "br",
+			expectedQuiet:  true,
+		},
+		{
+			testName:       "Holiday of the year",
+			product:        "Search",
+			at:             time.Date(2026, 11, 20, 14, 0, 0, 0, saoPaulo),
+			expectedRegion: "br",
+			expectedQuiet:  true,
+		},
+		{
+			testName:       "Holiday of every year",
+			product:        "Search",
+			at:             time.Date(2027, 12, 25, 14, 0, 0, 0, saoPaulo),
+			expectedRegion: "br",
+			expectedQuiet:  true,
+		},
+		{
+			testName:       "Weekend of the product region",
+			product:        "Checkout",
+			at:             time.Date(2026, 10, 23, 10, 0, 0, 0, newYork),
+			expectedRegion: "us",
+			expectedQuiet:  true,
+		},
+		{
+			testName:         "Business hours of the product region",
+			product:          "Checkout",
+			at:               time.Date(2026, 10, 22, 8, 30, 0, 0, newYork),
+			expectedRegion:   "us",
+			expectedBusiness: true,
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			region := calendar.Region(f.product)
+			assert.Equal(t, f.expectedRegion, region.Name())
+			assert.Equal(t, f.expectedBusiness, region.IsBusinessTime(f.at))
+			assert.Equal(t, f.expectedQuiet, region.IsQuietTime(f.at))
+		})
+	}
+}
+
+func TestMeetingSlot(t *testing.T) {
+	calendar, err := workcalendar.Parse([]byte(testCalendar))
+	assert.NoError(t, err)
+
+	saoPaulo, _ := time.LoadLocation("America/Sao_Paulo")
+	newYork, _ := time.LoadLocation("America/New_York")
+
+	table := []struct {
+		testName string
+		product  string
+		resolved time.Time
+		gapDays  int
+		expected time.Time
+	}{
+		{
+			testName: "Working day",
+			product:  "Search",
+			resolved: time.Date(2026, 10, 19, 23, 0, 0, 0, saoPaulo),
+			gapDays:  2,
+			expected: time.Date(2026, 10, 21, 15, 0, 0, 0, saoPaulo),
+		},
+		{
+			testName: "Skips the weekend",
+			product:  "Search",
+			resolved: time.Date(2026, 10, 22, 10, 0, 0, 0, saoPaulo),
+			gapDays:  2,
+			expected: time.Date(2026, 10, 26, 15, 0, 0, 0, saoPaulo),
+		},
+		{
+			testName: "Skips the holiday and the weekend",
+			product:  "Search",
+			resolved: time.Date(2026, 11, 18, 10, 0, 0, 0, saoPaulo),
+			gapDays:  2,
+			expected: time.Date(2026, 11, 23, 15, 0, 0, 0, saoPaulo),
+		},
+		{
+			testName: "Meeting time of the product region",
+			product:  "Checkout",
+			resolved: time.Date(2026, 10, 19, 10, 0, 0, 0, newYork),
+			gapDays:  1,
+			expected: time.Date(2026, 10, 20, 10, 0, 0, 0, newYork),
+		},
+	}
+
+	for index, f := range table {
+		t.Run(fmt.Sprintf("%v-%v", index, f.testName), func(t *testing.T) {
+			slot := calendar.Region(f.product).MeetingSlot(f.resolved.UTC(), f.gapDays)
+			assert.True(t, f.expected.Equal(slot), "expected %s, got %s", f.expected, slot)
+		})
+	}
+}
+
+func TestNoRegion(t *testing.T) {
+	region := workcalendar.Calendar{}.Region("Search")
+	assert.True(t, region.IsBusinessTime(time.Now()))
+	assert.False(t, region.IsQuietTime(time.Now()))
+}